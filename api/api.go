@@ -1,13 +1,21 @@
 package api
 
 import (
+	"bytes"
+	"compress/gzip"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"path"
 	"strings"
 
 	"github.com/julienschmidt/httprouter"
 )
 
+var (
+	errInvalidBasePath = errors.New("API base path may not contain ':', '*', '?' or '#'")
+)
+
 // Error is a type that is encoded as JSON and returned in an API response in
 // the event of an error. Only the Message field is required. More fields may
 // be added to this struct in the future for better error reporting.
@@ -115,51 +123,140 @@ func requirePassword(h httprouter.Handle, password string) httprouter.Handle {
 	}
 }
 
+// A prefixRouter registers every route of an httprouter.Router under a common
+// base path.
+type prefixRouter struct {
+	*httprouter.Router
+	prefix string
+}
+
+// GET registers a handler for GET requests to the prefixed path.
+func (pr prefixRouter) GET(path string, handle httprouter.Handle) {
+	pr.Router.GET(pr.prefix+path, handle)
+}
+
+// HEAD registers a handler for HEAD requests to the prefixed path.
+func (pr prefixRouter) HEAD(path string, handle httprouter.Handle) {
+	pr.Router.HEAD(pr.prefix+path, handle)
+}
+
+// POST registers a handler for POST requests to the prefixed path.
+func (pr prefixRouter) POST(path string, handle httprouter.Handle) {
+	pr.Router.POST(pr.prefix+path, handle)
+}
+
+// CleanBasePath normalizes a base path for the API, such that "sia", "/sia"
+// and "/sia/" all become "/sia". The root path becomes the empty string.
+// Base paths may not contain the route wildcards ':' and '*', nor a query or
+// fragment.
+func CleanBasePath(basePath string) (string, error) {
+	if strings.ContainsAny(basePath, ":*?#") {
+		return "", errInvalidBasePath
+	}
+	basePath = path.Clean("/" + basePath)
+	if basePath == "/" {
+		return "", nil
+	}
+	return basePath, nil
+}
+
 // initAPI determines which functions handle each API call. An empty string as
-// the password indicates no password.
+// the password indicates no password. Every route is registered under the
+// server's base path.
 func (srv *Server) initAPI(password string) {
-	router := httprouter.New()
+	router := prefixRouter{httprouter.New(), srv.basePath}
 	router.NotFound = http.HandlerFunc(srv.unrecognizedCallHandler) // custom 404
 
 	// Daemon API Calls
 	router.GET("/daemon/constants", srv.daemonConstantsHandler)
+	router.HEAD("/daemon/constants", srv.daemonConstantsHandler)
+	router.GET("/daemon/constants/pretty", srv.daemonConstantsPrettyHandler)
+	router.HEAD("/daemon/constants/pretty", srv.daemonConstantsPrettyHandler)
 	router.GET("/daemon/version", srv.daemonVersionHandler)
+	router.GET("/daemon/version/compare", srv.daemonVersionCompareHandler)
+	router.GET("/daemon/alerts", srv.daemonAlertsHandlerGET)
 	router.GET("/daemon/update", srv.daemonUpdateHandlerGET)
-	router.POST("/daemon/update", srv.daemonUpdateHandlerPOST)
+	router.POST("/daemon/update", requirePassword(srv.daemonUpdateHandlerPOST, password))
+	router.POST("/daemon/update/channel", requirePassword(srv.daemonUpdateChannelHandlerPOST, password))
+	router.GET("/daemon/update/progress", srv.daemonUpdateProgressHandlerGET)
+	router.GET("/daemon/update/history", srv.daemonUpdateHistoryHandlerGET)
+	router.GET("/daemon/memstats", srv.daemonMemStatsHandler)
+	router.GET("/daemon/settings", srv.daemonSettingsHandlerGET)
+	router.POST("/daemon/settings", requirePassword(srv.daemonSettingsHandlerPOST, password))
+	router.GET("/daemon/stack", requirePassword(srv.daemonStackHandler, password))
 	router.GET("/daemon/stop", requirePassword(srv.daemonStopHandler, password))
+	router.GET("/daemon/uptime", srv.daemonUptimeHandler)
+	router.GET("/daemon/verify", requirePassword(srv.daemonVerifyHandler, password))
+	if srv.debug {
+		router.GET("/daemon/debug/cpu", requirePassword(srv.daemonDebugCPUHandler, password))
+		router.GET("/daemon/debug/goroutines", requirePassword(srv.daemonDebugGoroutinesHandler, password))
+		router.GET("/daemon/debug/heap", requirePassword(srv.daemonDebugHeapHandler, password))
+	}
 
 	// Consensus API Calls
 	if srv.cs != nil {
 		router.GET("/consensus", srv.consensusHandler)
+		router.GET("/consensus/nextdifficulty", srv.consensusNextDifficultyHandler)
+		router.GET("/consensus/reorglimit", srv.consensusReorgLimitHandlerGET)
+		router.POST("/consensus/reorglimit", requirePassword(srv.consensusReorgLimitHandlerPOST, password))
+		router.POST("/consensus/sync/pause", requirePassword(srv.consensusSyncPauseHandler, password))
+		router.POST("/consensus/sync/resume", requirePassword(srv.consensusSyncResumeHandler, password))
+		router.POST("/consensus/export/cancel", requirePassword(srv.consensusExportCancelHandler, password))
+		router.GET("/consensus/snapshot", srv.consensusSnapshotHandlerGET)
+		router.POST("/consensus/snapshot", requirePassword(srv.consensusSnapshotHandlerPOST, password))
+		router.GET("/consensus/verify", srv.consensusVerifyHandlerGET)
+		router.POST("/consensus/verify", requirePassword(srv.consensusVerifyHandlerPOST, password))
+		router.POST("/consensus/verify/cancel", requirePassword(srv.consensusVerifyCancelHandler, password))
+		router.GET("/consensus/tipinfo", srv.consensusTipInfoHandler)
 	}
 
 	// Explorer API Calls
 	if srv.explorer != nil {
 		router.GET("/explorer", srv.explorerHandler)
+		router.POST("/explorer/balances", srv.explorerBalancesHandler)
 		router.GET("/explorer/blocks/:height", srv.explorerBlocksHandler)
+		router.GET("/explorer/blocktimes", srv.explorerBlockTimesHandler)
 		router.GET("/explorer/hashes/:hash", srv.explorerHashHandler)
 	}
 
 	// Gateway API Calls
 	if srv.gateway != nil {
 		router.GET("/gateway", srv.gatewayHandler)
+		router.GET("/gateway/externaladdress", srv.gatewayExternalAddressHandler)
 		router.POST("/gateway/connect/:netaddress", requirePassword(srv.gatewayConnectHandler, password))
 		router.POST("/gateway/disconnect/:netaddress", requirePassword(srv.gatewayDisconnectHandler, password))
+		router.GET("/gateway/rpclimits", srv.gatewayRPCLimitsHandlerGET)
+		router.POST("/gateway/rpclimits", requirePassword(srv.gatewayRPCLimitsHandlerPOST, password))
+		router.GET("/gateway/settings", srv.gatewaySettingsHandlerGET)
+		router.POST("/gateway/settings", requirePassword(srv.gatewaySettingsHandlerPOST, password))
 	}
 
 	// Host API Calls
 	if srv.host != nil {
 		// Calls directly pertaining to the host.
-		router.GET("/host", srv.hostHandlerGET)                                           // Get the host status.
-		router.POST("/host", requirePassword(srv.hostHandlerPOST, password))              // Change the settings of the host.
-		router.POST("/host/announce", requirePassword(srv.hostAnnounceHandler, password)) // Announce the host to the network.
+		router.GET("/host", srv.hostHandlerGET)                                                   // Get the host status.
+		router.POST("/host", requirePassword(srv.hostHandlerPOST, password))                      // Change the settings of the host.
+		router.POST("/host/announce", requirePassword(srv.hostAnnounceHandler, password))         // Announce the host to the network.
+		router.GET("/host/cache", srv.hostCacheHandlerGET)                                        // Get the size and hit rate of the sector cache.
+		router.POST("/host/cache", requirePassword(srv.hostCacheHandlerPOST, password))           // Set the size of the sector cache.
+		router.POST("/host/decommission", requirePassword(srv.hostDecommissionHandler, password)) // Wind down the host.
+		router.GET("/host/decommission/status", srv.hostDecommissionStatusHandler)                // Get the progress of decommissioning.
+		router.GET("/host/pricing", srv.hostPricingHandler)                                       // Get the prices set by automatic pricing.
+		router.GET("/host/proofs", srv.hostProofsHandler)                                         // Get the status of storage proofs.
+		router.GET("/host/rejections", srv.hostRejectionsHandler)                                 // Get recently rejected contract proposals.
+		router.GET("/host/rpcmetrics", srv.hostRPCMetricsHandler)                                 // Get the latency of each type of RPC.
+		router.GET("/host/sessions", srv.hostSessionsHandler)                                     // Get the open upload and download RPCs of each renter.
+		router.GET("/host/sectors", srv.hostSectorsHandler)                                       // Get the reference count and location of a sector.
+		router.GET("/host/sectors/orphaned", srv.hostSectorsOrphanedHandler)                      // Get the sectors not held by any storage obligation.
 
 		// Calls pertaining to the storage manager that the host uses.
 		router.GET("/host/storage", srv.storageHandler)
 		router.POST("/host/storage/folders/add", requirePassword(srv.storageFoldersAddHandler, password))
 		router.POST("/host/storage/folders/remove", requirePassword(srv.storageFoldersRemoveHandler, password))
 		router.POST("/host/storage/folders/resize", requirePassword(srv.storageFoldersResizeHandler, password))
+		router.POST("/host/storage/folders/tier", requirePassword(srv.storageFoldersTierHandler, password))
 		router.POST("/host/storage/sectors/delete/:merkleroot", requirePassword(srv.storageSectorsDeleteHandler, password))
+		router.POST("/host/storage/tierpolicy", requirePassword(srv.storageTierPolicyHandler, password))
 	}
 
 	// Miner API Calls
@@ -167,6 +264,8 @@ func (srv *Server) initAPI(password string) {
 		router.GET("/miner", srv.minerHandler)
 		router.GET("/miner/header", requirePassword(srv.minerHeaderHandlerGET, password))
 		router.POST("/miner/header", requirePassword(srv.minerHeaderHandlerPOST, password))
+		router.GET("/miner/payoutsplit", srv.minerPayoutSplitHandlerGET)
+		router.POST("/miner/payoutsplit", requirePassword(srv.minerPayoutSplitHandlerPOST, password))
 		router.GET("/miner/start", requirePassword(srv.minerStartHandler, password))
 		router.GET("/miner/stop", requirePassword(srv.minerStopHandler, password))
 	}
@@ -175,9 +274,20 @@ func (srv *Server) initAPI(password string) {
 	if srv.renter != nil {
 		router.GET("/renter", srv.renterHandlerGET)
 		router.POST("/renter", requirePassword(srv.renterHandlerPOST, password))
+		router.GET("/renter/accesslog", srv.renterAccessLogHandler)
 		router.GET("/renter/contracts", srv.renterContractsHandler)
+		router.GET("/renter/contract/:id", srv.renterContractHandlerGET)
+		router.GET("/renter/contracts/ages", srv.renterContractAgesHandler)
+		router.GET("/renter/contracts/expired", srv.renterExpiredContractsHandlerGET)
+		router.POST("/renter/contracts/expired/clear", requirePassword(srv.renterExpiredContractsClearHandler, password))
 		router.GET("/renter/downloads", srv.renterDownloadsHandler)
 		router.GET("/renter/files", srv.renterFilesHandler)
+		router.GET("/renter/hosts/usage", srv.renterHostsUsageHandler)
+		router.GET("/renter/migrate", srv.renterMigrateHandlerGET)
+		router.POST("/renter/migrate", requirePassword(srv.renterMigrateHandlerPOST, password))
+		router.POST("/renter/simulate-failure", requirePassword(srv.renterSimulateFailureHandler, password))
+		router.GET("/renter/workers", srv.renterWorkersHandlerGET)
+		router.POST("/renter/workers", requirePassword(srv.renterWorkersHandlerPOST, password))
 
 		// TODO: re-enable these routes once the new .sia format has been
 		// standardized and implemented.
@@ -188,8 +298,13 @@ func (srv *Server) initAPI(password string) {
 
 		router.POST("/renter/delete/*siapath", requirePassword(srv.renterDeleteHandler, password))
 		router.GET("/renter/download/*siapath", requirePassword(srv.renterDownloadHandler, password))
+		router.GET("/renter/downloadcost/*siapath", srv.renterDownloadCostHandler)
+		router.POST("/renter/redundancy/*siapath", requirePassword(srv.renterRedundancyHandler, password))
 		router.POST("/renter/rename/*siapath", requirePassword(srv.renterRenameHandler, password))
 		router.POST("/renter/upload/*siapath", requirePassword(srv.renterUploadHandler, password))
+		router.POST("/renter/uploadurl/*siapath", requirePassword(srv.renterUploadURLHandler, password))
+		router.POST("/renter/uploadestimate", requirePassword(srv.renterUploadEstimateHandler, password))
+		router.POST("/renter/verify/*siapath", requirePassword(srv.renterVerifyHandler, password))
 
 		// HostDB endpoints.
 		router.GET("/hostdb/active", srv.renterHostsActiveHandler)
@@ -200,6 +315,9 @@ func (srv *Server) initAPI(password string) {
 	if srv.tpool != nil {
 		// TODO: re-enable this route once the transaction pool API has been finalized
 		//router.GET("/transactionpool/transactions", srv.transactionpoolTransactionsHandler)
+		router.GET("/tpool/feerank", srv.tpoolFeeRankHandler)
+		router.GET("/tpool/origin", srv.tpoolOriginHandler)
+		router.GET("/tpool/stats", srv.tpoolStatsHandler)
 	}
 
 	// Wallet API Calls
@@ -208,23 +326,48 @@ func (srv *Server) initAPI(password string) {
 		router.POST("/wallet/033x", requirePassword(srv.wallet033xHandler, password))
 		router.GET("/wallet/address", requirePassword(srv.walletAddressHandler, password))
 		router.GET("/wallet/addresses", srv.walletAddressesHandler)
+		router.POST("/wallet/addresses/batch", requirePassword(srv.walletAddressesBatchHandler, password))
+		router.GET("/wallet/autobumps", srv.walletAutoBumpsHandler)
 		router.GET("/wallet/backup", requirePassword(srv.walletBackupHandler, password))
+		router.GET("/wallet/balance/breakdown", srv.walletBalanceBreakdownHandler)
+		router.POST("/wallet/canary", requirePassword(srv.walletCanaryHandler, password))
+		router.POST("/wallet/consolidate", requirePassword(srv.walletConsolidateHandler, password))
+		router.GET("/wallet/contracts", srv.walletContractsHandler)
+		router.GET("/wallet/export", srv.walletExportHandler)
+		router.GET("/wallet/fees", srv.walletFeesHandler)
+		router.GET("/wallet/paymenturi", srv.walletPaymentURIHandler)
+		router.GET("/wallet/paymenturi/parse", srv.walletPaymentURIParseHandler)
 		router.POST("/wallet/init", requirePassword(srv.walletInitHandler, password))
 		router.POST("/wallet/lock", requirePassword(srv.walletLockHandler, password))
+		router.POST("/wallet/reindex", requirePassword(srv.walletReindexHandler, password))
+		router.GET("/wallet/schedule", srv.walletScheduleHandlerGET)
+		router.POST("/wallet/schedule", requirePassword(srv.walletScheduleHandlerPOST, password))
+		router.POST("/wallet/schedule/cancel", requirePassword(srv.walletScheduleCancelHandler, password))
 		router.POST("/wallet/seed", requirePassword(srv.walletSeedHandler, password))
 		router.GET("/wallet/seeds", requirePassword(srv.walletSeedsHandler, password))
 		router.POST("/wallet/siacoins", requirePassword(srv.walletSiacoinsHandler, password))
+		router.POST("/wallet/siacoins/preview", requirePassword(srv.walletSiacoinsPreviewHandler, password))
 		router.POST("/wallet/siafunds", requirePassword(srv.walletSiafundsHandler, password))
+		router.GET("/wallet/siafunds/claims", srv.walletSiafundClaimsHandlerGET)
+		router.POST("/wallet/siafunds/claims", requirePassword(srv.walletSiafundClaimsHandlerPOST, password))
 		router.POST("/wallet/siagkey", requirePassword(srv.walletSiagkeyHandler, password))
+		router.GET("/wallet/syncstatus", srv.walletSyncStatusHandler)
 		router.GET("/wallet/transaction/:id", srv.walletTransactionHandler)
 		router.GET("/wallet/transactions", srv.walletTransactionsHandler)
 		router.GET("/wallet/transactions/:addr", srv.walletTransactionsAddrHandler)
+		router.POST("/wallet/txid", requirePassword(srv.walletTxIDHandler, password))
+		router.GET("/wallet/unconfirmed", srv.walletUnconfirmedHandler)
 		router.POST("/wallet/unlock", requirePassword(srv.walletUnlockHandler, password))
+		router.POST("/wallet/unlock/view", requirePassword(srv.walletUnlockViewHandler, password))
+		router.POST("/wallet/verifyseed", requirePassword(srv.walletVerifySeedHandler, password))
 	}
 
 	// Apply UserAgent middleware and create HTTP server
 	uaRouter := requireUserAgent(router, srv.requiredUserAgent)
-	srv.apiServer = &http.Server{Handler: uaRouter}
+	srv.apiServer = &http.Server{
+		Handler:   srv.requests.track(allowGzip(uaRouter)),
+		ConnState: srv.trackConn,
+	}
 }
 
 // unrecognizedCallHandler handles calls to unknown pages (404).
@@ -243,12 +386,24 @@ func writeError(w http.ResponseWriter, err Error, code int) {
 
 // writeJSON writes the object to the ResponseWriter. If the encoding fails, an
 // error is written instead. The Content-Type of the response header is set
-// accordingly.
+// accordingly. Responses of at least gzipMinSize bytes are gzip compressed if
+// the client accepts gzip.
 func writeJSON(w http.ResponseWriter, obj interface{}) {
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
-	if json.NewEncoder(w).Encode(obj) != nil {
+	w.Header().Add("Vary", "Accept-Encoding")
+	var buf bytes.Buffer
+	if json.NewEncoder(&buf).Encode(obj) != nil {
 		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+	if _, ok := w.(gzipResponseWriter); !ok || buf.Len() < gzipMinSize {
+		w.Write(buf.Bytes())
+		return
 	}
+	w.Header().Set("Content-Encoding", "gzip")
+	gz := gzip.NewWriter(w)
+	gz.Write(buf.Bytes())
+	gz.Close()
 }
 
 // writeSuccess writes the HTTP header with status 204 No Content to the
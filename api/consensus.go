@@ -1,30 +1,215 @@
 package api
 
 import (
+	"fmt"
 	"net/http"
+	"os"
+	"path/filepath"
 
+	"github.com/NebulousLabs/Sia/crypto"
+	"github.com/NebulousLabs/Sia/modules"
 	"github.com/NebulousLabs/Sia/types"
 
 	"github.com/julienschmidt/httprouter"
 )
 
 // ConsensusGET contains general information about the consensus set, with tags
-// to support idiomatic json encodings.
+// to support idiomatic json encodings. StateRoot is only set when it is
+// requested, because computing it reads the whole consensus database.
 type ConsensusGET struct {
 	Synced       bool              `json:"synced"`
+	SyncPaused   bool              `json:"syncpaused"`
 	Height       types.BlockHeight `json:"height"`
 	CurrentBlock types.BlockID     `json:"currentblock"`
 	Target       types.Target      `json:"target"`
+	StateRoot    *crypto.Hash      `json:"stateroot,omitempty"`
+}
+
+// ConsensusNextDifficultyGET contains the projected outcome of the next
+// difficulty adjustment.
+type ConsensusNextDifficultyGET struct {
+	Height                types.BlockHeight `json:"height"`
+	AdjustmentHeight      types.BlockHeight `json:"adjustmentheight"`
+	BlocksUntilAdjustment types.BlockHeight `json:"blocksuntiladjustment"`
+	CurrentTarget         types.Target      `json:"currenttarget"`
+	CurrentDifficulty     types.Currency    `json:"currentdifficulty"`
+	ProjectedTarget       types.Target      `json:"projectedtarget"`
+	ProjectedDifficulty   types.Currency    `json:"projecteddifficulty"`
+}
+
+// ConsensusReorgLimitGET contains the maximum reorg depth of the consensus
+// set and the most recent reorg that was refused for exceeding it.
+type ConsensusReorgLimitGET struct {
+	MaxReorgDepth types.BlockHeight              `json:"maxreorgdepth"`
+	RefusedReorg  *modules.ConsensusRefusedReorg `json:"refusedreorg"`
 }
 
 // consensusHandler handles the API calls to /consensus.
 func (srv *Server) consensusHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 	cbid := srv.cs.CurrentBlock().ID()
 	currentTarget, _ := srv.cs.ChildTarget(cbid)
-	writeJSON(w, ConsensusGET{
+	cg := ConsensusGET{
 		Synced:       srv.cs.Synced(),
+		SyncPaused:   srv.cs.SyncPaused(),
 		Height:       srv.cs.Height(),
 		CurrentBlock: cbid,
 		Target:       currentTarget,
+	}
+	if req.FormValue("stateroot") == "true" {
+		stateRoot, err := srv.cs.StateRoot()
+		if err != nil {
+			writeError(w, Error{"unable to compute state root: " + err.Error()}, http.StatusInternalServerError)
+			return
+		}
+		cg.StateRoot = &stateRoot
+	}
+	writeJSON(w, cg)
+}
+
+// consensusNextDifficultyHandler handles the API call to
+// /consensus/nextdifficulty.
+func (srv *Server) consensusNextDifficultyHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	height := srv.cs.Height()
+	currentTarget, _ := srv.cs.ChildTarget(srv.cs.CurrentBlock().ID())
+	projectedTarget, adjustmentHeight := srv.cs.NextChildTarget()
+	writeJSON(w, ConsensusNextDifficultyGET{
+		Height:                height,
+		AdjustmentHeight:      adjustmentHeight,
+		BlocksUntilAdjustment: adjustmentHeight - height,
+		CurrentTarget:         currentTarget,
+		CurrentDifficulty:     currentTarget.Difficulty(),
+		ProjectedTarget:       projectedTarget,
+		ProjectedDifficulty:   projectedTarget.Difficulty(),
 	})
 }
+
+// consensusReorgLimitHandlerGET handles the API call to GET
+// /consensus/reorglimit.
+func (srv *Server) consensusReorgLimitHandlerGET(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	crl := ConsensusReorgLimitGET{
+		MaxReorgDepth: srv.cs.MaxReorgDepth(),
+	}
+	if rr, ok := srv.cs.RefusedReorg(); ok {
+		crl.RefusedReorg = &rr
+	}
+	writeJSON(w, crl)
+}
+
+// consensusReorgLimitHandlerPOST handles the API call to POST
+// /consensus/reorglimit.
+func (srv *Server) consensusReorgLimitHandlerPOST(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	var depth types.BlockHeight
+	_, err := fmt.Sscan(req.FormValue("maxreorgdepth"), &depth)
+	if err != nil {
+		writeError(w, Error{"unable to parse maxreorgdepth: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	err = srv.cs.SetMaxReorgDepth(depth)
+	if err != nil {
+		writeError(w, Error{err.Error()}, http.StatusBadRequest)
+		return
+	}
+	writeSuccess(w)
+}
+
+// consensusSyncPauseHandler handles the API calls to /consensus/sync/pause.
+func (srv *Server) consensusSyncPauseHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	err := srv.cs.PauseSync()
+	if err != nil {
+		writeError(w, Error{err.Error()}, http.StatusBadRequest)
+		return
+	}
+	writeSuccess(w)
+}
+
+// consensusSyncResumeHandler handles the API calls to /consensus/sync/resume.
+func (srv *Server) consensusSyncResumeHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	err := srv.cs.ResumeSync()
+	if err != nil {
+		writeError(w, Error{err.Error()}, http.StatusBadRequest)
+		return
+	}
+	writeSuccess(w)
+}
+
+// consensusSnapshotHandlerGET handles the API call to GET /consensus/snapshot,
+// streaming a snapshot of the consensus database at the current block.
+func (srv *Server) consensusSnapshotHandlerGET(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	// A consensus database transaction is held open while the snapshot is
+	// written, so the export stops as soon as it is cancelled or the client
+	// goes away.
+	name := req.FormValue("name")
+	e, err := srv.startExport(name, w, req)
+	if err != nil {
+		writeError(w, Error{err.Error()}, http.StatusConflict)
+		return
+	}
+	defer srv.finishExport(name, e)
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	// Once streaming has started the status can no longer be changed, so an
+	// error is reported to the client as a truncated snapshot, which fails
+	// validation on import.
+	_ = srv.cs.WriteSnapshot(exportWriter{w: w, cancel: e.cancel})
+}
+
+// consensusSnapshotHandlerPOST handles the API call to POST
+// /consensus/snapshot, loading a snapshot into a node that has not yet
+// processed any blocks.
+func (srv *Server) consensusSnapshotHandlerPOST(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	if req.FormValue("trusted") != "true" {
+		writeError(w, Error{"loading a snapshot skips block validation; set trusted=true to acknowledge that the snapshot comes from a trusted source"}, http.StatusBadRequest)
+		return
+	}
+	source := req.FormValue("source")
+	if !filepath.IsAbs(source) {
+		writeError(w, Error{"source must be an absolute path"}, http.StatusBadRequest)
+		return
+	}
+	f, err := os.Open(source)
+	if err != nil {
+		writeError(w, Error{"unable to open snapshot: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	defer f.Close()
+
+	err = srv.cs.LoadSnapshot(f)
+	if err != nil {
+		writeError(w, Error{"unable to load snapshot: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	writeSuccess(w)
+}
+
+// consensusVerifyHandlerGET handles the API call to GET /consensus/verify,
+// reporting the progress of the most recent verification.
+func (srv *Server) consensusVerifyHandlerGET(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	v, ok := srv.cs.Verification()
+	if !ok {
+		writeError(w, Error{"the consensus set has not been verified"}, http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, v)
+}
+
+// consensusVerifyHandlerPOST handles the API call to POST /consensus/verify,
+// starting a verification of the consensus database.
+func (srv *Server) consensusVerifyHandlerPOST(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	err := srv.cs.StartVerification()
+	if err != nil {
+		writeError(w, Error{err.Error()}, http.StatusBadRequest)
+		return
+	}
+	writeSuccess(w)
+}
+
+// consensusVerifyCancelHandler handles the API call to
+// /consensus/verify/cancel.
+func (srv *Server) consensusVerifyCancelHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	err := srv.cs.CancelVerification()
+	if err != nil {
+		writeError(w, Error{err.Error()}, http.StatusBadRequest)
+		return
+	}
+	writeSuccess(w)
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"math/big"
 	"testing"
 
 	"github.com/NebulousLabs/Sia/types"
@@ -33,3 +34,77 @@ func TestIntegrationConsensusGET(t *testing.T) {
 		t.Error("wrong target returned in consensus GET call")
 	}
 }
+
+// TestConsensusSyncPause probes the POST calls to /consensus/sync/pause and
+// /consensus/sync/resume.
+func TestConsensusSyncPause(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	st, err := createServerTester("TestConsensusSyncPause")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.server.Close()
+
+	err = st.stdPostAPI("/consensus/sync/pause", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cg ConsensusGET
+	err = st.getAPI("/consensus", &cg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cg.SyncPaused {
+		t.Error("consensus should report that sync is paused")
+	}
+	if err = st.stdPostAPI("/consensus/sync/pause", nil); err == nil {
+		t.Error("pausing an already paused sync should fail")
+	}
+
+	err = st.stdPostAPI("/consensus/sync/resume", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = st.getAPI("/consensus", &cg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cg.SyncPaused {
+		t.Error("consensus should report that sync is not paused")
+	}
+}
+
+// TestConsensusNextDifficulty probes the GET call to
+// /consensus/nextdifficulty.
+func TestConsensusNextDifficulty(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	st, err := createServerTester("TestConsensusNextDifficulty")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.server.Close()
+
+	var cndg ConsensusNextDifficultyGET
+	err = st.getAPI("/consensus/nextdifficulty", &cndg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cndg.Height != 4 || cndg.AdjustmentHeight != types.TargetWindow/2 || cndg.BlocksUntilAdjustment != types.TargetWindow/2-4 {
+		t.Fatal("wrong adjustment height:", cndg)
+	}
+	if cndg.CurrentTarget != (types.Target{128}) || cndg.CurrentDifficulty.Cmp(cndg.CurrentTarget.Difficulty()) != 0 {
+		t.Fatal("wrong current target:", cndg.CurrentTarget)
+	}
+
+	// The projected adjustment must respect the clamps, allowing for the
+	// rounding of the projected target.
+	adjustment := new(big.Rat).Quo(cndg.ProjectedTarget.Rat(), cndg.CurrentTarget.Rat())
+	tolerance := big.NewRat(1, 1e9)
+	if adjustment.Cmp(new(big.Rat).Add(types.MaxAdjustmentUp, tolerance)) > 0 || adjustment.Cmp(new(big.Rat).Sub(types.MaxAdjustmentDown, tolerance)) < 0 {
+		t.Fatal("projected adjustment is outside of the clamps:", adjustment)
+	}
+}
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+var (
+	errExportCancelled = errors.New("export was cancelled")
+	errExportRunning   = errors.New("an export with that name is already running")
+	errUnknownExport   = errors.New("no export with that name is running")
+)
+
+// A runningExport is a long-running export, such as a consensus snapshot,
+// that can be cancelled while it is streamed to the client.
+type runningExport struct {
+	cancel   chan struct{}
+	finished chan struct{}
+	once     sync.Once
+}
+
+// stop cancels the export. It is safe to call more than once.
+func (e *runningExport) stop() {
+	e.once.Do(func() { close(e.cancel) })
+}
+
+// An exportWriter passes writes through to w until its export is cancelled,
+// after which every write fails. A write that is blocked on a stalled client
+// when the export is cancelled is interrupted by startExport.
+type exportWriter struct {
+	w      io.Writer
+	cancel <-chan struct{}
+}
+
+// Write implements io.Writer.
+func (ew exportWriter) Write(p []byte) (int, error) {
+	select {
+	case <-ew.cancel:
+		return 0, errExportCancelled
+	default:
+	}
+	return ew.w.Write(p)
+}
+
+// trackConn records the open connections of the API server. It is the
+// ConnState hook of the http.Server.
+func (srv *Server) trackConn(c net.Conn, state http.ConnState) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	switch state {
+	case http.StateNew:
+		if srv.conns == nil {
+			srv.conns = make(map[string]net.Conn)
+		}
+		srv.conns[c.RemoteAddr().String()] = c
+	case http.StateHijacked, http.StateClosed:
+		delete(srv.conns, c.RemoteAddr().String())
+	}
+}
+
+// startExport starts tracking an export of req streamed to w. If name is not
+// empty, the export can be cancelled with /consensus/export/cancel. The export
+// is also cancelled if the client disconnects. When the export is cancelled,
+// the write deadline of the connection is set to the past, so that a write
+// blocked on a stalled client fails instead of holding the export open.
+// finishExport must be called once the export has stopped.
+func (srv *Server) startExport(name string, w http.ResponseWriter, req *http.Request) (*runningExport, error) {
+	e := &runningExport{
+		cancel:   make(chan struct{}),
+		finished: make(chan struct{}),
+	}
+	srv.mu.Lock()
+	if name != "" {
+		if srv.exports == nil {
+			srv.exports = make(map[string]*runningExport)
+		}
+		if _, ok := srv.exports[name]; ok {
+			srv.mu.Unlock()
+			return nil, errExportRunning
+		}
+		srv.exports[name] = e
+	}
+	conn := srv.conns[req.RemoteAddr]
+	srv.mu.Unlock()
+
+	// closed is nil, and never ready, if w cannot report a disconnect.
+	var closed <-chan bool
+	if cn, ok := w.(http.CloseNotifier); ok {
+		closed = cn.CloseNotify()
+	}
+	go func() {
+		select {
+		case <-closed:
+			e.stop()
+		case <-e.cancel:
+			if conn != nil {
+				conn.SetWriteDeadline(time.Now())
+			}
+		case <-e.finished:
+		}
+	}()
+	return e, nil
+}
+
+// finishExport stops tracking an export started by startExport.
+func (srv *Server) finishExport(name string, e *runningExport) {
+	// release the goroutine waiting for the export to be cancelled
+	close(e.finished)
+	if name == "" {
+		return
+	}
+	srv.mu.Lock()
+	if srv.exports[name] == e {
+		delete(srv.exports, name)
+	}
+	srv.mu.Unlock()
+}
+
+// cancelExport cancels the running export with the given name.
+func (srv *Server) cancelExport(name string) error {
+	srv.mu.RLock()
+	e, ok := srv.exports[name]
+	srv.mu.RUnlock()
+	if !ok {
+		return errUnknownExport
+	}
+	e.stop()
+	return nil
+}
+
+// consensusExportCancelHandler handles the API call to cancel a named export.
+func (srv *Server) consensusExportCancelHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	name := req.FormValue("name")
+	if name == "" {
+		writeError(w, Error{"name is required"}, http.StatusBadRequest)
+		return
+	}
+	if err := srv.cancelExport(name); err != nil {
+		writeError(w, Error{err.Error()}, http.StatusBadRequest)
+		return
+	}
+	writeSuccess(w)
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"bytes"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// exportRequest returns a request for /consensus/snapshot.
+func exportRequest(t *testing.T) *http.Request {
+	req, err := http.NewRequest("GET", "http://localhost/consensus/snapshot", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return req
+}
+
+// TestExportCancel checks that a named export can be cancelled, which makes
+// further writes to the export fail.
+func TestExportCancel(t *testing.T) {
+	srv := new(Server)
+	var buf bytes.Buffer
+	e, err := srv.startExport("snap", httptest.NewRecorder(), exportRequest(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ew := exportWriter{w: &buf, cancel: e.cancel}
+	if _, err := ew.Write([]byte("foo")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := srv.startExport("snap", httptest.NewRecorder(), exportRequest(t)); err != errExportRunning {
+		t.Fatal("expected errExportRunning, got", err)
+	}
+
+	if err := srv.cancelExport("snap"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ew.Write([]byte("bar")); err != errExportCancelled {
+		t.Fatal("expected errExportCancelled, got", err)
+	}
+	if buf.String() != "foo" {
+		t.Fatal("unexpected export contents:", buf.String())
+	}
+
+	// once finished, the name can be reused
+	srv.finishExport("snap", e)
+	if err := srv.cancelExport("snap"); err != errUnknownExport {
+		t.Fatal("expected errUnknownExport, got", err)
+	}
+	e, err = srv.startExport("snap", httptest.NewRecorder(), exportRequest(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.finishExport("snap", e)
+
+	// unnamed exports can't be cancelled by name
+	e, err = srv.startExport("", httptest.NewRecorder(), exportRequest(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.finishExport("", e)
+	if len(srv.exports) != 0 {
+		t.Fatal("unnamed export was registered")
+	}
+}
+
+// TestExportCancelStalledClient checks that cancelling an export interrupts a
+// write that is blocked on a client that has stopped reading.
+func TestExportCancelStalledClient(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	srv := new(Server)
+	stopped := make(chan error)
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		e, err := srv.startExport("snap", w, req)
+		if err != nil {
+			stopped <- err
+			return
+		}
+		defer srv.finishExport("snap", e)
+		ew := exportWriter{w: w, cancel: e.cancel}
+		chunk := make([]byte, 1<<16)
+		for {
+			if _, err := ew.Write(chunk); err != nil {
+				stopped <- err
+				return
+			}
+		}
+	}))
+	ts.Config.ConnState = srv.trackConn
+	ts.Start()
+	defer ts.Close()
+
+	// request the export without ever reading the response
+	conn, err := net.Dial("tcp", ts.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte("GET / HTTP/1.1\r\nHost: localhost\r\n\r\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	// wait for the export to start and fill the connection's buffers, so
+	// that it is blocked in a write when it is cancelled
+	time.Sleep(500 * time.Millisecond)
+	if err := srv.cancelExport("snap"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-stopped:
+		if err == nil {
+			t.Fatal("export stopped without an error")
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("cancelled export is still blocked writing to the client")
+	}
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/NebulousLabs/Sia/crypto"
+	"github.com/NebulousLabs/Sia/types"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	// defaultTipInfoHeaders is the number of block IDs returned by
+	// /consensus/tipinfo when no number is given.
+	defaultTipInfoHeaders = 50
+
+	// maxTipInfoHeaders is the largest number of block IDs returned by
+	// /consensus/tipinfo.
+	maxTipInfoHeaders = 1000
+
+	// tipInfoAttempts is the number of times /consensus/tipinfo tries to
+	// read the chain before giving up because blocks keep arriving.
+	tipInfoAttempts = 3
+)
+
+var errTipChanged = errors.New("the current block changed while reading the chain; try again")
+
+// ConsensusTipInfoGET describes the current block and the blocks leading up
+// to it, in a form that can be compared between nodes. Headers[i] is the ID of
+// the block at height Height-i, so Headers[0] is the current block. StateRoot
+// is only set when it is requested.
+type ConsensusTipInfoGET struct {
+	Height    types.BlockHeight `json:"height"`
+	BlockID   types.BlockID     `json:"blockid"`
+	StateRoot *crypto.Hash      `json:"stateroot,omitempty"`
+	Headers   []types.BlockID   `json:"headers"`
+}
+
+// tipInfo reads the current block, the state root if withRoot is set, and the
+// IDs of the n most recent blocks. errTipChanged is returned if a block
+// arrives while they are being read, since they would then describe different
+// chains.
+func (srv *Server) tipInfo(n types.BlockHeight, withRoot bool) (ConsensusTipInfoGET, error) {
+	tip := srv.cs.CurrentBlock()
+	height := srv.cs.Height()
+	var stateRoot *crypto.Hash
+	if withRoot {
+		root, err := srv.cs.StateRoot()
+		if err != nil {
+			return ConsensusTipInfoGET{}, err
+		}
+		stateRoot = &root
+	}
+	if n > height+1 {
+		n = height + 1
+	}
+	headers := make([]types.BlockID, 0, n)
+	parent := tip.ID()
+	for h := height; types.BlockHeight(len(headers)) < n; h-- {
+		b, ok := srv.cs.BlockAtHeight(h)
+		if !ok || b.ID() != parent {
+			return ConsensusTipInfoGET{}, errTipChanged
+		}
+		headers = append(headers, parent)
+		parent = b.ParentID
+	}
+	if srv.cs.CurrentBlock().ID() != tip.ID() {
+		return ConsensusTipInfoGET{}, errTipChanged
+	}
+	return ConsensusTipInfoGET{
+		Height:    height,
+		BlockID:   tip.ID(),
+		StateRoot: stateRoot,
+		Headers:   headers,
+	}, nil
+}
+
+// consensusTipInfoHandler handles the API call to /consensus/tipinfo.
+func (srv *Server) consensusTipInfoHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	n := types.BlockHeight(defaultTipInfoHeaders)
+	if req.FormValue("headers") != "" {
+		if _, err := fmt.Sscan(req.FormValue("headers"), &n); err != nil {
+			writeError(w, Error{"unable to parse headers: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	if n > maxTipInfoHeaders {
+		writeError(w, Error{fmt.Sprintf("headers cannot be more than %v", maxTipInfoHeaders)}, http.StatusBadRequest)
+		return
+	}
+
+	var ti ConsensusTipInfoGET
+	var err error
+	for i := 0; i < tipInfoAttempts; i++ {
+		ti, err = srv.tipInfo(n, req.FormValue("stateroot") == "true")
+		if err != errTipChanged {
+			break
+		}
+	}
+	if err == errTipChanged {
+		writeError(w, Error{err.Error()}, http.StatusServiceUnavailable)
+		return
+	} else if err != nil {
+		writeError(w, Error{"unable to compute state root: " + err.Error()}, http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, ti)
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// TestConsensusTipInfo checks that /consensus/tipinfo reports the IDs of the
+// most recent blocks, newest first.
+func TestConsensusTipInfo(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	st, err := createServerTester("TestConsensusTipInfo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.server.Close()
+
+	var ti ConsensusTipInfoGET
+	if err := st.getAPI("/consensus/tipinfo?headers=3&stateroot=true", &ti); err != nil {
+		t.Fatal(err)
+	}
+	if ti.Height != st.cs.Height() || ti.BlockID != st.cs.CurrentBlock().ID() {
+		t.Fatalf("wrong tip: %+v", ti)
+	}
+	var cg ConsensusGET
+	if err := st.getAPI("/consensus?stateroot=true", &cg); err != nil {
+		t.Fatal(err)
+	}
+	if ti.StateRoot == nil || cg.StateRoot == nil || *ti.StateRoot != *cg.StateRoot {
+		t.Fatal("state root does not match /consensus")
+	}
+	if len(ti.Headers) != 3 {
+		t.Fatal("expected 3 headers, got", len(ti.Headers))
+	}
+	for i, id := range ti.Headers {
+		b, _ := st.cs.BlockAtHeight(ti.Height - types.BlockHeight(i))
+		if b.ID() != id {
+			t.Fatalf("header %v is not the block at height %v", i, ti.Height-types.BlockHeight(i))
+		}
+	}
+
+	// Asking for more headers than there are blocks returns the whole chain.
+	ti = ConsensusTipInfoGET{}
+	if err := st.getAPI("/consensus/tipinfo?headers=1000", &ti); err != nil {
+		t.Fatal(err)
+	}
+	if types.BlockHeight(len(ti.Headers)) != ti.Height+1 || ti.Headers[ti.Height] != types.GenesisID {
+		t.Fatalf("expected the chain back to the genesis block, got %v headers", len(ti.Headers))
+	}
+	// The state root is only computed when it is asked for.
+	if ti.StateRoot != nil {
+		t.Fatal("state root was returned without being requested")
+	}
+	if err := st.getAPI("/consensus/tipinfo?headers=1001", &ti); err == nil {
+		t.Fatal("expected an error for too many headers")
+	}
+}
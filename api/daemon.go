@@ -3,16 +3,24 @@ package api
 import (
 	"archive/zip"
 	"bytes"
+	"crypto"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"log"
 	"math/big"
 	"net/http"
+	"os"
 	"path"
 	"path/filepath"
 	"runtime"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/NebulousLabs/Sia/build"
 	"github.com/NebulousLabs/Sia/types"
@@ -22,7 +30,19 @@ import (
 	"github.com/kardianos/osext"
 )
 
-var errEmptyUpdateResponse = errors.New("API call to https://api.github.com/repos/NebulousLabs/Sia/releases/latest is returning an empty response")
+var (
+	errEmptyUpdateResponse = errors.New("API call to the releases endpoint is returning an empty response")
+	errUnknownRelease      = errors.New("no release exists with that version")
+	errNoReleaseTag        = errors.New("the release has no tag")
+	errInvalidVersion      = errors.New("version must be numbers separated by dots, such as 1.3.2")
+	errBadUpdateChannel    = errors.New("update channel must be 'stable' or 'beta'")
+	errChecksumMismatch    = errors.New("SHA256 checksum of the downloaded release does not match the published checksum")
+	errUpdateTooLarge      = errors.New("release archive is larger than the maximum update size")
+	errGithubCanceled      = errors.New("request to GitHub timed out or was canceled")
+	errBadUpdateTimeout    = errors.New("update check timeout must be positive")
+	errBadUpdateCacheTTL   = errors.New("update cache TTL cannot be negative")
+	errUpdateInProgress    = errors.New("an update is already in progress")
+)
 
 // SiaConstants is a struct listing all of the constants in use.
 type SiaConstants struct {
@@ -49,26 +69,223 @@ type SiaConstants struct {
 }
 
 type DaemonVersion struct {
+	Version     string `json:"version"`
+	GitRevision string `json:"gitrevision"`
+	BuildTime   string `json:"buildtime"`
+}
+
+// DaemonMemStatsGET contains memory and garbage collection statistics of the
+// daemon, taken from runtime.MemStats.
+type DaemonMemStatsGET struct {
+	HeapAlloc    uint64 `json:"heapalloc"`
+	HeapInuse    uint64 `json:"heapinuse"`
+	HeapSys      uint64 `json:"heapsys"`
+	Sys          uint64 `json:"sys"`
+	NumGC        uint32 `json:"numgc"`
+	PauseTotalNs uint64 `json:"pausetotalns"`
+	LastPauseNs  uint64 `json:"lastpausens"`
+	NumGoroutine int    `json:"numgoroutine"`
+	GoVersion    string `json:"goversion"`
+	GOMAXPROCS   int    `json:"gomaxprocs"`
+}
+
+// DaemonUptimeGET reports how long the daemon has been running. DataDir is
+// the directory that siad keeps its files in, and is empty if it is unknown.
+type DaemonUptimeGET struct {
+	StartTime     time.Time `json:"starttime"`
+	UptimeSeconds uint64    `json:"uptimeseconds"`
+	PID           int       `json:"pid"`
+	DataDir       string    `json:"datadir"`
+}
+
+// DaemonVersionCompareGET is the result of comparing two version strings.
+// Result is -1 if a is older than b, 0 if they are the same version, and 1 if
+// a is newer than b. Version is the version of the responding daemon.
+type DaemonVersionCompareGET struct {
+	Result  int    `json:"result"`
 	Version string `json:"version"`
 }
 
 // UpdateInfo indicates whether an update is available, and to what
-// version.
+// version. Channel is the update channel that was checked, and CheckedAt is
+// when GitHub was last asked for the latest release on it.
 type UpdateInfo struct {
-	Available bool   `json:"available"`
-	Version   string `json:"version"`
+	Available bool      `json:"available"`
+	Version   string    `json:"version"`
+	Channel   string    `json:"channel"`
+	CheckedAt time.Time `json:"checkedat"`
+}
+
+// cachedRelease is the latest release on an update channel, as returned by
+// GitHub at checkedAt.
+type cachedRelease struct {
+	channel   string
+	release   githubRelease
+	checkedAt time.Time
+}
+
+// DaemonUpdateUpToDatePOST is returned by /daemon/update [POST] instead of
+// installing a release that is not newer than the running version. Version is
+// the running version.
+type DaemonUpdateUpToDatePOST struct {
+	UpToDate bool   `json:"uptodate"`
+	Version  string `json:"version"`
+	Message  string `json:"message"`
+}
+
+// DaemonUpdateDryRunPOST is the result of a dry run of /daemon/update [POST].
+// Verified is true if every binary in the release was found and matches its
+// signature.
+type DaemonUpdateDryRunPOST struct {
+	Version  string              `json:"version"`
+	Verified bool                `json:"verified"`
+	Binaries []UpdateBinaryCheck `json:"binaries"`
+}
+
+// UpdateBinaryCheck is the result of checking one binary of a release during
+// a dry run. File and Signature are the names of the binary and signature
+// found in the release archive, and are empty if they were not found. Error
+// explains why the binary could not be verified.
+type UpdateBinaryCheck struct {
+	Binary    string `json:"binary"`
+	File      string `json:"file"`
+	Signature string `json:"signature"`
+	Verified  bool   `json:"verified"`
+	Error     string `json:"error,omitempty"`
+}
+
+// DaemonUpdateProgressGET reports the progress of an update started by
+// /daemon/update [POST]. Phase is "downloading", "verifying", or "applying",
+// and is empty when no update is in progress. Total is the size of the
+// release archive in bytes, and is zero if GitHub did not report it.
+type DaemonUpdateProgressGET struct {
+	Downloaded uint64 `json:"downloaded"`
+	Total      uint64 `json:"total"`
+	Phase      string `json:"phase"`
+}
+
+// updateProgress tracks the progress of the update being applied by
+// updateToRelease.
+type updateProgress struct {
+	downloaded uint64
+	total      uint64
+	phase      string
+	running    bool
+	mu         sync.Mutex
+}
+
+// start claims the update for the caller. It returns false if another update
+// is already running.
+func (up *updateProgress) start() bool {
+	up.mu.Lock()
+	defer up.mu.Unlock()
+	if up.running {
+		return false
+	}
+	up.running = true
+	return true
+}
+
+// finish releases the update claimed by start.
+func (up *updateProgress) finish() {
+	up.mu.Lock()
+	up.running = false
+	up.mu.Unlock()
+}
+
+// setPhase moves the update to a new phase.
+func (up *updateProgress) setPhase(phase string) {
+	up.mu.Lock()
+	up.phase = phase
+	up.mu.Unlock()
+}
+
+// status returns the current progress of the update.
+func (up *updateProgress) status() DaemonUpdateProgressGET {
+	up.mu.Lock()
+	defer up.mu.Unlock()
+	return DaemonUpdateProgressGET{
+		Downloaded: up.downloaded,
+		Total:      up.total,
+		Phase:      up.phase,
+	}
+}
+
+// progressReader counts the bytes read from a download towards the progress
+// of the update.
+type progressReader struct {
+	r        io.Reader
+	progress *updateProgress
+}
+
+// Read implements io.Reader.
+func (pr progressReader) Read(b []byte) (int, error) {
+	n, err := pr.r.Read(b)
+	pr.progress.mu.Lock()
+	pr.progress.downloaded += uint64(n)
+	pr.progress.mu.Unlock()
+	return n, err
 }
 
 // githubRelease represents some of the JSON returned by the GitHub release API
 // endpoint. Only the fields relevant to updating are included.
 type githubRelease struct {
-	TagName string `json:"tag_name"`
-	Assets  []struct {
+	TagName    string `json:"tag_name"`
+	Prerelease bool   `json:"prerelease"`
+	Assets     []struct {
 		Name        string `json:"name"`
 		DownloadURL string `json:"browser_download_url"`
 	} `json:"assets"`
 }
 
+var (
+	// maxUpdateSize is the largest release archive that will be downloaded.
+	// The archive is held in memory while it is verified.
+	maxUpdateSize int64 = 1 << 28 // 256 MiB
+
+	// updateClient downloads release archives and their checksums. Unlike
+	// http.DefaultClient, it gives up on a stalled mirror, so that an update
+	// cannot hang while holding the update slot.
+	updateClient = &http.Client{Timeout: updateDownloadTimeout}
+)
+
+const (
+	// defaultUpdateCheckTimeout is how long a request to the GitHub API may
+	// take before the update check is abandoned.
+	defaultUpdateCheckTimeout = 30 * time.Second
+
+	// defaultUpdateCacheTTL is how long the latest release found by an update
+	// check is reused before GitHub is asked again.
+	defaultUpdateCacheTTL = 10 * time.Minute
+
+	// updateCheckAttempts is the number of times fetching the latest release
+	// is attempted by an update check.
+	updateCheckAttempts = 3
+
+	// updateDownloadAttempts is the number of times downloading a release
+	// archive is attempted before the update is abandoned.
+	updateDownloadAttempts = 3
+
+	// updateRetryDelay is how long to wait before retrying a failed download.
+	// The delay doubles after each failed attempt.
+	updateRetryDelay = time.Second
+
+	// updateDownloadTimeout is how long a single attempt at downloading a
+	// release archive may take. If the server accepts byte ranges, the next
+	// attempt resumes where a timed out attempt stopped, so a slow download
+	// can still finish.
+	updateDownloadTimeout = 10 * time.Minute
+)
+
+const (
+	// UpdateChannelStable is the update channel that only tracks full
+	// releases.
+	UpdateChannelStable = "stable"
+
+	// UpdateChannelBeta is the update channel that also tracks prereleases.
+	UpdateChannelBeta = "beta"
+)
+
 const (
 	// The developer key is used to sign updates and other important Sia-
 	// related information.
@@ -99,149 +316,640 @@ bwIDAQAB
 -----END PUBLIC KEY-----`
 )
 
-// fetchLatestRelease returns metadata about the most recent GitHub release.
-func fetchLatestRelease() (githubRelease, error) {
-	req, err := http.NewRequest("GET", "https://api.github.com/repos/NebulousLabs/Sia/releases/latest", nil)
+// updateCheckCancel returns a channel that is closed once the update check
+// timeout has passed or the client of the request disconnects, whichever
+// comes first. The returned function must be called when the check is done.
+func (srv *Server) updateCheckCancel(w http.ResponseWriter) (<-chan struct{}, func()) {
+	srv.mu.RLock()
+	timeout := srv.updateCheckTimeout
+	srv.mu.RUnlock()
+	var closed <-chan bool
+	if cn, ok := w.(http.CloseNotifier); ok {
+		closed = cn.CloseNotify()
+	}
+
+	cancel := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		t := time.NewTimer(timeout)
+		defer t.Stop()
+		select {
+		case <-t.C:
+		case <-closed:
+		case <-done:
+		}
+		close(cancel)
+	}()
+	return cancel, func() { close(done) }
+}
+
+// fetchGithub decodes the JSON returned by the GitHub API URL into v. The
+// request is abandoned when cancel is closed.
+func fetchGithub(url string, v interface{}, cancel <-chan struct{}) error {
+	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		return githubRelease{}, err
+		return err
 	}
 	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	req.Cancel = cancel
 	resp, err := http.DefaultClient.Do(req)
+	if err == nil {
+		defer resp.Body.Close()
+		if resp.StatusCode == http.StatusNotFound {
+			return errUnknownRelease
+		}
+		err = json.NewDecoder(resp.Body).Decode(v)
+	}
 	if err != nil {
-		return githubRelease{}, err
+		// The transport reports cancellation with an unexported error, so
+		// check the channel directly.
+		select {
+		case <-cancel:
+			return errGithubCanceled
+		default:
+		}
 	}
-	defer resp.Body.Close()
+	return err
+}
+
+// fetchGithubRelease returns metadata about the GitHub release at the given
+// API URL.
+func fetchGithubRelease(url string, cancel <-chan struct{}) (githubRelease, error) {
 	var release githubRelease
-	err = json.NewDecoder(resp.Body).Decode(&release)
+	err := fetchGithub(url, &release, cancel)
 	if err != nil {
 		return githubRelease{}, err
 	}
 	if release.TagName == "" && len(release.Assets) == 0 {
 		return githubRelease{}, errEmptyUpdateResponse
+	} else if release.TagName == "" {
+		return githubRelease{}, errNoReleaseTag
 	}
 	return release, nil
 }
 
-// updateToRelease updates siad and siac to the release specified. siac is
-// assumed to be in the same folder as siad.
-func updateToRelease(release githubRelease) error {
-	updateOpts := update.Options{
-		Verifier: update.NewRSAVerifier(),
+// fetchLatestRelease returns metadata about the most recent release of the
+// source on the given update channel. The stable channel skips prereleases,
+// while the beta channel takes whichever release is newest. The requests are
+// abandoned when cancel is closed.
+func (src updateSource) fetchLatestRelease(channel string, cancel <-chan struct{}) (githubRelease, error) {
+	if channel != UpdateChannelBeta {
+		return fetchGithubRelease(src.baseURL+"/releases/latest", cancel)
 	}
-	err := updateOpts.SetPublicKeyPEM([]byte(developerKey))
+	var releases []githubRelease
+	err := fetchGithub(src.baseURL+"/releases", &releases, cancel)
 	if err != nil {
-		// should never happen
-		return err
+		return githubRelease{}, err
+	}
+	// GitHub lists releases from newest to oldest.
+	if len(releases) == 0 || releases[0].TagName == "" {
+		return githubRelease{}, errEmptyUpdateResponse
 	}
+	return releases[0], nil
+}
 
-	binaryFolder, err := osext.ExecutableFolder()
+// fetchRelease returns metadata about the release of the source with the
+// given tag, such as "v1.3.2". The request is abandoned when cancel is closed.
+func (src updateSource) fetchRelease(tag string, cancel <-chan struct{}) (githubRelease, error) {
+	return fetchGithubRelease(src.baseURL+"/releases/tags/"+tag, cancel)
+}
+
+// fetchReleaseChecksums downloads the SHA256SUMS file of the release. A nil
+// slice is returned if the release does not publish one.
+func (src updateSource) fetchReleaseChecksums(release githubRelease) ([]byte, error) {
+	sumsName := assetName(src.checksumsName, release.TagName)
+	var sumsURL string
+	for _, asset := range release.Assets {
+		if asset.Name == sumsName {
+			sumsURL = asset.DownloadURL
+			break
+		}
+	}
+	if sumsURL == "" {
+		return nil, nil
+	}
+	resp, err := updateClient.Get(sumsURL)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not download %v: %v", sumsName, resp.Status)
 	}
+	// the file has one short line per release archive
+	return ioutil.ReadAll(io.LimitReader(resp.Body, 1<<16))
+}
 
-	// construct release filename
-	releaseName := fmt.Sprintf("Sia-%s-%s-%s.zip", release.TagName, runtime.GOOS, runtime.GOARCH)
+// verifyChecksum checks the SHA256 of a downloaded release archive against
+// its entry in a SHA256SUMS file, which has the format written by sha256sum:
+// a hex checksum, whitespace, and the filename, optionally prefixed by '*'.
+func verifyChecksum(content []byte, sums []byte, releaseName string) error {
+	for _, line := range strings.Split(string(sums), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || strings.TrimPrefix(fields[1], "*") != releaseName {
+			continue
+		}
+		expected, err := hex.DecodeString(fields[0])
+		if err != nil || len(expected) != sha256.Size {
+			return errors.New("invalid checksum listed for " + releaseName)
+		}
+		actual := sha256.Sum256(content)
+		if !bytes.Equal(actual[:], expected) {
+			return fmt.Errorf("%v: expected %x, got %x", errChecksumMismatch, expected, actual)
+		}
+		return nil
+	}
+	return errors.New("no checksum listed for " + releaseName)
+}
 
-	// find release
-	var downloadURL string
+// releaseArchive returns the name and download URL of the archive of the
+// release that was built for this platform.
+func (src updateSource) releaseArchive(release githubRelease) (name, url string, err error) {
+	name = assetName(src.archiveName, release.TagName)
 	for _, asset := range release.Assets {
-		if asset.Name == releaseName {
-			downloadURL = asset.DownloadURL
-			break
+		if asset.Name == name {
+			return name, asset.DownloadURL, nil
+		}
+	}
+	return "", "", errors.New("couldn't find download URL for " + name)
+}
+
+// downloadRelease downloads the release archive at url, making up to
+// updateDownloadAttempts attempts with exponential backoff between them. If
+// the server accepts byte ranges, a failed attempt is resumed from the bytes
+// already received instead of starting over. The error of the last attempt is
+// returned if every attempt fails.
+func downloadRelease(url string, progress *updateProgress) ([]byte, error) {
+	progress.mu.Lock()
+	progress.downloaded = 0
+	progress.total = 0
+	progress.phase = "downloading"
+	progress.mu.Unlock()
+
+	var content []byte
+	var resumable bool
+	var err error
+	for attempt := 0; attempt < updateDownloadAttempts; attempt++ {
+		if attempt > 0 {
+			time.Sleep(updateRetryDelay << uint(attempt-1))
+		}
+		if !resumable {
+			content = nil
+		}
+		content, resumable, err = resumeDownload(url, content, progress)
+		if err == nil {
+			return content, nil
+		} else if err == errUpdateTooLarge {
+			// retrying won't make the archive any smaller
+			return nil, err
+		}
+	}
+	return nil, err
+}
+
+// resumeDownload makes a single attempt at downloading url, requesting only
+// the bytes after 'content' if any have already been received. It returns the
+// bytes received so far, and whether the server accepts byte ranges so that a
+// failed attempt can be resumed.
+func resumeDownload(url string, content []byte, progress *updateProgress) ([]byte, bool, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return content, false, err
+	}
+	if len(content) > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", len(content)))
+	}
+	resp, err := updateClient.Do(req)
+	if err != nil {
+		return content, len(content) > 0, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && len(content) > 0:
+	case resp.StatusCode == http.StatusOK:
+		// the server sent the whole archive, so start over
+		content = nil
+		progress.mu.Lock()
+		progress.downloaded = 0
+		progress.total = 0
+		if resp.ContentLength > 0 {
+			progress.total = uint64(resp.ContentLength)
+		}
+		progress.mu.Unlock()
+	default:
+		return nil, false, errors.New("couldn't download release: " + resp.Status)
+	}
+	resumable := resp.Header.Get("Accept-Ranges") == "bytes"
+
+	// The archive is held in memory, so its size is limited. If the server
+	// reports the size, an archive that is too large is rejected before it is
+	// downloaded. Otherwise, one byte more than the limit is read so that an
+	// archive that is too large is detected instead of being truncated.
+	limit := maxUpdateSize
+	if resp.ContentLength >= 0 {
+		size := int64(len(content)) + resp.ContentLength
+		if size > maxUpdateSize {
+			return nil, false, errUpdateTooLarge
 		}
+		limit = size
+	}
+	buf := bytes.NewBuffer(content)
+	_, err = buf.ReadFrom(progressReader{io.LimitReader(resp.Body, limit-int64(len(content))+1), progress})
+	if int64(buf.Len()) > limit {
+		return nil, false, errUpdateTooLarge
+	}
+	return buf.Bytes(), resumable, err
+}
+
+// updateToRelease updates siad and siac to the release specified. siac is
+// assumed to be in the same folder as siad. The progress of the update is
+// reported to 'progress'. If dryRun is true, the binaries are only checked
+// against their signatures and platform and nothing is written to disk; the
+// result of checking each binary is returned. A binary is accepted if its
+// signature matches any of keys and it was built for this platform.
+func updateToRelease(src updateSource, release githubRelease, progress *updateProgress, dryRun bool, keys []crypto.PublicKey) ([]UpdateBinaryCheck, error) {
+	updateOpts := update.Options{
+		Hash:     crypto.SHA256,
+		Verifier: update.NewRSAVerifier(),
+	}
+
+	binaryFolder, err := osext.ExecutableFolder()
+	if err != nil {
+		return nil, err
 	}
-	if downloadURL == "" {
-		return errors.New("couldn't find download URL for " + releaseName)
+
+	releaseName, downloadURL, err := src.releaseArchive(release)
+	if err != nil {
+		return nil, err
 	}
 
 	// download release archive
-	resp, err := http.Get(downloadURL)
+	content, err := downloadRelease(downloadURL, progress)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	// release should be small enough to store in memory (<10 MiB); use
-	// LimitReader to ensure we don't download more than 32 MiB
-	content, err := ioutil.ReadAll(io.LimitReader(resp.Body, 1<<25))
-	resp.Body.Close()
+	progress.setPhase("verifying")
+
+	// verify the archive against the published checksum before trusting its
+	// contents; older releases do not publish one
+	sums, err := src.fetchReleaseChecksums(release)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if sums != nil {
+		if err := verifyChecksum(content, sums, releaseName); err != nil {
+			return nil, err
+		}
+	} else if build.DEBUG {
+		log.Printf("WARN: release %v has no SHA256SUMS file; skipping checksum verification", release.TagName)
 	}
+
 	r := bytes.NewReader(content)
 	z, err := zip.NewReader(r, r.Size())
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// process zip, finding siad/siac binaries and signatures
+	// process zip, finding siad/siac binaries and signatures. Every binary is
+	// verified before any of them is applied, so that a bad archive leaves
+	// the installed binaries untouched.
+	var checks []UpdateBinaryCheck
+	var verified []verifiedBinary
 	for _, binary := range []string{"siad", "siac"} {
 		var binData io.ReadCloser
 		var signature []byte
 		var binaryName string // needed for TargetPath below
+		var signatureName string
 		for _, zf := range z.File {
 			switch base := path.Base(zf.Name); base {
 			case binary, binary + ".exe":
 				binaryName = base
 				binData, err = zf.Open()
 				if err != nil {
-					return err
+					return nil, err
 				}
 				defer binData.Close()
 			case binary + ".sig", binary + ".exe.sig":
+				signatureName = base
 				sigFile, err := zf.Open()
 				if err != nil {
-					return err
+					return nil, err
 				}
 				defer sigFile.Close()
 				signature, err = ioutil.ReadAll(sigFile)
 				if err != nil {
-					return err
+					return nil, err
 				}
 			}
 		}
+		if dryRun {
+			checks = append(checks, checkBinary(updateOpts, keys, binary, binaryName, binData, signatureName, signature))
+			continue
+		}
 		if binData == nil {
-			return errors.New("could not find " + binary + " binary")
+			return nil, errors.New("could not find " + binary + " binary")
 		} else if signature == nil {
-			return errors.New("could not find " + binary + " signature")
+			return nil, errors.New("could not find " + binary + " signature")
 		}
 
-		// apply update
-		updateOpts.Signature = signature
-		updateOpts.TargetMode = 0775 // executable
-		updateOpts.TargetPath = filepath.Join(binaryFolder, binaryName)
-		err = update.Apply(binData, updateOpts)
+		// find the trusted key that signed the binary
+		bin, err := ioutil.ReadAll(binData)
 		if err != nil {
-			return err
+			return nil, err
+		}
+		checksum := sha256.Sum256(bin)
+		key, err := findSigningKey(updateOpts, keys, checksum[:], signature)
+		if err != nil {
+			return nil, errors.New(binary + ": " + err.Error())
+		}
+
+		// a mislabeled archive could hold binaries that cannot run here
+		if err := checkExecutablePlatform(bin, runtime.GOOS, runtime.GOARCH); err != nil {
+			return nil, errors.New(binary + ": " + err.Error())
+		}
+		verified = append(verified, verifiedBinary{
+			name:      binaryName,
+			data:      bin,
+			key:       key,
+			signature: signature,
+		})
+	}
+	if dryRun {
+		return checks, nil
+	}
+
+	progress.setPhase("applying")
+	return nil, applyBinaries(updateOpts, binaryFolder, verified)
+}
+
+// A verifiedBinary is a binary from a release archive whose signature and
+// platform have been checked.
+type verifiedBinary struct {
+	name      string
+	data      []byte
+	key       crypto.PublicKey
+	signature []byte
+}
+
+// applyBinaries replaces the binaries in folder with bins. If a binary cannot
+// be replaced, the binaries that were already replaced are restored, so that
+// siad and siac are never left from different releases. The signature of each
+// binary is checked again before it is replaced.
+func applyBinaries(opts update.Options, folder string, bins []verifiedBinary) error {
+	for i, vb := range bins {
+		target := filepath.Join(folder, vb.name)
+		opts.PublicKey = vb.key
+		opts.Signature = vb.signature
+		opts.TargetMode = 0775 // executable
+		opts.TargetPath = target
+		opts.OldSavePath = target + ".old"
+		err := update.Apply(bytes.NewReader(vb.data), opts)
+		if err == nil {
+			continue
+		}
+		for _, applied := range bins[:i] {
+			appliedPath := filepath.Join(folder, applied.name)
+			if rerr := os.Rename(appliedPath+".old", appliedPath); rerr != nil {
+				return fmt.Errorf("%v; could not restore %v: %v", err, applied.name, rerr)
+			}
 		}
+		return err
+	}
+	for _, vb := range bins {
+		// the running binary cannot be removed on Windows; update.Apply
+		// replaces the leftover file on the next update
+		os.Remove(filepath.Join(folder, vb.name) + ".old")
+	}
+	return nil
+}
+
+// checkBinary checks a binary from a release archive against its signature
+// without installing it, the same way update.Apply does before replacing the
+// running binary. The signature may be made by any of keys. The binary must
+// also be an executable for this platform.
+func checkBinary(opts update.Options, keys []crypto.PublicKey, binary, binaryName string, binData io.Reader, signatureName string, signature []byte) UpdateBinaryCheck {
+	check := UpdateBinaryCheck{
+		Binary:    binary,
+		File:      binaryName,
+		Signature: signatureName,
 	}
+	if binData == nil {
+		check.Error = "could not find " + binary + " binary"
+		return check
+	} else if signature == nil {
+		check.Error = "could not find " + binary + " signature"
+		return check
+	}
+	bin, err := ioutil.ReadAll(binData)
+	if err != nil {
+		check.Error = err.Error()
+		return check
+	}
+	checksum := sha256.Sum256(bin)
+	_, err = findSigningKey(opts, keys, checksum[:], signature)
+	if err != nil {
+		check.Error = "signature verification failed: " + err.Error()
+		return check
+	}
+	if err := checkExecutablePlatform(bin, runtime.GOOS, runtime.GOARCH); err != nil {
+		check.Error = "wrong platform: " + err.Error()
+		return check
+	}
+	check.Verified = true
+	return check
+}
+
+// UpdateChannel returns the update channel that /daemon/update checks.
+func (srv *Server) UpdateChannel() string {
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	return srv.updateChannel
+}
 
+// SetUpdateChannel sets the update channel that /daemon/update checks.
+func (srv *Server) SetUpdateChannel(channel string) error {
+	if channel != UpdateChannelStable && channel != UpdateChannelBeta {
+		return errBadUpdateChannel
+	}
+	srv.mu.Lock()
+	srv.updateChannel = channel
+	srv.mu.Unlock()
 	return nil
 }
 
-// daemonUpdateHandlerGET handles the API call that checks for an update.
-func (srv *Server) daemonUpdateHandlerGET(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
-	release, err := fetchLatestRelease()
+// daemonUpdateHandlerGET handles the API call that checks for an update. The
+// result of the last check is reused until the update cache TTL has passed,
+// unless 'force' is set.
+func (srv *Server) daemonUpdateHandlerGET(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	channel := srv.UpdateChannel()
+	cancel, done := srv.updateCheckCancel(w)
+	release, checkedAt, err := srv.latestRelease(channel, req.FormValue("force") == "true", cancel)
+	done()
 	if err != nil {
 		writeError(w, Error{"Failed to fetch latest release: " + err.Error()}, http.StatusInternalServerError)
 		return
 	}
-	latestVersion := release.TagName[1:] // delete leading 'v'
+	latestVersion := strings.TrimPrefix(release.TagName, "v")
+	available := build.VersionCmp(latestVersion, build.Version) > 0
+	if available {
+		srv.registerAlert(alertUpdateAvailable, AlertSeverityInfo, "daemon", "version "+latestVersion+" is available on the "+channel+" channel")
+	} else {
+		srv.unregisterAlert(alertUpdateAvailable)
+	}
 	writeJSON(w, UpdateInfo{
-		Available: build.VersionCmp(latestVersion, build.Version) > 0,
+		Available: available,
 		Version:   latestVersion,
+		Channel:   channel,
+		CheckedAt: checkedAt,
 	})
 }
 
+// SetUpdateCacheTTL sets how long the result of an update check is reused. A
+// TTL of zero disables the cache.
+func (srv *Server) SetUpdateCacheTTL(ttl time.Duration) error {
+	if ttl < 0 {
+		return errBadUpdateCacheTTL
+	}
+	srv.mu.Lock()
+	srv.updateCacheTTL = ttl
+	srv.mu.Unlock()
+	return nil
+}
+
+// latestRelease returns the latest release on the update channel and when it
+// was fetched from GitHub. A release fetched within the update cache TTL is
+// reused unless force is set. Failed fetches are retried with the same delays
+// as release downloads, until cancel is closed.
+func (srv *Server) latestRelease(channel string, force bool, cancel <-chan struct{}) (githubRelease, time.Time, error) {
+	src := srv.releaseSource()
+	srv.mu.RLock()
+	cached := srv.updateCache
+	ttl := srv.updateCacheTTL
+	srv.mu.RUnlock()
+	if !force && cached != nil && cached.channel == channel && time.Since(cached.checkedAt) < ttl {
+		return cached.release, cached.checkedAt, nil
+	}
+
+	var release githubRelease
+	var err error
+	for attempt := 0; attempt < updateCheckAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(updateRetryDelay << uint(attempt-1)):
+			case <-cancel:
+				return githubRelease{}, time.Time{}, errGithubCanceled
+			}
+		}
+		release, err = src.fetchLatestRelease(channel, cancel)
+		if err == nil || err == errGithubCanceled {
+			break
+		}
+	}
+	if err != nil {
+		return githubRelease{}, time.Time{}, err
+	}
+	checkedAt := time.Now()
+	srv.mu.Lock()
+	srv.updateCache = &cachedRelease{
+		channel:   channel,
+		release:   release,
+		checkedAt: checkedAt,
+	}
+	srv.mu.Unlock()
+	return release, checkedAt, nil
+}
+
+// SetUpdateCheckTimeout sets how long a request to GitHub may take while
+// checking for an update.
+func (srv *Server) SetUpdateCheckTimeout(timeout time.Duration) error {
+	if timeout <= 0 {
+		return errBadUpdateTimeout
+	}
+	srv.mu.Lock()
+	srv.updateCheckTimeout = timeout
+	srv.mu.Unlock()
+	return nil
+}
+
+// daemonUpdateChannelHandlerPOST handles the API call that changes the update
+// channel. Moving from beta back to stable may leave the daemon newer than the
+// latest stable release, which can then only be installed with
+// 'allowdowngrade'.
+func (srv *Server) daemonUpdateChannelHandlerPOST(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	err := srv.SetUpdateChannel(req.FormValue("channel"))
+	if err != nil {
+		writeError(w, Error{err.Error()}, http.StatusBadRequest)
+		return
+	}
+	writeSuccess(w)
+}
+
 // daemonUpdateHandlerPOST handles the API call that updates siad and siac.
-// There is no safeguard to prevent "updating" to the same release, so callers
-// should always check the latest version via daemonUpdateHandlerGET first.
-// TODO: add support for specifying version to update to.
-func (srv *Server) daemonUpdateHandlerPOST(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
-	release, err := fetchLatestRelease()
+// The latest release on the update channel is installed unless a version is
+// specified. If the release is the running version, or the latest release is
+// older than it, nothing is installed and DaemonUpdateUpToDatePOST is
+// returned. Updating to an older release is refused unless 'allowdowngrade'
+// is set.
+func (srv *Server) daemonUpdateHandlerPOST(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	// Two updates writing over the same binaries would corrupt them, so only
+	// one runs at a time.
+	if !srv.updateProgress.start() {
+		writeError(w, Error{errUpdateInProgress.Error()}, http.StatusConflict)
+		return
+	}
+	defer srv.updateProgress.finish()
+
+	var release githubRelease
+	var err error
+	src := srv.releaseSource()
+	cancel, done := srv.updateCheckCancel(w)
+	version := req.FormValue("version")
+	if version != "" {
+		// The version becomes part of the URL of the release, so anything
+		// other than a plain version number is refused.
+		if !build.IsVersion(strings.TrimPrefix(version, "v")) {
+			done()
+			writeError(w, Error{"Failed to fetch release " + version + ": " + errInvalidVersion.Error()}, http.StatusBadRequest)
+			return
+		}
+		version = "v" + strings.TrimPrefix(version, "v")
+		release, err = src.fetchRelease(version, cancel)
+		if err == errUnknownRelease {
+			done()
+			writeError(w, Error{"Failed to fetch release " + version + ": " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	} else {
+		release, err = src.fetchLatestRelease(srv.UpdateChannel(), cancel)
+	}
+	done()
 	if err != nil {
-		writeError(w, Error{"Failed to fetch latest release: " + err.Error()}, http.StatusInternalServerError)
+		writeError(w, Error{"Failed to fetch release: " + err.Error()}, http.StatusInternalServerError)
+		return
+	}
+	dryRun := req.FormValue("dryrun") == "true"
+	allowDowngrade := req.FormValue("allowdowngrade") == "true"
+	cmp := build.VersionCmp(strings.TrimPrefix(release.TagName, "v"), build.Version)
+	if !dryRun && (cmp == 0 || (cmp < 0 && version == "" && !allowDowngrade)) {
+		writeJSON(w, DaemonUpdateUpToDatePOST{
+			UpToDate: true,
+			Version:  build.Version,
+			Message:  "already up to date; " + release.TagName + " is not newer than the running version",
+		})
 		return
 	}
-	err = updateToRelease(release)
+	if cmp < 0 && !allowDowngrade {
+		writeError(w, Error{"Refusing to downgrade from " + build.Version + " to " + release.TagName + " without allowdowngrade"}, http.StatusBadRequest)
+		return
+	}
+	keys, err := srv.trustedUpdateKeys()
+	if err != nil {
+		writeError(w, Error{"Failed to load update keys: " + err.Error()}, http.StatusInternalServerError)
+		return
+	}
+	checks, err := updateToRelease(src, release, &srv.updateProgress, dryRun, keys)
+	srv.updateProgress.setPhase("")
 	if err != nil {
 		if rerr := update.RollbackError(err); rerr != nil {
 			writeError(w, Error{"Serious error: Failed to rollback from bad update: " + rerr.Error()}, http.StatusInternalServerError)
@@ -250,11 +958,40 @@ func (srv *Server) daemonUpdateHandlerPOST(w http.ResponseWriter, _ *http.Reques
 		}
 		return
 	}
+	if dryRun {
+		dr := DaemonUpdateDryRunPOST{
+			Version:  release.TagName,
+			Verified: true,
+			Binaries: checks,
+		}
+		for _, check := range checks {
+			dr.Verified = dr.Verified && check.Verified
+		}
+		writeJSON(w, dr)
+		return
+	}
+	err = srv.recordUpdate(UpdateHistoryEntry{
+		Timestamp:   time.Now(),
+		FromVersion: build.Version,
+		ToVersion:   strings.TrimPrefix(release.TagName, "v"),
+	})
+	if err != nil {
+		// the update itself succeeded, so don't report a failure
+		log.Println("WARN: could not save the update history:", err)
+	}
+	srv.unregisterAlert(alertUpdateAvailable)
+	srv.registerAlert(alertUpdateInstalled, AlertSeverityWarning, "daemon", release.TagName+" has been installed; restart siad to run it")
 	writeSuccess(w)
 }
 
-// debugConstantsHandler prints a json file containing all of the constants.
-func (srv *Server) daemonConstantsHandler(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+// daemonUpdateProgressHandlerGET handles the API call that reports the
+// progress of an update.
+func (srv *Server) daemonUpdateProgressHandlerGET(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	writeJSON(w, srv.updateProgress.status())
+}
+
+// daemonConstantsHandler prints a json file containing all of the constants.
+func (srv *Server) daemonConstantsHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 	sc := SiaConstants{
 		GenesisTimestamp:      types.GenesisTimestamp,
 		BlockSizeLimit:        types.BlockSizeLimit,
@@ -278,17 +1015,85 @@ func (srv *Server) daemonConstantsHandler(w http.ResponseWriter, _ *http.Request
 		SiacoinPrecision: types.SiacoinPrecision,
 	}
 
-	writeJSON(w, sc)
+	writeConstants(w, req, sc)
+}
+
+// daemonMemStatsHandler handles the API call that returns memory and garbage
+// collection statistics of the daemon.
+func (srv *Server) daemonMemStatsHandler(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	writeJSON(w, DaemonMemStatsGET{
+		HeapAlloc:    ms.HeapAlloc,
+		HeapInuse:    ms.HeapInuse,
+		HeapSys:      ms.HeapSys,
+		Sys:          ms.Sys,
+		NumGC:        ms.NumGC,
+		PauseTotalNs: ms.PauseTotalNs,
+		// PauseNs is a circular buffer of recent pauses
+		LastPauseNs:  ms.PauseNs[(ms.NumGC+255)%256],
+		NumGoroutine: runtime.NumGoroutine(),
+		GoVersion:    runtime.Version(),
+		GOMAXPROCS:   runtime.GOMAXPROCS(0),
+	})
+}
+
+// daemonUptimeHandler handles the API call that returns how long the daemon
+// has been running.
+func (srv *Server) daemonUptimeHandler(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	srv.mu.RLock()
+	dataDir := srv.persistDir
+	srv.mu.RUnlock()
+	writeJSON(w, DaemonUptimeGET{
+		StartTime:     srv.startTime,
+		UptimeSeconds: uint64(time.Since(srv.startTime).Seconds()),
+		PID:           os.Getpid(),
+		DataDir:       dataDir,
+	})
 }
 
 // daemonVersionHandler handles the API call that requests the daemon's version.
 func (srv *Server) daemonVersionHandler(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
-	writeJSON(w, DaemonVersion{Version: build.Version})
+	writeJSON(w, DaemonVersion{
+		Version:     build.Version,
+		GitRevision: build.GitRevision,
+		BuildTime:   build.BuildTime,
+	})
+}
+
+// daemonVersionCompareHandler handles the API call that compares two version
+// strings.
+func (srv *Server) daemonVersionCompareHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	a, b := req.FormValue("a"), req.FormValue("b")
+	if !build.IsVersion(a) {
+		writeError(w, Error{"a is not a valid version: " + a}, http.StatusBadRequest)
+		return
+	}
+	if !build.IsVersion(b) {
+		writeError(w, Error{"b is not a valid version: " + b}, http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, DaemonVersionCompareGET{
+		Result:  build.VersionCmp(a, b),
+		Version: build.Version,
+	})
 }
 
 // daemonStopHandler handles the API call to stop the daemon cleanly.
-func (srv *Server) daemonStopHandler(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+func (srv *Server) daemonStopHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	timeout := defaultStopTimeout
+	if req.FormValue("timeout") != "" {
+		var secs uint64
+		_, err := fmt.Sscan(req.FormValue("timeout"), &secs)
+		if err != nil {
+			writeError(w, Error{"unable to parse timeout: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		timeout = time.Duration(secs) * time.Second
+	}
+
 	// can't write after we stop the server, so lie a bit.
+	srv.clearAlerts()
 	writeSuccess(w)
 
 	// need to flush the response before shutting down the server
@@ -298,7 +1103,9 @@ func (srv *Server) daemonStopHandler(w http.ResponseWriter, _ *http.Request, _ h
 	}
 	f.Flush()
 
-	if err := srv.Close(); err != nil {
+	// wait for other requests to finish; this request is one of the active
+	// requests until the handler returns
+	if err := srv.shutdown(timeout, 1); err != nil {
 		build.Critical(err)
 	}
 }
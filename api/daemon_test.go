@@ -1,10 +1,26 @@
 package api
 
 import (
+	"bytes"
+	"crypto"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/NebulousLabs/Sia/build"
+	"github.com/NebulousLabs/Sia/types"
+
+	"github.com/inconshreveable/go-update"
+	"github.com/julienschmidt/httprouter"
 )
 
 // TestVersion checks that /daemon/version is responding with the correct
@@ -23,6 +39,48 @@ func TestVersion(t *testing.T) {
 	if dv.Version != build.Version {
 		t.Fatalf("/daemon/version reporting bad version: expected %v, got %v", build.Version, dv.Version)
 	}
+	if dv.GitRevision != build.GitRevision || dv.BuildTime != build.BuildTime {
+		t.Fatalf("/daemon/version reporting bad build info: %+v", dv)
+	}
+}
+
+// TestVersionCompare probes the /daemon/version/compare endpoint.
+func TestVersionCompare(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	st, err := createServerTester("TestVersionCompare")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.server.Close()
+
+	tests := []struct {
+		a, b   string
+		result int
+	}{
+		{"1.3.10", "1.3.9", 1},
+		{"1.3.9", "1.3.10", -1},
+		{"1.0.1", "1.0.1", 0},
+		{"1.1", "1.1.0", -1},
+	}
+	for _, test := range tests {
+		var vc DaemonVersionCompareGET
+		err := st.getAPI("/daemon/version/compare?a="+test.a+"&b="+test.b, &vc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if vc.Result != test.result || vc.Version != build.Version {
+			t.Errorf("comparing %v to %v: expected %v, got %+v", test.a, test.b, test.result, vc)
+		}
+	}
+
+	for _, query := range []string{"a=1.3.x&b=1.3.9", "a=1.3.9", "a=v1.3.9&b=1.3.9"} {
+		var vc DaemonVersionCompareGET
+		if err := st.getAPI("/daemon/version/compare?"+query, &vc); err == nil {
+			t.Errorf("expected an error for %q", query)
+		}
+	}
 }
 
 // TestUpdate checks that /daemon/update correctly asserts that an update is
@@ -67,6 +125,755 @@ func TestUpdate(t *testing.T) {
 	}
 }
 
+// TestUpdateCache checks that the latest release is reused within the update
+// cache TTL, and fetched again when it is stale, forced, or on a different
+// channel.
+func TestUpdateCache(t *testing.T) {
+	checkedAt := time.Now().Add(-time.Minute)
+	srv := &Server{
+		updateCacheTTL: 10 * time.Minute,
+		updateCache: &cachedRelease{
+			channel:   UpdateChannelStable,
+			release:   githubRelease{TagName: "v1.0.0"},
+			checkedAt: checkedAt,
+		},
+	}
+	release, at, err := srv.latestRelease(UpdateChannelStable, false, nil)
+	if err != nil || release.TagName != "v1.0.0" || !at.Equal(checkedAt) {
+		t.Fatal("cached release was not used:", release, at, err)
+	}
+
+	// A closed cancel channel makes any request to GitHub fail immediately,
+	// which shows whether the cache was bypassed.
+	canceled := make(chan struct{})
+	close(canceled)
+	if _, _, err := srv.latestRelease(UpdateChannelStable, true, canceled); err != errGithubCanceled {
+		t.Fatal("forced check did not bypass the cache:", err)
+	}
+	if _, _, err := srv.latestRelease(UpdateChannelBeta, false, canceled); err != errGithubCanceled {
+		t.Fatal("cached release was used for a different channel:", err)
+	}
+	srv.updateCacheTTL = 30 * time.Second
+	if _, _, err := srv.latestRelease(UpdateChannelStable, false, canceled); err != errGithubCanceled {
+		t.Fatal("stale cached release was used:", err)
+	}
+	if srv.updateCache.release.TagName != "v1.0.0" {
+		t.Fatal("failed check replaced the cached release")
+	}
+}
+
+// TestUpdateUnknownVersion checks that asking /daemon/update for a release
+// that does not exist is rejected before anything is downloaded.
+func TestUpdateUnknownVersion(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	st, err := createServerTester("TestUpdateUnknownVersion")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.server.Close()
+
+	// Versions that are not plain version numbers are refused without
+	// contacting GitHub.
+	for _, version := range []string{"0.0.0-nonexistent", "1.0/../../latest", "v"} {
+		err = st.stdPostAPI("/daemon/update", url.Values{"version": {version}})
+		if err == nil || !strings.HasSuffix(err.Error(), errInvalidVersion.Error()) {
+			t.Fatalf("expected errInvalidVersion for %q, got %v", version, err)
+		}
+	}
+
+	err = st.stdPostAPI("/daemon/update", url.Values{"version": {"0.0.0"}})
+	if err == nil {
+		t.Fatal("expected an error when updating to a nonexistent release")
+	}
+	// Skip test if the GitHub API is not answering or the testing machine is
+	// offline.
+	if strings.HasSuffix(err.Error(), errEmptyUpdateResponse.Error()) {
+		t.Skip(err)
+	}
+	if strings.HasSuffix(err.Error(), "no such host") {
+		t.Skipf("skipping since testing machine appears to be offline; call to /daemon/update failed with error %v", err)
+	}
+	if !strings.HasSuffix(err.Error(), errUnknownRelease.Error()) {
+		t.Fatal("expected errUnknownRelease, got", err)
+	}
+}
+
+// TestUpdateTagNames checks that /daemon/update [GET] accepts release tags
+// with or without a leading 'v', and rejects a release without a tag.
+func TestUpdateTagNames(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch {
+		case strings.HasPrefix(req.URL.Path, "/v/"):
+			w.Write([]byte(`{"tag_name": "v1.0.0"}`))
+		case strings.HasPrefix(req.URL.Path, "/bare/"):
+			w.Write([]byte(`{"tag_name": "1.0.0"}`))
+		default:
+			w.Write([]byte(`{"tag_name": "", "assets": [{"name": "Sia.zip", "browser_download_url": "http://example.com/Sia.zip"}]}`))
+		}
+	}))
+	defer ts.Close()
+
+	st, err := createServerTester("TestUpdateTagNames")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.server.Close()
+
+	for _, repo := range []string{"/v", "/bare"} {
+		if err := st.server.SetUpdateSource(ts.URL+repo, "", ""); err != nil {
+			t.Fatal(err)
+		}
+		var info UpdateInfo
+		if err := st.getAPI("/daemon/update?force=true", &info); err != nil {
+			t.Fatal(err)
+		}
+		if info.Version != "1.0.0" {
+			t.Fatalf("expected version 1.0.0 from %v, got %q", repo, info.Version)
+		}
+	}
+
+	if err := st.server.SetUpdateSource(ts.URL+"/empty", "", ""); err != nil {
+		t.Fatal(err)
+	}
+	err = st.getAPI("/daemon/update?force=true", new(UpdateInfo))
+	if err == nil || !strings.HasSuffix(err.Error(), errNoReleaseTag.Error()) {
+		t.Fatal("expected errNoReleaseTag, got", err)
+	}
+}
+
+// TestUpdateAlreadyUpToDate checks that /daemon/update [POST] does not
+// reinstall the running version, or install an older latest release.
+func TestUpdateAlreadyUpToDate(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		// No assets are listed, so any attempt to install fails.
+		tag := "v" + build.Version
+		if strings.HasPrefix(req.URL.Path, "/old/") {
+			tag = "v0.0.1"
+		}
+		w.Write([]byte(`{"tag_name": "` + tag + `"}`))
+	}))
+	defer ts.Close()
+
+	st, err := createServerTester("TestUpdateAlreadyUpToDate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.server.Close()
+	if err := st.server.SetUpdateSource(ts.URL+"/current", "", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	var utd DaemonUpdateUpToDatePOST
+	if err := st.postAPI("/daemon/update", url.Values{}, &utd); err != nil {
+		t.Fatal(err)
+	}
+	if !utd.UpToDate || utd.Version != build.Version {
+		t.Fatalf("expected the running version to be up to date: %+v", utd)
+	}
+
+	// An older latest release is not installed either, but asking for an
+	// older version explicitly is still refused.
+	if err := st.server.SetUpdateSource(ts.URL+"/old", "", ""); err != nil {
+		t.Fatal(err)
+	}
+	utd = DaemonUpdateUpToDatePOST{}
+	if err := st.postAPI("/daemon/update", url.Values{}, &utd); err != nil {
+		t.Fatal(err)
+	}
+	if !utd.UpToDate {
+		t.Fatalf("expected an older latest release to be up to date: %+v", utd)
+	}
+	err = st.stdPostAPI("/daemon/update", url.Values{"version": {"0.0.1"}})
+	if err == nil || !strings.Contains(err.Error(), "Refusing to downgrade") {
+		t.Fatal("expected the downgrade to be refused, got", err)
+	}
+}
+
+// TestUpdateInProgress checks that a second update is refused while one is
+// running, and allowed again once it finishes.
+func TestUpdateInProgress(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte(`{"tag_name": "v` + build.Version + `"}`))
+	}))
+	defer ts.Close()
+
+	st, err := createServerTester("TestUpdateInProgress")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.server.Close()
+	if err := st.server.SetUpdateSource(ts.URL, "", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// Hold the update as if one were running.
+	if !st.server.updateProgress.start() {
+		t.Fatal("could not start an update")
+	}
+	err = st.stdPostAPI("/daemon/update", url.Values{})
+	if err == nil || !strings.Contains(err.Error(), errUpdateInProgress.Error()) {
+		t.Fatal("expected the second update to be refused, got", err)
+	}
+	st.server.updateProgress.finish()
+
+	var utd DaemonUpdateUpToDatePOST
+	if err := st.postAPI("/daemon/update", url.Values{}, &utd); err != nil {
+		t.Fatal(err)
+	}
+	if !utd.UpToDate {
+		t.Fatalf("expected the running version to be up to date: %+v", utd)
+	}
+	// The finished update must not hold the slot.
+	if !st.server.updateProgress.start() {
+		t.Fatal("update was still marked as running after it finished")
+	}
+	st.server.updateProgress.finish()
+}
+
+// TestUpdateChannel checks that the update channel can be changed through the
+// API, and that unknown channels are rejected.
+func TestUpdateChannel(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	st, err := createServerTester("TestUpdateChannel")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.server.Close()
+
+	if channel := st.server.UpdateChannel(); channel != UpdateChannelStable {
+		t.Fatal("expected the stable channel by default, got", channel)
+	}
+	if err := st.stdPostAPI("/daemon/update/channel", url.Values{"channel": {"nightly"}}); err == nil {
+		t.Fatal("expected an error for an unknown channel")
+	}
+	if err := st.stdPostAPI("/daemon/update/channel", url.Values{"channel": {UpdateChannelBeta}}); err != nil {
+		t.Fatal(err)
+	}
+	if channel := st.server.UpdateChannel(); channel != UpdateChannelBeta {
+		t.Fatal("channel was not changed, got", channel)
+	}
+}
+
+// TestUpdateProgress checks that bytes read through a progressReader are
+// counted towards the progress of an update.
+func TestUpdateProgress(t *testing.T) {
+	var progress updateProgress
+	progress.total = 11
+	progress.setPhase("downloading")
+	content, err := ioutil.ReadAll(progressReader{strings.NewReader("release zip"), &progress})
+	if err != nil {
+		t.Fatal(err)
+	}
+	up := progress.status()
+	if up.Downloaded != uint64(len(content)) || up.Total != 11 || up.Phase != "downloading" {
+		t.Fatalf("unexpected progress: %+v", up)
+	}
+}
+
+// TestDownloadReleaseResume checks that a release download that is cut off is
+// retried and resumed from the bytes already received.
+func TestDownloadReleaseResume(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	archive := bytes.Repeat([]byte("release zip "), 1000)
+	var requests int
+	var ranges []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests++
+		ranges = append(ranges, req.Header.Get("Range"))
+		if requests == 1 {
+			// send half of the archive, then drop the connection
+			w.Header().Set("Accept-Ranges", "bytes")
+			w.Header().Set("Content-Length", fmt.Sprint(len(archive)))
+			w.Write(archive[:len(archive)/2])
+			return
+		}
+		http.ServeContent(w, req, "release.zip", time.Time{}, bytes.NewReader(archive))
+	}))
+	defer ts.Close()
+
+	var progress updateProgress
+	content, err := downloadRelease(ts.URL, &progress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(content, archive) {
+		t.Fatal("downloaded archive does not match")
+	}
+	if requests != 2 || ranges[0] != "" || ranges[1] != fmt.Sprintf("bytes=%d-", len(archive)/2) {
+		t.Fatalf("unexpected requests: %q", ranges)
+	}
+	if up := progress.status(); up.Downloaded != uint64(len(archive)) || up.Total != uint64(len(archive)) {
+		t.Fatalf("unexpected progress: %+v", up)
+	}
+
+	// A download that keeps failing returns the last error.
+	ts404 := httptest.NewServer(http.NotFoundHandler())
+	defer ts404.Close()
+	if _, err := downloadRelease(ts404.URL, &progress); err == nil {
+		t.Fatal("expected an error downloading a missing release")
+	}
+}
+
+// closeNotifyRecorder is a ResponseRecorder whose client disconnects when
+// closed is sent to.
+type closeNotifyRecorder struct {
+	*httptest.ResponseRecorder
+	closed chan bool
+}
+
+func (r closeNotifyRecorder) CloseNotify() <-chan bool { return r.closed }
+
+// TestFetchGithubTimeout checks that a request to an unresponsive GitHub is
+// abandoned once the update check times out or the client disconnects.
+func TestFetchGithubTimeout(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	unblock := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/fast" {
+			w.Write([]byte(`{"tag_name": "v1.0.0"}`))
+			return
+		}
+		<-unblock
+	}))
+	defer ts.Close()
+	defer close(unblock)
+
+	srv := &Server{updateCheckTimeout: 100 * time.Millisecond}
+	cancel, done := srv.updateCheckCancel(httptest.NewRecorder())
+	release, err := fetchGithubRelease(ts.URL+"/fast", cancel)
+	done()
+	if err != nil || release.TagName != "v1.0.0" {
+		t.Fatal("fetch from a responsive server failed:", release, err)
+	}
+
+	start := time.Now()
+	cancel, done = srv.updateCheckCancel(httptest.NewRecorder())
+	_, err = fetchGithubRelease(ts.URL+"/slow", cancel)
+	done()
+	if err != errGithubCanceled {
+		t.Fatal("expected errGithubCanceled, got", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatal("fetch was not abandoned after the timeout, took", elapsed)
+	}
+
+	// A client disconnect cancels the fetch before the timeout.
+	srv.updateCheckTimeout = time.Hour
+	w := closeNotifyRecorder{httptest.NewRecorder(), make(chan bool, 1)}
+	cancel, done = srv.updateCheckCancel(w)
+	defer done()
+	w.closed <- true
+	if _, err := fetchGithubRelease(ts.URL+"/slow", cancel); err != errGithubCanceled {
+		t.Fatal("expected errGithubCanceled after disconnect, got", err)
+	}
+}
+
+// TestDownloadReleaseTooLarge checks that a release archive larger than
+// maxUpdateSize is rejected with errUpdateTooLarge instead of being truncated,
+// whether or not the server reports its size.
+func TestDownloadReleaseTooLarge(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	defer func(old int64) { maxUpdateSize = old }(maxUpdateSize)
+	maxUpdateSize = 1000
+
+	archive := bytes.Repeat([]byte("x"), 1001)
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests++
+		if req.URL.Path == "/chunked" {
+			// without a Content-Length, the size is only known once the
+			// whole body has been read
+			w.Write(archive[:500])
+			w.(http.Flusher).Flush()
+			w.Write(archive[500:])
+			return
+		}
+		w.Header().Set("Content-Length", fmt.Sprint(len(archive)))
+		w.Write(archive)
+	}))
+	defer ts.Close()
+
+	var progress updateProgress
+	for _, path := range []string{"/sized", "/chunked"} {
+		requests = 0
+		_, err := downloadRelease(ts.URL+path, &progress)
+		if err != errUpdateTooLarge {
+			t.Fatalf("%v: expected errUpdateTooLarge, got %v", path, err)
+		}
+		if requests != 1 {
+			t.Fatalf("%v: expected no retries, got %v requests", path, requests)
+		}
+	}
+	if !strings.Contains(errUpdateTooLarge.Error(), "larger than the maximum update size") {
+		t.Fatal("unclear error:", errUpdateTooLarge)
+	}
+
+	// an archive exactly at the limit is downloaded in full
+	maxUpdateSize = 1001
+	for _, path := range []string{"/sized", "/chunked"} {
+		content, err := downloadRelease(ts.URL+path, &progress)
+		if err != nil {
+			t.Fatal(path, err)
+		}
+		if !bytes.Equal(content, archive) {
+			t.Fatal(path, "downloaded archive does not match")
+		}
+	}
+}
+
+// TestDownloadReleaseStalled checks that downloading a release archive gives up
+// on a server that stops responding.
+func TestDownloadReleaseStalled(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	defer func(old *http.Client) { updateClient = old }(updateClient)
+	updateClient = &http.Client{Timeout: 100 * time.Millisecond}
+
+	stalled := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		<-stalled
+	}))
+	defer ts.Close()
+	defer close(stalled)
+
+	done := make(chan error)
+	go func() {
+		var progress updateProgress
+		_, err := downloadRelease(ts.URL, &progress)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("expected an error from a stalled server")
+		}
+	case <-time.After(time.Minute):
+		t.Fatal("download from a stalled server did not time out")
+	}
+}
+
+// TestConstantsPretty checks the units and displayed values of the constants
+// returned by /daemon/constants/pretty.
+func TestConstantsPretty(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	st, err := createServerTester("TestConstantsPretty")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.server.Close()
+
+	var sc SiaConstantsPretty
+	if err := st.getAPI("/daemon/constants/pretty", &sc); err != nil {
+		t.Fatal(err)
+	}
+	if sc.SiacoinPrecision != (ConstantValue{"1000000000000000000000000", "H", "1", "SC"}) {
+		t.Errorf("unexpected siacoinprecision: %+v", sc.SiacoinPrecision)
+	}
+	if sc.SiafundPortion != (ConstantValue{"39/1000", "ratio", "0.039", "decimal"}) {
+		t.Errorf("unexpected siafundportion: %+v", sc.SiafundPortion)
+	}
+	if sc.InitialCoinbase.Display != "300000" || sc.InitialCoinbase.DisplayUnit != "SC" {
+		t.Errorf("unexpected initialcoinbase: %+v", sc.InitialCoinbase)
+	}
+	if sc.MaturityDelay.Unit != "blocks" || sc.MaturityDelay.Value != fmt.Sprint(types.MaturityDelay) {
+		t.Errorf("unexpected maturitydelay: %+v", sc.MaturityDelay)
+	}
+	if sc.RootDepth.Display != strings.Repeat("ff", 32) {
+		t.Errorf("unexpected rootdepth: %+v", sc.RootDepth)
+	}
+}
+
+// TestConstantsETag checks that the constants are served with an ETag, that a
+// matching If-None-Match is answered with 304, and that HEAD requests get the
+// headers without a body.
+func TestConstantsETag(t *testing.T) {
+	srv := new(Server)
+	serve := func(handler func(http.ResponseWriter, *http.Request, httprouter.Params), method, ifNoneMatch string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(method, "/daemon/constants", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		handler(rec, req, nil)
+		return rec
+	}
+
+	rec := serve(srv.daemonConstantsHandler, "GET", "")
+	etag := rec.Header().Get("ETag")
+	if rec.Code != http.StatusOK || etag == "" || rec.Body.Len() == 0 {
+		t.Fatal("constants were not served with an ETag:", rec.Code, etag)
+	}
+	if rec = serve(srv.daemonConstantsHandler, "GET", ""); rec.Header().Get("ETag") != etag {
+		t.Fatal("ETag is not stable:", rec.Header().Get("ETag"), etag)
+	}
+
+	for _, inm := range []string{etag, "W/" + etag, `"other", ` + etag, "*"} {
+		rec = serve(srv.daemonConstantsHandler, "GET", inm)
+		if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+			t.Errorf("expected 304 for If-None-Match %v, got %v", inm, rec.Code)
+		}
+	}
+	rec = serve(srv.daemonConstantsHandler, "GET", `"other"`)
+	if rec.Code != http.StatusOK || rec.Body.Len() == 0 {
+		t.Error("stale ETag was not answered with the constants:", rec.Code)
+	}
+
+	rec = serve(srv.daemonConstantsHandler, "HEAD", "")
+	if rec.Code != http.StatusOK || rec.Body.Len() != 0 || rec.Header().Get("ETag") != etag {
+		t.Error("HEAD request was not answered with the headers alone:", rec.Code, rec.Body.Len())
+	}
+
+	// The pretty constants have a tag of their own.
+	rec = serve(srv.daemonConstantsPrettyHandler, "GET", "")
+	if tag := rec.Header().Get("ETag"); tag == "" || tag == etag {
+		t.Error("pretty constants were not given their own ETag:", tag)
+	}
+}
+
+// TestMemStats checks that /daemon/memstats reports the runtime statistics.
+func TestMemStats(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	st, err := createServerTester("TestMemStats")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.server.Close()
+
+	var ms DaemonMemStatsGET
+	if err := st.getAPI("/daemon/memstats", &ms); err != nil {
+		t.Fatal(err)
+	}
+	if ms.HeapAlloc == 0 || ms.Sys < ms.HeapSys || ms.NumGoroutine == 0 {
+		t.Errorf("unexpected memory stats: %+v", ms)
+	}
+	if ms.GoVersion != runtime.Version() || ms.GOMAXPROCS != runtime.GOMAXPROCS(0) {
+		t.Errorf("unexpected runtime info: %+v", ms)
+	}
+}
+
+// TestUptime checks that /daemon/uptime reports the start time of the server.
+func TestUptime(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	st, err := createServerTester("TestUptime")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.server.Close()
+
+	var du DaemonUptimeGET
+	if err := st.getAPI("/daemon/uptime", &du); err != nil {
+		t.Fatal(err)
+	}
+	if !du.StartTime.Equal(st.server.startTime) || du.StartTime.After(time.Now()) {
+		t.Errorf("unexpected start time %v", du.StartTime)
+	}
+	if du.UptimeSeconds > uint64(time.Since(du.StartTime).Seconds()) {
+		t.Errorf("uptime %v is longer than the time since %v", du.UptimeSeconds, du.StartTime)
+	}
+	if du.PID != os.Getpid() {
+		t.Errorf("expected pid %v, got %v", os.Getpid(), du.PID)
+	}
+
+	dir := build.TempDir("api", "TestUptime", "persist")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.server.SetPersistDir(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.getAPI("/daemon/uptime", &du); err != nil {
+		t.Fatal(err)
+	}
+	if du.DataDir != dir {
+		t.Errorf("expected data directory %v, got %v", dir, du.DataDir)
+	}
+}
+
+// checksumVerifier is an update.Verifier that accepts a signature if it is
+// the key, which must be a string, followed by the checksum of the binary.
+type checksumVerifier struct{}
+
+func (checksumVerifier) VerifySignature(checksum, signature []byte, _ crypto.Hash, key crypto.PublicKey) error {
+	if !bytes.Equal(append([]byte(key.(string)), checksum...), signature) {
+		return errors.New("bad signature")
+	}
+	return nil
+}
+
+// TestCheckBinary probes the signature checks made by checkBinary during a
+// dry run of an update.
+func TestCheckBinary(t *testing.T) {
+	opts := update.Options{
+		Hash:     crypto.SHA256,
+		Verifier: checksumVerifier{},
+	}
+	keys := []crypto.PublicKey{"foo", "bar"}
+	// the test binary is an executable for this platform
+	bin, err := ioutil.ReadFile(os.Args[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(bin)
+	sig := append([]byte("bar"), sum[:]...)
+
+	check := checkBinary(opts, keys, "siad", "siad", bytes.NewReader(bin), "siad.sig", sig)
+	if !check.Verified || check.Error != "" || check.File != "siad" || check.Signature != "siad.sig" {
+		t.Fatalf("unexpected check: %+v", check)
+	}
+	check = checkBinary(opts, keys[:1], "siad", "siad", bytes.NewReader(bin), "siad.sig", sig)
+	if check.Verified || check.Error == "" {
+		t.Fatalf("unexpected check: %+v", check)
+	}
+	check = checkBinary(opts, keys, "siad", "siad", bytes.NewReader(bin), "siad.sig", []byte("forged"))
+	if check.Verified || check.Error == "" {
+		t.Fatalf("unexpected check: %+v", check)
+	}
+
+	// a correctly signed binary for another platform is rejected
+	other := peHeader(peMachineAMD64)
+	if runtime.GOOS == "windows" {
+		other = elfHeader(elfMachineAMD64)
+	}
+	otherSum := sha256.Sum256(other)
+	check = checkBinary(opts, keys, "siad", "siad", bytes.NewReader(other), "siad.sig", append([]byte("foo"), otherSum[:]...))
+	if check.Verified || !strings.HasPrefix(check.Error, "wrong platform") {
+		t.Fatalf("unexpected check: %+v", check)
+	}
+
+	check = checkBinary(opts, keys, "siac", "", nil, "siac.sig", sig)
+	if check.Verified || check.Error != "could not find siac binary" {
+		t.Fatalf("unexpected check: %+v", check)
+	}
+	check = checkBinary(opts, keys, "siac", "siac", bytes.NewReader(bin), "", nil)
+	if check.Verified || check.Error != "could not find siac signature" {
+		t.Fatalf("unexpected check: %+v", check)
+	}
+}
+
+// TestParseUpdateKeys checks that files of one or more update keys are
+// parsed, and that the developer key is used when no file is set.
+func TestParseUpdateKeys(t *testing.T) {
+	keys, err := parseUpdateKeys([]byte(developerKey + "\n" + developerKey + "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 2 {
+		t.Fatal("expected 2 keys, got", len(keys))
+	}
+	if _, err := parseUpdateKeys([]byte("not a key")); err != errNoUpdateKeys {
+		t.Fatal("expected errNoUpdateKeys, got", err)
+	}
+	cert := "-----BEGIN CERTIFICATE-----\nAAAA\n-----END CERTIFICATE-----\n"
+	if _, err := parseUpdateKeys([]byte(cert)); err != errBadUpdateKeyBlock {
+		t.Fatal("expected errBadUpdateKeyBlock, got", err)
+	}
+
+	srv := new(Server)
+	keys, err = srv.trustedUpdateKeys()
+	if err != nil || len(keys) != 1 {
+		t.Fatal("expected the developer key, got", keys, err)
+	}
+	if err := srv.SetUpdateKeyFile("nonexistent.pem"); err == nil {
+		t.Fatal("expected an error for a missing key file")
+	}
+}
+
+// TestUpdateHistory checks that the update history is capped and survives
+// restarts.
+func TestUpdateHistory(t *testing.T) {
+	dir := build.TempDir("api", "TestUpdateHistory")
+	os.RemoveAll(dir)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	srv := new(Server)
+	if err := srv.SetPersistDir(dir); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < maxUpdateHistory+5; i++ {
+		err := srv.recordUpdate(UpdateHistoryEntry{
+			Timestamp:   time.Unix(int64(i), 0),
+			FromVersion: fmt.Sprintf("1.0.%v", i),
+			ToVersion:   fmt.Sprintf("1.0.%v", i+1),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	srv = new(Server)
+	if err := srv.SetPersistDir(dir); err != nil {
+		t.Fatal(err)
+	}
+	if len(srv.updateHistory) != maxUpdateHistory {
+		t.Fatal("expected", maxUpdateHistory, "updates, got", len(srv.updateHistory))
+	}
+	if first := srv.updateHistory[0]; first.FromVersion != "1.0.5" || first.Timestamp.Unix() != 5 {
+		t.Fatalf("oldest updates were not dropped: %+v", first)
+	}
+	if last := srv.updateHistory[maxUpdateHistory-1]; last.ToVersion != fmt.Sprintf("1.0.%v", maxUpdateHistory+5) {
+		t.Fatalf("unexpected last update: %+v", last)
+	}
+}
+
+// TestVerifyChecksum probes the parsing of SHA256SUMS files by verifyChecksum.
+func TestVerifyChecksum(t *testing.T) {
+	content := []byte("release archive")
+	sum := sha256.Sum256(content)
+	name := "Sia-v1.3.2-linux-amd64.zip"
+
+	valid := []string{
+		fmt.Sprintf("%x  %s\n", sum, name),
+		fmt.Sprintf("%x  Sia-v1.3.2-darwin-amd64.zip\n%x *%s\n", sha256.Sum256([]byte("other")), sum, name),
+	}
+	for _, sums := range valid {
+		if err := verifyChecksum(content, []byte(sums), name); err != nil {
+			t.Error(err)
+		}
+	}
+
+	invalid := []string{
+		"",
+		fmt.Sprintf("%x  Sia-v1.3.2-darwin-amd64.zip\n", sum),
+		fmt.Sprintf("%x  %s\n", sha256.Sum256([]byte("truncated")), name),
+		fmt.Sprintf("%x  %s\n", sum[:16], name),
+	}
+	for _, sums := range invalid {
+		if err := verifyChecksum(content, []byte(sums), name); err == nil {
+			t.Errorf("expected an error for %q", sums)
+		}
+	}
+}
+
 /*
 // TODO: enable this test again once proper daemon shutdown is implemented (shutting down modules and listener separately).
 // TestStop tests the /daemon/stop handler.
@@ -92,3 +899,72 @@ func TestStop(t *testing.T) {
 	}
 }
 */
+
+// TestDebug checks that the profiling routes are only served once enabled,
+// that they require the API password, and that they return profiles.
+func TestDebug(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	// EnableDebug must be called before Serve, so a separate server is used
+	// to check that the routes are disabled by default.
+	disabled, err := NewServer("localhost:0", "Sia-Agent", "password", nil, nil, nil, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go disabled.Serve()
+	resp, err := HttpGETAuthenticated("http://"+disabled.listener.Addr().String()+"/daemon/debug/goroutines", "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatal("debug routes should not be served unless enabled:", resp.StatusCode)
+	}
+	if err := disabled.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	srv, err := NewServer("localhost:0", "Sia-Agent", "password", nil, nil, nil, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := "http://" + srv.listener.Addr().String()
+	srv.EnableDebug()
+	go srv.Serve()
+	defer srv.Close()
+
+	resp, err = HttpGET(addr + "/daemon/debug/goroutines")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatal("debug routes should require the API password:", resp.StatusCode)
+	}
+
+	get := func(path string) (int, []byte) {
+		resp, err := HttpGETAuthenticated(addr+path, "password")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode, body
+	}
+	if code, body := get("/daemon/debug/goroutines"); code != http.StatusOK || !strings.Contains(string(body), "goroutine") {
+		t.Fatal("bad goroutine dump:", code, string(body))
+	}
+	if code, body := get("/daemon/debug/heap"); code != http.StatusOK || len(body) == 0 {
+		t.Fatal("bad heap profile:", code)
+	}
+	if code, _ := get("/daemon/debug/cpu?seconds=0"); code != http.StatusBadRequest {
+		t.Fatal("expected an out of range duration to be rejected:", code)
+	}
+	if code, body := get("/daemon/debug/cpu?seconds=1"); code != http.StatusOK || len(body) == 0 {
+		t.Fatal("bad CPU profile:", code)
+	}
+}
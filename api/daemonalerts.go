@@ -0,0 +1,143 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/NebulousLabs/Sia/modules"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	// AlertSeverityInfo, AlertSeverityWarning and AlertSeverityError are the
+	// severities of an alert, from least to most severe.
+	AlertSeverityInfo    = "info"
+	AlertSeverityWarning = "warning"
+	AlertSeverityError   = "error"
+)
+
+// Alert ids identify the alerts that the server raises, so that an alert is
+// replaced rather than repeated when the same problem is seen again.
+const (
+	alertAllowanceExpiring  = "allowance-expiring"
+	alertConsensusNotSynced = "consensus-not-synced"
+	alertUpdateAvailable    = "update-available"
+	alertUpdateInstalled    = "update-installed"
+	alertWalletLocked       = "wallet-locked"
+)
+
+type (
+	// A DaemonAlert is a problem that an operator may need to act on.
+	// Timestamp is when the problem was first seen.
+	DaemonAlert struct {
+		Severity  string    `json:"severity"`
+		Module    string    `json:"module"`
+		Message   string    `json:"message"`
+		Timestamp time.Time `json:"timestamp"`
+	}
+
+	// DaemonAlertsGET contains the current alerts of the daemon, most severe
+	// first.
+	DaemonAlertsGET struct {
+		Alerts []DaemonAlert `json:"alerts"`
+	}
+
+	// alertsBySeverity sorts alerts from most to least severe, and then from
+	// oldest to newest.
+	alertsBySeverity []DaemonAlert
+)
+
+// alertSeverityRank orders the severities of alerts.
+var alertSeverityRank = map[string]int{
+	AlertSeverityInfo:    0,
+	AlertSeverityWarning: 1,
+	AlertSeverityError:   2,
+}
+
+func (as alertsBySeverity) Len() int      { return len(as) }
+func (as alertsBySeverity) Swap(i, j int) { as[i], as[j] = as[j], as[i] }
+func (as alertsBySeverity) Less(i, j int) bool {
+	ri, rj := alertSeverityRank[as[i].Severity], alertSeverityRank[as[j].Severity]
+	if ri != rj {
+		return ri > rj
+	}
+	return as[i].Timestamp.Before(as[j].Timestamp)
+}
+
+// registerAlert raises the alert with the given id, replacing any alert with
+// the same id. An alert that is raised again with the same message keeps the
+// time at which it was first raised.
+func (srv *Server) registerAlert(id, severity, module, message string) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if srv.alerts == nil {
+		srv.alerts = make(map[string]DaemonAlert)
+	}
+	if a, exists := srv.alerts[id]; exists && a.Severity == severity && a.Message == message {
+		return
+	}
+	srv.alerts[id] = DaemonAlert{
+		Severity:  severity,
+		Module:    module,
+		Message:   message,
+		Timestamp: time.Now(),
+	}
+}
+
+// unregisterAlert clears the alert with the given id, if it is raised.
+func (srv *Server) unregisterAlert(id string) {
+	srv.mu.Lock()
+	delete(srv.alerts, id)
+	srv.mu.Unlock()
+}
+
+// clearAlerts clears every alert.
+func (srv *Server) clearAlerts() {
+	srv.mu.Lock()
+	srv.alerts = nil
+	srv.mu.Unlock()
+}
+
+// checkModuleAlerts raises or clears the alerts that reflect the current
+// state of the modules.
+func (srv *Server) checkModuleAlerts() {
+	if srv.cs != nil {
+		if srv.cs.Synced() {
+			srv.unregisterAlert(alertConsensusNotSynced)
+		} else {
+			srv.registerAlert(alertConsensusNotSynced, AlertSeverityWarning, "consensus", "the consensus set is not synced with the network")
+		}
+	}
+	if srv.renter != nil {
+		if warning := srv.renter.ExpiryWarning(); warning == "" {
+			srv.unregisterAlert(alertAllowanceExpiring)
+		} else if srv.renter.Settings().Allowance.ExpiryPolicy == modules.AllowanceExpiryStop {
+			srv.registerAlert(alertAllowanceExpiring, AlertSeverityWarning, "renter", warning)
+		} else {
+			srv.registerAlert(alertAllowanceExpiring, AlertSeverityInfo, "renter", warning)
+		}
+	}
+	if srv.wallet != nil {
+		if srv.wallet.Encrypted() && !srv.wallet.Unlocked() {
+			srv.registerAlert(alertWalletLocked, AlertSeverityWarning, "wallet", "the wallet is locked; unlock it to send siacoins and to form contracts")
+		} else {
+			srv.unregisterAlert(alertWalletLocked)
+		}
+	}
+}
+
+// daemonAlertsHandlerGET handles the API call that returns the current alerts
+// of the daemon.
+func (srv *Server) daemonAlertsHandlerGET(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	srv.checkModuleAlerts()
+	srv.mu.RLock()
+	alerts := make([]DaemonAlert, 0, len(srv.alerts))
+	for _, a := range srv.alerts {
+		alerts = append(alerts, a)
+	}
+	srv.mu.RUnlock()
+	sort.Sort(alertsBySeverity(alerts))
+	writeJSON(w, DaemonAlertsGET{Alerts: alerts})
+}
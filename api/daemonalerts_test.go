@@ -0,0 +1,81 @@
+package api
+
+import (
+	"sort"
+	"testing"
+	"time"
+)
+
+// TestRegisterAlert checks that alerts are replaced by id, keep the time at
+// which they were first raised, and are sorted by severity.
+func TestRegisterAlert(t *testing.T) {
+	srv := new(Server)
+	srv.registerAlert("a", AlertSeverityInfo, "daemon", "first")
+	srv.registerAlert("b", AlertSeverityError, "wallet", "second")
+	first := srv.alerts["a"].Timestamp
+
+	time.Sleep(time.Millisecond)
+	srv.registerAlert("a", AlertSeverityInfo, "daemon", "first")
+	if !srv.alerts["a"].Timestamp.Equal(first) {
+		t.Fatal("alert raised again was given a new timestamp")
+	}
+	srv.registerAlert("a", AlertSeverityWarning, "daemon", "changed")
+	if a := srv.alerts["a"]; a.Message != "changed" || a.Severity != AlertSeverityWarning || len(srv.alerts) != 2 {
+		t.Fatal("alert was not replaced:", srv.alerts)
+	}
+	time.Sleep(time.Millisecond)
+	srv.registerAlert("c", AlertSeverityWarning, "consensus", "third")
+
+	alerts := alertsBySeverity{srv.alerts["c"], srv.alerts["a"], srv.alerts["b"]}
+	sort.Sort(alerts)
+	if alerts[0].Message != "second" || alerts[1].Message != "changed" || alerts[2].Message != "third" {
+		t.Fatal("alerts are not ordered by severity and then age:", alerts)
+	}
+
+	srv.unregisterAlert("b")
+	if _, exists := srv.alerts["b"]; exists {
+		t.Fatal("alert was not cleared")
+	}
+	srv.clearAlerts()
+	if len(srv.alerts) != 0 {
+		t.Fatal("alerts were not cleared")
+	}
+}
+
+// TestDaemonAlerts checks that /daemon/alerts reports a locked wallet.
+func TestDaemonAlerts(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	st, err := createServerTester("TestDaemonAlerts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.server.Close()
+
+	var dag DaemonAlertsGET
+	if err := st.getAPI("/daemon/alerts", &dag); err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range dag.Alerts {
+		if a.Module == "wallet" {
+			t.Fatal("unexpected wallet alert for an unlocked wallet:", a)
+		}
+	}
+
+	if err := st.stdPostAPI("/wallet/lock", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.getAPI("/daemon/alerts", &dag); err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, a := range dag.Alerts {
+		if a.Module == "wallet" && a.Severity == AlertSeverityWarning {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("locked wallet was not reported:", dag.Alerts)
+	}
+}
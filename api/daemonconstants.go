@@ -0,0 +1,177 @@
+package api
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NebulousLabs/Sia/build"
+	"github.com/NebulousLabs/Sia/crypto"
+	"github.com/NebulousLabs/Sia/types"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type (
+	// A ConstantValue is a constant together with the unit it is measured in.
+	// Value is exact, in the base unit. Display is the same value in a form
+	// that is easier to read, such as siacoins instead of hastings.
+	ConstantValue struct {
+		Value       string `json:"value"`
+		Unit        string `json:"unit"`
+		Display     string `json:"display"`
+		DisplayUnit string `json:"displayunit"`
+	}
+
+	// SiaConstantsPretty lists the same constants as SiaConstants, with the
+	// unit of each one.
+	SiaConstantsPretty struct {
+		GenesisTimestamp      ConstantValue `json:"genesistimestamp"`
+		BlockSizeLimit        ConstantValue `json:"blocksizelimit"`
+		BlockFrequency        ConstantValue `json:"blockfrequency"`
+		TargetWindow          ConstantValue `json:"targetwindow"`
+		MedianTimestampWindow ConstantValue `json:"mediantimestampwindow"`
+		FutureThreshold       ConstantValue `json:"futurethreshold"`
+		SiafundCount          ConstantValue `json:"siafundcount"`
+		SiafundPortion        ConstantValue `json:"siafundportion"`
+		MaturityDelay         ConstantValue `json:"maturitydelay"`
+
+		InitialCoinbase ConstantValue `json:"initialcoinbase"`
+		MinimumCoinbase ConstantValue `json:"minimumcoinbase"`
+
+		RootTarget ConstantValue `json:"roottarget"`
+		RootDepth  ConstantValue `json:"rootdepth"`
+
+		MaxAdjustmentUp   ConstantValue `json:"maxadjustmentup"`
+		MaxAdjustmentDown ConstantValue `json:"maxadjustmentdown"`
+
+		SiacoinPrecision ConstantValue `json:"siacoinprecision"`
+	}
+)
+
+// constantsETag returns the entity tag of a constants response. The constants
+// only change between builds, so the tag covers the encoded constants and the
+// version of siad.
+func constantsETag(js []byte) string {
+	h := crypto.HashAll(build.Version, js)
+	return `"` + hex.EncodeToString(h[:16]) + `"`
+}
+
+// etagMatches reports whether an If-None-Match header lists etag. Weak tags
+// match their strong equivalent, as If-None-Match uses weak comparison.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// writeConstants writes a constants response with an ETag. A request whose
+// If-None-Match header lists the tag is answered with 304 Not Modified, and a
+// HEAD request is answered with the headers alone.
+func writeConstants(w http.ResponseWriter, req *http.Request, obj interface{}) {
+	js, err := json.Marshal(obj)
+	if err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("ETag", constantsETag(js))
+	if etagMatches(req.Header.Get("If-None-Match"), w.Header().Get("ETag")) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	if req.Method == "HEAD" {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Header().Add("Vary", "Accept-Encoding")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	writeJSON(w, obj)
+}
+
+// countConstant returns a ConstantValue for a plain count of unit.
+func countConstant(n uint64, unit string) ConstantValue {
+	s := strconv.FormatUint(n, 10)
+	return ConstantValue{Value: s, Unit: unit, Display: s, DisplayUnit: unit}
+}
+
+// currencyConstant returns a ConstantValue for an amount of hastings, which
+// is displayed in siacoins.
+func currencyConstant(c types.Currency) ConstantValue {
+	return ConstantValue{
+		Value:       c.String(),
+		Unit:        "H",
+		Display:     formatSiacoins(c),
+		DisplayUnit: "SC",
+	}
+}
+
+// ratioConstant returns a ConstantValue for a ratio, which is displayed as a
+// decimal rounded to 18 places.
+func ratioConstant(r *big.Rat) ConstantValue {
+	display := r.FloatString(18)
+	display = strings.TrimRight(strings.TrimRight(display, "0"), ".")
+	return ConstantValue{
+		Value:       r.RatString(),
+		Unit:        "ratio",
+		Display:     display,
+		DisplayUnit: "decimal",
+	}
+}
+
+// targetConstant returns a ConstantValue for a target, which is displayed
+// in hex.
+func targetConstant(t types.Target) ConstantValue {
+	return ConstantValue{
+		Value:       t.Int().String(),
+		Unit:        "target",
+		Display:     fmt.Sprintf("%x", t[:]),
+		DisplayUnit: "hex",
+	}
+}
+
+// daemonConstantsPrettyHandler handles the API call that returns the
+// constants in use with their units.
+func (srv *Server) daemonConstantsPrettyHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	genesis := countConstant(uint64(types.GenesisTimestamp), "unix seconds")
+	genesis.Display = time.Unix(int64(types.GenesisTimestamp), 0).UTC().Format(time.RFC3339)
+	genesis.DisplayUnit = "RFC 3339"
+
+	siafunds := ConstantValue{
+		Value:       types.SiafundCount.String(),
+		Unit:        "SF",
+		Display:     types.SiafundCount.String(),
+		DisplayUnit: "SF",
+	}
+
+	writeConstants(w, req, SiaConstantsPretty{
+		GenesisTimestamp:      genesis,
+		BlockSizeLimit:        countConstant(types.BlockSizeLimit, "bytes"),
+		BlockFrequency:        countConstant(uint64(types.BlockFrequency), "seconds"),
+		TargetWindow:          countConstant(uint64(types.TargetWindow), "blocks"),
+		MedianTimestampWindow: countConstant(types.MedianTimestampWindow, "blocks"),
+		FutureThreshold:       countConstant(uint64(types.FutureThreshold), "seconds"),
+		SiafundCount:          siafunds,
+		SiafundPortion:        ratioConstant(types.SiafundPortion),
+		MaturityDelay:         countConstant(uint64(types.MaturityDelay), "blocks"),
+
+		InitialCoinbase: currencyConstant(types.SiacoinPrecision.Mul64(types.InitialCoinbase)),
+		MinimumCoinbase: currencyConstant(types.SiacoinPrecision.Mul64(types.MinimumCoinbase)),
+
+		RootTarget: targetConstant(types.RootTarget),
+		RootDepth:  targetConstant(types.RootDepth),
+
+		MaxAdjustmentUp:   ratioConstant(types.MaxAdjustmentUp),
+		MaxAdjustmentDown: ratioConstant(types.MaxAdjustmentDown),
+
+		SiacoinPrecision: currencyConstant(types.SiacoinPrecision),
+	})
+}
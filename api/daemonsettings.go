@@ -0,0 +1,193 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// maxDaemonSettingsSize is the largest request body accepted by
+// /daemon/settings [POST].
+const maxDaemonSettingsSize = 1 << 16
+
+var errNoSettings = errors.New("no settings were given")
+
+// DaemonSettings are the options of the API server that can be inspected and
+// changed at /daemon/settings. The update timeout and TTL are in seconds.
+// Debug and BasePath are only reported; they take effect when the routes are
+// registered, so they can only be changed by restarting siad. UpdateRepo and
+// UpdateKeyFile decide which binaries siad will install, so they are only
+// reported too, and can only be changed with the siad flags.
+type DaemonSettings struct {
+	UpdateChannel       string `json:"updatechannel"`
+	UpdateCheckTimeout  int    `json:"updatechecktimeout"`
+	UpdateCacheTTL      int    `json:"updatecachettl"`
+	UpdateRepo          string `json:"updaterepo"`
+	UpdateArchiveName   string `json:"updatearchivename"`
+	UpdateChecksumsName string `json:"updatechecksumsname"`
+	UpdateKeyFile       string `json:"updatekeyfile"`
+	PageSize            int    `json:"pagesize"`
+	MaxPageSize         int    `json:"maxpagesize"`
+
+	Debug    bool   `json:"debug"`
+	BasePath string `json:"basepath"`
+}
+
+// settings returns the current settings of the server.
+func (srv *Server) settings() DaemonSettings {
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	return srv.currentSettings()
+}
+
+// currentSettings returns the current settings of the server. The caller must
+// hold srv.mu.
+func (srv *Server) currentSettings() DaemonSettings {
+	src := defaultUpdateSource()
+	if srv.updateSource != nil {
+		src = *srv.updateSource
+	}
+	return DaemonSettings{
+		UpdateChannel:       srv.updateChannel,
+		UpdateCheckTimeout:  int(srv.updateCheckTimeout / time.Second),
+		UpdateCacheTTL:      int(srv.updateCacheTTL / time.Second),
+		UpdateRepo:          src.repo,
+		UpdateArchiveName:   src.archiveName,
+		UpdateChecksumsName: src.checksumsName,
+		UpdateKeyFile:       srv.updateKeyFile,
+		PageSize:            srv.defaultPageSize,
+		MaxPageSize:         srv.maxPageSize,
+		Debug:               srv.debug,
+		BasePath:            srv.basePath,
+	}
+}
+
+// updateSettings changes the settings named by the keys of changes, which are
+// the JSON names of the DaemonSettings fields, to the JSON values they map to.
+// The current settings are read and the new ones applied under a single lock,
+// so that concurrent updates do not undo each other.
+func (srv *Server) updateSettings(changes map[string]json.RawMessage) error {
+	if len(changes) == 0 {
+		return errNoSettings
+	}
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	ds := srv.currentSettings()
+	for key, value := range changes {
+		var field interface{}
+		switch key {
+		case "updatechannel":
+			field = &ds.UpdateChannel
+		case "updatechecktimeout":
+			field = &ds.UpdateCheckTimeout
+		case "updatecachettl":
+			field = &ds.UpdateCacheTTL
+		case "updaterepo":
+			field = &ds.UpdateRepo
+		case "updatearchivename":
+			field = &ds.UpdateArchiveName
+		case "updatechecksumsname":
+			field = &ds.UpdateChecksumsName
+		case "updatekeyfile":
+			field = &ds.UpdateKeyFile
+		case "pagesize":
+			field = &ds.PageSize
+		case "maxpagesize":
+			field = &ds.MaxPageSize
+		case "debug":
+			field = &ds.Debug
+		case "basepath":
+			field = &ds.BasePath
+		default:
+			return errors.New("unknown setting: " + key)
+		}
+		if err := json.Unmarshal(value, field); err != nil {
+			return errors.New("unable to parse " + key + ": " + err.Error())
+		}
+	}
+	if _, ok := changes["basepath"]; ok {
+		basePath, err := CleanBasePath(ds.BasePath)
+		if err != nil {
+			return errors.New("unable to parse basepath: " + err.Error())
+		}
+		ds.BasePath = basePath
+	}
+	return srv.applySettings(ds)
+}
+
+// applySettings validates every setting in ds and then applies them all at
+// once, so that an invalid setting leaves the server unchanged. Debug,
+// BasePath, UpdateRepo and UpdateKeyFile must match the running server. The
+// caller must hold srv.mu.
+func (srv *Server) applySettings(ds DaemonSettings) error {
+	if ds.UpdateChannel != UpdateChannelStable && ds.UpdateChannel != UpdateChannelBeta {
+		return errBadUpdateChannel
+	}
+	if ds.UpdateCheckTimeout <= 0 {
+		return errBadUpdateTimeout
+	}
+	if ds.UpdateCacheTTL < 0 {
+		return errBadUpdateCacheTTL
+	}
+	src, err := newUpdateSource(ds.UpdateRepo, ds.UpdateArchiveName, ds.UpdateChecksumsName)
+	if err != nil {
+		return err
+	}
+	if err := checkPageSizes(ds.PageSize, ds.MaxPageSize); err != nil {
+		return err
+	}
+
+	if ds.Debug != srv.debug {
+		return errors.New("debug cannot be changed while siad is running; restart siad with or without --api-debug")
+	}
+	if ds.BasePath != srv.basePath {
+		return errors.New("basepath cannot be changed while siad is running; restart siad with --api-base-path")
+	}
+	current := defaultUpdateSource()
+	if srv.updateSource != nil {
+		current = *srv.updateSource
+	}
+	if src.repo != current.repo {
+		return errors.New("updaterepo cannot be changed while siad is running; restart siad with --update-repo")
+	}
+	if ds.UpdateKeyFile != srv.updateKeyFile {
+		return errors.New("updatekeyfile cannot be changed while siad is running; restart siad with --update-key-file")
+	}
+	srv.updateChannel = ds.UpdateChannel
+	srv.updateCheckTimeout = time.Duration(ds.UpdateCheckTimeout) * time.Second
+	srv.updateCacheTTL = time.Duration(ds.UpdateCacheTTL) * time.Second
+	if srv.updateSource == nil || *srv.updateSource != src {
+		srv.updateSource = &src
+		srv.updateCache = nil
+	}
+	srv.defaultPageSize = ds.PageSize
+	srv.maxPageSize = ds.MaxPageSize
+	return nil
+}
+
+// daemonSettingsHandlerGET handles the API call that returns the settings of
+// the API server.
+func (srv *Server) daemonSettingsHandlerGET(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	writeJSON(w, srv.settings())
+}
+
+// daemonSettingsHandlerPOST handles the API call that changes the settings of
+// the API server. The request body is a JSON object holding the settings to
+// change. Only the settings given are changed, and none are changed if any of
+// them is invalid.
+func (srv *Server) daemonSettingsHandlerPOST(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	var changes map[string]json.RawMessage
+	if err := json.NewDecoder(io.LimitReader(req.Body, maxDaemonSettingsSize)).Decode(&changes); err != nil {
+		writeError(w, Error{"request body must be a JSON object of settings: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	if err := srv.updateSettings(changes); err != nil {
+		writeError(w, Error{err.Error()}, http.StatusBadRequest)
+		return
+	}
+	writeSuccess(w)
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"strings"
+	"testing"
+)
+
+// postSettings posts a JSON object of settings to /daemon/settings.
+func (st *serverTester) postSettings(settings string) error {
+	resp, err := HttpPOST("http://"+st.server.listener.Addr().String()+"/daemon/settings", settings)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if non2xx(resp.StatusCode) {
+		return decodeError(resp)
+	}
+	return nil
+}
+
+// TestDaemonSettings checks that /daemon/settings reports the settings of the
+// server and changes them only when every given setting is valid.
+func TestDaemonSettings(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	st, err := createServerTester("TestDaemonSettings")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.server.Close()
+
+	var ds DaemonSettings
+	if err := st.getAPI("/daemon/settings", &ds); err != nil {
+		t.Fatal(err)
+	}
+	if ds.UpdateChannel != UpdateChannelStable || ds.UpdateCheckTimeout != 30 || ds.UpdateCacheTTL != 600 || ds.UpdateRepo != defaultUpdateRepo || ds.Debug {
+		t.Fatalf("unexpected default settings: %+v", ds)
+	}
+
+	err = st.postSettings(`{
+		"updatechannel":      "beta",
+		"updatechecktimeout": 5,
+		"updatearchivename":  "Sia-{tag}-{os}-{arch}-full.zip",
+		"maxpagesize":        50
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := st.getAPI("/daemon/settings", &ds); err != nil {
+		t.Fatal(err)
+	}
+	if ds.UpdateChannel != UpdateChannelBeta || ds.UpdateCheckTimeout != 5 || ds.UpdateArchiveName != "Sia-{tag}-{os}-{arch}-full.zip" || ds.MaxPageSize != 50 || ds.UpdateCacheTTL != 600 {
+		t.Fatalf("settings were not applied: %+v", ds)
+	}
+	if st.server.UpdateChannel() != UpdateChannelBeta || st.server.releaseSource().archiveName != "Sia-{tag}-{os}-{arch}-full.zip" {
+		t.Fatal("settings were not applied to the server")
+	}
+
+	// An invalid setting leaves the valid ones unapplied.
+	before := ds
+	for _, settings := range []string{
+		`{"updatechannel": "stable", "pagesize": 100}`,
+		`{"updatechannel": "stable", "updatechecktimeout": 0}`,
+		`{"updatechannel": "stable", "updatechecktimeout": "5"}`,
+		`{"updatechannel": "stable", "updatekeyfile": "nonexistent.pem"}`,
+		`{"updatechannel": "stable", "nosuchsetting": 1}`,
+		`{"updatechannel": "stable", "debug": true}`,
+		`updatechannel=stable`,
+		`{}`,
+		``,
+	} {
+		if err := st.postSettings(settings); err == nil {
+			t.Fatal("expected an error for", settings)
+		}
+	}
+	if err := st.getAPI("/daemon/settings", &ds); err != nil {
+		t.Fatal(err)
+	}
+	if ds != before {
+		t.Fatalf("rejected settings changed the server: %+v", ds)
+	}
+
+	// Giving a setting that cannot be changed live its current value is
+	// allowed.
+	if err := st.postSettings(`{"debug": false, "updaterepo": "` + defaultUpdateRepo + `", "updatekeyfile": ""}`); err != nil {
+		t.Fatal(err)
+	}
+	for _, settings := range []string{
+		`{"basepath": "/sia"}`,
+		`{"updaterepo": "fork/Sia"}`,
+		`{"updatekeyfile": "keys.pem"}`,
+	} {
+		err = st.postSettings(settings)
+		if err == nil || !strings.Contains(err.Error(), "restart") {
+			t.Fatal("expected an error asking for a restart, got", err)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package api
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/NebulousLabs/Sia/build"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/kardianos/osext"
+)
+
+type (
+	// DaemonBinaryVerification compares an installed binary with the same
+	// binary in the release. Expected and Actual are hex SHA256 checksums, and
+	// Actual is empty if the installed binary could not be read.
+	DaemonBinaryVerification struct {
+		Binary   string `json:"binary"`
+		Path     string `json:"path"`
+		Expected string `json:"expected"`
+		Actual   string `json:"actual"`
+		Match    bool   `json:"match"`
+		Error    string `json:"error,omitempty"`
+	}
+
+	// DaemonVerifyGET reports whether the installed siad and siac binaries
+	// match the release of the running version.
+	DaemonVerifyGET struct {
+		Version  string                     `json:"version"`
+		Verified bool                       `json:"verified"`
+		Binaries []DaemonBinaryVerification `json:"binaries"`
+	}
+)
+
+// hashFile returns the hex SHA256 checksum of everything read from r.
+func hashFile(r io.Reader) (string, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// compareBinaries compares the siad and siac binaries in a release archive
+// with the binaries of the same name in folder.
+func compareBinaries(z *zip.Reader, folder string) []DaemonBinaryVerification {
+	var vs []DaemonBinaryVerification
+	for _, binary := range []string{"siad", "siac"} {
+		v := DaemonBinaryVerification{Binary: binary}
+		var zf *zip.File
+		for _, f := range z.File {
+			if base := path.Base(f.Name); base == binary || base == binary+".exe" {
+				zf = f
+				break
+			}
+		}
+		if zf == nil {
+			v.Error = "could not find " + binary + " in the release archive"
+			vs = append(vs, v)
+			continue
+		}
+		v.Path = filepath.Join(folder, path.Base(zf.Name))
+
+		rc, err := zf.Open()
+		if err == nil {
+			v.Expected, err = hashFile(rc)
+			rc.Close()
+		}
+		if err != nil {
+			v.Error = "could not read " + binary + " from the release archive: " + err.Error()
+			vs = append(vs, v)
+			continue
+		}
+		f, err := os.Open(v.Path)
+		if err == nil {
+			v.Actual, err = hashFile(f)
+			f.Close()
+		}
+		if err != nil {
+			v.Error = err.Error()
+			vs = append(vs, v)
+			continue
+		}
+		v.Match = v.Actual == v.Expected
+		vs = append(vs, v)
+	}
+	return vs
+}
+
+// daemonVerifyHandler handles the API call that checks the installed binaries
+// against the release of the running version. The release archive for this
+// platform is downloaded and checked against the SHA256SUMS file of the
+// release, and the binaries in it are compared with the installed ones.
+func (srv *Server) daemonVerifyHandler(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	src := srv.releaseSource()
+	cancel, done := srv.updateCheckCancel(w)
+	release, err := src.fetchRelease("v"+build.Version, cancel)
+	done()
+	if err == errUnknownRelease {
+		writeError(w, Error{"no release exists for version " + build.Version + "; development builds cannot be verified"}, http.StatusBadRequest)
+		return
+	} else if err != nil {
+		writeError(w, Error{"Failed to fetch release: " + err.Error()}, http.StatusInternalServerError)
+		return
+	}
+	sums, err := src.fetchReleaseChecksums(release)
+	if err != nil {
+		writeError(w, Error{"Failed to fetch release checksums: " + err.Error()}, http.StatusInternalServerError)
+		return
+	} else if sums == nil {
+		writeError(w, Error{"release " + release.TagName + " does not publish a SHA256SUMS file"}, http.StatusBadRequest)
+		return
+	}
+	releaseName, downloadURL, err := src.releaseArchive(release)
+	if err != nil {
+		writeError(w, Error{err.Error()}, http.StatusBadRequest)
+		return
+	}
+	content, err := downloadRelease(downloadURL, new(updateProgress))
+	if err != nil {
+		writeError(w, Error{"Failed to download release: " + err.Error()}, http.StatusInternalServerError)
+		return
+	}
+	if err := verifyChecksum(content, sums, releaseName); err != nil {
+		writeError(w, Error{err.Error()}, http.StatusInternalServerError)
+		return
+	}
+	r := bytes.NewReader(content)
+	z, err := zip.NewReader(r, r.Size())
+	if err != nil {
+		writeError(w, Error{"Failed to read release archive: " + err.Error()}, http.StatusInternalServerError)
+		return
+	}
+	folder, err := osext.ExecutableFolder()
+	if err != nil {
+		writeError(w, Error{"Failed to locate binaries: " + err.Error()}, http.StatusInternalServerError)
+		return
+	}
+
+	dv := DaemonVerifyGET{
+		Version:  build.Version,
+		Verified: true,
+		Binaries: compareBinaries(z, folder),
+	}
+	for _, v := range dv.Binaries {
+		dv.Verified = dv.Verified && v.Match
+	}
+	writeJSON(w, dv)
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NebulousLabs/Sia/build"
+)
+
+// TestCompareBinaries checks that installed binaries are compared with the
+// binaries in a release archive.
+func TestCompareBinaries(t *testing.T) {
+	dir := build.TempDir("api", "TestCompareBinaries")
+	os.RemoveAll(dir)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	// Build a release archive holding siad and siac.
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range []string{"Sia-v1.0.0-linux-amd64/siad", "Sia-v1.0.0-linux-amd64/siac"} {
+		f, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte(filepath.Base(name) + " binary")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	z, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Install a matching siad and a different siac.
+	if err := ioutil.WriteFile(filepath.Join(dir, "siad"), []byte("siad binary"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "siac"), []byte("dev build"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	vs := compareBinaries(z, dir)
+	if len(vs) != 2 {
+		t.Fatal("expected 2 binaries, got", len(vs))
+	}
+	siadSum := sha256.Sum256([]byte("siad binary"))
+	if v := vs[0]; !v.Match || v.Error != "" || v.Expected != hex.EncodeToString(siadSum[:]) || v.Path != filepath.Join(dir, "siad") {
+		t.Fatalf("unexpected result for siad: %+v", v)
+	}
+	devSum := sha256.Sum256([]byte("dev build"))
+	if v := vs[1]; v.Match || v.Error != "" || v.Actual != hex.EncodeToString(devSum[:]) {
+		t.Fatalf("unexpected result for siac: %+v", v)
+	}
+
+	// A missing binary is reported as an error.
+	if err := os.Remove(filepath.Join(dir, "siac")); err != nil {
+		t.Fatal(err)
+	}
+	vs = compareBinaries(z, dir)
+	if v := vs[1]; v.Match || v.Error == "" || v.Actual != "" {
+		t.Fatalf("unexpected result for missing siac: %+v", v)
+	}
+}
@@ -0,0 +1,139 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net/http"
+	"runtime"
+	"runtime/pprof"
+	"strings"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	// defaultCPUProfileSeconds is the duration of a CPU profile if none is
+	// requested.
+	defaultCPUProfileSeconds = 30
+
+	// maxCPUProfileSeconds is the longest CPU profile that can be requested.
+	maxCPUProfileSeconds = 300
+)
+
+// DaemonStackGET summarizes the goroutines of the daemon. States maps each
+// goroutine state, such as "running" or "chan receive", to the number of
+// goroutines in that state.
+type DaemonStackGET struct {
+	Goroutines int            `json:"goroutines"`
+	States     map[string]int `json:"states"`
+}
+
+// EnableDebug registers the /daemon/debug routes, which expose goroutine, heap,
+// and CPU profiles of the daemon. The routes are disabled by default because
+// the profiles reveal the internals of the daemon, and taking them briefly
+// slows it down. EnableDebug must be called before Serve.
+func (srv *Server) EnableDebug() {
+	srv.debug = true
+	srv.initAPI(srv.requiredPassword)
+}
+
+// daemonDebugGoroutinesHandler handles the API call that dumps the stack of
+// every goroutine.
+func (srv *Server) daemonDebugGoroutinesHandler(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	pprof.Lookup("goroutine").WriteTo(w, 2)
+}
+
+// daemonDebugHeapHandler handles the API call that returns a heap profile.
+func (srv *Server) daemonDebugHeapHandler(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	// Run a garbage collection so that the profile reflects live objects.
+	runtime.GC()
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", `attachment; filename="heap.pprof"`)
+	pprof.WriteHeapProfile(w)
+}
+
+// daemonDebugCPUHandler handles the API call that profiles the CPU for the
+// requested number of seconds and returns the profile.
+func (srv *Server) daemonDebugCPUHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	seconds := uint64(defaultCPUProfileSeconds)
+	if req.FormValue("seconds") != "" {
+		_, err := fmt.Sscan(req.FormValue("seconds"), &seconds)
+		if err != nil {
+			writeError(w, Error{"unable to parse seconds: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	if seconds == 0 || seconds > maxCPUProfileSeconds {
+		writeError(w, Error{fmt.Sprintf("seconds must be between 1 and %v", maxCPUProfileSeconds)}, http.StatusBadRequest)
+		return
+	}
+
+	// Only one CPU profile can be taken at a time, so the profile has to be
+	// started before any of the response is written.
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", `attachment; filename="cpu.pprof"`)
+	if err := pprof.StartCPUProfile(w); err != nil {
+		w.Header().Del("Content-Disposition")
+		writeError(w, Error{"unable to start CPU profile: " + err.Error()}, http.StatusConflict)
+		return
+	}
+	time.Sleep(time.Duration(seconds) * time.Second)
+	pprof.StopCPUProfile()
+}
+
+// stackDump returns the stack of every goroutine, as printed by an
+// unrecovered panic.
+func stackDump() []byte {
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n]
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
+
+// goroutineStates counts the goroutines in a stack dump by state. Each
+// goroutine starts with a header such as
+//
+//	goroutine 12 [chan receive, 5 minutes]:
+func goroutineStates(dump []byte) DaemonStackGET {
+	ds := DaemonStackGET{States: make(map[string]int)}
+	s := bufio.NewScanner(bytes.NewReader(dump))
+	for s.Scan() {
+		line := s.Text()
+		if !strings.HasPrefix(line, "goroutine ") {
+			continue
+		}
+		start, end := strings.Index(line, "["), strings.LastIndex(line, "]")
+		if start < 0 || end < start {
+			continue
+		}
+		state := line[start+1 : end]
+		if i := strings.Index(state, ","); i >= 0 {
+			state = state[:i]
+		}
+		ds.Goroutines++
+		ds.States[state]++
+	}
+	return ds
+}
+
+// daemonStackHandler handles the API call that dumps the stack of every
+// goroutine. Unlike the /daemon/debug routes, it is always served.
+func (srv *Server) daemonStackHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	dump := stackDump()
+	switch req.FormValue("format") {
+	case "", "text":
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write(dump)
+	case "json":
+		writeJSON(w, goroutineStates(dump))
+	default:
+		writeError(w, Error{"format must be 'text' or 'json'"}, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,35 @@
+package api
+
+import (
+	"testing"
+)
+
+// TestGoroutineStates checks that goroutines in a stack dump are counted by
+// state.
+func TestGoroutineStates(t *testing.T) {
+	dump := []byte(`goroutine 1 [running]:
+main.main()
+	/tmp/main.go:5 +0x20
+
+goroutine 6 [chan receive, 5 minutes]:
+main.worker()
+	/tmp/main.go:9 +0x40
+
+goroutine 7 [chan receive]:
+main.worker()
+	/tmp/main.go:9 +0x40
+`)
+	ds := goroutineStates(dump)
+	if ds.Goroutines != 3 || ds.States["running"] != 1 || ds.States["chan receive"] != 2 || len(ds.States) != 2 {
+		t.Fatalf("unexpected states: %+v", ds)
+	}
+
+	// a live dump includes this goroutine, which is running
+	block := make(chan struct{})
+	defer close(block)
+	go func() { <-block }()
+	ds = goroutineStates(stackDump())
+	if ds.Goroutines < 2 || ds.States["running"] < 1 {
+		t.Fatalf("unexpected states: %+v", ds)
+	}
+}
@@ -1,7 +1,9 @@
 package api
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 
 	"github.com/NebulousLabs/Sia/build"
@@ -12,6 +14,14 @@ import (
 	"github.com/julienschmidt/httprouter"
 )
 
+// maxExplorerBalancesBatch is the largest number of addresses that can be
+// queried in a single call to /explorer/balances.
+const maxExplorerBalancesBatch = 1000
+
+// defaultBlockTimesRange is the number of blocks that /explorer/blocktimes
+// covers when 'from' is not given, about one day at the target block time.
+const defaultBlockTimesRange = 144
+
 type (
 	// ExplorerBlock is a block with some extra information such as the id and
 	// height. This information is provided for programs that may not be
@@ -53,6 +63,12 @@ type (
 		modules.BlockFacts
 	}
 
+	// ExplorerBlockTimesGET contains statistics about the time between
+	// consecutive blocks in a range of heights.
+	ExplorerBlockTimesGET struct {
+		modules.BlockTimeStats
+	}
+
 	// ExplorerBlockGET is the object returned by a GET request to
 	// /explorer/block.
 	ExplorerBlockGET struct {
@@ -74,6 +90,21 @@ type (
 		Transaction  ExplorerTransaction   `json:"transaction"`
 		Transactions []ExplorerTransaction `json:"transactions"`
 	}
+
+	// ExplorerAddressBalance is the balance of a single address queried
+	// through /explorer/balances. If the address could not be parsed, Error
+	// is set and the balance fields are empty.
+	ExplorerAddressBalance struct {
+		Address string `json:"address"`
+		Error   string `json:"error,omitempty"`
+		modules.SiacoinBalance
+	}
+
+	// ExplorerBalancesPOST is the object returned as a response to a POST
+	// request to /explorer/balances.
+	ExplorerBalancesPOST struct {
+		Balances []ExplorerAddressBalance `json:"balances"`
+	}
 )
 
 // buildExplorerTransaction takes a transaction and the height + id of the
@@ -358,3 +389,70 @@ func (srv *Server) explorerHandler(w http.ResponseWriter, req *http.Request, _ h
 		BlockFacts: facts,
 	})
 }
+
+// explorerBlockTimesHandler handles GET requests to /explorer/blocktimes.
+func (srv *Server) explorerBlockTimesHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	to := srv.explorer.LatestBlockFacts().Height
+	if req.FormValue("to") != "" {
+		if _, err := fmt.Sscan(req.FormValue("to"), &to); err != nil {
+			writeError(w, Error{"unable to parse to: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	var from types.BlockHeight
+	if to > types.BlockHeight(defaultBlockTimesRange) {
+		from = to - defaultBlockTimesRange
+	}
+	if req.FormValue("from") != "" {
+		if _, err := fmt.Sscan(req.FormValue("from"), &from); err != nil {
+			writeError(w, Error{"unable to parse from: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	stats, err := srv.explorer.BlockTimes(from, to)
+	if err != nil {
+		writeError(w, Error{"error after call to /explorer/blocktimes: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, ExplorerBlockTimesGET{stats})
+}
+
+// explorerBalancesHandler handles POST requests to /explorer/balances. The
+// request body is a JSON array of addresses. Malformed addresses are reported
+// individually instead of failing the whole request.
+func (srv *Server) explorerBalancesHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	var addrs []string
+	// Each address is 76 hex characters plus quotes and a separator.
+	body := io.LimitReader(req.Body, (maxExplorerBalancesBatch+1)*80)
+	if err := json.NewDecoder(body).Decode(&addrs); err != nil {
+		writeError(w, Error{"request body must be a JSON array of addresses: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	if len(addrs) > maxExplorerBalancesBatch {
+		writeError(w, Error{fmt.Sprintf("at most %v addresses can be queried at once", maxExplorerBalancesBatch)}, http.StatusBadRequest)
+		return
+	}
+
+	results := make([]ExplorerAddressBalance, len(addrs))
+	var uhs []types.UnlockHash
+	var indices []int
+	for i, addr := range addrs {
+		results[i].Address = addr
+		uh, err := scanAddress(addr)
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		uhs = append(uhs, uh)
+		indices = append(indices, i)
+	}
+	balances, err := srv.explorer.SiacoinBalances(uhs)
+	if err != nil {
+		writeError(w, Error{"error after call to /explorer/balances: " + err.Error()}, http.StatusInternalServerError)
+		return
+	}
+	for j, i := range indices {
+		results[i].SiacoinBalance = balances[j]
+	}
+	writeJSON(w, ExplorerBalancesPOST{Balances: results})
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/NebulousLabs/Sia/modules"
@@ -14,6 +15,27 @@ type GatewayGET struct {
 	Peers      []modules.Peer     `json:"peers"`
 }
 
+// GatewayExternalAddressGET contains the fields returned by a GET call to
+// "/gateway/externaladdress".
+type GatewayExternalAddressGET struct {
+	NetAddress    modules.NetAddress `json:"netaddress"`
+	Confirmations int                `json:"confirmations"`
+	PortForwarded bool               `json:"portforwarded"`
+	Reachable     bool               `json:"reachable"`
+}
+
+// GatewayRPCLimitsGET contains the fields returned by a GET call to
+// "/gateway/rpclimits".
+type GatewayRPCLimitsGET struct {
+	modules.GatewayRPCLimits
+}
+
+// GatewaySettingsGET contains the fields returned by a GET call to
+// "/gateway/settings".
+type GatewaySettingsGET struct {
+	modules.GatewaySettings
+}
+
 // gatewayHandler handles the API call asking for the gatway status.
 func (srv *Server) gatewayHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 	peers := srv.gateway.Peers()
@@ -26,6 +48,18 @@ func (srv *Server) gatewayHandler(w http.ResponseWriter, req *http.Request, _ ht
 	writeJSON(w, GatewayGET{srv.gateway.Address(), peers})
 }
 
+// gatewayExternalAddressHandler handles the API call asking for the address
+// that the rest of the network sees the gateway at.
+func (srv *Server) gatewayExternalAddressHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	ea := srv.gateway.ExternalAddress()
+	writeJSON(w, GatewayExternalAddressGET{
+		NetAddress:    ea.NetAddress,
+		Confirmations: ea.Confirmations,
+		PortForwarded: ea.PortForwarded,
+		Reachable:     ea.Reachable,
+	})
+}
+
 // gatewayConnectHandler handles the API call to add a peer to the gateway.
 func (srv *Server) gatewayConnectHandler(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 	addr := modules.NetAddress(ps.ByName("netaddress"))
@@ -49,3 +83,69 @@ func (srv *Server) gatewayDisconnectHandler(w http.ResponseWriter, req *http.Req
 
 	writeSuccess(w)
 }
+
+// gatewayRPCLimitsHandlerGET handles the API call asking for the limits on
+// incoming RPCs and the peers that have violated them.
+func (srv *Server) gatewayRPCLimitsHandlerGET(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	writeJSON(w, GatewayRPCLimitsGET{srv.gateway.RPCLimits()})
+}
+
+// gatewayRPCLimitsHandlerPOST handles the API call to change the limits on
+// incoming RPCs.
+func (srv *Server) gatewayRPCLimitsHandlerPOST(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	var disconnectAfter uint64
+	if req.FormValue("disconnectafter") != "" {
+		if _, err := fmt.Sscan(req.FormValue("disconnectafter"), &disconnectAfter); err != nil {
+			writeError(w, Error{"unable to parse disconnectafter: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	limit := modules.RPCLimit{RPC: req.FormValue("rpc")}
+	if req.FormValue("maxsize") != "" {
+		if _, err := fmt.Sscan(req.FormValue("maxsize"), &limit.MaxSize); err != nil {
+			writeError(w, Error{"unable to parse maxsize: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	if req.FormValue("callsperminute") != "" {
+		if _, err := fmt.Sscan(req.FormValue("callsperminute"), &limit.CallsPerMinute); err != nil {
+			writeError(w, Error{"unable to parse callsperminute: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	if limit.RPC == "" && (req.FormValue("maxsize") != "" || req.FormValue("callsperminute") != "") {
+		writeError(w, Error{"rpc must be specified to set its limits"}, http.StatusBadRequest)
+		return
+	}
+
+	if limit.RPC != "" {
+		srv.gateway.SetRPCLimit(limit)
+	}
+	if req.FormValue("disconnectafter") != "" {
+		srv.gateway.SetRPCDisconnectThreshold(disconnectAfter)
+	}
+	writeSuccess(w)
+}
+
+// gatewaySettingsHandlerGET handles the API call asking for the settings of
+// the gateway.
+func (srv *Server) gatewaySettingsHandlerGET(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	writeJSON(w, GatewaySettingsGET{srv.gateway.Settings()})
+}
+
+// gatewaySettingsHandlerPOST handles the API call to change the settings of
+// the gateway. Settings that are not provided keep their current values.
+func (srv *Server) gatewaySettingsHandlerPOST(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	settings := srv.gateway.Settings()
+	if req.FormValue("discoveryinterval") != "" {
+		if _, err := fmt.Sscan(req.FormValue("discoveryinterval"), &settings.DiscoveryInterval); err != nil {
+			writeError(w, Error{"unable to parse discoveryinterval: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	if err := srv.gateway.SetSettings(settings); err != nil {
+		writeError(w, Error{err.Error()}, http.StatusBadRequest)
+		return
+	}
+	writeSuccess(w)
+}
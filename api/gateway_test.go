@@ -93,3 +93,28 @@ func TestGatewayPeerDisconnect(t *testing.T) {
 		t.Fatal("/gateway/disconnect did not disconnect from peer", peer.Address())
 	}
 }
+
+// TestGatewayExternalAddress checks that /gateway/externaladdress reports the
+// gateway's address and that a gateway with no peers is unreachable.
+func TestGatewayExternalAddress(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	st, err := createServerTester("TestGatewayExternalAddress")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.server.Close()
+
+	var ea GatewayExternalAddressGET
+	err = st.getAPI("/gateway/externaladdress", &ea)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ea.NetAddress != st.netAddress() {
+		t.Fatalf("expected %v, got %v", st.netAddress(), ea.NetAddress)
+	}
+	if ea.Confirmations != 0 || ea.Reachable {
+		t.Fatal("gateway without peers should be unreachable:", ea)
+	}
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// gzipMinSize is the smallest JSON response, in bytes, that is compressed.
+// Compressing smaller responses saves little and costs a gzip header.
+const gzipMinSize = 1024
+
+// A gzipResponseWriter marks the response to a request whose client accepts
+// gzip, so that writeJSON knows it can compress the response.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+}
+
+// CloseNotify implements http.CloseNotifier. The returned channel never
+// receives if the underlying ResponseWriter cannot report disconnects.
+func (w gzipResponseWriter) CloseNotify() <-chan bool {
+	if cn, ok := w.ResponseWriter.(http.CloseNotifier); ok {
+		return cn.CloseNotify()
+	}
+	return make(chan bool)
+}
+
+// Flush implements http.Flusher.
+func (w gzipResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// acceptsGzip reports whether the Accept-Encoding header of a request allows
+// a gzip encoded response.
+func acceptsGzip(req *http.Request) bool {
+	for _, coding := range strings.Split(req.Header.Get("Accept-Encoding"), ",") {
+		params := strings.Split(coding, ";")
+		if strings.TrimSpace(params[0]) != "gzip" {
+			continue
+		}
+		for _, param := range params[1:] {
+			param = strings.TrimSpace(param)
+			if !strings.HasPrefix(param, "q=") {
+				continue
+			}
+			if q, err := strconv.ParseFloat(param[2:], 64); err == nil && q == 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// allowGzip is middleware that lets writeJSON compress the responses to
+// clients that accept gzip.
+func allowGzip(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if acceptsGzip(req) {
+			w = gzipResponseWriter{w}
+		}
+		h.ServeHTTP(w, req)
+	})
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestAcceptsGzip probes the parsing of the Accept-Encoding header.
+func TestAcceptsGzip(t *testing.T) {
+	tests := []struct {
+		header string
+		gzip   bool
+	}{
+		{"", false},
+		{"gzip", true},
+		{"deflate, gzip;q=0.5", true},
+		{"gzip;q=0", false},
+		{"gzip; q=0.0, deflate", false},
+		{"deflate", false},
+		{"x-gzip", false},
+	}
+	for _, test := range tests {
+		req, err := http.NewRequest("GET", "/", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Accept-Encoding", test.header)
+		if acceptsGzip(req) != test.gzip {
+			t.Errorf("expected %v for %q", test.gzip, test.header)
+		}
+	}
+}
+
+// TestWriteJSONGzip checks that writeJSON compresses large responses for
+// clients that accept gzip, and leaves everything else uncompressed.
+func TestWriteJSONGzip(t *testing.T) {
+	large := strings.Repeat("siacoin", gzipMinSize)
+	tests := []struct {
+		obj        string
+		acceptGzip bool
+		compressed bool
+	}{
+		{large, true, true},
+		{large, false, false},
+		{"small", true, false},
+	}
+	for _, test := range tests {
+		obj := test.obj
+		h := allowGzip(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			writeJSON(w, obj)
+		}))
+		req, err := http.NewRequest("GET", "/", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if test.acceptGzip {
+			req.Header.Set("Accept-Encoding", "gzip")
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		if rec.Header().Get("Vary") != "Accept-Encoding" {
+			t.Error("response does not vary by Accept-Encoding")
+		}
+		var decoded string
+		if test.compressed {
+			if rec.Header().Get("Content-Encoding") != "gzip" {
+				t.Fatal("expected a gzip response")
+			}
+			gz, err := gzip.NewReader(rec.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = json.NewDecoder(gz).Decode(&decoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+		} else {
+			if rec.Header().Get("Content-Encoding") != "" {
+				t.Fatal("expected an uncompressed response")
+			}
+			if err := json.NewDecoder(rec.Body).Decode(&decoded); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if decoded != obj {
+			t.Fatal("response did not round trip")
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
 
 	"github.com/julienschmidt/httprouter"
 )
@@ -27,11 +28,65 @@ type (
 		NetworkMetrics   modules.HostNetworkMetrics   `json:"networkmetrics"`
 	}
 
+	// HostDecommissionStatusGET contains the progress of decommissioning the
+	// host.
+	HostDecommissionStatusGET struct {
+		modules.HostDecommissionStatus
+	}
+
+	// HostPricingGET contains the prices that the host currently charges and
+	// the most recent attempt by automatic pricing to follow the market.
+	HostPricingGET struct {
+		AutoPricing            bool                         `json:"autopricing"`
+		StoragePrice           types.Currency               `json:"storageprice"`
+		UploadBandwidthPrice   types.Currency               `json:"uploadbandwidthprice"`
+		DownloadBandwidthPrice types.Currency               `json:"downloadbandwidthprice"`
+		LastAdjustment         *modules.HostPriceAdjustment `json:"lastadjustment"`
+	}
+
+	// HostProofsGET contains the number of storage proofs that the host has
+	// precomputed and the number that are outstanding.
+	HostProofsGET struct {
+		modules.HostProofStatus
+	}
+
+	// HostCacheGET contains the size and hit rate of the host's cache of
+	// recently read sectors.
+	HostCacheGET struct {
+		modules.StorageSectorCacheStats
+	}
+
+	// HostRejectionsGET contains the file contract proposals that were recently
+	// rejected by the host.
+	HostRejectionsGET struct {
+		Rejections []modules.HostContractRejection `json:"rejections"`
+	}
+
+	// HostRPCMetricsGET contains the latency histogram of each type of RPC
+	// handled by the host.
+	HostRPCMetricsGET struct {
+		RPCs []modules.HostRPCLatency `json:"rpcs"`
+	}
+
+	// HostSessionsGET contains the number of upload and download RPCs that
+	// each renter has open with the host.
+	HostSessionsGET struct {
+		Sessions []modules.HostRenterSessions `json:"sessions"`
+	}
+
+	// HostSectorsOrphanedGET contains the sectors that are not contained in
+	// any unresolved storage obligation.
+	HostSectorsOrphanedGET struct {
+		Sectors []modules.StorageSectorUsage `json:"sectors"`
+		Pagination
+	}
+
 	// StorageGET contains the information that is returned after a GET request
 	// to /host/storage - a bunch of information about the status of storage
 	// management on the host.
 	StorageGET struct {
-		Folders []modules.StorageFolderMetadata `json:"folders"`
+		Folders    []modules.StorageFolderMetadata `json:"folders"`
+		TierPolicy modules.StorageTierPolicy       `json:"tierpolicy"`
 	}
 )
 
@@ -83,6 +138,24 @@ func (srv *Server) hostHandlerPOST(w http.ResponseWriter, req *http.Request, _ h
 		"mindownloadbandwidthprice": &settings.MinDownloadBandwidthPrice,
 		"minstorageprice":           &settings.MinStoragePrice,
 		"minuploadbandwidthprice":   &settings.MinUploadBandwidthPrice,
+
+		"minduration":       &settings.MinDuration,
+		"mincontractsize":   &settings.MinContractSize,
+		"mincontractpayout": &settings.MinContractPayout,
+
+		"proofprecomputeblocks": &settings.ProofPrecomputeBlocks,
+
+		"maxrenteruploads":   &settings.MaxRenterUploads,
+		"maxrenterdownloads": &settings.MaxRenterDownloads,
+
+		"autopricing":                   &settings.AutoPricing,
+		"autopriceoffset":               &settings.AutoPriceOffset,
+		"autostoragepricemin":           &settings.AutoStoragePriceMin,
+		"autostoragepricemax":           &settings.AutoStoragePriceMax,
+		"autouploadbandwidthpricemin":   &settings.AutoUploadBandwidthPriceMin,
+		"autouploadbandwidthpricemax":   &settings.AutoUploadBandwidthPriceMax,
+		"autodownloadbandwidthpricemin": &settings.AutoDownloadBandwidthPriceMin,
+		"autodownloadbandwidthpricemax": &settings.AutoDownloadBandwidthPriceMax,
 	}
 
 	// Iterate through the query string and replace any fields that have been
@@ -104,6 +177,89 @@ func (srv *Server) hostHandlerPOST(w http.ResponseWriter, req *http.Request, _ h
 	writeSuccess(w)
 }
 
+// hostPricingHandler handles GET requests to the /host/pricing API endpoint,
+// returning the host's prices and the outcome of automatic pricing.
+func (srv *Server) hostPricingHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	is := srv.host.InternalSettings()
+	hp := HostPricingGET{
+		AutoPricing:            is.AutoPricing,
+		StoragePrice:           is.MinStoragePrice,
+		UploadBandwidthPrice:   is.MinUploadBandwidthPrice,
+		DownloadBandwidthPrice: is.MinDownloadBandwidthPrice,
+	}
+	if pa, ok := srv.host.PriceAdjustment(); ok {
+		hp.LastAdjustment = &pa
+	}
+	writeJSON(w, hp)
+}
+
+// hostProofsHandler handles GET requests to the /host/proofs API endpoint,
+// returning the status of the host's storage proofs.
+func (srv *Server) hostProofsHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	writeJSON(w, HostProofsGET{srv.host.ProofStatus()})
+}
+
+// hostRPCMetricsHandler handles GET requests to the /host/rpcmetrics API
+// endpoint, returning how long the host has taken to handle each type of RPC.
+func (srv *Server) hostRPCMetricsHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	writeJSON(w, HostRPCMetricsGET{srv.host.RPCLatencies()})
+}
+
+// hostSessionsHandler handles GET requests to the /host/sessions API endpoint,
+// returning the number of upload and download RPCs that each renter has open.
+func (srv *Server) hostSessionsHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	writeJSON(w, HostSessionsGET{srv.host.RenterSessions()})
+}
+
+// hostSectorsHandler handles GET requests to the /host/sectors API endpoint,
+// returning the reference count and location of a sector.
+func (srv *Server) hostSectorsHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	root, err := scanHash(req.FormValue("root"))
+	if err != nil {
+		writeError(w, Error{"could not read root: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	sector, err := srv.host.Sector(root)
+	if err != nil {
+		writeError(w, Error{err.Error()}, http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, sector)
+}
+
+// hostSectorsOrphanedHandler handles GET requests to the
+// /host/sectors/orphaned API endpoint, returning the sectors that are not
+// contained in any unresolved storage obligation.
+func (srv *Server) hostSectorsOrphanedHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	p, err := srv.scanPage(req)
+	if err != nil {
+		writeError(w, Error{err.Error()}, http.StatusBadRequest)
+		return
+	}
+	sectors, err := srv.host.OrphanedSectors()
+	if err != nil {
+		writeError(w, Error{err.Error()}, http.StatusInternalServerError)
+		return
+	}
+	if sectors == nil {
+		sectors = []modules.StorageSectorUsage{}
+	}
+	start, end, pg := p.bounds(len(sectors))
+	writeJSON(w, HostSectorsOrphanedGET{
+		Sectors:    sectors[start:end],
+		Pagination: pg,
+	})
+}
+
+// hostRejectionsHandler handles GET requests to the /host/rejections API
+// endpoint, returning the file contract proposals that the host recently
+// turned down.
+func (srv *Server) hostRejectionsHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	writeJSON(w, HostRejectionsGET{
+		Rejections: srv.host.ContractRejections(),
+	})
+}
+
 // hostAnnounceHandler handles the API call to get the host to announce itself
 // to the network.
 func (srv *Server) hostAnnounceHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
@@ -120,11 +276,29 @@ func (srv *Server) hostAnnounceHandler(w http.ResponseWriter, req *http.Request,
 	writeSuccess(w)
 }
 
+// hostDecommissionHandler handles the API call to start decommissioning the
+// host.
+func (srv *Server) hostDecommissionHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	err := srv.host.Decommission()
+	if err != nil {
+		writeError(w, Error{err.Error()}, http.StatusBadRequest)
+		return
+	}
+	writeSuccess(w)
+}
+
+// hostDecommissionStatusHandler handles the API call to get the progress of
+// decommissioning the host.
+func (srv *Server) hostDecommissionStatusHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	writeJSON(w, HostDecommissionStatusGET{srv.host.DecommissionStatus()})
+}
+
 // storageHandler returns a bunch of information about storage management on
 // the host.
 func (srv *Server) storageHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 	writeJSON(w, StorageGET{
-		Folders: srv.host.StorageFolders(),
+		Folders:    srv.host.StorageFolders(),
+		TierPolicy: srv.host.TierPolicy(),
 	})
 }
 
@@ -199,6 +373,70 @@ func (srv *Server) storageFoldersRemoveHandler(w http.ResponseWriter, req *http.
 	writeSuccess(w)
 }
 
+// storageFoldersTierHandler sets the tier of a storage folder in the storage
+// manager.
+func (srv *Server) storageFoldersTierHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	folderPath := req.FormValue("path")
+	if folderPath == "" {
+		writeError(w, Error{"path parameter is required"}, http.StatusBadRequest)
+		return
+	}
+
+	storageFolders := srv.host.StorageFolders()
+	folderIndex, err := folderIndex(folderPath, storageFolders)
+	if err != nil {
+		writeError(w, Error{err.Error()}, http.StatusBadRequest)
+		return
+	}
+
+	err = srv.host.SetStorageFolderTier(folderIndex, modules.StorageTier(req.FormValue("tier")))
+	if err != nil {
+		writeError(w, Error{err.Error()}, http.StatusBadRequest)
+		return
+	}
+	writeSuccess(w)
+}
+
+// storageTierPolicyHandler sets the policy for migrating sectors between
+// storage tiers.
+func (srv *Server) storageTierPolicyHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	var policy modules.StorageTierPolicy
+	_, err := fmt.Sscan(req.FormValue("migrateafter"), &policy.MigrateAfter)
+	if err != nil {
+		writeError(w, Error{"unable to parse migrateafter: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	err = srv.host.SetTierPolicy(policy)
+	if err != nil {
+		writeError(w, Error{err.Error()}, http.StatusBadRequest)
+		return
+	}
+	writeSuccess(w)
+}
+
+// hostCacheHandlerGET handles the API call that returns the statistics of the
+// host's sector cache.
+func (srv *Server) hostCacheHandlerGET(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	writeJSON(w, HostCacheGET{srv.host.SectorCacheStats()})
+}
+
+// hostCacheHandlerPOST handles the API call that sets the size of the host's
+// sector cache.
+func (srv *Server) hostCacheHandlerPOST(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	var maxSize uint64
+	_, err := fmt.Sscan(req.FormValue("maxsize"), &maxSize)
+	if err != nil {
+		writeError(w, Error{"unable to parse maxsize: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	err = srv.host.SetSectorCacheSize(maxSize)
+	if err != nil {
+		writeError(w, Error{err.Error()}, http.StatusBadRequest)
+		return
+	}
+	writeSuccess(w)
+}
+
 // storageSectorsDeleteHandler handles the call to delete a sector from the
 // storage manager.
 func (srv *Server) storageSectorsDeleteHandler(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
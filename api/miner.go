@@ -1,9 +1,13 @@
 package api
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/NebulousLabs/Sia/encoding"
+	"github.com/NebulousLabs/Sia/modules"
 	"github.com/NebulousLabs/Sia/types"
 
 	"github.com/julienschmidt/httprouter"
@@ -18,6 +22,12 @@ type (
 		CPUMining        bool `json:"cpumining"`
 		StaleBlocksMined int  `json:"staleblocksmined"`
 	}
+
+	// MinerPayoutSplitGET contains the addresses that the rewards of mined
+	// blocks are split between.
+	MinerPayoutSplitGET struct {
+		PayoutSplit []modules.MinerPayoutSplit `json:"payoutsplit"`
+	}
 )
 
 // minerHandler handles the API call that queries the miner's status.
@@ -71,3 +81,51 @@ func (srv *Server) minerHeaderHandlerPOST(w http.ResponseWriter, req *http.Reque
 	}
 	writeSuccess(w)
 }
+
+// scanPayoutSplit parses a comma-separated list of address:fraction pairs.
+func scanPayoutSplit(s string) ([]modules.MinerPayoutSplit, error) {
+	var split []modules.MinerPayoutSplit
+	if s == "" {
+		return split, nil
+	}
+	for _, pair := range strings.Split(s, ",") {
+		i := strings.LastIndex(pair, ":")
+		if i == -1 {
+			return nil, errors.New("expected address:fraction, got " + pair)
+		}
+		addr, err := scanAddress(pair[:i])
+		if err != nil {
+			return nil, errors.New("could not read address " + pair[:i] + ": " + err.Error())
+		}
+		fraction, err := strconv.ParseFloat(pair[i+1:], 64)
+		if err != nil {
+			return nil, errors.New("could not read fraction " + pair[i+1:] + ": " + err.Error())
+		}
+		split = append(split, modules.MinerPayoutSplit{UnlockHash: addr, Fraction: fraction})
+	}
+	return split, nil
+}
+
+// minerPayoutSplitHandlerGET handles the API call that returns the payout
+// split of the miner.
+func (srv *Server) minerPayoutSplitHandlerGET(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	writeJSON(w, MinerPayoutSplitGET{
+		PayoutSplit: srv.miner.PayoutSplit(),
+	})
+}
+
+// minerPayoutSplitHandlerPOST handles the API call that sets the payout split
+// of the miner.
+func (srv *Server) minerPayoutSplitHandlerPOST(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	split, err := scanPayoutSplit(req.FormValue("payoutsplit"))
+	if err != nil {
+		writeError(w, Error{err.Error()}, http.StatusBadRequest)
+		return
+	}
+	err = srv.miner.SetPayoutSplit(split)
+	if err != nil {
+		writeError(w, Error{err.Error()}, http.StatusBadRequest)
+		return
+	}
+	writeSuccess(w)
+}
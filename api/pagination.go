@@ -0,0 +1,94 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var errBadPageSize = errors.New("default page size cannot be larger than the maximum page size")
+
+// Pagination reports which part of a list is included in a response. It is
+// embedded in the responses of the endpoints that accept 'offset' and 'limit'
+// parameters.
+type Pagination struct {
+	Offset int  `json:"offset"`
+	Total  int  `json:"total"`
+	More   bool `json:"more"`
+}
+
+// A page is a range of a list requested by the 'offset' and 'limit'
+// parameters. A limit of zero returns the rest of the list.
+type page struct {
+	offset int
+	limit  int
+}
+
+// checkPageSizes returns an error if the default and maximum page sizes are
+// not a valid pair.
+func checkPageSizes(defaultSize, maxSize int) error {
+	if defaultSize < 0 || maxSize < 0 {
+		return errors.New("page sizes cannot be negative")
+	}
+	if maxSize != 0 && defaultSize > maxSize {
+		return errBadPageSize
+	}
+	return nil
+}
+
+// SetPagination sets the number of items that list endpoints return when the
+// caller does not give a limit, and the most items they return on any one
+// call. Zero means no limit.
+func (srv *Server) SetPagination(defaultSize, maxSize int) error {
+	if err := checkPageSizes(defaultSize, maxSize); err != nil {
+		return err
+	}
+	srv.mu.Lock()
+	srv.defaultPageSize = defaultSize
+	srv.maxPageSize = maxSize
+	srv.mu.Unlock()
+	return nil
+}
+
+// scanPage reads the 'offset' and 'limit' parameters of a request, applying
+// the default and maximum page sizes of the server.
+func (srv *Server) scanPage(req *http.Request) (page, error) {
+	srv.mu.RLock()
+	defaultSize, maxSize := srv.defaultPageSize, srv.maxPageSize
+	srv.mu.RUnlock()
+	p := page{limit: defaultSize}
+	if req.FormValue("offset") != "" {
+		if _, err := fmt.Sscan(req.FormValue("offset"), &p.offset); err != nil {
+			return page{}, errors.New("unable to parse offset: " + err.Error())
+		}
+	}
+	if req.FormValue("limit") != "" {
+		if _, err := fmt.Sscan(req.FormValue("limit"), &p.limit); err != nil {
+			return page{}, errors.New("unable to parse limit: " + err.Error())
+		}
+	}
+	if p.offset < 0 || p.limit < 0 {
+		return page{}, errors.New("offset and limit cannot be negative")
+	}
+	if maxSize != 0 && (p.limit == 0 || p.limit > maxSize) {
+		p.limit = maxSize
+	}
+	return p, nil
+}
+
+// bounds returns the range [start, end) of a list of n items that is in the
+// page, along with the Pagination describing it.
+func (p page) bounds(n int) (start, end int, pg Pagination) {
+	start, end = p.offset, n
+	if start > n {
+		start = n
+	}
+	if p.limit != 0 && n-start > p.limit {
+		end = start + p.limit
+	}
+	return start, end, Pagination{
+		Offset: start,
+		Total:  n,
+		More:   end < n,
+	}
+}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+	"testing"
+
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// pageRequest returns a request for /renter/files with the given query.
+func pageRequest(t *testing.T, query string) *http.Request {
+	req, err := http.NewRequest("GET", "http://localhost/renter/files"+query, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return req
+}
+
+// TestPagination checks that pages are read from requests, capped by the
+// server's maximum page size, and applied to lists.
+func TestPagination(t *testing.T) {
+	srv := new(Server)
+	if err := srv.SetPagination(20, 10); err != errBadPageSize {
+		t.Fatal("expected errBadPageSize, got", err)
+	}
+	if err := srv.SetPagination(-1, 0); err == nil {
+		t.Fatal("expected an error for a negative page size")
+	}
+	if err := srv.SetPagination(5, 10); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query  string
+		n      int
+		start  int
+		end    int
+		more   bool
+		offset int
+	}{
+		{"", 3, 0, 3, false, 0},           // shorter than the default
+		{"", 8, 0, 5, true, 0},            // default page size
+		{"?offset=5", 8, 5, 8, false, 5},  // last page
+		{"?limit=0", 30, 0, 10, true, 0},  // no limit is capped
+		{"?limit=50", 30, 0, 10, true, 0}, // large limits are capped
+		{"?offset=7&limit=2", 30, 7, 9, true, 7},
+		{"?offset=40", 30, 30, 30, false, 30}, // past the end
+	}
+	for _, test := range tests {
+		p, err := srv.scanPage(pageRequest(t, test.query))
+		if err != nil {
+			t.Fatal(err)
+		}
+		start, end, pg := p.bounds(test.n)
+		if start != test.start || end != test.end || pg.More != test.more || pg.Offset != test.offset || pg.Total != test.n {
+			t.Errorf("%q of %v: got [%v, %v) %+v", test.query, test.n, start, end, pg)
+		}
+	}
+
+	for _, query := range []string{"?offset=-1", "?limit=x"} {
+		if _, err := srv.scanPage(pageRequest(t, query)); err == nil {
+			t.Errorf("%q: expected an error", query)
+		}
+	}
+
+	// without any page sizes, the whole list is returned
+	p, err := new(Server).scanPage(pageRequest(t, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if start, end, pg := p.bounds(1000); start != 0 || end != 1000 || pg.More {
+		t.Errorf("unexpected page: [%v, %v) %+v", start, end, pg)
+	}
+}
+
+// TestPaginatedListOrder checks that lists built from maps are put in a fixed
+// order before they are paginated.
+func TestPaginatedListOrder(t *testing.T) {
+	files := []modules.FileInfo{{SiaPath: "c"}, {SiaPath: "a"}, {SiaPath: "b"}}
+	sort.Sort(sortedFiles{files: files})
+	if files[0].SiaPath != "a" || files[1].SiaPath != "b" || files[2].SiaPath != "c" {
+		t.Error("files without a sort were not ordered by siapath:", files)
+	}
+
+	contracts := []modules.RenterContract{{ID: types.FileContractID{3}}, {ID: types.FileContractID{1}}, {ID: types.FileContractID{2}}}
+	sort.Sort(contractsByID(contracts))
+	for i, c := range contracts {
+		if c.ID != (types.FileContractID{byte(i + 1)}) {
+			t.Fatal("contracts were not ordered by id:", contracts)
+		}
+	}
+
+	wcs := []WalletContract{{ID: types.FileContractID{2}}, {ID: types.FileContractID{1}, Role: "renter"}, {ID: types.FileContractID{1}, Role: "host"}}
+	sort.Sort(walletContractsByID(wcs))
+	if wcs[0].Role != "host" || wcs[1].Role != "renter" || wcs[2].ID != (types.FileContractID{2}) {
+		t.Error("wallet contracts were not ordered by id and role:", wcs)
+	}
+}
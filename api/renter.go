@@ -1,12 +1,18 @@
 package api
 
 import (
+	"bytes"
+	"encoding/base64"
 	"fmt"
 	"net/http"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/NebulousLabs/Sia/build"
+	"github.com/NebulousLabs/Sia/crypto"
 	"github.com/NebulousLabs/Sia/modules"
 	"github.com/NebulousLabs/Sia/types"
 
@@ -27,8 +33,60 @@ var (
 		}
 		panic("unrecognized release constant in api")
 	}()
+
+	// fileSorters are the orderings accepted by /renter/files. Each one
+	// sorts files in ascending order.
+	fileSorters = map[string]func(a, b modules.FileInfo) bool{
+		"size":       func(a, b modules.FileInfo) bool { return a.Filesize < b.Filesize },
+		"cost":       func(a, b modules.FileInfo) bool { return a.Cost.Cmp(b.Cost) < 0 },
+		"redundancy": func(a, b modules.FileInfo) bool { return a.Redundancy < b.Redundancy },
+		"uploadtime": func(a, b modules.FileInfo) bool { return a.UploadTime.Before(b.UploadTime) },
+	}
 )
 
+// sortedFiles sorts files using one of the fileSorters. Files that compare
+// equal, or all files if less is nil, are sorted by siapath.
+type sortedFiles struct {
+	files []modules.FileInfo
+	less  func(a, b modules.FileInfo) bool
+}
+
+func (sf sortedFiles) Len() int      { return len(sf.files) }
+func (sf sortedFiles) Swap(i, j int) { sf.files[i], sf.files[j] = sf.files[j], sf.files[i] }
+func (sf sortedFiles) Less(i, j int) bool {
+	a, b := sf.files[i], sf.files[j]
+	if sf.less != nil {
+		if sf.less(a, b) {
+			return true
+		} else if sf.less(b, a) {
+			return false
+		}
+	}
+	return a.SiaPath < b.SiaPath
+}
+
+// contractsByID sorts the renter's contracts by id, so that the pages of a
+// list of contracts are consistent between calls.
+type contractsByID []modules.RenterContract
+
+func (c contractsByID) Len() int           { return len(c) }
+func (c contractsByID) Less(i, j int) bool { return bytes.Compare(c[i].ID[:], c[j].ID[:]) < 0 }
+func (c contractsByID) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
+
+// expiredContractsByID sorts the renter's expired contracts by id.
+type expiredContractsByID []modules.RenterExpiredContract
+
+func (c expiredContractsByID) Len() int           { return len(c) }
+func (c expiredContractsByID) Less(i, j int) bool { return bytes.Compare(c[i].ID[:], c[j].ID[:]) < 0 }
+func (c expiredContractsByID) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
+
+// hostsByAddress sorts hosts by their network address.
+type hostsByAddress []modules.HostDBEntry
+
+func (h hostsByAddress) Len() int           { return len(h) }
+func (h hostsByAddress) Less(i, j int) bool { return h[i].NetAddress < h[j].NetAddress }
+func (h hostsByAddress) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
 type (
 	// RenterGET contains various renter metrics.
 	RenterGET struct {
@@ -36,28 +94,91 @@ type (
 		FinancialMetrics modules.RenterFinancialMetrics `json:"financialmetrics"`
 	}
 
+	// RenterAccessLog contains the downloads recorded in the renter's access
+	// log.
+	RenterAccessLog struct {
+		Entries []modules.DownloadAccess `json:"entries"`
+	}
+
 	// RenterContract represents a contract formed by the renter.
 	RenterContract struct {
 		EndHeight   types.BlockHeight    `json:"endheight"`
 		ID          types.FileContractID `json:"id"`
 		NetAddress  modules.NetAddress   `json:"netaddress"`
 		RenterFunds types.Currency       `json:"renterfunds"`
+		Retired     bool                 `json:"retired"`
 		Size        uint64               `json:"size"`
 	}
 
+	// RenterContractGET contains the latest revision of a contract formed by
+	// the renter.
+	RenterContractGET struct {
+		ID                 types.FileContractID  `json:"id"`
+		NetAddress         modules.NetAddress    `json:"netaddress"`
+		HostPublicKey      types.SiaPublicKey    `json:"hostpublickey"`
+		RevisionNumber     uint64                `json:"revisionnumber"`
+		FileSize           uint64                `json:"filesize"`
+		FileMerkleRoot     crypto.Hash           `json:"filemerkleroot"`
+		WindowStart        types.BlockHeight     `json:"windowstart"`
+		WindowEnd          types.BlockHeight     `json:"windowend"`
+		RenterFunds        types.Currency        `json:"renterfunds"`
+		ValidProofOutputs  []types.SiacoinOutput `json:"validproofoutputs"`
+		MissedProofOutputs []types.SiacoinOutput `json:"missedproofoutputs"`
+		Retired            bool                  `json:"retired"`
+	}
+
+	// RenterHostsUsageGET lists how much of the renter's data is stored on
+	// each host.
+	RenterHostsUsageGET struct {
+		Hosts []modules.RenterHostUsage `json:"hosts"`
+	}
+
+	// RenterMigrationGET contains the progress of the renter's most recent
+	// host migration.
+	RenterMigrationGET struct {
+		modules.RenterMigration
+	}
+
+	// RenterSimulateFailurePOST lists the files that would be lost if a set
+	// of hosts failed.
+	RenterSimulateFailurePOST struct {
+		modules.RenterFailureSimulation
+	}
+
 	// RenterContracts contains the renter's contracts.
 	RenterContracts struct {
 		Contracts []RenterContract `json:"contracts"`
+		Pagination
+	}
+
+	// RenterExpiredContractsGET lists the renter's expired contracts.
+	RenterExpiredContractsGET struct {
+		Contracts []modules.RenterExpiredContract `json:"contracts"`
+		Pagination
+	}
+
+	// RenterExpiredContractsClearPOST lists the expired contracts that were
+	// cleared.
+	RenterExpiredContractsClearPOST struct {
+		Cleared []types.FileContractID `json:"cleared"`
 	}
 
 	// DownloadQueue contains the renter's download queue.
 	RenterDownloadQueue struct {
 		Downloads []modules.DownloadInfo `json:"downloads"`
+		Pagination
+	}
+
+	// RenterDownloadCostGET contains the estimated cost of downloading a
+	// file, or a range of a file.
+	RenterDownloadCostGET struct {
+		Cost types.Currency `json:"cost"`
 	}
 
 	// RenterFiles lists the files known to the renter.
 	RenterFiles struct {
 		Files []modules.FileInfo `json:"files"`
+		Pagination
 	}
 
 	// RenterLoad lists files that were loaded into the renter.
@@ -65,19 +186,44 @@ type (
 		FilesAdded []string `json:"filesadded"`
 	}
 
+	// RenterRedundancyPOST describes the work caused by changing the
+	// redundancy of a file.
+	RenterRedundancyPOST struct {
+		modules.RenterRedundancyChange
+	}
+
+	// RenterVerifyPOST contains the result of downloading a file in full to
+	// confirm that it can be recovered.
+	RenterVerifyPOST struct {
+		modules.FileVerification
+	}
+
 	// RenterShareASCII contains an ASCII-encoded .sia file.
 	RenterShareASCII struct {
 		ASCIIsia string `json:"asciisia"`
 	}
 
+	// RenterUploadEstimatePOST contains the projected duration of an upload.
+	RenterUploadEstimatePOST struct {
+		modules.RenterUploadEstimate
+	}
+
+	// RenterWorkersGET contains the limits of the renter's worker pool and
+	// the state of its workers.
+	RenterWorkersGET struct {
+		modules.RenterWorkerPool
+	}
+
 	// ActiveHosts lists active hosts on the network.
 	ActiveHosts struct {
 		Hosts []modules.HostDBEntry `json:"hosts"`
+		Pagination
 	}
 
 	// AllHosts lists all hosts that the renter is aware of.
 	AllHosts struct {
 		Hosts []modules.HostDBEntry `json:"hosts"`
+		Pagination
 	}
 )
 
@@ -91,6 +237,34 @@ func (srv *Server) renterHandlerGET(w http.ResponseWriter, req *http.Request, _
 
 // renterHandlerPOST handles the API call to set the Renter's settings.
 func (srv *Server) renterHandlerPOST(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	settings := srv.renter.Settings()
+	expiryPolicy := settings.Allowance.ExpiryPolicy
+
+	// accesslogging and expirypolicy are optional. If they are the only
+	// values supplied, the rest of the allowance is left untouched.
+	if req.FormValue("accesslogging") != "" || req.FormValue("expirypolicy") != "" {
+		if req.FormValue("accesslogging") != "" {
+			accessLogging, err := strconv.ParseBool(req.FormValue("accesslogging"))
+			if err != nil {
+				writeError(w, Error{"Couldn't parse accesslogging: " + err.Error()}, http.StatusBadRequest)
+				return
+			}
+			settings.AccessLogging = accessLogging
+		}
+		if req.FormValue("expirypolicy") != "" {
+			expiryPolicy = req.FormValue("expirypolicy")
+			settings.Allowance.ExpiryPolicy = expiryPolicy
+		}
+		if req.FormValue("funds") == "" && req.FormValue("period") == "" {
+			if err := srv.renter.SetSettings(settings); err != nil {
+				writeError(w, Error{err.Error()}, http.StatusBadRequest)
+				return
+			}
+			writeSuccess(w)
+			return
+		}
+	}
+
 	// scan values
 	funds, ok := scanAmount(req.FormValue("funds"))
 	if !ok {
@@ -116,16 +290,16 @@ func (srv *Server) renterHandlerPOST(w http.ResponseWriter, req *http.Request, _
 	// 	return
 	// }
 
-	err = srv.renter.SetSettings(modules.RenterSettings{
-		Allowance: modules.Allowance{
-			Funds:  funds,
-			Period: period,
+	settings.Allowance = modules.Allowance{
+		Funds:        funds,
+		Period:       period,
+		ExpiryPolicy: expiryPolicy,
 
-			// TODO: let user specify these
-			Hosts:       recommendedHosts,
-			RenewWindow: period / 2,
-		},
-	})
+		// TODO: let user specify these
+		Hosts:       recommendedHosts,
+		RenewWindow: period / 2,
+	}
+	err = srv.renter.SetSettings(settings)
 	if err != nil {
 		writeError(w, Error{err.Error()}, http.StatusBadRequest)
 		return
@@ -133,30 +307,208 @@ func (srv *Server) renterHandlerPOST(w http.ResponseWriter, req *http.Request, _
 	writeSuccess(w)
 }
 
+// renterAccessLogHandler handles the API call to request the Renter's access
+// log.
+func (srv *Server) renterAccessLogHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	writeJSON(w, RenterAccessLog{
+		Entries: srv.renter.AccessLog(req.FormValue("siapath")),
+	})
+}
+
 // renterContractsHandler handles the API call to request the Renter's contracts.
-func (srv *Server) renterContractsHandler(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+func (srv *Server) renterContractsHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	p, err := srv.scanPage(req)
+	if err != nil {
+		writeError(w, Error{err.Error()}, http.StatusBadRequest)
+		return
+	}
+	all := srv.renter.Contracts()
+	sort.Sort(contractsByID(all))
+	start, end, pg := p.bounds(len(all))
 	contracts := []RenterContract{}
-	for _, c := range srv.renter.Contracts() {
+	for _, c := range all[start:end] {
 		contracts = append(contracts, RenterContract{
 			EndHeight:   c.EndHeight(),
 			ID:          c.ID,
 			NetAddress:  c.NetAddress,
 			RenterFunds: c.RenterFunds(),
+			Retired:     c.Retired,
 			Size:        modules.SectorSize * uint64(len(c.MerkleRoots)),
 		})
 	}
 	writeJSON(w, RenterContracts{
-		Contracts: contracts,
+		Contracts:  contracts,
+		Pagination: pg,
+	})
+}
+
+// renterContractHandlerGET handles the API call to look up the latest revision
+// of one of the renter's contracts.
+func (srv *Server) renterContractHandlerGET(w http.ResponseWriter, _ *http.Request, ps httprouter.Params) {
+	h, err := scanHash(ps.ByName("id"))
+	if err != nil {
+		writeError(w, Error{"unable to parse contract id: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	id := types.FileContractID(h)
+	for _, c := range srv.renter.Contracts() {
+		if c.ID != id {
+			continue
+		}
+		rev := c.LastRevision
+		rc := RenterContractGET{
+			ID:                 c.ID,
+			NetAddress:         c.NetAddress,
+			RevisionNumber:     rev.NewRevisionNumber,
+			FileSize:           rev.NewFileSize,
+			FileMerkleRoot:     rev.NewFileMerkleRoot,
+			WindowStart:        rev.NewWindowStart,
+			WindowEnd:          rev.NewWindowEnd,
+			RenterFunds:        c.RenterFunds(),
+			ValidProofOutputs:  rev.NewValidProofOutputs,
+			MissedProofOutputs: rev.NewMissedProofOutputs,
+			Retired:            c.Retired,
+		}
+		// The unlock conditions of a contract hold the renter's key followed
+		// by the host's key.
+		if len(rev.UnlockConditions.PublicKeys) == 2 {
+			rc.HostPublicKey = rev.UnlockConditions.PublicKeys[1]
+		}
+		writeJSON(w, rc)
+		return
+	}
+	writeError(w, Error{"no contract with that id"}, http.StatusBadRequest)
+}
+
+// renterExpiredContractsHandlerGET handles the API call to list the renter's
+// expired contracts.
+func (srv *Server) renterExpiredContractsHandlerGET(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	p, err := srv.scanPage(req)
+	if err != nil {
+		writeError(w, Error{err.Error()}, http.StatusBadRequest)
+		return
+	}
+	contracts := srv.renter.ExpiredContracts()
+	sort.Sort(expiredContractsByID(contracts))
+	start, end, pg := p.bounds(len(contracts))
+	writeJSON(w, RenterExpiredContractsGET{
+		Contracts:  contracts[start:end],
+		Pagination: pg,
+	})
+}
+
+// renterExpiredContractsClearHandler handles the API call to clear the
+// renter's expired contracts.
+func (srv *Server) renterExpiredContractsClearHandler(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	cleared, err := srv.renter.ClearExpiredContracts()
+	if err != nil {
+		writeError(w, Error{"unable to clear expired contracts: " + err.Error()}, http.StatusInternalServerError)
+		return
+	}
+	if cleared == nil {
+		cleared = []types.FileContractID{}
+	}
+	writeJSON(w, RenterExpiredContractsClearPOST{
+		Cleared: cleared,
 	})
 }
 
 // renterDownloadsHandler handles the API call to request the download queue.
-func (srv *Server) renterDownloadsHandler(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+func (srv *Server) renterDownloadsHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	p, err := srv.scanPage(req)
+	if err != nil {
+		writeError(w, Error{err.Error()}, http.StatusBadRequest)
+		return
+	}
+	downloads := srv.renter.DownloadQueue()
+	start, end, pg := p.bounds(len(downloads))
 	writeJSON(w, RenterDownloadQueue{
-		Downloads: srv.renter.DownloadQueue(),
+		Downloads:  downloads[start:end],
+		Pagination: pg,
 	})
 }
 
+// renterHostsUsageHandler handles the API call to list how much of the
+// renter's data is stored on each host.
+func (srv *Server) renterHostsUsageHandler(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	writeJSON(w, RenterHostsUsageGET{Hosts: srv.renter.HostUsage()})
+}
+
+// renterMigrateHandlerGET handles the API call to request the progress of
+// the most recent host migration.
+func (srv *Server) renterMigrateHandlerGET(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	writeJSON(w, RenterMigrationGET{srv.renter.Migration()})
+}
+
+// renterMigrateHandlerPOST handles the API call to move all of the renter's
+// data off of a host.
+func (srv *Server) renterMigrateHandlerPOST(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	key, err := base64.StdEncoding.DecodeString(req.FormValue("host"))
+	if err != nil || len(key) == 0 {
+		writeError(w, Error{"unable to parse host public key"}, http.StatusBadRequest)
+		return
+	}
+	pk := types.SiaPublicKey{
+		Algorithm: types.SignatureEd25519,
+		Key:       key,
+	}
+	err = srv.renter.MigrateHost(pk)
+	if err != nil {
+		writeError(w, Error{"unable to migrate off of host: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	writeSuccess(w)
+}
+
+// renterSimulateFailureHandler handles the API call that reports which files
+// would be lost if a set of hosts failed at once.
+func (srv *Server) renterSimulateFailureHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	var hosts []types.SiaPublicKey
+	for _, s := range strings.Split(req.FormValue("hosts"), ",") {
+		if s == "" {
+			continue
+		}
+		key, err := base64.StdEncoding.DecodeString(s)
+		if err != nil || len(key) == 0 {
+			writeError(w, Error{"unable to parse host public key " + s}, http.StatusBadRequest)
+			return
+		}
+		hosts = append(hosts, types.SiaPublicKey{
+			Algorithm: types.SignatureEd25519,
+			Key:       key,
+		})
+	}
+	sim, err := srv.renter.SimulateHostFailure(hosts)
+	if err != nil {
+		writeError(w, Error{"unable to simulate host failure: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, RenterSimulateFailurePOST{sim})
+}
+
+// renterWorkersHandlerGET handles the API call to inspect the renter's worker
+// pool.
+func (srv *Server) renterWorkersHandlerGET(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	writeJSON(w, RenterWorkersGET{srv.renter.WorkerPool()})
+}
+
+// renterWorkersHandlerPOST handles the API call to resize the renter's worker
+// pool.
+func (srv *Server) renterWorkersHandlerPOST(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	var size int
+	_, err := fmt.Sscan(req.FormValue("size"), &size)
+	if err != nil {
+		writeError(w, Error{"unable to parse size: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	err = srv.renter.SetWorkerPool(size)
+	if err != nil {
+		writeError(w, Error{"unable to set worker pool size: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	writeSuccess(w)
+}
+
 // renterLoadHandler handles the API call to load a '.sia' file.
 func (srv *Server) renterLoadHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 	source := req.FormValue("source")
@@ -198,10 +550,39 @@ func (srv *Server) renterRenameHandler(w http.ResponseWriter, req *http.Request,
 	writeSuccess(w)
 }
 
-// renterFilesHandler handles the API call to list all of the files.
+// renterFilesHandler handles the API call to list all of the files. The
+// files can be sorted by one of the fileSorters.
 func (srv *Server) renterFilesHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	p, err := srv.scanPage(req)
+	if err != nil {
+		writeError(w, Error{err.Error()}, http.StatusBadRequest)
+		return
+	}
+	files := srv.renter.FileList()
+	// Without a sort, the files are ordered by siapath, so that the pages of
+	// the list are consistent between calls.
+	var sorter sort.Interface = sortedFiles{files: files}
+	if by := req.FormValue("sort"); by != "" {
+		less, ok := fileSorters[by]
+		if !ok {
+			writeError(w, Error{"unrecognized sort '" + by + "'; must be size, cost, redundancy or uploadtime"}, http.StatusBadRequest)
+			return
+		}
+		sorter = sortedFiles{files, less}
+		switch req.FormValue("order") {
+		case "", "asc":
+		case "desc":
+			sorter = sort.Reverse(sorter)
+		default:
+			writeError(w, Error{"order must be asc or desc"}, http.StatusBadRequest)
+			return
+		}
+	}
+	sort.Sort(sorter)
+	start, end, pg := p.bounds(len(files))
 	writeJSON(w, RenterFiles{
-		Files: srv.renter.FileList(),
+		Files:      files[start:end],
+		Pagination: pg,
 	})
 }
 
@@ -235,6 +616,86 @@ func (srv *Server) renterDownloadHandler(w http.ResponseWriter, req *http.Reques
 	writeSuccess(w)
 }
 
+// renterDownloadCostHandler handles the API call to estimate the cost of
+// downloading a file.
+func (srv *Server) renterDownloadCostHandler(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	var offset, length uint64
+	if req.FormValue("offset") != "" {
+		_, err := fmt.Sscan(req.FormValue("offset"), &offset)
+		if err != nil {
+			writeError(w, Error{"unable to parse offset: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	if req.FormValue("length") != "" {
+		_, err := fmt.Sscan(req.FormValue("length"), &length)
+		if err != nil {
+			writeError(w, Error{"unable to parse length: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+
+	cost, err := srv.renter.DownloadCost(strings.TrimPrefix(ps.ByName("siapath"), "/"), offset, length)
+	if err != nil {
+		writeError(w, Error{"unable to estimate download cost: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, RenterDownloadCostGET{Cost: cost})
+}
+
+// renterVerifyHandler handles the API call to verify that a file can be
+// downloaded in full.
+func (srv *Server) renterVerifyHandler(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	fv, err := srv.renter.VerifyFile(strings.TrimPrefix(ps.ByName("siapath"), "/"))
+	if err != nil {
+		writeError(w, Error{"unable to verify file: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, RenterVerifyPOST{fv})
+}
+
+// renterRedundancyHandler handles the API call to change the redundancy of a
+// file.
+func (srv *Server) renterRedundancyHandler(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	var redundancy float64
+	_, err := fmt.Sscan(req.FormValue("redundancy"), &redundancy)
+	if err != nil {
+		writeError(w, Error{"unable to parse redundancy: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	change, err := srv.renter.SetFileRedundancy(strings.TrimPrefix(ps.ByName("siapath"), "/"), redundancy)
+	if err != nil {
+		writeError(w, Error{"unable to change redundancy: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, RenterRedundancyPOST{change})
+}
+
+// renterUploadEstimateHandler handles the API call to estimate how long an
+// upload would take.
+func (srv *Server) renterUploadEstimateHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	var size uint64
+	_, err := fmt.Sscan(req.FormValue("size"), &size)
+	if err != nil {
+		writeError(w, Error{"unable to parse size: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	var redundancy float64
+	if req.FormValue("redundancy") != "" {
+		_, err = fmt.Sscan(req.FormValue("redundancy"), &redundancy)
+		if err != nil {
+			writeError(w, Error{"unable to parse redundancy: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	estimate, err := srv.renter.EstimateUpload(size, redundancy)
+	if err != nil {
+		writeError(w, Error{"unable to estimate upload: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, RenterUploadEstimatePOST{estimate})
+}
+
 // renterShareHandler handles the API call to create a '.sia' file that
 // shares a set of file.
 func (srv *Server) renterShareHandler(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
@@ -275,11 +736,23 @@ func (srv *Server) renterUploadHandler(w http.ResponseWriter, req *http.Request,
 		return
 	}
 
+	var deleteAfterSuccess bool
+	if req.FormValue("deleteaftersuccess") != "" {
+		var err error
+		deleteAfterSuccess, err = strconv.ParseBool(req.FormValue("deleteaftersuccess"))
+		if err != nil {
+			writeError(w, Error{"unable to parse deleteaftersuccess: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+
 	err := srv.renter.Upload(modules.FileUploadParams{
 		Source:  source,
 		SiaPath: strings.TrimPrefix(ps.ByName("siapath"), "/"),
 		// let the renter decide these values; eventually they will be configurable
 		ErasureCode: nil,
+
+		DeleteAfterSuccess: deleteAfterSuccess,
 	})
 	if err != nil {
 		writeError(w, Error{"Upload failed: " + err.Error()}, http.StatusInternalServerError)
@@ -289,11 +762,54 @@ func (srv *Server) renterUploadHandler(w http.ResponseWriter, req *http.Request,
 	writeSuccess(w)
 }
 
+// renterUploadURLHandler handles the API call to upload the content at a
+// remote URL.
+func (srv *Server) renterUploadURLHandler(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	source := req.FormValue("url")
+	if source == "" {
+		writeError(w, Error{"url must be provided"}, http.StatusBadRequest)
+		return
+	}
+	var maxSize uint64
+	if req.FormValue("maxsize") != "" {
+		_, err := fmt.Sscan(req.FormValue("maxsize"), &maxSize)
+		if err != nil {
+			writeError(w, Error{"unable to parse maxsize: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	var timeout uint64
+	if req.FormValue("timeout") != "" {
+		_, err := fmt.Sscan(req.FormValue("timeout"), &timeout)
+		if err != nil {
+			writeError(w, Error{"unable to parse timeout: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+
+	siapath := strings.TrimPrefix(ps.ByName("siapath"), "/")
+	err := srv.renter.UploadURL(source, siapath, maxSize, time.Duration(timeout)*time.Second)
+	if err != nil {
+		writeError(w, Error{"Upload failed: " + err.Error()}, http.StatusInternalServerError)
+		return
+	}
+	writeSuccess(w)
+}
+
 // renterHostsActiveHandler handles the API call asking for the list of active
 // hosts.
 func (srv *Server) renterHostsActiveHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 	var numHosts uint64
-	hosts := srv.renter.ActiveHosts()
+	hosts, err := filterHostCapabilities(srv.renter.ActiveHosts(), req.FormValue("capabilities"))
+	if err != nil {
+		writeError(w, Error{err.Error()}, http.StatusBadRequest)
+		return
+	}
+	p, err := srv.scanPage(req)
+	if err != nil {
+		writeError(w, Error{err.Error()}, http.StatusBadRequest)
+		return
+	}
 
 	if req.FormValue("numhosts") == "" {
 		// Default value for 'numhosts' is all of them.
@@ -312,14 +828,50 @@ func (srv *Server) renterHostsActiveHandler(w http.ResponseWriter, req *http.Req
 		}
 	}
 
+	// 'numhosts' picks the best hosts, which are then paginated
+	hosts = hosts[:numHosts]
+	start, end, pg := p.bounds(len(hosts))
 	writeJSON(w, ActiveHosts{
-		Hosts: hosts[:numHosts],
+		Hosts:      hosts[start:end],
+		Pagination: pg,
 	})
 }
 
 // renterHostsAllHandler handles the API call asking for the list of all hosts.
 func (srv *Server) renterHostsAllHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	hosts, err := filterHostCapabilities(srv.renter.AllHosts(), req.FormValue("capabilities"))
+	if err != nil {
+		writeError(w, Error{err.Error()}, http.StatusBadRequest)
+		return
+	}
+	p, err := srv.scanPage(req)
+	if err != nil {
+		writeError(w, Error{err.Error()}, http.StatusBadRequest)
+		return
+	}
+	sort.Sort(hostsByAddress(hosts))
+	start, end, pg := p.bounds(len(hosts))
 	writeJSON(w, AllHosts{
-		Hosts: srv.renter.AllHosts(),
+		Hosts:      hosts[start:end],
+		Pagination: pg,
 	})
 }
+
+// filterHostCapabilities returns the hosts that advertise every capability in
+// the comma separated list of names. An empty list keeps every host.
+func filterHostCapabilities(hosts []modules.HostDBEntry, names string) ([]modules.HostDBEntry, error) {
+	want, err := modules.ParseHostCapabilities(names)
+	if err != nil {
+		return nil, err
+	}
+	if want == 0 {
+		return hosts, nil
+	}
+	filtered := make([]modules.HostDBEntry, 0, len(hosts))
+	for _, host := range hosts {
+		if host.Capabilities.Has(want) {
+			filtered = append(filtered, host)
+		}
+	}
+	return filtered, nil
+}
@@ -316,6 +316,17 @@ func TestRenterHandlerContracts(t *testing.T) {
 		t.Fatalf("expected contract spending to be %v; got %v", expectedContractSpending, got)
 	}
 
+	// Look up the contract's latest revision.
+	var rc RenterContractGET
+	if err = st.getAPI("/renter/contract/"+contracts.Contracts[0].ID.String(), &rc); err != nil {
+		t.Fatal(err)
+	}
+	if rc.ID != contracts.Contracts[0].ID || rc.RenterFunds.Cmp(contracts.Contracts[0].RenterFunds) != 0 || len(rc.HostPublicKey.Key) == 0 {
+		t.Fatalf("contract lookup does not match the contract list: %+v", rc)
+	}
+	if err = st.getAPI("/renter/contract/"+types.FileContractID{}.String(), &rc); err == nil {
+		t.Fatal("expected an error when looking up an unknown contract")
+	}
 }
 
 // TestRenterHandlerGetAndPost checks that valid /renter calls successfully set
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	// defaultContractAgeBucket is the width in blocks of the buckets returned
+	// by /renter/contracts/ages, about a week.
+	defaultContractAgeBucket = 1008
+
+	// maxContractAgeBuckets is the largest number of buckets that
+	// /renter/contracts/ages will return.
+	maxContractAgeBuckets = 1000
+)
+
+type (
+	// RenterContractAgeBucket counts the contracts with an age in the range
+	// [MinAge, MaxAge].
+	RenterContractAgeBucket struct {
+		MinAge    types.BlockHeight `json:"minage"`
+		MaxAge    types.BlockHeight `json:"maxage"`
+		Contracts int               `json:"contracts"`
+	}
+
+	// RenterContractAgesGET contains the age distribution of the renter's
+	// active contracts, measured in blocks since each contract was formed or
+	// last renewed.
+	RenterContractAgesGET struct {
+		Height       types.BlockHeight         `json:"height"`
+		Buckets      []RenterContractAgeBucket `json:"buckets"`
+		AverageAge   float64                   `json:"averageage"`
+		MedianAge    float64                   `json:"medianage"`
+		RenewingSoon int                       `json:"renewingsoon"`
+		UnknownAge   int                       `json:"unknownage"`
+	}
+)
+
+// blockHeights implements sort.Interface for a slice of block heights.
+type blockHeights []types.BlockHeight
+
+func (bh blockHeights) Len() int           { return len(bh) }
+func (bh blockHeights) Less(i, j int) bool { return bh[i] < bh[j] }
+func (bh blockHeights) Swap(i, j int)      { bh[i], bh[j] = bh[j], bh[i] }
+
+// contractAges sorts the contracts into buckets of the given width by their
+// age at the given height. Contracts with no recorded start height are only
+// counted in UnknownAge and RenewingSoon.
+func contractAges(contracts []modules.RenterContract, height, renewWindow, bucket types.BlockHeight) (RenterContractAgesGET, error) {
+	ca := RenterContractAgesGET{
+		Height:  height,
+		Buckets: []RenterContractAgeBucket{},
+	}
+	var ages []types.BlockHeight
+	for _, c := range contracts {
+		// same condition the contractor uses to decide whether to renew
+		if height+renewWindow >= c.EndHeight() {
+			ca.RenewingSoon++
+		}
+		if c.StartHeight == 0 || c.StartHeight > height {
+			ca.UnknownAge++
+			continue
+		}
+		ages = append(ages, height-c.StartHeight)
+	}
+	if len(ages) == 0 {
+		return ca, nil
+	}
+	sort.Sort(blockHeights(ages))
+
+	oldest := ages[len(ages)-1]
+	if oldest/bucket >= maxContractAgeBuckets {
+		return RenterContractAgesGET{}, fmt.Errorf("the ages cannot be split into more than %v buckets", maxContractAgeBuckets)
+	}
+	for min := types.BlockHeight(0); min <= oldest; min += bucket {
+		ca.Buckets = append(ca.Buckets, RenterContractAgeBucket{
+			MinAge: min,
+			MaxAge: min + bucket - 1,
+		})
+	}
+	var total float64
+	for _, age := range ages {
+		ca.Buckets[age/bucket].Contracts++
+		total += float64(age)
+	}
+	ca.AverageAge = total / float64(len(ages))
+	if mid := len(ages) / 2; len(ages)%2 == 1 {
+		ca.MedianAge = float64(ages[mid])
+	} else {
+		ca.MedianAge = float64(ages[mid-1]+ages[mid]) / 2
+	}
+	return ca, nil
+}
+
+// renterContractAgesHandler handles API calls to /renter/contracts/ages.
+func (srv *Server) renterContractAgesHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	bucket := types.BlockHeight(defaultContractAgeBucket)
+	if req.FormValue("bucket") != "" {
+		if _, err := fmt.Sscan(req.FormValue("bucket"), &bucket); err != nil {
+			writeError(w, Error{"unable to parse bucket: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	if bucket == 0 {
+		writeError(w, Error{"bucket must be at least 1"}, http.StatusBadRequest)
+		return
+	}
+	renewWindow := srv.renter.Settings().Allowance.RenewWindow
+	ca, err := contractAges(srv.renter.Contracts(), srv.cs.Height(), renewWindow, bucket)
+	if err != nil {
+		writeError(w, Error{err.Error()}, http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, ca)
+}
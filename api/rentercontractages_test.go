@@ -0,0 +1,60 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// TestContractAges checks the buckets and statistics computed from contract
+// start heights.
+func TestContractAges(t *testing.T) {
+	contract := func(start, end types.BlockHeight) modules.RenterContract {
+		var c modules.RenterContract
+		c.StartHeight = start
+		c.LastRevision.NewWindowStart = end
+		return c
+	}
+	contracts := []modules.RenterContract{
+		contract(100, 150), // age 0, renewing soon
+		contract(95, 300),  // age 5
+		contract(75, 300),  // age 25
+		contract(70, 110),  // age 30, renewing soon
+		contract(0, 500),   // unknown age
+	}
+	ca, err := contractAges(contracts, 100, 50, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ca.Buckets) != 4 {
+		t.Fatalf("expected 4 buckets, got %+v", ca.Buckets)
+	}
+	for i, exp := range []int{2, 0, 1, 1} {
+		b := ca.Buckets[i]
+		if b.Contracts != exp || b.MinAge != types.BlockHeight(10*i) || b.MaxAge != types.BlockHeight(10*i+9) {
+			t.Errorf("bucket %v: unexpected %+v", i, b)
+		}
+	}
+	if ca.AverageAge != 15 || ca.MedianAge != 15 {
+		t.Error("unexpected average or median:", ca.AverageAge, ca.MedianAge)
+	}
+	if ca.RenewingSoon != 2 || ca.UnknownAge != 1 || ca.Height != 100 {
+		t.Errorf("unexpected ages: %+v", ca)
+	}
+
+	ca, err = contractAges(contracts[:3], 100, 50, 10)
+	if err != nil || ca.MedianAge != 5 {
+		t.Fatal("expected a median of 5, got", ca.MedianAge, err)
+	}
+	if _, err := contractAges(contracts, 100, 50, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := contractAges([]modules.RenterContract{contract(1, 5000)}, 2000, 0, 1); err == nil {
+		t.Fatal("expected an error for too many buckets")
+	}
+	ca, err = contractAges(nil, 100, 50, 10)
+	if err != nil || len(ca.Buckets) != 0 || ca.AverageAge != 0 {
+		t.Fatalf("unexpected ages: %+v, %v", ca, err)
+	}
+}
@@ -1,14 +1,18 @@
 package api
 
 import (
+	"crypto"
 	"fmt"
 	"io"
+	"log"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"strings"
 	"sync"
+	"syscall"
+	"time"
 
 	"github.com/NebulousLabs/Sia/build"
 	"github.com/NebulousLabs/Sia/modules"
@@ -30,6 +34,86 @@ type Server struct {
 	listener          net.Listener
 	requiredUserAgent string
 
+	// certs is nil unless TLS has been enabled via EnableTLS.
+	certs *certReloader
+
+	// basePath is prepended to the path of every route, so that the API can
+	// be served under a subdirectory. requiredPassword is kept so that the
+	// routes can be registered again when the base path changes.
+	basePath         string
+	requiredPassword string
+
+	// defaultPageSize and maxPageSize bound the number of items returned by
+	// list endpoints. Zero is no limit. Protected by mu.
+	defaultPageSize int
+	maxPageSize     int
+
+	// startTime is when the server was created, which is reported as the
+	// start time of the daemon.
+	startTime time.Time
+
+	// debug indicates whether the profiling routes under /daemon/debug are
+	// served.
+	debug bool
+
+	// updateChannel is the release track that /daemon/update checks, either
+	// UpdateChannelStable or UpdateChannelBeta. It can be changed while the
+	// server is running, so it is protected by mu.
+	updateChannel string
+	mu            sync.RWMutex
+
+	// updateCheckTimeout is how long a request to GitHub may take while
+	// checking for an update. Protected by mu.
+	updateCheckTimeout time.Duration
+
+	// updateCache is the result of the last update check, which is reused
+	// for updateCacheTTL. Both are protected by mu.
+	updateCache    *cachedRelease
+	updateCacheTTL time.Duration
+
+	// updateSource is the repository that releases are fetched from and the
+	// names of their assets, or nil for the official Sia releases. Protected
+	// by mu.
+	updateSource *updateSource
+
+	// updateProgress reports the progress of an update started through
+	// /daemon/update.
+	updateProgress updateProgress
+
+	// persistDir is the directory that the update history is saved in, or
+	// empty if it is only kept in memory. Both are protected by mu.
+	persistDir    string
+	updateHistory []UpdateHistoryEntry
+
+	// updateKeys are the public keys that updates must be signed by, read
+	// from updateKeyFile. If nil, the developer key is used. Both are
+	// protected by mu.
+	updateKeys    []crypto.PublicKey
+	updateKeyFile string
+
+	// exports are the running exports that can be cancelled by name,
+	// protected by mu.
+	exports map[string]*runningExport
+
+	// conns are the open connections of the API server, keyed by remote
+	// address, so that a cancelled export can interrupt a write to a stalled
+	// client. Protected by mu.
+	conns map[string]net.Conn
+
+	// alerts are the problems reported by /daemon/alerts, keyed by alert
+	// id. Protected by mu.
+	alerts map[string]DaemonAlert
+
+	// requests counts the API requests being handled, so that /daemon/stop
+	// can wait for them before closing the modules.
+	requests *requestTracker
+
+	// done is closed once Close or shutdown has closed the modules, and
+	// blocks Serve() from returning until then, so that the caller of Serve()
+	// does not exit while the server is still shutting down.
+	done     chan struct{}
+	doneOnce sync.Once
+
 	// wg is used to block Close() from returning until Serve() has finished. A
 	// WaitGroup is used instead of a chan struct{} so that Close() can be called
 	// without necessarily calling Serve() first.
@@ -57,8 +141,15 @@ func NewServer(APIaddr string, requiredUserAgent string, requiredPassword string
 		tpool:    tp,
 		wallet:   w,
 
-		listener:          l,
-		requiredUserAgent: requiredUserAgent,
+		listener:           l,
+		requiredPassword:   requiredPassword,
+		requiredUserAgent:  requiredUserAgent,
+		updateChannel:      UpdateChannelStable,
+		updateCheckTimeout: defaultUpdateCheckTimeout,
+		updateCacheTTL:     defaultUpdateCacheTTL,
+		requests:           newRequestTracker(),
+		startTime:          time.Now(),
+		done:               make(chan struct{}),
 	}
 
 	// Register API handlers
@@ -67,6 +158,19 @@ func NewServer(APIaddr string, requiredUserAgent string, requiredPassword string
 	return srv, nil
 }
 
+// SetBasePath serves the API under the provided base path, such that
+// /daemon/version becomes /<basePath>/daemon/version. SetBasePath must be
+// called before Serve.
+func (srv *Server) SetBasePath(basePath string) error {
+	basePath, err := CleanBasePath(basePath)
+	if err != nil {
+		return err
+	}
+	srv.basePath = basePath
+	srv.initAPI(srv.requiredPassword)
+	return nil
+}
+
 // Serve listens for and handles API calls. It is a blocking function.
 func (srv *Server) Serve() error {
 	// Block the Close() method until Serve() has finished.
@@ -89,31 +193,89 @@ func (srv *Server) Serve() error {
 		}
 	}()
 
+	// reload the TLS certificate if a hangup signal is caught
+	if srv.certs != nil {
+		hupChan := make(chan os.Signal, 1)
+		signal.Notify(hupChan, syscall.SIGHUP)
+		defer signal.Stop(hupChan)
+		go func() {
+			for {
+				select {
+				case <-hupChan:
+					if err := srv.ReloadTLSCert(); err != nil {
+						fmt.Println("Failed to reload TLS certificate:", err)
+					} else {
+						fmt.Println("Reloaded TLS certificate")
+					}
+				case <-stop:
+					return
+				}
+			}
+		}()
+	}
+
 	// The server will run until an error is encountered or the listener is
 	// closed, via either the Close method or the signal handling above.
-	// Closing the listener will result in the benign error handled below.
+	// Closing the listener will result in the benign error handled below, after
+	// which Serve waits for the modules to be closed.
 	err := srv.apiServer.Serve(srv.listener)
 	if err != nil && !strings.HasSuffix(err.Error(), "use of closed network connection") {
 		return err
 	}
+	<-srv.done
 	return nil
 }
 
 // Close closes the Server's listener, causing the HTTP server to shut down.
 func (srv *Server) Close() error {
 	var errs []error
+	if err := srv.closeListener(); err != nil {
+		errs = append(errs, err)
+	}
+	errs = append(errs, srv.closeModules()...)
+	srv.finishShutdown()
+	return build.JoinErrors(errs, "\n")
+}
+
+// shutdown closes the Server like Close, but waits up to timeout for the API
+// requests being handled to finish before closing the modules. 'self' is the
+// number of those requests that are calling shutdown, and won't finish until
+// it returns.
+func (srv *Server) shutdown(timeout time.Duration, self int) error {
+	var errs []error
+	if err := srv.closeListener(); err != nil {
+		errs = append(errs, err)
+	}
+	if n := srv.requests.drain(timeout, self); n > 0 {
+		log.Printf("WARN: API requests did not finish within %v; forcibly terminating %v requests", timeout, n)
+	}
+	errs = append(errs, srv.closeModules()...)
+	srv.finishShutdown()
+	return build.JoinErrors(errs, "\n")
+}
 
-	// Close the listener, which will cause Server.Serve() to return.
-	if err := srv.listener.Close(); err != nil {
-		errs = append(errs, fmt.Errorf("listener.Close failed: %v", err))
+// closeListener closes the Server's listener, so that no new connections are
+// accepted. Requests that are already being handled are not interrupted.
+func (srv *Server) closeListener() error {
+	err := srv.listener.Close()
+	if err != nil {
+		err = fmt.Errorf("listener.Close failed: %v", err)
 	}
+	return err
+}
 
-	// Wait for Server.Serve() to exit. We wait so that it's guaranteed that the
-	// server has completely closed after Close() returns. This is particularly
-	// useful during testing so that we don't exit a test before Serve() finishes.
+// finishShutdown lets Serve return now that the modules are closed, and waits
+// for it to exit. We wait so that it's guaranteed that the server has
+// completely closed after Close() returns. This is particularly useful during
+// testing so that we don't exit a test before Serve() finishes.
+func (srv *Server) finishShutdown() {
+	srv.doneOnce.Do(func() { close(srv.done) })
 	srv.wg.Wait()
+}
 
-	// Safely close each module.
+// closeModules safely closes each module.
+func (srv *Server) closeModules() []error {
+	var errs []error
 	mods := []struct {
 		name string
 		c    io.Closer
@@ -134,6 +296,5 @@ func (srv *Server) Close() error {
 			}
 		}
 	}
-
-	return build.JoinErrors(errs, "\n")
+	return errs
 }
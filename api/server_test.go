@@ -129,3 +129,58 @@ func TestAuthentication(t *testing.T) {
 		t.Fatal("authenticated API call failed with the correct password")
 	}
 }
+
+// TestCleanBasePath probes the normalization of API base paths.
+func TestCleanBasePath(t *testing.T) {
+	tests := []struct {
+		in, out string
+		err     error
+	}{
+		{"", "", nil},
+		{"/", "", nil},
+		{"sia", "/sia", nil},
+		{"/sia/", "/sia", nil},
+		{"/sia//api/", "/sia/api", nil},
+		{"/:sia", "", errInvalidBasePath},
+		{"/sia/*", "", errInvalidBasePath},
+		{"/sia?x=1", "", errInvalidBasePath},
+	}
+	for _, test := range tests {
+		out, err := CleanBasePath(test.in)
+		if out != test.out || err != test.err {
+			t.Errorf("CleanBasePath(%q): expected (%q, %v), got (%q, %v)", test.in, test.out, test.err, out, err)
+		}
+	}
+}
+
+// TestBasePath checks that a server with a base path serves the API under
+// that path only.
+func TestBasePath(t *testing.T) {
+	srv, err := NewServer("localhost:0", "Sia-Agent", "", nil, nil, nil, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.SetBasePath("/sia/"); err != nil {
+		t.Fatal(err)
+	}
+	addr := "http://" + srv.listener.Addr().String()
+	go srv.Serve()
+	defer srv.Close()
+
+	resp, err := HttpGET(addr + "/sia/daemon/version")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatal("call under the base path failed:", resp.StatusCode)
+	}
+	resp, err = HttpGET(addr + "/daemon/version")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatal("call outside of the base path was served:", resp.StatusCode)
+	}
+}
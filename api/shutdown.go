@@ -0,0 +1,71 @@
+package api
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// defaultStopTimeout is how long /daemon/stop waits for API requests to
+// finish before closing the modules anyway.
+const defaultStopTimeout = 30 * time.Second
+
+// A requestTracker counts the API requests being handled, so that a shutdown
+// can wait for them to finish.
+type requestTracker struct {
+	active   int
+	draining bool
+	mu       sync.Mutex
+	cond     *sync.Cond
+}
+
+// newRequestTracker returns a requestTracker with no active requests.
+func newRequestTracker() *requestTracker {
+	rt := new(requestTracker)
+	rt.cond = sync.NewCond(&rt.mu)
+	return rt
+}
+
+// track is middleware that counts the requests handled by h. Once the
+// tracker is draining, new requests are refused.
+func (rt *requestTracker) track(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		rt.mu.Lock()
+		if rt.draining {
+			rt.mu.Unlock()
+			writeError(w, Error{"siad is shutting down"}, http.StatusServiceUnavailable)
+			return
+		}
+		rt.active++
+		rt.mu.Unlock()
+		defer func() {
+			rt.mu.Lock()
+			rt.active--
+			rt.mu.Unlock()
+			rt.cond.Broadcast()
+		}()
+		h.ServeHTTP(w, req)
+	})
+}
+
+// drain refuses new requests and waits up to timeout for the active requests
+// to finish, not counting the 'self' requests that are calling drain. It
+// returns the number of requests that were still active when it gave up.
+func (rt *requestTracker) drain(timeout time.Duration, self int) int {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	rt.draining = true
+
+	timedOut := false
+	t := time.AfterFunc(timeout, func() {
+		rt.mu.Lock()
+		timedOut = true
+		rt.mu.Unlock()
+		rt.cond.Broadcast()
+	})
+	defer t.Stop()
+	for rt.active > self && !timedOut {
+		rt.cond.Wait()
+	}
+	return rt.active - self
+}
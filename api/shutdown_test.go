@@ -0,0 +1,110 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestRequestTrackerDrain checks that draining waits for active requests,
+// gives up after the timeout, and refuses new requests.
+func TestRequestTrackerDrain(t *testing.T) {
+	rt := newRequestTracker()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	ts := httptest.NewServer(rt.track(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/slow" {
+			started <- struct{}{}
+			<-release
+		}
+		writeSuccess(w)
+	})))
+	defer ts.Close()
+
+	done := make(chan error)
+	go func() {
+		resp, err := http.Get(ts.URL + "/slow")
+		if err == nil {
+			resp.Body.Close()
+		}
+		done <- err
+	}()
+	<-started
+
+	// the slow request is still running when the timeout elapses
+	if n := rt.drain(50*time.Millisecond, 0); n != 1 {
+		t.Fatal("expected 1 active request, got", n)
+	}
+	resp, err := http.Get(ts.URL + "/fast")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatal("expected a draining tracker to refuse requests, got", resp.Status)
+	}
+
+	// once the slow request finishes, draining returns before the timeout
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	if n := rt.drain(time.Minute, 0); n != 0 {
+		t.Fatal("expected no active requests, got", n)
+	}
+	if time.Since(start) > 10*time.Second {
+		t.Fatal("drain waited for the timeout")
+	}
+}
+
+// TestServeWaitsForShutdown checks that Serve does not return until shutdown
+// has finished draining requests, so that siad does not exit early.
+func TestServeWaitsForShutdown(t *testing.T) {
+	srv, err := NewServer("localhost:0", "", "", nil, nil, nil, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	started := make(chan struct{})
+	release := make(chan struct{})
+	srv.apiServer = &http.Server{Handler: srv.requests.track(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		started <- struct{}{}
+		<-release
+		writeSuccess(w)
+	}))}
+	served := make(chan error)
+	go func() {
+		served <- srv.Serve()
+	}()
+	go func() {
+		resp, err := http.Get("http://" + srv.listener.Addr().String() + "/slow")
+		if err == nil {
+			resp.Body.Close()
+		}
+	}()
+	<-started
+
+	shutdownErr := make(chan error)
+	go func() {
+		shutdownErr <- srv.shutdown(time.Minute, 0)
+	}()
+	select {
+	case <-served:
+		t.Fatal("Serve returned while a request was still being drained")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+	if err := <-shutdownErr; err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Serve did not return after shutdown")
+	}
+}
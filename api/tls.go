@@ -0,0 +1,163 @@
+package api
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// selfSignedCertValidity is how long a generated self-signed certificate
+	// remains valid.
+	selfSignedCertValidity = 10 * 365 * 24 * time.Hour
+)
+
+var (
+	errTLSAlreadyEnabled = errors.New("TLS has already been enabled on this server")
+	errTLSNotEnabled     = errors.New("TLS is not enabled on this server")
+)
+
+// A certReloader holds the certificate that the API server presents to
+// clients. The certificate can be reloaded from disk while the server is
+// running, and new connections will use the reloaded certificate.
+type certReloader struct {
+	certPath string
+	keyPath  string
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+}
+
+// reload reads the certificate and key from disk, replacing the certificate
+// currently in use. If the files cannot be read, the current certificate is
+// kept.
+func (cr *certReloader) reload() error {
+	cert, err := tls.LoadX509KeyPair(cr.certPath, cr.keyPath)
+	if err != nil {
+		return err
+	}
+	cr.mu.Lock()
+	cr.cert = &cert
+	cr.mu.Unlock()
+	return nil
+}
+
+// getCertificate implements the tls.Config GetCertificate callback.
+func (cr *certReloader) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	cr.mu.RLock()
+	defer cr.mu.RUnlock()
+	return cr.cert, nil
+}
+
+// generateSelfSignedCert creates a self-signed certificate that is valid for
+// localhost and the loopback addresses, writing the certificate and its
+// private key to the provided paths in PEM format.
+func generateSelfSignedCert(certPath, keyPath string) error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return err
+	}
+	hostname, _ := os.Hostname()
+	template := x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			Organization: []string{"Sia"},
+			CommonName:   "siad",
+		},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(selfSignedCertValidity),
+		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+	}
+	if hostname != "" && hostname != "localhost" {
+		template.DNSNames = append(template.DNSNames, hostname)
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		return err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+
+	// Write the key first, so that a certificate is never left on disk
+	// without its key.
+	keyFile, err := os.OpenFile(keyPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer keyFile.Close()
+	if err := pem.Encode(keyFile, &pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}); err != nil {
+		return err
+	}
+	if err := keyFile.Sync(); err != nil {
+		return err
+	}
+	certFile, err := os.OpenFile(certPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer certFile.Close()
+	if err := pem.Encode(certFile, &pem.Block{Type: "CERTIFICATE", Bytes: der}); err != nil {
+		return err
+	}
+	return certFile.Sync()
+}
+
+// EnableTLS makes the server accept only TLS connections, using the
+// certificate and key at the provided paths. If neither file exists, a
+// self-signed certificate is generated and written to those paths. EnableTLS
+// must be called before Serve.
+func (srv *Server) EnableTLS(certPath, keyPath string) error {
+	if srv.certs != nil {
+		return errTLSAlreadyEnabled
+	}
+	_, certErr := os.Stat(certPath)
+	_, keyErr := os.Stat(keyPath)
+	if os.IsNotExist(certErr) && os.IsNotExist(keyErr) {
+		if err := generateSelfSignedCert(certPath, keyPath); err != nil {
+			return err
+		}
+	}
+
+	cr := &certReloader{
+		certPath: certPath,
+		keyPath:  keyPath,
+	}
+	if err := cr.reload(); err != nil {
+		return err
+	}
+	srv.certs = cr
+	srv.listener = tls.NewListener(srv.listener, &tls.Config{
+		GetCertificate: cr.getCertificate,
+		MinVersion:     tls.VersionTLS12,
+	})
+	return nil
+}
+
+// ReloadTLSCert reloads the server's TLS certificate and key from disk. The
+// new certificate is used for all subsequent connections.
+func (srv *Server) ReloadTLSCert() error {
+	if srv.certs == nil {
+		return errTLSNotEnabled
+	}
+	return srv.certs.reload()
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NebulousLabs/Sia/build"
+)
+
+// TestAPITLS checks that a server with TLS enabled generates a self-signed
+// certificate, serves requests over https, and can reload its certificate.
+func TestAPITLS(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	dir := build.TempDir("api", "TestAPITLS")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	certPath := filepath.Join(dir, "api.crt")
+	keyPath := filepath.Join(dir, "api.key")
+
+	srv, err := NewServer("localhost:0", "Sia-Agent", "", nil, nil, nil, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.ReloadTLSCert(); err != errTLSNotEnabled {
+		t.Fatal("expected errTLSNotEnabled, got", err)
+	}
+	if err := srv.EnableTLS(certPath, keyPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.EnableTLS(certPath, keyPath); err != errTLSAlreadyEnabled {
+		t.Fatal("expected errTLSAlreadyEnabled, got", err)
+	}
+	addr := srv.listener.Addr().String()
+	go srv.Serve()
+	defer srv.Close()
+
+	// The generated certificate should be trusted by a client that pins it.
+	certPEM, err := ioutil.ReadFile(certPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(certPEM) {
+		t.Fatal("generated certificate could not be parsed")
+	}
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{RootCAs: roots},
+	}}
+	req, err := http.NewRequest("GET", "https://"+addr+"/daemon/version", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("User-Agent", "Sia-Agent")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatal("unexpected status code:", resp.StatusCode)
+	}
+
+	// Plain http requests should not be served.
+	resp, err = HttpGET("http://" + addr + "/daemon/version")
+	if err == nil {
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusOK {
+			t.Fatal("plain http request was served by a TLS server")
+		}
+	}
+
+	// Reloading should fail without replacing the certificate if the files
+	// are missing, and succeed once they are restored.
+	if err := os.Rename(certPath, certPath+".bak"); err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.ReloadTLSCert(); err == nil {
+		t.Fatal("expected an error when reloading a missing certificate")
+	}
+	if err := os.Rename(certPath+".bak", certPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.ReloadTLSCert(); err != nil {
+		t.Fatal(err)
+	}
+}
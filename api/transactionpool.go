@@ -3,6 +3,7 @@ package api
 import (
 	"net/http"
 
+	"github.com/NebulousLabs/Sia/modules"
 	"github.com/NebulousLabs/Sia/types"
 
 	"github.com/julienschmidt/httprouter"
@@ -12,8 +13,64 @@ type TransactionPoolGET struct {
 	Transactions []types.Transaction `json:"transactions"`
 }
 
+// TpoolFeeRankGET contains how the fee of a transaction compares with the
+// rest of the transaction pool.
+type TpoolFeeRankGET struct {
+	modules.TransactionFeeRank
+}
+
+// TpoolOriginGET contains where the transaction pool first saw a
+// transaction.
+type TpoolOriginGET struct {
+	modules.TransactionOrigin
+}
+
+// TpoolStatsGET contains the limits of the transaction pool and their
+// utilization.
+type TpoolStatsGET struct {
+	modules.TransactionPoolStats
+}
+
 // transactionpoolTransactionsHandler handles the API call to get the
 // transaction pool trasactions.
 func (srv *Server) transactionpoolTransactionsHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 	writeJSON(w, TransactionPoolGET{Transactions: srv.tpool.TransactionList()})
 }
+
+// tpoolFeeRankHandler handles the API call to rank the fee of a transaction
+// among the transactions in the pool.
+func (srv *Server) tpoolFeeRankHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	var id types.TransactionID
+	if err := id.UnmarshalJSON([]byte("\"" + req.FormValue("id") + "\"")); err != nil {
+		writeError(w, Error{"unable to parse id: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	rank, ok := srv.tpool.FeeRank(id)
+	if !ok {
+		writeError(w, Error{"transaction is not in the transaction pool"}, http.StatusNotFound)
+		return
+	}
+	writeJSON(w, TpoolFeeRankGET{rank})
+}
+
+// tpoolOriginHandler handles the API call to get the peer that first relayed a
+// transaction.
+func (srv *Server) tpoolOriginHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	var id types.TransactionID
+	if err := id.UnmarshalJSON([]byte("\"" + req.FormValue("id") + "\"")); err != nil {
+		writeError(w, Error{"unable to parse id: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	origin, ok := srv.tpool.TransactionOrigin(id)
+	if !ok {
+		writeError(w, Error{"transaction has not been seen recently"}, http.StatusNotFound)
+		return
+	}
+	writeJSON(w, TpoolOriginGET{origin})
+}
+
+// tpoolStatsHandler handles the API call to get the limits and utilization of
+// the transaction pool.
+func (srv *Server) tpoolStatsHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	writeJSON(w, TpoolStatsGET{srv.tpool.Stats()})
+}
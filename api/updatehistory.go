@@ -0,0 +1,84 @@
+package api
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/NebulousLabs/Sia/persist"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	// updateHistoryFile is the name of the file in the persist directory that
+	// the update history is stored in.
+	updateHistoryFile = "update-history.json"
+
+	// maxUpdateHistory is the number of updates kept in the update history.
+	maxUpdateHistory = 50
+)
+
+var updateHistoryMetadata = persist.Metadata{
+	Header:  "Sia Update History",
+	Version: "1.0",
+}
+
+type (
+	// UpdateHistoryEntry records an update applied through /daemon/update.
+	// FromVersion is the version that was running when the update was
+	// applied.
+	UpdateHistoryEntry struct {
+		Timestamp   time.Time `json:"timestamp"`
+		FromVersion string    `json:"fromversion"`
+		ToVersion   string    `json:"toversion"`
+	}
+
+	// DaemonUpdateHistoryGET contains the updates applied to the daemon,
+	// oldest first.
+	DaemonUpdateHistoryGET struct {
+		Updates []UpdateHistoryEntry `json:"updates"`
+	}
+)
+
+// SetPersistDir sets the directory that the server keeps its own files in,
+// such as the update history, and loads the update history from it. Without
+// a persist directory, the update history is only kept in memory.
+func (srv *Server) SetPersistDir(dir string) error {
+	var history []UpdateHistoryEntry
+	err := persist.LoadFile(updateHistoryMetadata, &history, filepath.Join(dir, updateHistoryFile))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	srv.persistDir = dir
+	srv.updateHistory = history
+	return nil
+}
+
+// recordUpdate adds an update to the update history, dropping the oldest
+// updates beyond maxUpdateHistory, and saves the history if the server has a
+// persist directory.
+func (srv *Server) recordUpdate(entry UpdateHistoryEntry) error {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	srv.updateHistory = append(srv.updateHistory, entry)
+	if len(srv.updateHistory) > maxUpdateHistory {
+		srv.updateHistory = srv.updateHistory[len(srv.updateHistory)-maxUpdateHistory:]
+	}
+	if srv.persistDir == "" {
+		return nil
+	}
+	return persist.SaveFileSync(updateHistoryMetadata, srv.updateHistory, filepath.Join(srv.persistDir, updateHistoryFile))
+}
+
+// daemonUpdateHistoryHandlerGET handles the API call that returns the updates
+// applied to the daemon.
+func (srv *Server) daemonUpdateHistoryHandlerGET(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	srv.mu.RLock()
+	updates := append([]UpdateHistoryEntry{}, srv.updateHistory...)
+	srv.mu.RUnlock()
+	writeJSON(w, DaemonUpdateHistoryGET{Updates: updates})
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+
+	"github.com/inconshreveable/go-update"
+)
+
+var (
+	errNoUpdateKeys      = errors.New("no public keys found")
+	errNoMatchingKey     = errors.New("signature does not match any trusted update key")
+	errBadUpdateKeyBlock = errors.New("update keys must be PEM encoded public keys")
+)
+
+// parseUpdateKeys parses one or more PEM encoded public keys, such as the
+// contents of a file with several keys concatenated.
+func parseUpdateKeys(data []byte) ([]crypto.PublicKey, error) {
+	var keys []crypto.PublicKey
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "PUBLIC KEY" {
+			return nil, errBadUpdateKeyBlock
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, errNoUpdateKeys
+	}
+	return keys, nil
+}
+
+// readUpdateKeyFile reads the public keys in the file at path. If path is
+// empty, nil is returned, which stands for the developer key.
+func readUpdateKeyFile(path string) ([]crypto.PublicKey, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseUpdateKeys(data)
+}
+
+// SetUpdateKeyFile replaces the developer key with the public keys in the
+// file at path, which may hold several PEM encoded keys. Updates signed by
+// any of them are accepted by /daemon/update. If path is empty, the
+// developer key built into siad is used.
+func (srv *Server) SetUpdateKeyFile(path string) error {
+	keys, err := readUpdateKeyFile(path)
+	if err != nil {
+		return err
+	}
+	srv.mu.Lock()
+	srv.updateKeys = keys
+	srv.updateKeyFile = path
+	srv.mu.Unlock()
+	return nil
+}
+
+// trustedUpdateKeys returns the keys that updates are verified against.
+func (srv *Server) trustedUpdateKeys() ([]crypto.PublicKey, error) {
+	srv.mu.RLock()
+	keys := srv.updateKeys
+	srv.mu.RUnlock()
+	if keys != nil {
+		return keys, nil
+	}
+	return parseUpdateKeys([]byte(developerKey))
+}
+
+// findSigningKey returns the first of keys that signature is a valid
+// signature of checksum for.
+func findSigningKey(opts update.Options, keys []crypto.PublicKey, checksum, signature []byte) (crypto.PublicKey, error) {
+	for _, key := range keys {
+		if opts.Verifier.VerifySignature(checksum, signature, opts.Hash, key) == nil {
+			return key, nil
+		}
+	}
+	return nil, errNoMatchingKey
+}
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+var errUnknownExecutable = errors.New("not an ELF, Mach-O, or PE executable")
+
+// Executable formats and the machine types that release binaries are built
+// for, taken from the ELF, Mach-O, and PE specifications.
+const (
+	formatELF   = "ELF"
+	formatMachO = "Mach-O"
+	formatPE    = "PE"
+
+	elfMachine386   = 3
+	elfMachineARM   = 40
+	elfMachineAMD64 = 62
+	elfMachineARM64 = 183
+
+	machoCPU386   = 7
+	machoCPUAMD64 = 0x01000007
+	machoCPUARM   = 12
+	machoCPUARM64 = 0x0100000c
+
+	peMachine386   = 0x14c
+	peMachineARM   = 0x1c4
+	peMachineAMD64 = 0x8664
+	peMachineARM64 = 0xaa64
+)
+
+var (
+	elfArchs = map[uint16]string{
+		elfMachine386:   "386",
+		elfMachineARM:   "arm",
+		elfMachineAMD64: "amd64",
+		elfMachineARM64: "arm64",
+	}
+	machoArchs = map[uint32]string{
+		machoCPU386:   "386",
+		machoCPUAMD64: "amd64",
+		machoCPUARM:   "arm",
+		machoCPUARM64: "arm64",
+	}
+	peArchs = map[uint16]string{
+		peMachine386:   "386",
+		peMachineARM:   "arm",
+		peMachineAMD64: "amd64",
+		peMachineARM64: "arm64",
+	}
+)
+
+// executablePlatform reads the header of an executable and returns its format
+// and the GOARCH it was built for.
+func executablePlatform(bin []byte) (format, arch string, err error) {
+	switch {
+	case len(bin) >= 20 && bytes.HasPrefix(bin, []byte("\x7fELF")):
+		// e_ident[EI_DATA] gives the byte order of the rest of the header
+		var order binary.ByteOrder = binary.LittleEndian
+		if bin[5] == 2 {
+			order = binary.BigEndian
+		}
+		machine := order.Uint16(bin[18:])
+		arch, ok := elfArchs[machine]
+		if !ok {
+			return formatELF, "", fmt.Errorf("unknown ELF machine type %v", machine)
+		}
+		return formatELF, arch, nil
+
+	case len(bin) >= 8 && (bytes.HasPrefix(bin, []byte{0xce, 0xfa, 0xed, 0xfe}) || bytes.HasPrefix(bin, []byte{0xcf, 0xfa, 0xed, 0xfe})):
+		cpu := binary.LittleEndian.Uint32(bin[4:])
+		arch, ok := machoArchs[cpu]
+		if !ok {
+			return formatMachO, "", fmt.Errorf("unknown Mach-O CPU type %#x", cpu)
+		}
+		return formatMachO, arch, nil
+
+	case len(bin) >= 0x40 && bytes.HasPrefix(bin, []byte("MZ")):
+		// the DOS header points to the PE signature, which is followed by
+		// the machine type
+		off := int64(binary.LittleEndian.Uint32(bin[0x3c:]))
+		if off+6 > int64(len(bin)) || !bytes.Equal(bin[off:off+4], []byte("PE\x00\x00")) {
+			return "", "", errUnknownExecutable
+		}
+		machine := binary.LittleEndian.Uint16(bin[off+4:])
+		arch, ok := peArchs[machine]
+		if !ok {
+			return formatPE, "", fmt.Errorf("unknown PE machine type %#x", machine)
+		}
+		return formatPE, arch, nil
+	}
+	return "", "", errUnknownExecutable
+}
+
+// checkExecutablePlatform returns an error unless bin is an executable for
+// the given GOOS and GOARCH. ELF does not reliably record the operating
+// system, so any ELF executable is accepted on platforms other than darwin
+// and windows.
+func checkExecutablePlatform(bin []byte, goos, goarch string) error {
+	format, arch, err := executablePlatform(bin)
+	if err != nil {
+		return err
+	}
+	wantFormat := formatELF
+	switch goos {
+	case "darwin":
+		wantFormat = formatMachO
+	case "windows":
+		wantFormat = formatPE
+	}
+	if format != wantFormat || arch != goarch {
+		return fmt.Errorf("binary is a %v %v executable, but this platform is %v/%v", arch, format, goos, goarch)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"runtime"
+	"testing"
+)
+
+// elfHeader returns the start of a little-endian ELF header for machine.
+func elfHeader(machine uint16) []byte {
+	bin := make([]byte, 64)
+	copy(bin, "\x7fELF\x02\x01\x01")
+	binary.LittleEndian.PutUint16(bin[18:], machine)
+	return bin
+}
+
+// machoHeader returns the start of a 64-bit Mach-O header for cpu.
+func machoHeader(cpu uint32) []byte {
+	bin := make([]byte, 32)
+	binary.LittleEndian.PutUint32(bin, 0xfeedfacf)
+	binary.LittleEndian.PutUint32(bin[4:], cpu)
+	return bin
+}
+
+// peHeader returns the start of a PE file for machine.
+func peHeader(machine uint16) []byte {
+	bin := make([]byte, 0x100)
+	copy(bin, "MZ")
+	binary.LittleEndian.PutUint32(bin[0x3c:], 0x80)
+	copy(bin[0x80:], "PE\x00\x00")
+	binary.LittleEndian.PutUint16(bin[0x84:], machine)
+	return bin
+}
+
+// TestExecutablePlatform checks that the platform of an executable is read
+// from its header.
+func TestExecutablePlatform(t *testing.T) {
+	tests := []struct {
+		bin    []byte
+		format string
+		arch   string
+	}{
+		{elfHeader(elfMachineAMD64), formatELF, "amd64"},
+		{elfHeader(elfMachineARM), formatELF, "arm"},
+		{machoHeader(machoCPUAMD64), formatMachO, "amd64"},
+		{machoHeader(machoCPU386), formatMachO, "386"},
+		{peHeader(peMachineAMD64), formatPE, "amd64"},
+		{peHeader(peMachine386), formatPE, "386"},
+	}
+	for _, test := range tests {
+		format, arch, err := executablePlatform(test.bin)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if format != test.format || arch != test.arch {
+			t.Errorf("expected %v %v, got %v %v", test.arch, test.format, arch, format)
+		}
+	}
+
+	for _, bin := range [][]byte{nil, []byte("siad binary"), elfHeader(0xffff), peHeader(0)[:0x80]} {
+		if _, _, err := executablePlatform(bin); err == nil {
+			t.Errorf("expected an error for %q", bin)
+		}
+	}
+
+	if err := checkExecutablePlatform(elfHeader(elfMachineAMD64), "linux", "amd64"); err != nil {
+		t.Error(err)
+	}
+	if err := checkExecutablePlatform(elfHeader(elfMachineAMD64), "linux", "386"); err == nil {
+		t.Error("expected an error for the wrong architecture")
+	}
+	if err := checkExecutablePlatform(elfHeader(elfMachineAMD64), "darwin", "amd64"); err == nil {
+		t.Error("expected an error for the wrong operating system")
+	}
+	if err := checkExecutablePlatform(peHeader(peMachineAMD64), "windows", "amd64"); err != nil {
+		t.Error(err)
+	}
+
+	// the test binary is built for the platform it runs on
+	bin, err := ioutil.ReadFile(os.Args[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := checkExecutablePlatform(bin, runtime.GOOS, runtime.GOARCH); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,123 @@
+package api
+
+import (
+	"errors"
+	"net/url"
+	"runtime"
+	"strings"
+)
+
+const (
+	// defaultUpdateRepo is the GitHub repository that releases are fetched
+	// from unless another is configured.
+	defaultUpdateRepo = "NebulousLabs/Sia"
+
+	// defaultUpdateArchiveName and defaultUpdateChecksumsName are the names
+	// of the release assets holding the binaries for this platform and their
+	// SHA256 checksums.
+	defaultUpdateArchiveName   = "Sia-{tag}-{os}-{arch}.zip"
+	defaultUpdateChecksumsName = "Sia-{tag}-SHA256SUMS.txt"
+)
+
+var (
+	errBadUpdateRepo      = errors.New("update repo must be 'owner/repo' or an http(s) URL of a GitHub compatible releases API")
+	errBadUpdateAssetName = errors.New("update asset names cannot contain '/', and the archive must be a .zip")
+)
+
+// An updateSource is where releases are fetched from and how the assets of a
+// release are named. repo is the repository as it was configured, and baseURL
+// is the API endpoint of the repository, such as
+// https://api.github.com/repos/NebulousLabs/Sia, which must serve GitHub's
+// /releases, /releases/latest, and /releases/tags/<tag> routes. The asset
+// names are templates in which {tag}, {version}, {os}, and {arch} are
+// replaced by the release tag, the tag without its leading 'v', and the
+// platform that siad was built for.
+type updateSource struct {
+	repo          string
+	baseURL       string
+	archiveName   string
+	checksumsName string
+}
+
+// defaultUpdateSource returns the source of the official Sia releases.
+func defaultUpdateSource() updateSource {
+	src, _ := newUpdateSource("", "", "")
+	return src
+}
+
+// newUpdateSource creates an update source from the configured repo and
+// asset names. Each of them takes its default if empty. The repo is either a
+// GitHub 'owner/repo' or the full URL of a mirror's repository endpoint.
+func newUpdateSource(repo, archiveName, checksumsName string) (updateSource, error) {
+	if repo == "" {
+		repo = defaultUpdateRepo
+	}
+	if archiveName == "" {
+		archiveName = defaultUpdateArchiveName
+	}
+	if checksumsName == "" {
+		checksumsName = defaultUpdateChecksumsName
+	}
+
+	var baseURL string
+	if strings.Contains(repo, "://") {
+		u, err := url.Parse(repo)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.RawQuery != "" {
+			return updateSource{}, errBadUpdateRepo
+		}
+		baseURL = strings.TrimSuffix(repo, "/")
+	} else {
+		parts := strings.Split(repo, "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" || strings.ContainsAny(repo, " ?#") {
+			return updateSource{}, errBadUpdateRepo
+		}
+		baseURL = "https://api.github.com/repos/" + repo
+	}
+
+	if strings.Contains(archiveName, "/") || strings.Contains(checksumsName, "/") || !strings.HasSuffix(archiveName, ".zip") {
+		return updateSource{}, errBadUpdateAssetName
+	}
+	return updateSource{
+		repo:          repo,
+		baseURL:       baseURL,
+		archiveName:   archiveName,
+		checksumsName: checksumsName,
+	}, nil
+}
+
+// assetName fills in the asset name template for the given release tag.
+func assetName(template, tag string) string {
+	return strings.NewReplacer(
+		"{tag}", tag,
+		"{version}", strings.TrimPrefix(tag, "v"),
+		"{os}", runtime.GOOS,
+		"{arch}", runtime.GOARCH,
+	).Replace(template)
+}
+
+// SetUpdateSource sets the repository that /daemon/update fetches releases
+// from and the names of the release assets it downloads. Empty values keep
+// the official Sia repository and asset names. Changing the source discards
+// the cached update check.
+func (srv *Server) SetUpdateSource(repo, archiveName, checksumsName string) error {
+	src, err := newUpdateSource(repo, archiveName, checksumsName)
+	if err != nil {
+		return err
+	}
+	srv.mu.Lock()
+	srv.updateSource = &src
+	srv.updateCache = nil
+	srv.mu.Unlock()
+	return nil
+}
+
+// releaseSource returns the configured update source, or the official one
+// if none has been set.
+func (srv *Server) releaseSource() updateSource {
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	if srv.updateSource == nil {
+		return defaultUpdateSource()
+	}
+	return *srv.updateSource
+}
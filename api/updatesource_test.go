@@ -0,0 +1,98 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// TestNewUpdateSource checks the parsing of the update repo and asset names.
+func TestNewUpdateSource(t *testing.T) {
+	src := defaultUpdateSource()
+	if src.baseURL != "https://api.github.com/repos/NebulousLabs/Sia" {
+		t.Fatal("wrong default base URL:", src.baseURL)
+	}
+	if name := assetName(src.archiveName, "v1.3.2"); name != "Sia-v1.3.2-"+runtime.GOOS+"-"+runtime.GOARCH+".zip" {
+		t.Fatal("wrong default archive name:", name)
+	}
+	if name := assetName(src.checksumsName, "v1.3.2"); name != "Sia-v1.3.2-SHA256SUMS.txt" {
+		t.Fatal("wrong default checksums name:", name)
+	}
+
+	src, err := newUpdateSource("fork/Sia", "", "")
+	if err != nil || src.baseURL != "https://api.github.com/repos/fork/Sia" {
+		t.Fatal("GitHub repo was not used:", src.baseURL, err)
+	}
+	src, err = newUpdateSource("https://mirror.example.com/repos/fork/Sia/", "", "")
+	if err != nil || src.baseURL != "https://mirror.example.com/repos/fork/Sia" {
+		t.Fatal("mirror URL was not used:", src.baseURL, err)
+	}
+
+	for _, repo := range []string{"Sia", "fork/Sia/extra", "/Sia", "fork/", "ftp://mirror.example.com", "https://", "fork/Sia?x=1"} {
+		if _, err := newUpdateSource(repo, "", ""); err != errBadUpdateRepo {
+			t.Errorf("expected errBadUpdateRepo for %q, got %v", repo, err)
+		}
+	}
+	for _, names := range [][2]string{{"bin/Sia-{tag}.zip", ""}, {"Sia-{tag}.tar.gz", ""}, {"", "sums/{tag}.txt"}} {
+		if _, err := newUpdateSource("", names[0], names[1]); err != errBadUpdateAssetName {
+			t.Errorf("expected errBadUpdateAssetName for %q, got %v", names, err)
+		}
+	}
+}
+
+// TestUpdateSourceMirror checks that releases are fetched from a configured
+// mirror and that their assets are matched by the configured names.
+func TestUpdateSourceMirror(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	archive := "fork-1.3.2-" + runtime.GOOS + "-" + runtime.GOARCH + ".zip"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/repos/fork/Sia/releases/latest" {
+			http.NotFound(w, req)
+			return
+		}
+		w.Write([]byte(`{"tag_name": "v1.3.2", "assets": [
+			{"name": "Sia-v1.3.2-` + runtime.GOOS + `-` + runtime.GOARCH + `.zip", "browser_download_url": "http://example.com/official"},
+			{"name": "` + archive + `", "browser_download_url": "http://example.com/fork"}
+		]}`))
+	}))
+	defer ts.Close()
+
+	srv := &Server{
+		updateCacheTTL: time.Minute,
+		updateCache: &cachedRelease{
+			channel: UpdateChannelStable,
+			release: githubRelease{TagName: "v1.0.0"},
+			// the cached release would be used if it were kept
+			checkedAt: time.Now(),
+		},
+	}
+	if err := srv.SetUpdateSource(ts.URL+"/repos/fork/Sia", "fork-{version}-{os}-{arch}.zip", ""); err != nil {
+		t.Fatal(err)
+	}
+	release, _, err := srv.latestRelease(UpdateChannelStable, false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if release.TagName != "v1.3.2" {
+		t.Fatal("release was not fetched from the mirror:", release.TagName)
+	}
+	name, url, err := srv.releaseSource().releaseArchive(release)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != archive || url != "http://example.com/fork" {
+		t.Fatal("wrong archive matched:", name, url)
+	}
+
+	// An invalid source leaves the current one in place.
+	if err := srv.SetUpdateSource("not a repo", "", ""); err != errBadUpdateRepo {
+		t.Fatal("expected errBadUpdateRepo, got", err)
+	}
+	if srv.releaseSource().baseURL != ts.URL+"/repos/fork/Sia" {
+		t.Fatal("invalid source replaced the mirror")
+	}
+}
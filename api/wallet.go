@@ -1,8 +1,12 @@
 package api
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -14,11 +18,22 @@ import (
 	"github.com/julienschmidt/httprouter"
 )
 
+const (
+	// maxVerifySeedAddresses is the largest number of addresses that can be
+	// checked in one call to /wallet/verifyseed.
+	maxVerifySeedAddresses = 10e3
+
+	// maxAddressBatch is the largest number of addresses that can be
+	// generated in one call to /wallet/addresses/batch.
+	maxAddressBatch = 1000
+)
+
 type (
 	// WalletGET contains general information about the wallet.
 	WalletGET struct {
-		Encrypted bool `json:"encrypted"`
-		Unlocked  bool `json:"unlocked"`
+		Encrypted   bool                      `json:"encrypted"`
+		Unlocked    bool                      `json:"unlocked"`
+		UnlockLevel modules.WalletUnlockLevel `json:"unlocklevel"`
 
 		ConfirmedSiacoinBalance     types.Currency `json:"confirmedsiacoinbalance"`
 		UnconfirmedOutgoingSiacoins types.Currency `json:"unconfirmedoutgoingsiacoins"`
@@ -28,6 +43,44 @@ type (
 		SiacoinClaimBalance types.Currency `json:"siacoinclaimbalance"`
 	}
 
+	// WalletBalanceBreakdownGET contains the siacoin balance of the wallet
+	// split by how soon the outputs can be spent.
+	WalletBalanceBreakdownGET struct {
+		modules.WalletBalanceBreakdown
+	}
+
+	// WalletAutoBumpsGET contains the sends whose fees are raised
+	// automatically by the wallet.
+	WalletAutoBumpsGET struct {
+		AutoBumps []modules.WalletAutoBump `json:"autobumps"`
+	}
+
+	// WalletSiafundClaimsGET contains the settings for the automatic
+	// collection of siafund claims, the claim balance of the wallet's
+	// siafunds, and the last collection made by the wallet.
+	WalletSiafundClaimsGET struct {
+		modules.WalletClaimSettings
+		ClaimBalance   types.Currency                 `json:"claimbalance"`
+		LastCollection *modules.WalletClaimCollection `json:"lastcollection"`
+	}
+
+	// WalletContract is a file contract that the wallet is party to, either
+	// as the renter funding the contract or as the host storing its data.
+	WalletContract struct {
+		ID         types.FileContractID `json:"id"`
+		Role       string               `json:"role"`
+		Status     string               `json:"status"`
+		Value      types.Currency       `json:"value"`
+		Expiration types.BlockHeight    `json:"expiration"`
+	}
+
+	// WalletContractsGET contains the file contracts that the wallet is party
+	// to.
+	WalletContractsGET struct {
+		Contracts []WalletContract `json:"contracts"`
+		Pagination
+	}
+
 	// WalletAddressGET contains an address returned by a GET call to
 	// /wallet/address.
 	WalletAddressGET struct {
@@ -38,6 +91,13 @@ type (
 	// GET call to /wallet/addresses.
 	WalletAddressesGET struct {
 		Addresses []types.UnlockHash `json:"addresses"`
+		Pagination
+	}
+
+	// WalletAddressesBatchPOST contains the addresses generated by a call to
+	// /wallet/addresses/batch.
+	WalletAddressesBatchPOST struct {
+		Addresses []types.UnlockHash `json:"addresses"`
 	}
 
 	// WalletInitPOST contains the primary seed that gets generated during a
@@ -50,6 +110,33 @@ type (
 	// /wallet/siafunds.
 	WalletSiacoinsPOST struct {
 		TransactionIDs []types.TransactionID `json:"transactionids"`
+
+		// Split is true if the send needed too many inputs for one
+		// transaction, and was paid to the destination across several
+		// transactions that confirm independently.
+		Split bool `json:"split"`
+
+		// UnlockConditions are the conditions of the timelocked output, which
+		// are needed to spend the output once the timelock has passed.
+		UnlockConditions *types.UnlockConditions `json:"unlockconditions,omitempty"`
+	}
+
+	// WalletConsolidatePOST describes the transaction that consolidated the
+	// wallet's outputs, and how many outputs were not eligible.
+	WalletConsolidatePOST struct {
+		modules.WalletConsolidation
+	}
+
+	// WalletSiacoinsPreviewPOST contains the outputs, fee, and change that a
+	// send would use.
+	WalletSiacoinsPreviewPOST struct {
+		modules.WalletSendPreview
+	}
+
+	// WalletSyncStatusGET contains how far the wallet is behind the consensus
+	// set.
+	WalletSyncStatusGET struct {
+		modules.WalletSyncStatus
 	}
 
 	// WalletSiafundsPOST contains the transaction sent in the POST call to
@@ -58,6 +145,18 @@ type (
 		TransactionIDs []types.TransactionID `json:"transactionids"`
 	}
 
+	// WalletReindexPOST contains the discrepancies that were repaired during
+	// a POST call to /wallet/reindex.
+	WalletReindexPOST struct {
+		modules.WalletReindexReport
+	}
+
+	// WalletVerifySeedPOST contains the result of checking a seed against the
+	// wallet's addresses in a POST call to /wallet/verifyseed.
+	WalletVerifySeedPOST struct {
+		modules.WalletSeedVerification
+	}
+
 	// WalletSeedsGET contains the seeds used by the wallet.
 	WalletSeedsGET struct {
 		PrimarySeed        string   `json:"primaryseed"`
@@ -76,6 +175,7 @@ type (
 	WalletTransactionsGET struct {
 		ConfirmedTransactions   []modules.ProcessedTransaction `json:"confirmedtransactions"`
 		UnconfirmedTransactions []modules.ProcessedTransaction `json:"unconfirmedtransactions"`
+		Pagination
 	}
 
 	// WalletTransactionsGETaddr contains the set of wallet transactions
@@ -84,6 +184,7 @@ type (
 	WalletTransactionsGETaddr struct {
 		ConfirmedTransactions   []modules.ProcessedTransaction `json:"confirmedtransactions"`
 		UnconfirmedTransactions []modules.ProcessedTransaction `json:"unconfirmedtransactions"`
+		Pagination
 	}
 )
 
@@ -107,8 +208,9 @@ func (srv *Server) walletHandler(w http.ResponseWriter, req *http.Request, _ htt
 	siacoinBal, siafundBal, siaclaimBal := srv.wallet.ConfirmedBalance()
 	siacoinsOut, siacoinsIn := srv.wallet.UnconfirmedBalance()
 	writeJSON(w, WalletGET{
-		Encrypted: srv.wallet.Encrypted(),
-		Unlocked:  srv.wallet.Unlocked(),
+		Encrypted:   srv.wallet.Encrypted(),
+		Unlocked:    srv.wallet.Unlocked(),
+		UnlockLevel: srv.wallet.UnlockLevel(),
 
 		ConfirmedSiacoinBalance:     siacoinBal,
 		UnconfirmedOutgoingSiacoins: siacoinsOut,
@@ -119,6 +221,132 @@ func (srv *Server) walletHandler(w http.ResponseWriter, req *http.Request, _ htt
 	})
 }
 
+// walletAutoBumpsHandler handles API calls to /wallet/autobumps.
+func (srv *Server) walletAutoBumpsHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	writeJSON(w, WalletAutoBumpsGET{AutoBumps: srv.wallet.AutoBumps()})
+}
+
+// walletBalanceBreakdownHandler handles API calls to
+// /wallet/balance/breakdown.
+func (srv *Server) walletBalanceBreakdownHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	writeJSON(w, WalletBalanceBreakdownGET{srv.wallet.BalanceBreakdown()})
+}
+
+// walletConsolidateHandler handles API calls to /wallet/consolidate.
+func (srv *Server) walletConsolidateHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	var maxInputs int
+	if req.FormValue("maxinputs") != "" {
+		_, err := fmt.Sscan(req.FormValue("maxinputs"), &maxInputs)
+		if err != nil || maxInputs < 2 {
+			writeError(w, Error{"unable to parse maxinputs: must be a number of at least 2"}, http.StatusBadRequest)
+			return
+		}
+	}
+	minConfirmations := types.BlockHeight(modules.ConsolidationSafeDepth)
+	if req.FormValue("minconfirmations") != "" {
+		_, err := fmt.Sscan(req.FormValue("minconfirmations"), &minConfirmations)
+		if err != nil {
+			writeError(w, Error{"unable to parse minconfirmations: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	report, err := srv.wallet.ConsolidateOutputs(maxInputs, minConfirmations)
+	if err != nil {
+		writeError(w, Error{fmt.Sprintf("error after call to /wallet/consolidate: %v (skipped %v immature, %v unconfirmed, and %v shallow outputs)",
+			err, report.SkippedImmature, report.SkippedUnconfirmed, report.SkippedShallow)}, http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, WalletConsolidatePOST{report})
+}
+
+// walletContractsByID sorts the wallet's contracts by id and then by role, so
+// that the pages of the list are consistent between calls.
+type walletContractsByID []WalletContract
+
+func (c walletContractsByID) Len() int      { return len(c) }
+func (c walletContractsByID) Swap(i, j int) { c[i], c[j] = c[j], c[i] }
+func (c walletContractsByID) Less(i, j int) bool {
+	if cmp := bytes.Compare(c[i].ID[:], c[j].ID[:]); cmp != 0 {
+		return cmp < 0
+	}
+	return c[i].Role < c[j].Role
+}
+
+// walletContractsHandler handles API calls to /wallet/contracts. Contracts
+// formed by the renter and storage obligations held by the host are listed if
+// the payout for that role goes to one of the wallet's addresses.
+func (srv *Server) walletContractsHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	p, err := srv.scanPage(req)
+	if err != nil {
+		writeError(w, Error{err.Error()}, http.StatusBadRequest)
+		return
+	}
+	addrs := make(map[types.UnlockHash]struct{})
+	for _, addr := range srv.wallet.AllAddresses() {
+		addrs[addr] = struct{}{}
+	}
+	ownsPayout := func(outputs []types.SiacoinOutput, i int) bool {
+		if len(outputs) <= i {
+			return false
+		}
+		_, exists := addrs[outputs[i].UnlockHash]
+		return exists
+	}
+	var height types.BlockHeight
+	if srv.cs != nil {
+		height = srv.cs.Height()
+	}
+
+	contracts := []WalletContract{}
+	if srv.renter != nil {
+		for _, c := range srv.renter.Contracts() {
+			rev := c.LastRevision
+			if !ownsPayout(rev.NewValidProofOutputs, 0) {
+				continue
+			}
+			// The renter cannot tell whether the host submitted a storage
+			// proof, so its contracts are only ever active or expired.
+			status := "active"
+			if height > rev.NewWindowEnd {
+				status = "expired"
+			}
+			contracts = append(contracts, WalletContract{
+				ID:         c.ID,
+				Role:       "renter",
+				Status:     status,
+				Value:      rev.NewValidProofOutputs[0].Value,
+				Expiration: rev.NewWindowStart,
+			})
+		}
+	}
+	if srv.host != nil {
+		for _, so := range srv.host.StorageObligations() {
+			if !ownsPayout(so.ValidProofOutputs, 1) {
+				continue
+			}
+			status := "active"
+			if so.Status != "unresolved" {
+				status = "resolved"
+			} else if height > so.ProofDeadline {
+				status = "expired"
+			}
+			contracts = append(contracts, WalletContract{
+				ID:         so.ContractID,
+				Role:       "host",
+				Status:     status,
+				Value:      so.Value,
+				Expiration: so.Expiration,
+			})
+		}
+	}
+	sort.Sort(walletContractsByID(contracts))
+	start, end, pg := p.bounds(len(contracts))
+	writeJSON(w, WalletContractsGET{
+		Contracts:  contracts[start:end],
+		Pagination: pg,
+	})
+}
+
 // wallet033xHandler handles API calls to /wallet/033x.
 func (srv *Server) wallet033xHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 	source := req.FormValue("source")
@@ -156,11 +384,43 @@ func (srv *Server) walletAddressHandler(w http.ResponseWriter, req *http.Request
 
 // walletAddressHandler handles API calls to /wallet/addresses.
 func (srv *Server) walletAddressesHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	p, err := srv.scanPage(req)
+	if err != nil {
+		writeError(w, Error{err.Error()}, http.StatusBadRequest)
+		return
+	}
+	addrs := srv.wallet.AllAddresses()
+	start, end, pg := p.bounds(len(addrs))
 	writeJSON(w, WalletAddressesGET{
-		Addresses: srv.wallet.AllAddresses(),
+		Addresses:  addrs[start:end],
+		Pagination: pg,
 	})
 }
 
+// walletAddressesBatchHandler handles API calls to /wallet/addresses/batch.
+func (srv *Server) walletAddressesBatchHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	var count uint64
+	_, err := fmt.Sscan(req.FormValue("count"), &count)
+	if err != nil {
+		writeError(w, Error{"unable to parse count: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	if count == 0 || count > maxAddressBatch {
+		writeError(w, Error{fmt.Sprintf("count must be between 1 and %v", maxAddressBatch)}, http.StatusBadRequest)
+		return
+	}
+	ucs, err := srv.wallet.NextAddresses(count)
+	if err != nil {
+		writeError(w, Error{"error after call to /wallet/addresses/batch: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	addrs := make([]types.UnlockHash, len(ucs))
+	for i, uc := range ucs {
+		addrs[i] = uc.UnlockHash()
+	}
+	writeJSON(w, WalletAddressesBatchPOST{Addresses: addrs})
+}
+
 // walletBackupHandler handles API calls to /wallet/backup.
 func (srv *Server) walletBackupHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 	destination := req.FormValue("destination")
@@ -269,6 +529,16 @@ func (srv *Server) walletLockHandler(w http.ResponseWriter, req *http.Request, _
 	writeSuccess(w)
 }
 
+// walletReindexHandler handles API calls to /wallet/reindex.
+func (srv *Server) walletReindexHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	report, err := srv.wallet.Reindex()
+	if err != nil {
+		writeError(w, Error{"error when calling /wallet/reindex: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, WalletReindexPOST{report})
+}
+
 // walletSeedsHandler handles API calls to /wallet/seeds.
 func (srv *Server) walletSeedsHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 	dictionary := mnemonics.DictionaryID(req.FormValue("dictionary"))
@@ -317,26 +587,100 @@ func (srv *Server) walletSiacoinsHandler(w http.ResponseWriter, req *http.Reques
 		writeError(w, Error{"could not read 'amount' from POST call to /wallet/siacoins"}, http.StatusBadRequest)
 		return
 	}
-	dest, err := scanAddress(req.FormValue("destination"))
-	if err != nil {
-		writeError(w, Error{"error after call to /wallet/siacoins: " + err.Error()}, http.StatusBadRequest)
-		return
+
+	// A timelocked output is sent to unlock conditions rather than an
+	// address, because the timelock is part of the preimage of the address.
+	var uc *types.UnlockConditions
+	var dest types.UnlockHash
+	var err error
+	if req.FormValue("timelock") != "" {
+		var timelock types.BlockHeight
+		if _, err := fmt.Sscan(req.FormValue("timelock"), &timelock); err != nil {
+			writeError(w, Error{"could not read 'timelock' from POST call to /wallet/siacoins"}, http.StatusBadRequest)
+			return
+		}
+		if srv.cs != nil && timelock <= srv.cs.Height() {
+			writeError(w, Error{"timelock must be greater than the current block height"}, http.StatusBadRequest)
+			return
+		}
+		if req.FormValue("destination") != "" {
+			writeError(w, Error{"timelocked sends use 'unlockconditions' instead of 'destination'"}, http.StatusBadRequest)
+			return
+		}
+		if ucJSON := req.FormValue("unlockconditions"); ucJSON != "" {
+			uc = new(types.UnlockConditions)
+			if err := json.Unmarshal([]byte(ucJSON), uc); err != nil {
+				writeError(w, Error{"could not read 'unlockconditions' from POST call to /wallet/siacoins: " + err.Error()}, http.StatusBadRequest)
+				return
+			}
+			uc.Timelock = timelock
+		} else {
+			walletUC, err := srv.wallet.NextTimelockedAddress(timelock)
+			if err != nil {
+				writeError(w, Error{"error after call to /wallet/siacoins: " + err.Error()}, http.StatusBadRequest)
+				return
+			}
+			uc = &walletUC
+		}
+		dest = uc.UnlockHash()
+	} else {
+		dest, err = scanAddress(req.FormValue("destination"))
+		if err != nil {
+			writeError(w, Error{"error after call to /wallet/siacoins: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
 	}
 
-	txns, err := srv.wallet.SendSiacoins(amount, dest)
-	if err != nil {
-		writeError(w, Error{"error after call to /wallet/siacoins: " + err.Error()}, http.StatusInternalServerError)
-		return
+	var sets [][]types.Transaction
+	if req.FormValue("autobump") == "true" {
+		maxFee, ok := scanAmount(req.FormValue("maxfee"))
+		if !ok {
+			writeError(w, Error{"could not read 'maxfee' from POST call to /wallet/siacoins"}, http.StatusBadRequest)
+			return
+		}
+		var txns []types.Transaction
+		txns, err = srv.wallet.SendSiacoinsAutoBump(amount, dest, maxFee)
+		sets = [][]types.Transaction{txns}
+	} else {
+		sets, err = srv.wallet.SendSiacoinsSplit(amount, dest)
 	}
 	var txids []types.TransactionID
-	for _, txn := range txns {
-		txids = append(txids, txn.ID())
+	for _, txns := range sets {
+		for _, txn := range txns {
+			txids = append(txids, txn.ID())
+		}
+	}
+	if err != nil {
+		msg := "error after call to /wallet/siacoins: " + err.Error()
+		if len(txids) > 0 {
+			// Part of a split send went through and cannot be taken back.
+			msg += fmt.Sprintf(" (%v of the split transactions were already sent: %v)", len(txids), txids)
+		}
+		writeError(w, Error{msg}, http.StatusInternalServerError)
+		return
 	}
 	writeJSON(w, WalletSiacoinsPOST{
-		TransactionIDs: txids,
+		TransactionIDs:   txids,
+		Split:            len(sets) > 1,
+		UnlockConditions: uc,
 	})
 }
 
+// walletSiacoinsPreviewHandler handles API calls to /wallet/siacoins/preview.
+func (srv *Server) walletSiacoinsPreviewHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	amount, ok := scanAmount(req.FormValue("amount"))
+	if !ok {
+		writeError(w, Error{"could not read 'amount' from POST call to /wallet/siacoins/preview"}, http.StatusBadRequest)
+		return
+	}
+	preview, err := srv.wallet.PreviewSiacoinSend(amount)
+	if err != nil {
+		writeError(w, Error{"error after call to /wallet/siacoins/preview: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, WalletSiacoinsPreviewPOST{preview})
+}
+
 // walletSiafundsHandler handles API calls to /wallet/siafunds.
 func (srv *Server) walletSiafundsHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 	amount, ok := scanAmount(req.FormValue("amount"))
@@ -364,6 +708,53 @@ func (srv *Server) walletSiafundsHandler(w http.ResponseWriter, req *http.Reques
 	})
 }
 
+// walletSiafundClaimsHandlerGET handles API calls to GET
+// /wallet/siafunds/claims.
+func (srv *Server) walletSiafundClaimsHandlerGET(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	_, _, claimBal := srv.wallet.ConfirmedBalance()
+	wsc := WalletSiafundClaimsGET{
+		WalletClaimSettings: srv.wallet.ClaimSettings(),
+		ClaimBalance:        claimBal,
+	}
+	if lc, ok := srv.wallet.LastClaimCollection(); ok {
+		wsc.LastCollection = &lc
+	}
+	writeJSON(w, wsc)
+}
+
+// walletSiafundClaimsHandlerPOST handles API calls to POST
+// /wallet/siafunds/claims. Values that are not supplied are left unchanged.
+func (srv *Server) walletSiafundClaimsHandlerPOST(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	settings := srv.wallet.ClaimSettings()
+	if req.FormValue("enabled") != "" {
+		enabled, err := strconv.ParseBool(req.FormValue("enabled"))
+		if err != nil {
+			writeError(w, Error{"could not read 'enabled': " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		settings.Enabled = enabled
+	}
+	if req.FormValue("threshold") != "" {
+		threshold, ok := scanAmount(req.FormValue("threshold"))
+		if !ok {
+			writeError(w, Error{"could not read 'threshold'"}, http.StatusBadRequest)
+			return
+		}
+		settings.Threshold = threshold
+	}
+	err := srv.wallet.SetClaimSettings(settings)
+	if err != nil {
+		writeError(w, Error{err.Error()}, http.StatusBadRequest)
+		return
+	}
+	writeSuccess(w)
+}
+
+// walletSyncStatusHandler handles API calls to /wallet/syncstatus.
+func (srv *Server) walletSyncStatusHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	writeJSON(w, WalletSyncStatusGET{srv.wallet.SyncStatus()})
+}
+
 // walletTransactionHandler handles API calls to /wallet/transaction/:id.
 func (srv *Server) walletTransactionHandler(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 	// Parse the id from the url.
@@ -410,9 +801,19 @@ func (srv *Server) walletTransactionsHandler(w http.ResponseWriter, req *http.Re
 	}
 	unconfirmedTxns := srv.wallet.UnconfirmedTransactions()
 
+	// Only the confirmed transactions are paginated. Unconfirmed
+	// transactions are bounded by the size of the transaction pool and
+	// always returned in full.
+	p, err := srv.scanPage(req)
+	if err != nil {
+		writeError(w, Error{err.Error()}, http.StatusBadRequest)
+		return
+	}
+	first, last, pg := p.bounds(len(confirmedTxns))
 	writeJSON(w, WalletTransactionsGET{
-		ConfirmedTransactions:   confirmedTxns,
+		ConfirmedTransactions:   confirmedTxns[first:last],
 		UnconfirmedTransactions: unconfirmedTxns,
+		Pagination:              pg,
 	})
 }
 
@@ -428,11 +829,20 @@ func (srv *Server) walletTransactionsAddrHandler(w http.ResponseWriter, req *htt
 		return
 	}
 
+	// as with /wallet/transactions, only the confirmed transactions are
+	// paginated
+	p, err := srv.scanPage(req)
+	if err != nil {
+		writeError(w, Error{err.Error()}, http.StatusBadRequest)
+		return
+	}
 	confirmedATs := srv.wallet.AddressTransactions(addr)
 	unconfirmedATs := srv.wallet.AddressUnconfirmedTransactions(addr)
+	start, end, pg := p.bounds(len(confirmedATs))
 	writeJSON(w, WalletTransactionsGETaddr{
-		ConfirmedTransactions:   confirmedATs,
+		ConfirmedTransactions:   confirmedATs[start:end],
 		UnconfirmedTransactions: unconfirmedATs,
+		Pagination:              pg,
 	})
 }
 
@@ -452,3 +862,52 @@ func (srv *Server) walletUnlockHandler(w http.ResponseWriter, req *http.Request,
 	}
 	writeError(w, Error{"error when calling /wallet/unlock: " + modules.ErrBadEncryptionKey.Error()}, http.StatusBadRequest)
 }
+
+// walletUnlockViewHandler handles API calls to /wallet/unlock/view.
+func (srv *Server) walletUnlockViewHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	potentialKeys := encryptionKeys(req.FormValue("encryptionpassword"))
+	for _, key := range potentialKeys {
+		err := srv.wallet.ViewUnlock(key)
+		if err == nil {
+			writeSuccess(w)
+			return
+		}
+		if err != modules.ErrBadEncryptionKey {
+			writeError(w, Error{"error when calling /wallet/unlock/view: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	writeError(w, Error{"error when calling /wallet/unlock/view: " + modules.ErrBadEncryptionKey.Error()}, http.StatusBadRequest)
+}
+
+// walletVerifySeedHandler handles API calls to /wallet/verifyseed.
+func (srv *Server) walletVerifySeedHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	dictID := mnemonics.DictionaryID(req.FormValue("dictionary"))
+	if dictID == "" {
+		dictID = "english"
+	}
+	seed, err := modules.StringToSeed(req.FormValue("seed"), dictID)
+	if err != nil {
+		writeError(w, Error{"error when calling /wallet/verifyseed: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	// Zero checks every address that the wallet has generated from its
+	// primary seed.
+	var n uint64
+	if req.FormValue("addresses") != "" {
+		if _, err := fmt.Sscan(req.FormValue("addresses"), &n); err != nil {
+			writeError(w, Error{"unable to parse addresses: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		if n == 0 || n > maxVerifySeedAddresses {
+			writeError(w, Error{fmt.Sprintf("addresses must be between 1 and %v", maxVerifySeedAddresses)}, http.StatusBadRequest)
+			return
+		}
+	}
+	v, err := srv.wallet.VerifySeed(seed, n)
+	if err != nil {
+		writeError(w, Error{"error when calling /wallet/verifyseed: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, WalletVerifySeedPOST{v})
+}
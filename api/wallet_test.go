@@ -503,3 +503,191 @@ func TestWalletRelativePathErrorSiag(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+// TestWalletReindex probes the POST call to /wallet/reindex.
+func TestWalletReindex(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	st, err := createServerTester("TestWalletReindex")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.server.Close()
+
+	var wg WalletGET
+	err = st.getAPI("/wallet", &wg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wrp WalletReindexPOST
+	err = st.postAPI("/wallet/reindex", nil, &wrp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(wrp.RemovedSiacoinOutputs) != 0 || len(wrp.RestoredSiacoinOutputs) != 0 || len(wrp.CorrectedSiacoinOutputs) != 0 {
+		t.Error("reindex of a healthy wallet reported discrepancies")
+	}
+	if wrp.SiacoinBalanceAfter.Cmp(wg.ConfirmedSiacoinBalance) != 0 {
+		t.Error("reindex reported a different balance than /wallet")
+	}
+
+	// Reindexing a locked wallet should fail.
+	err = st.stdPostAPI("/wallet/lock", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = st.postAPI("/wallet/reindex", nil, &wrp)
+	if err == nil {
+		t.Error("expected an error when reindexing a locked wallet")
+	}
+}
+
+// TestWalletBalanceBreakdown checks that /wallet/balance/breakdown agrees with
+// the balances reported by /wallet.
+func TestWalletBalanceBreakdown(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	st, err := createServerTester("TestWalletBalanceBreakdown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.server.Close()
+
+	var wbb WalletBalanceBreakdownGET
+	err = st.getAPI("/wallet/balance/breakdown", &wbb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg WalletGET
+	err = st.getAPI("/wallet", &wg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wbb.Spendable.Value.Cmp(wg.ConfirmedSiacoinBalance) != 0 || wbb.Spendable.Count == 0 {
+		t.Error("spendable bucket does not match the confirmed balance:", wbb.Spendable)
+	}
+	// The most recently mined blocks have not matured yet.
+	if wbb.Maturing.Value.IsZero() || wbb.Maturing.Count == 0 {
+		t.Error("expected immature miner payouts in the maturing bucket")
+	}
+	if !wbb.Unconfirmed.Value.IsZero() || wbb.Unconfirmed.Count != 0 {
+		t.Error("expected no unconfirmed outputs")
+	}
+
+	// Sending coins should produce an unconfirmed refund output.
+	_, err = st.wallet.SendSiacoins(types.SiacoinPrecision, types.UnlockHash{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = st.getAPI("/wallet/balance/breakdown", &wbb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = st.getAPI("/wallet", &wg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wbb.Unconfirmed.Value.Cmp(wg.UnconfirmedIncomingSiacoins) != 0 || wbb.Unconfirmed.Count == 0 {
+		t.Error("unconfirmed bucket does not match the unconfirmed incoming balance:", wbb.Unconfirmed)
+	}
+}
+
+// TestWalletConsolidate probes the /wallet/consolidate endpoint.
+func TestWalletConsolidate(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	st, err := createServerTester("TestWalletConsolidate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.server.Close()
+
+	if err := st.stdPostAPI("/wallet/consolidate", url.Values{"maxinputs": {"1"}}); err == nil {
+		t.Fatal("expected an error when consolidating a single input")
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := st.miner.AddBlock(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var wcp WalletConsolidatePOST
+	err = st.postAPI("/wallet/consolidate", url.Values{"minconfirmations": {"0"}}, &wcp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wcp.Inputs < 2 || wcp.SkippedImmature == 0 {
+		t.Fatalf("unexpected consolidation: %+v", wcp)
+	}
+	if _, exists := st.wallet.Transaction(wcp.TransactionID); exists {
+		t.Fatal("consolidation should not be confirmed yet")
+	}
+	if len(st.tpool.TransactionList()) == 0 {
+		t.Fatal("consolidation was not submitted to the transaction pool")
+	}
+}
+
+// TestWalletContracts checks that a node which is both renter and host lists
+// both sides of a contract it formed with itself.
+func TestWalletContracts(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	st, err := createServerTester("TestWalletContracts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.server.Close()
+
+	var wc WalletContractsGET
+	if err = st.getAPI("/wallet/contracts", &wc); err != nil {
+		t.Fatal(err)
+	}
+	if len(wc.Contracts) != 0 {
+		t.Fatal("expected no contracts, got", len(wc.Contracts))
+	}
+
+	// Form a contract between the node's renter and host.
+	if err = st.announceHost(); err != nil {
+		t.Fatal(err)
+	}
+	if err = st.acceptContracts(); err != nil {
+		t.Fatal(err)
+	}
+	if err = st.setHostStorage(); err != nil {
+		t.Fatal(err)
+	}
+	allowanceValues := url.Values{}
+	allowanceValues.Set("funds", testFunds)
+	allowanceValues.Set("period", testPeriod)
+	if err = st.stdPostAPI("/renter", allowanceValues); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = st.getAPI("/wallet/contracts", &wc); err != nil {
+		t.Fatal(err)
+	}
+	if len(wc.Contracts) != 2 {
+		t.Fatal("expected 2 contracts, got", len(wc.Contracts))
+	}
+	roles := make(map[string]WalletContract)
+	for _, c := range wc.Contracts {
+		if c.Status != "active" {
+			t.Error("expected contract to be active, got", c.Status)
+		}
+		roles[c.Role] = c
+	}
+	renter, ok1 := roles["renter"]
+	host, ok2 := roles["host"]
+	if !ok1 || !ok2 {
+		t.Fatal("expected a renter and a host contract:", wc.Contracts)
+	}
+	if renter.ID != host.ID {
+		t.Error("renter and host should be party to the same contract")
+	}
+	if renter.Value.IsZero() || host.Value.IsZero() {
+		t.Error("contract values should be nonzero:", wc.Contracts)
+	}
+}
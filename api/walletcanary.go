@@ -0,0 +1,165 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	// defaultCanaryTimeout is how long /wallet/canary waits for the canary
+	// transaction to confirm when no timeout is given.
+	defaultCanaryTimeout = 30 * time.Minute
+)
+
+var (
+	// defaultCanaryAmount is the amount sent by /wallet/canary when no amount
+	// is given.
+	defaultCanaryAmount = types.NewCurrency64(1)
+
+	// defaultCanaryFee is the miner fee paid by /wallet/canary when no fee is
+	// given. It is the fee the transaction pool requires of each transaction
+	// once the pool is full, so the canary is accepted even when the network
+	// is busy.
+	defaultCanaryFee = types.SiacoinPrecision.Mul64(2)
+
+	// canaryPollInterval is how often /wallet/canary checks whether the
+	// canary transaction has confirmed.
+	canaryPollInterval = time.Second
+
+	errCanaryTimeout = errors.New("canary transaction did not confirm before the timeout")
+)
+
+// WalletCanaryPOST contains the canary transaction sent by /wallet/canary. If
+// the caller waited for confirmation, Confirmed reports whether the
+// transaction confirmed and ConfirmationHeight is the height it confirmed at.
+type WalletCanaryPOST struct {
+	TransactionID      types.TransactionID `json:"transactionid"`
+	Address            types.UnlockHash    `json:"address"`
+	Amount             types.Currency      `json:"amount"`
+	Fee                types.Currency      `json:"fee"`
+	Confirmed          bool                `json:"confirmed"`
+	ConfirmationHeight types.BlockHeight   `json:"confirmationheight"`
+}
+
+// sendCanary sends amount to a new address of the wallet, paying the given
+// miner fee, and returns the id of the transaction that was broadcast.
+func (srv *Server) sendCanary(amount, fee types.Currency) (types.TransactionID, types.UnlockHash, error) {
+	uc, err := srv.wallet.NextAddress()
+	if err != nil {
+		return types.TransactionID{}, types.UnlockHash{}, err
+	}
+	dest := uc.UnlockHash()
+
+	txnBuilder := srv.wallet.StartTransaction()
+	if err := txnBuilder.FundSiacoins(amount.Add(fee)); err != nil {
+		txnBuilder.Drop()
+		return types.TransactionID{}, types.UnlockHash{}, err
+	}
+	txnBuilder.AddMinerFee(fee)
+	txnBuilder.AddSiacoinOutput(types.SiacoinOutput{
+		Value:      amount,
+		UnlockHash: dest,
+	})
+	txnSet, err := txnBuilder.Sign(true)
+	if err != nil {
+		txnBuilder.Drop()
+		return types.TransactionID{}, types.UnlockHash{}, err
+	}
+	if err := srv.tpool.AcceptTransactionSet(txnSet); err != nil {
+		txnBuilder.Drop()
+		return types.TransactionID{}, types.UnlockHash{}, err
+	}
+	return txnSet[len(txnSet)-1].ID(), dest, nil
+}
+
+// waitForConfirmation polls the wallet until the transaction with the given
+// id has confirmed, the timeout passes, or cancel is closed.
+func (srv *Server) waitForConfirmation(id types.TransactionID, timeout time.Duration, cancel <-chan bool) (modules.ProcessedTransaction, error) {
+	deadline := time.After(timeout)
+	ticker := time.NewTicker(canaryPollInterval)
+	defer ticker.Stop()
+	for {
+		// the wallet only knows a transaction by id once it has confirmed
+		if pt, ok := srv.wallet.Transaction(id); ok {
+			return pt, nil
+		}
+		select {
+		case <-ticker.C:
+		case <-deadline:
+			return modules.ProcessedTransaction{}, errCanaryTimeout
+		case <-cancel:
+			return modules.ProcessedTransaction{}, errors.New("client disconnected")
+		}
+	}
+}
+
+// walletCanaryHandler handles API calls to /wallet/canary.
+func (srv *Server) walletCanaryHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	amount, fee := defaultCanaryAmount, defaultCanaryFee
+	if req.FormValue("amount") != "" {
+		var ok bool
+		amount, ok = scanAmount(req.FormValue("amount"))
+		if !ok || amount.IsZero() {
+			writeError(w, Error{"could not read 'amount' from POST call to /wallet/canary"}, http.StatusBadRequest)
+			return
+		}
+	}
+	if req.FormValue("fee") != "" {
+		var ok bool
+		fee, ok = scanAmount(req.FormValue("fee"))
+		if !ok {
+			writeError(w, Error{"could not read 'fee' from POST call to /wallet/canary"}, http.StatusBadRequest)
+			return
+		}
+	}
+	timeout := defaultCanaryTimeout
+	if req.FormValue("timeout") != "" {
+		var secs uint64
+		if _, err := fmt.Sscan(req.FormValue("timeout"), &secs); err != nil {
+			writeError(w, Error{"unable to parse timeout: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		timeout = time.Duration(secs) * time.Second
+	}
+
+	id, dest, err := srv.sendCanary(amount, fee)
+	if err != nil {
+		writeError(w, Error{"error after call to /wallet/canary: " + err.Error()}, http.StatusInternalServerError)
+		return
+	}
+	wcp := WalletCanaryPOST{
+		TransactionID: id,
+		Address:       dest,
+		Amount:        amount,
+		Fee:           fee,
+	}
+	if req.FormValue("waitconfirm") != "true" {
+		writeJSON(w, wcp)
+		return
+	}
+
+	var cancel <-chan bool
+	if cn, ok := w.(http.CloseNotifier); ok {
+		cancel = cn.CloseNotify()
+	}
+	pt, err := srv.waitForConfirmation(id, timeout, cancel)
+	if err == errCanaryTimeout {
+		// the transaction was still broadcast, so report it along with the
+		// fact that it did not confirm
+		writeJSON(w, wcp)
+		return
+	} else if err != nil {
+		writeError(w, Error{"error after call to /wallet/canary: " + err.Error()}, http.StatusInternalServerError)
+		return
+	}
+	wcp.Confirmed = true
+	wcp.ConfirmationHeight = pt.ConfirmationHeight
+	writeJSON(w, wcp)
+}
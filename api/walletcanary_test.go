@@ -0,0 +1,76 @@
+package api
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// TestWalletCanary checks that /wallet/canary sends the requested amount to the
+// wallet and reports the confirmation height when asked to wait.
+func TestWalletCanary(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	st, err := createServerTester("TestWalletCanary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.server.Close()
+
+	// Send a canary without waiting. With a timeout of zero, waiting
+	// returns before the transaction can be mined.
+	for _, values := range []url.Values{
+		{"amount": {"5"}, "fee": {"100"}},
+		{"amount": {"5"}, "fee": {"100"}, "waitconfirm": {"true"}, "timeout": {"0"}},
+	} {
+		var wcp WalletCanaryPOST
+		if err := st.postAPI("/wallet/canary", values, &wcp); err != nil {
+			t.Fatal(err)
+		}
+		if wcp.Amount.Cmp(types.NewCurrency64(5)) != 0 || wcp.Fee.Cmp(types.NewCurrency64(100)) != 0 {
+			t.Fatal("canary reports the wrong amount or fee:", wcp.Amount, wcp.Fee)
+		}
+		if wcp.Confirmed {
+			t.Fatal("canary confirmed before a block was mined")
+		}
+	}
+	if err := st.stdPostAPI("/wallet/canary", url.Values{"amount": {"0"}}); err == nil {
+		t.Fatal("expected an error for a zero amount")
+	}
+
+	// Wait for a canary to confirm while blocks are mined.
+	done := make(chan error)
+	var wcp WalletCanaryPOST
+	go func() {
+		done <- st.postAPI("/wallet/canary", url.Values{"waitconfirm": {"true"}, "timeout": {"60"}}, &wcp)
+	}()
+	for {
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !wcp.Confirmed {
+				t.Fatal("canary did not confirm")
+			}
+			if wcp.ConfirmationHeight == 0 || wcp.ConfirmationHeight > st.cs.Height() {
+				t.Fatal("bad confirmation height:", wcp.ConfirmationHeight)
+			}
+			if wcp.Fee.Cmp(defaultCanaryFee) != 0 {
+				t.Fatal("canary did not pay the default fee")
+			}
+			var wtg WalletTransactionGETid
+			if err := st.getAPI("/wallet/transaction/"+wcp.TransactionID.String(), &wtg); err != nil {
+				t.Fatal(err)
+			}
+			return
+		case <-time.After(100 * time.Millisecond):
+			if _, err := st.miner.AddBlock(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
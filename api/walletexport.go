@@ -0,0 +1,196 @@
+package api
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// ofxCurrency is the currency declared in OFX exports. Siacoin has no ISO 4217
+// code, so the code reserved for private use by its X prefix is used.
+const ofxCurrency = "XSC"
+
+var errUnknownExportFormat = errors.New("format must be one of 'csv', 'ofx', or 'qif'")
+
+// exportEntry is a confirmed wallet transaction reduced to the net change in
+// the wallet's siacoin balance.
+type exportEntry struct {
+	id        types.TransactionID
+	height    types.BlockHeight
+	timestamp time.Time
+	incoming  bool
+	amount    types.Currency
+}
+
+// signedAmount returns the amount of the entry in siacoins, negative if the
+// entry is outgoing. The amount is exact, with trailing zeros removed.
+func (e exportEntry) signedAmount() string {
+	sc := new(big.Rat).SetFrac(e.amount.Big(), types.SiacoinPrecision.Big()).FloatString(24)
+	sc = strings.TrimRight(strings.TrimRight(sc, "0"), ".")
+	if !e.incoming {
+		sc = "-" + sc
+	}
+	return sc
+}
+
+// exportEntries converts processed transactions into export entries. Siafund
+// transfers are ignored, and transactions that do not change the wallet's
+// siacoin balance are skipped.
+func exportEntries(pts []modules.ProcessedTransaction) []exportEntry {
+	var entries []exportEntry
+	for _, pt := range pts {
+		var incoming, outgoing types.Currency
+		for _, input := range pt.Inputs {
+			if input.FundType == types.SpecifierSiacoinInput && input.WalletAddress {
+				outgoing = outgoing.Add(input.Value)
+			}
+		}
+		for _, output := range pt.Outputs {
+			if (output.FundType == types.SpecifierSiacoinOutput || output.FundType == types.SpecifierMinerPayout) && output.WalletAddress {
+				incoming = incoming.Add(output.Value)
+			}
+		}
+		e := exportEntry{
+			id:        pt.TransactionID,
+			height:    pt.ConfirmationHeight,
+			timestamp: time.Unix(int64(pt.ConfirmationTimestamp), 0).UTC(),
+		}
+		switch incoming.Cmp(outgoing) {
+		case 0:
+			continue
+		case 1:
+			e.incoming, e.amount = true, incoming.Sub(outgoing)
+		case -1:
+			e.amount = outgoing.Sub(incoming)
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+// writeCSV writes the entries as comma separated values with a header row.
+func writeCSV(w io.Writer, entries []exportEntry) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"transactionid", "confirmationheight", "date", "amount"})
+	for _, e := range entries {
+		cw.Write([]string{
+			e.id.String(),
+			strconv.FormatUint(uint64(e.height), 10),
+			e.timestamp.Format(time.RFC3339),
+			e.signedAmount(),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeQIF writes the entries as a QIF bank account. QIF has no way to declare
+// a currency; amounts are in siacoins.
+func writeQIF(w io.Writer, entries []exportEntry) error {
+	if _, err := fmt.Fprint(w, "!Type:Bank\n"); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		_, err := fmt.Fprintf(w, "D%s\nT%s\nN%s\nPSia transaction\nMSiacoin transaction at height %d\n^\n",
+			e.timestamp.Format("01/02/2006"), e.signedAmount(), e.id, e.height)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ofxTime formats t as an OFX date and time.
+func ofxTime(t time.Time) string {
+	return t.UTC().Format("20060102150405") + "[0:GMT]"
+}
+
+// writeOFX writes the entries as an OFX 1.02 bank statement for an account
+// denominated in siacoins. The ledger balance is the wallet's confirmed
+// siacoin balance at the time of the export.
+func writeOFX(w io.Writer, entries []exportEntry, balance types.Currency, now time.Time) error {
+	start, end := now, now
+	if len(entries) > 0 {
+		start, end = entries[0].timestamp, entries[len(entries)-1].timestamp
+	}
+	var b bytes.Buffer
+	b.WriteString("OFXHEADER:100\nDATA:OFXSGML\nVERSION:102\nSECURITY:NONE\nENCODING:USASCII\nCHARSET:1252\nCOMPRESSION:NONE\nOLDFILEUID:NONE\nNEWFILEUID:NONE\n\n")
+	b.WriteString("<OFX>\n<SIGNONMSGSRSV1>\n<SONRS>\n<STATUS>\n<CODE>0\n<SEVERITY>INFO\n</STATUS>\n")
+	fmt.Fprintf(&b, "<DTSERVER>%s\n<LANGUAGE>ENG\n</SONRS>\n</SIGNONMSGSRSV1>\n", ofxTime(now))
+	b.WriteString("<BANKMSGSRSV1>\n<STMTTRNRS>\n<TRNUID>0\n<STATUS>\n<CODE>0\n<SEVERITY>INFO\n</STATUS>\n<STMTRS>\n")
+	fmt.Fprintf(&b, "<CURDEF>%s\n", ofxCurrency)
+	b.WriteString("<BANKACCTFROM>\n<BANKID>SIA\n<ACCTID>WALLET\n<ACCTTYPE>CHECKING\n</BANKACCTFROM>\n")
+	fmt.Fprintf(&b, "<BANKTRANLIST>\n<DTSTART>%s\n<DTEND>%s\n", ofxTime(start), ofxTime(end))
+	for _, e := range entries {
+		trnType := "DEBIT"
+		if e.incoming {
+			trnType = "CREDIT"
+		}
+		fmt.Fprintf(&b, "<STMTTRN>\n<TRNTYPE>%s\n<DTPOSTED>%s\n<TRNAMT>%s\n<FITID>%s\n<NAME>Sia transaction\n<MEMO>Siacoin transaction at height %d\n</STMTTRN>\n",
+			trnType, ofxTime(e.timestamp), e.signedAmount(), e.id, e.height)
+	}
+	b.WriteString("</BANKTRANLIST>\n")
+	fmt.Fprintf(&b, "<LEDGERBAL>\n<BALAMT>%s\n<DTASOF>%s\n</LEDGERBAL>\n", exportEntry{incoming: true, amount: balance}.signedAmount(), ofxTime(now))
+	b.WriteString("</STMTRS>\n</STMTTRNRS>\n</BANKMSGSRSV1>\n</OFX>\n")
+	_, err := b.WriteTo(w)
+	return err
+}
+
+// walletExportHandler handles API calls to /wallet/export.
+func (srv *Server) walletExportHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	format := req.FormValue("format")
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "ofx" && format != "qif" {
+		writeError(w, Error{errUnknownExportFormat.Error()}, http.StatusBadRequest)
+		return
+	}
+	start, end := types.BlockHeight(0), srv.cs.Height()
+	if req.FormValue("startheight") != "" {
+		if _, err := fmt.Sscan(req.FormValue("startheight"), &start); err != nil {
+			writeError(w, Error{"unable to parse startheight: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	if req.FormValue("endheight") != "" {
+		if _, err := fmt.Sscan(req.FormValue("endheight"), &end); err != nil {
+			writeError(w, Error{"unable to parse endheight: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	pts, err := srv.wallet.Transactions(start, end)
+	if err != nil {
+		writeError(w, Error{"error after call to /wallet/export: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	entries := exportEntries(pts)
+
+	// Errors while writing can only be caused by the client going away, as
+	// the status has already been sent.
+	w.Header().Set("Content-Disposition", `attachment; filename="sia-transactions.`+format+`"`)
+	switch format {
+	case "csv":
+		w.Header().Set("Content-Type", "text/csv")
+		_ = writeCSV(w, entries)
+	case "qif":
+		w.Header().Set("Content-Type", "application/qif")
+		_ = writeQIF(w, entries)
+	case "ofx":
+		balance, _, _ := srv.wallet.ConfirmedBalance()
+		w.Header().Set("Content-Type", "application/x-ofx")
+		_ = writeOFX(w, entries, balance, time.Now())
+	}
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// TestExportEntries checks that transactions are reduced to their net effect
+// on the wallet's siacoin balance, and that the serializers render them.
+func TestExportEntries(t *testing.T) {
+	id1 := types.TransactionID{1}.String()
+	sc := func(n uint64) types.Currency { return types.SiacoinPrecision.Mul64(n) }
+	pts := []modules.ProcessedTransaction{
+		{
+			// Receive 10 SC.
+			TransactionID:         types.TransactionID{1},
+			ConfirmationHeight:    5,
+			ConfirmationTimestamp: 1476619200,
+			Outputs: []modules.ProcessedOutput{
+				{FundType: types.SpecifierSiacoinOutput, WalletAddress: true, Value: sc(10)},
+				{FundType: types.SpecifierSiacoinOutput, WalletAddress: false, Value: sc(3)},
+			},
+		},
+		{
+			// Send 2.5 SC, spending a 10 SC input and receiving 7.5 SC change.
+			TransactionID:         types.TransactionID{2},
+			ConfirmationHeight:    6,
+			ConfirmationTimestamp: 1476622800,
+			Inputs: []modules.ProcessedInput{
+				{FundType: types.SpecifierSiacoinInput, WalletAddress: true, Value: sc(10)},
+			},
+			Outputs: []modules.ProcessedOutput{
+				{FundType: types.SpecifierSiacoinOutput, WalletAddress: true, Value: sc(15).Div64(2)},
+				{FundType: types.SpecifierSiacoinOutput, WalletAddress: false, Value: sc(5).Div64(2)},
+			},
+		},
+		{
+			// A siafund transfer does not change the siacoin balance.
+			TransactionID:      types.TransactionID{3},
+			ConfirmationHeight: 7,
+			Inputs: []modules.ProcessedInput{
+				{FundType: types.SpecifierSiafundInput, WalletAddress: true, Value: types.NewCurrency64(1)},
+			},
+		},
+	}
+
+	entries := exportEntries(pts)
+	if len(entries) != 2 {
+		t.Fatal("expected 2 entries, got", len(entries))
+	}
+	if entries[0].signedAmount() != "10" || entries[1].signedAmount() != "-2.5" {
+		t.Fatal("wrong amounts:", entries[0].signedAmount(), entries[1].signedAmount())
+	}
+
+	var buf bytes.Buffer
+	if err := writeCSV(&buf, entries); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || lines[1] != id1+",5,2016-10-16T12:00:00Z,10" {
+		t.Fatal("unexpected CSV:", buf.String())
+	}
+
+	buf.Reset()
+	if err := writeQIF(&buf, entries); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "!Type:Bank\nD10/16/2016\nT10\n") || strings.Count(buf.String(), "^\n") != 2 {
+		t.Fatal("unexpected QIF:", buf.String())
+	}
+
+	buf.Reset()
+	if err := writeOFX(&buf, entries, sc(42), time.Unix(1476626400, 0)); err != nil {
+		t.Fatal(err)
+	}
+	ofx := buf.String()
+	for _, s := range []string{
+		"<CURDEF>XSC\n",
+		"<TRNTYPE>CREDIT\n<DTPOSTED>20161016120000[0:GMT]\n<TRNAMT>10\n<FITID>" + id1,
+		"<TRNTYPE>DEBIT\n<DTPOSTED>20161016130000[0:GMT]\n<TRNAMT>-2.5\n",
+		"<BALAMT>42\n",
+	} {
+		if !strings.Contains(ofx, s) {
+			t.Fatalf("OFX is missing %q:\n%v", s, ofx)
+		}
+	}
+}
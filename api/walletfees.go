@@ -0,0 +1,157 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// maxFeePeriods is the largest number of periods that /wallet/fees will break
+// a range of blocks into.
+const maxFeePeriods = 1000
+
+type (
+	// WalletFeePeriod contains the miner fees paid by the wallet in a range
+	// of blocks, along with the fees paid from the start of the query up to
+	// the end of the range.
+	WalletFeePeriod struct {
+		StartHeight  types.BlockHeight `json:"startheight"`
+		EndHeight    types.BlockHeight `json:"endheight"`
+		Fees         types.Currency    `json:"fees"`
+		Transactions int               `json:"transactions"`
+		RunningTotal types.Currency    `json:"runningtotal"`
+	}
+
+	// WalletFeesGET contains the miner fees paid by the wallet, in total and
+	// per period.
+	WalletFeesGET struct {
+		Total   types.Currency    `json:"total"`
+		Periods []WalletFeePeriod `json:"periods"`
+	}
+)
+
+// walletMinerFees returns the share of the miner fees of a transaction that
+// the wallet paid, and false if the wallet did not fund the transaction. The
+// wallet pays all of the fees if all of the siacoin inputs belong to it. If
+// only some do, as in a transaction funded jointly with another party such as
+// a file contract, the fees are prorated by the value of the wallet's inputs.
+func walletMinerFees(pt modules.ProcessedTransaction) (types.Currency, bool) {
+	var walletInputs, totalInputs types.Currency
+	funded, shared := false, false
+	for _, input := range pt.Inputs {
+		if input.FundType != types.SpecifierSiacoinInput {
+			continue
+		}
+		totalInputs = totalInputs.Add(input.Value)
+		if input.WalletAddress {
+			walletInputs = walletInputs.Add(input.Value)
+			funded = true
+		} else {
+			shared = true
+		}
+	}
+	if !funded {
+		return types.Currency{}, false
+	}
+	var fees types.Currency
+	for _, output := range pt.Outputs {
+		if output.FundType == types.SpecifierMinerFee {
+			fees = fees.Add(output.Value)
+		}
+	}
+	if shared {
+		if totalInputs.IsZero() {
+			return types.ZeroCurrency, true
+		}
+		fees = fees.Mul(walletInputs).Div(totalInputs)
+	}
+	return fees, true
+}
+
+// feePeriods sums the miner fees paid by the wallet in consecutive periods of
+// the given number of blocks, covering the heights from start to end.
+func feePeriods(pts []modules.ProcessedTransaction, start, end, period types.BlockHeight) []WalletFeePeriod {
+	var periods []WalletFeePeriod
+	for height := start; height <= end; height += period {
+		p := WalletFeePeriod{
+			StartHeight: height,
+			EndHeight:   height + period - 1,
+		}
+		if p.EndHeight > end || p.EndHeight < height {
+			p.EndHeight = end
+		}
+		periods = append(periods, p)
+		if p.EndHeight == end {
+			break
+		}
+	}
+	for _, pt := range pts {
+		if pt.ConfirmationHeight < start || pt.ConfirmationHeight > end {
+			continue
+		}
+		fees, ok := walletMinerFees(pt)
+		if !ok {
+			continue
+		}
+		i := (pt.ConfirmationHeight - start) / period
+		periods[i].Fees = periods[i].Fees.Add(fees)
+		periods[i].Transactions++
+	}
+	var total types.Currency
+	for i := range periods {
+		total = total.Add(periods[i].Fees)
+		periods[i].RunningTotal = total
+	}
+	return periods
+}
+
+// walletFeesHandler handles API calls to /wallet/fees.
+func (srv *Server) walletFeesHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	from, to := types.BlockHeight(0), srv.cs.Height()
+	if req.FormValue("from") != "" {
+		if _, err := fmt.Sscan(req.FormValue("from"), &from); err != nil {
+			writeError(w, Error{"unable to parse from: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	if req.FormValue("to") != "" {
+		if _, err := fmt.Sscan(req.FormValue("to"), &to); err != nil {
+			writeError(w, Error{"unable to parse to: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	if from > to {
+		writeError(w, Error{"from must not be greater than to"}, http.StatusBadRequest)
+		return
+	}
+	period := to - from + 1
+	if req.FormValue("period") != "" {
+		if _, err := fmt.Sscan(req.FormValue("period"), &period); err != nil {
+			writeError(w, Error{"unable to parse period: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+	}
+	if period == 0 {
+		writeError(w, Error{"period must be at least 1"}, http.StatusBadRequest)
+		return
+	}
+	if (to-from)/period >= maxFeePeriods {
+		writeError(w, Error{fmt.Sprintf("the range cannot be split into more than %v periods", maxFeePeriods)}, http.StatusBadRequest)
+		return
+	}
+
+	pts, err := srv.wallet.Transactions(from, to)
+	if err != nil {
+		writeError(w, Error{"error after call to /wallet/fees: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	periods := feePeriods(pts, from, to, period)
+	writeJSON(w, WalletFeesGET{
+		Total:   periods[len(periods)-1].RunningTotal,
+		Periods: periods,
+	})
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// TestFeePeriods checks that only the wallet's share of the fees of
+// transactions funded by the wallet is counted, and that the fees are summed
+// into the right periods.
+func TestFeePeriods(t *testing.T) {
+	funded := func(height types.BlockHeight, fee uint64) modules.ProcessedTransaction {
+		return modules.ProcessedTransaction{
+			ConfirmationHeight: height,
+			Inputs: []modules.ProcessedInput{
+				{FundType: types.SpecifierSiacoinInput, WalletAddress: true},
+			},
+			Outputs: []modules.ProcessedOutput{
+				{FundType: types.SpecifierMinerFee, Value: types.NewCurrency64(fee)},
+			},
+		}
+	}
+	received := funded(12, 1000)
+	received.Inputs[0].WalletAddress = false
+	// the wallet put up 3 of the 4 SC of inputs, so it pays 3/4 of the fee
+	shared := funded(16, 8)
+	shared.Inputs = []modules.ProcessedInput{
+		{FundType: types.SpecifierSiacoinInput, WalletAddress: true, Value: types.NewCurrency64(3)},
+		{FundType: types.SpecifierSiacoinInput, Value: types.NewCurrency64(1)},
+	}
+	pts := []modules.ProcessedTransaction{
+		funded(5, 100), // before the range
+		funded(10, 1),
+		funded(14, 2),
+		received,
+		shared,
+		funded(25, 4),
+		funded(30, 8),
+	}
+
+	periods := feePeriods(pts, 10, 27, 5)
+	if len(periods) != 4 {
+		t.Fatal("expected 4 periods, got", len(periods))
+	}
+	if periods[3].StartHeight != 25 || periods[3].EndHeight != 27 {
+		t.Fatal("last period should end at the end of the range:", periods[3])
+	}
+	expected := []struct {
+		fees, total uint64
+		txns        int
+	}{{3, 3, 2}, {6, 9, 1}, {0, 9, 0}, {4, 13, 1}}
+	for i, e := range expected {
+		p := periods[i]
+		if p.Fees.Cmp(types.NewCurrency64(e.fees)) != 0 || p.RunningTotal.Cmp(types.NewCurrency64(e.total)) != 0 || p.Transactions != e.txns {
+			t.Errorf("period %v is wrong: %+v", i, p)
+		}
+	}
+
+	// A single period covers the whole range.
+	periods = feePeriods(pts, 0, 30, 31)
+	if len(periods) != 1 || periods[0].Fees.Cmp(types.NewCurrency64(121)) != 0 {
+		t.Fatal("wrong single period:", periods)
+	}
+}
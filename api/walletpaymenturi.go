@@ -0,0 +1,153 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/NebulousLabs/Sia/types"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// paymentURIScheme is the scheme of payment URIs.
+const paymentURIScheme = "siacoin"
+
+var (
+	errBadURIScheme       = errors.New("payment URI must start with " + paymentURIScheme + ":")
+	errBadURIAmount       = errors.New("payment URI amount must be a decimal number of siacoins with at most 24 decimal places")
+	errRequiredURIParam   = errors.New("payment URI has an unsupported required parameter")
+	errDuplicateURIParams = errors.New("payment URI has a repeated parameter")
+)
+
+// WalletPaymentURIGET contains a payment URI along with the fields it
+// encodes. Amount is in hastings.
+type WalletPaymentURIGET struct {
+	URI     string           `json:"uri"`
+	Address types.UnlockHash `json:"address"`
+	Amount  types.Currency   `json:"amount"`
+	Label   string           `json:"label"`
+}
+
+// formatSiacoins writes an amount of hastings as a decimal number of
+// siacoins, with no trailing zeros after the decimal point.
+func formatSiacoins(c types.Currency) string {
+	sc, h := new(big.Int).QuoRem(c.Big(), types.SiacoinPrecision.Big(), new(big.Int))
+	if h.Sign() == 0 {
+		return sc.String()
+	}
+	frac := fmt.Sprintf("%024s", h.String())
+	return sc.String() + "." + strings.TrimRight(frac, "0")
+}
+
+// parseURIAmount parses a decimal number of siacoins into hastings.
+func parseURIAmount(s string) (types.Currency, error) {
+	whole, frac := s, ""
+	if i := strings.Index(s, "."); i >= 0 {
+		whole, frac = s[:i], s[i+1:]
+	}
+	if whole == "" || len(frac) > 24 || strings.Trim(whole+frac, "0123456789") != "" {
+		return types.Currency{}, errBadURIAmount
+	}
+	h, ok := new(big.Int).SetString(whole+frac+strings.Repeat("0", 24-len(frac)), 10)
+	if !ok {
+		return types.Currency{}, errBadURIAmount
+	}
+	return types.NewCurrency(h), nil
+}
+
+// encodePaymentURI builds the canonical payment URI for a payment to addr.
+// The amount and label are left out when they are zero or empty.
+func encodePaymentURI(addr types.UnlockHash, amount types.Currency, label string) string {
+	uri := paymentURIScheme + ":" + addr.String()
+	var params []string
+	if !amount.IsZero() {
+		params = append(params, "amount="+formatSiacoins(amount))
+	}
+	if label != "" {
+		params = append(params, "label="+strings.Replace(url.QueryEscape(label), "+", "%20", -1))
+	}
+	if len(params) > 0 {
+		uri += "?" + strings.Join(params, "&")
+	}
+	return uri
+}
+
+// decodePaymentURI decodes a payment URI. Unknown parameters are ignored
+// unless their name starts with "req-", which marks a parameter that the
+// payment cannot be made without.
+func decodePaymentURI(uri string) (WalletPaymentURIGET, error) {
+	i := strings.Index(uri, ":")
+	if i < 0 || !strings.EqualFold(uri[:i], paymentURIScheme) {
+		return WalletPaymentURIGET{}, errBadURIScheme
+	}
+	rest, rawQuery := uri[i+1:], ""
+	if j := strings.Index(rest, "?"); j >= 0 {
+		rest, rawQuery = rest[:j], rest[j+1:]
+	}
+
+	var p WalletPaymentURIGET
+	if err := p.Address.LoadString(rest); err != nil {
+		return WalletPaymentURIGET{}, err
+	}
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return WalletPaymentURIGET{}, err
+	}
+	for name, values := range query {
+		if len(values) > 1 {
+			return WalletPaymentURIGET{}, errDuplicateURIParams
+		}
+		switch {
+		case name == "amount":
+			p.Amount, err = parseURIAmount(values[0])
+			if err != nil {
+				return WalletPaymentURIGET{}, err
+			}
+		case name == "label":
+			p.Label = values[0]
+		case strings.HasPrefix(name, "req-"):
+			return WalletPaymentURIGET{}, errRequiredURIParam
+		}
+	}
+	p.URI = encodePaymentURI(p.Address, p.Amount, p.Label)
+	return p, nil
+}
+
+// walletPaymentURIHandler handles API calls to /wallet/paymenturi.
+func (srv *Server) walletPaymentURIHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	addr, err := scanAddress(req.FormValue("address"))
+	if err != nil {
+		writeError(w, Error{"unable to parse address: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	var amount types.Currency
+	if req.FormValue("amount") != "" {
+		var ok bool
+		amount, ok = scanAmount(req.FormValue("amount"))
+		if !ok {
+			writeError(w, Error{"could not read amount"}, http.StatusBadRequest)
+			return
+		}
+	}
+	label := req.FormValue("label")
+	writeJSON(w, WalletPaymentURIGET{
+		URI:     encodePaymentURI(addr, amount, label),
+		Address: addr,
+		Amount:  amount,
+		Label:   label,
+	})
+}
+
+// walletPaymentURIParseHandler handles API calls to /wallet/paymenturi/parse.
+func (srv *Server) walletPaymentURIParseHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	p, err := decodePaymentURI(req.FormValue("uri"))
+	if err != nil {
+		writeError(w, Error{"unable to parse payment URI: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, p)
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// TestPaymentURI checks that payment URIs are encoded canonically and decode
+// back to the same fields.
+func TestPaymentURI(t *testing.T) {
+	var addr types.UnlockHash
+	addr[0] = 1
+	amount := types.SiacoinPrecision.Mul64(3).Add(types.SiacoinPrecision.Div64(4))
+	uri := encodePaymentURI(addr, amount, "rent & bills")
+	if exp := "siacoin:" + addr.String() + "?amount=3.25&label=rent%20%26%20bills"; uri != exp {
+		t.Fatalf("expected %v, got %v", exp, uri)
+	}
+	if uri := encodePaymentURI(addr, types.ZeroCurrency, ""); uri != "siacoin:"+addr.String() {
+		t.Fatal("unexpected URI:", uri)
+	}
+
+	p, err := decodePaymentURI("SIACOIN:" + addr.String() + "?label=rent+%26+bills&amount=3.250&foo=bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Address != addr || p.Amount.Cmp(amount) != 0 || p.Label != "rent & bills" || p.URI != uri {
+		t.Fatalf("unexpected payment: %+v", p)
+	}
+	if p, err := decodePaymentURI("siacoin:" + addr.String() + "?amount=0.000000000000000000000001"); err != nil || p.Amount.Cmp(types.NewCurrency64(1)) != 0 {
+		t.Fatal("expected 1 hasting, got", p.Amount, err)
+	}
+
+	bad := map[string]error{
+		"bitcoin:" + addr.String():                                              errBadURIScheme,
+		"siacoin:" + addr.String() + "?amount=1e3":                              errBadURIAmount,
+		"siacoin:" + addr.String() + "?amount=-1":                               errBadURIAmount,
+		"siacoin:" + addr.String() + "?amount=.5":                               errBadURIAmount,
+		"siacoin:" + addr.String() + "?amount=1&amount=2":                       errDuplicateURIParams,
+		"siacoin:" + addr.String() + "?req-expires=1000":                        errRequiredURIParam,
+		"siacoin:" + addr.String() + "?amount=1." + "0000000000000000000000001": errBadURIAmount,
+	}
+	for uri, exp := range bad {
+		if _, err := decodePaymentURI(uri); err != exp {
+			t.Errorf("%v: expected %v, got %v", uri, exp, err)
+		}
+	}
+	if _, err := decodePaymentURI("siacoin:notanaddress"); err == nil {
+		t.Fatal("expected an error for a bad address")
+	}
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type (
+	// WalletScheduleGET contains the sends that the wallet holds until their
+	// broadcast time, along with the sends that have already finished.
+	WalletScheduleGET struct {
+		ScheduledSends []modules.WalletScheduledSend `json:"scheduledsends"`
+	}
+
+	// WalletSchedulePOST contains the send created by a call to
+	// /wallet/schedule.
+	WalletSchedulePOST struct {
+		modules.WalletScheduledSend
+	}
+)
+
+// scanRecipients parses a comma-separated list of address:amount pairs, where
+// the amounts are in hastings.
+func scanRecipients(s string) ([]types.SiacoinOutput, error) {
+	if s == "" {
+		return nil, errors.New("no recipients were given")
+	}
+	var outputs []types.SiacoinOutput
+	for _, pair := range strings.Split(s, ",") {
+		i := strings.LastIndex(pair, ":")
+		if i == -1 {
+			return nil, errors.New("expected address:amount, got " + pair)
+		}
+		addr, err := scanAddress(pair[:i])
+		if err != nil {
+			return nil, errors.New("could not read address " + pair[:i] + ": " + err.Error())
+		}
+		amount, ok := scanAmount(pair[i+1:])
+		if !ok {
+			return nil, errors.New("could not read amount " + pair[i+1:])
+		}
+		outputs = append(outputs, types.SiacoinOutput{Value: amount, UnlockHash: addr})
+	}
+	return outputs, nil
+}
+
+// walletScheduleHandlerGET handles the API call that lists the scheduled
+// sends of the wallet.
+func (srv *Server) walletScheduleHandlerGET(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	writeJSON(w, WalletScheduleGET{ScheduledSends: srv.wallet.ScheduledSends()})
+}
+
+// walletScheduleHandlerPOST handles the API call that schedules a send to be
+// broadcast later.
+func (srv *Server) walletScheduleHandlerPOST(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	outputs, err := scanRecipients(req.FormValue("recipients"))
+	if err != nil {
+		writeError(w, Error{"could not read 'recipients' from POST call to /wallet/schedule: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	var timestamp int64
+	if _, err := fmt.Sscan(req.FormValue("broadcastat"), &timestamp); err != nil {
+		writeError(w, Error{"could not read 'broadcastat' from POST call to /wallet/schedule: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	ss, err := srv.wallet.ScheduleSend(outputs, time.Unix(timestamp, 0))
+	if err != nil {
+		writeError(w, Error{"error after call to /wallet/schedule: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, WalletSchedulePOST{ss})
+}
+
+// walletScheduleCancelHandler handles the API call that cancels a scheduled
+// send.
+func (srv *Server) walletScheduleCancelHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	h, err := scanHash(req.FormValue("transactionid"))
+	if err != nil {
+		writeError(w, Error{"could not read 'transactionid' from POST call to /wallet/schedule/cancel: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	if err := srv.wallet.CancelScheduledSend(types.TransactionID(h)); err != nil {
+		writeError(w, Error{"error after call to /wallet/schedule/cancel: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	writeSuccess(w)
+}
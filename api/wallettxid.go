@@ -0,0 +1,51 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/NebulousLabs/Sia/encoding"
+	"github.com/NebulousLabs/Sia/types"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// WalletTxIDPOST contains the id computed for a transaction by a call to
+// /wallet/txid.
+type WalletTxIDPOST struct {
+	TransactionID types.TransactionID `json:"transactionid"`
+}
+
+// decodeTransaction decodes a transaction that is either encoded as JSON or
+// as base64 of its binary encoding.
+func decodeTransaction(s string) (txn types.Transaction, err error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "{") {
+		err = json.Unmarshal([]byte(s), &txn)
+		return txn, err
+	}
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return types.Transaction{}, err
+	}
+	err = encoding.Unmarshal(b, &txn)
+	return txn, err
+}
+
+// walletTxIDHandler handles API calls to /wallet/txid. The id does not cover
+// the transaction's signatures, so it can be computed before the transaction
+// is signed, and does not change when it is.
+func (srv *Server) walletTxIDHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	if req.FormValue("transaction") == "" {
+		writeError(w, Error{"transaction parameter is required"}, http.StatusBadRequest)
+		return
+	}
+	txn, err := decodeTransaction(req.FormValue("transaction"))
+	if err != nil {
+		writeError(w, Error{"could not decode transaction: " + err.Error()}, http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, WalletTxIDPOST{TransactionID: txn.ID()})
+}
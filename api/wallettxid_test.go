@@ -0,0 +1,74 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/NebulousLabs/Sia/encoding"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// TestWalletTxID checks that the id computed by /wallet/txid matches the id
+// that the transaction is known by once it has been submitted and confirmed.
+func TestWalletTxID(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	st, err := createServerTester("TestWalletTxID")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.server.Close()
+
+	// Build a signed transaction without broadcasting it.
+	amount := types.NewCurrency64(5000)
+	fee := types.SiacoinPrecision
+	tb := st.wallet.StartTransaction()
+	if err := tb.FundSiacoins(amount.Add(fee)); err != nil {
+		t.Fatal(err)
+	}
+	tb.AddMinerFee(fee)
+	tb.AddSiacoinOutput(types.SiacoinOutput{Value: amount})
+	txnSet, err := tb.Sign(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txn := txnSet[len(txnSet)-1]
+
+	txnJSON, err := json.Marshal(txn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fromJSON, fromBinary WalletTxIDPOST
+	if err := st.postAPI("/wallet/txid", url.Values{"transaction": {string(txnJSON)}}, &fromJSON); err != nil {
+		t.Fatal(err)
+	}
+	txnBinary := base64.StdEncoding.EncodeToString(encoding.Marshal(txn))
+	if err := st.postAPI("/wallet/txid", url.Values{"transaction": {txnBinary}}, &fromBinary); err != nil {
+		t.Fatal(err)
+	}
+	if fromJSON.TransactionID != txn.ID() || fromBinary.TransactionID != txn.ID() {
+		t.Fatal("computed ids do not match the transaction id")
+	}
+	if err := st.stdPostAPI("/wallet/txid", url.Values{"transaction": {"not a transaction"}}); err == nil {
+		t.Fatal("expected an error for an invalid transaction")
+	}
+
+	// Submit the transaction and confirm it. The wallet should know it by the
+	// computed id.
+	if err := st.tpool.AcceptTransactionSet(txnSet); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.miner.AddBlock(); err != nil {
+		t.Fatal(err)
+	}
+	var wtg WalletTransactionGETid
+	if err := st.getAPI("/wallet/transaction/"+fromJSON.TransactionID.String(), &wtg); err != nil {
+		t.Fatal(err)
+	}
+	if wtg.Transaction.TransactionID != fromJSON.TransactionID {
+		t.Fatal("wallet reports a different transaction id")
+	}
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type (
+	// WalletUnconfirmedTransaction contains the siacoins that an unconfirmed
+	// transaction moves into and out of the wallet.
+	WalletUnconfirmedTransaction struct {
+		TransactionID types.TransactionID `json:"transactionid"`
+		Outgoing      types.Currency      `json:"outgoing"`
+		Incoming      types.Currency      `json:"incoming"`
+	}
+
+	// WalletUnconfirmedGET contains the change in the wallet's balance that
+	// will occur once the transactions in the transaction pool confirm.
+	WalletUnconfirmedGET struct {
+		OutgoingSiacoins    types.Currency                 `json:"outgoingsiacoins"`
+		IncomingSiacoins    types.Currency                 `json:"incomingsiacoins"`
+		NetOutgoingSiacoins types.Currency                 `json:"netoutgoingsiacoins"`
+		NetIncomingSiacoins types.Currency                 `json:"netincomingsiacoins"`
+		Transactions        []WalletUnconfirmedTransaction `json:"transactions"`
+	}
+)
+
+// unconfirmedImpact sums the wallet's siacoin inputs and outputs in each of
+// the unconfirmed transactions. Transactions that do not touch the wallet's
+// siacoins are left out.
+func unconfirmedImpact(upts []modules.ProcessedTransaction) WalletUnconfirmedGET {
+	wu := WalletUnconfirmedGET{
+		Transactions: []WalletUnconfirmedTransaction{},
+	}
+	for _, upt := range upts {
+		ut := WalletUnconfirmedTransaction{TransactionID: upt.TransactionID}
+		touched := false
+		for _, input := range upt.Inputs {
+			if input.FundType == types.SpecifierSiacoinInput && input.WalletAddress {
+				ut.Outgoing = ut.Outgoing.Add(input.Value)
+				touched = true
+			}
+		}
+		for _, output := range upt.Outputs {
+			if output.FundType == types.SpecifierSiacoinOutput && output.WalletAddress {
+				ut.Incoming = ut.Incoming.Add(output.Value)
+				touched = true
+			}
+		}
+		if !touched {
+			continue
+		}
+		wu.OutgoingSiacoins = wu.OutgoingSiacoins.Add(ut.Outgoing)
+		wu.IncomingSiacoins = wu.IncomingSiacoins.Add(ut.Incoming)
+		wu.Transactions = append(wu.Transactions, ut)
+	}
+	if wu.OutgoingSiacoins.Cmp(wu.IncomingSiacoins) > 0 {
+		wu.NetOutgoingSiacoins = wu.OutgoingSiacoins.Sub(wu.IncomingSiacoins)
+	} else {
+		wu.NetIncomingSiacoins = wu.IncomingSiacoins.Sub(wu.OutgoingSiacoins)
+	}
+	return wu
+}
+
+// walletUnconfirmedHandler handles API calls to /wallet/unconfirmed.
+func (srv *Server) walletUnconfirmedHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	writeJSON(w, unconfirmedImpact(srv.wallet.UnconfirmedTransactions()))
+}
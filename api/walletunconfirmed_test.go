@@ -0,0 +1,60 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// TestUnconfirmedImpact checks that the wallet's inputs are counted as
+// outgoing, its outputs as incoming, and that transactions which do not touch
+// the wallet are left out.
+func TestUnconfirmedImpact(t *testing.T) {
+	input := func(value uint64, ours bool) modules.ProcessedInput {
+		return modules.ProcessedInput{FundType: types.SpecifierSiacoinInput, WalletAddress: ours, Value: types.NewCurrency64(value)}
+	}
+	output := func(value uint64, ours bool) modules.ProcessedOutput {
+		return modules.ProcessedOutput{FundType: types.SpecifierSiacoinOutput, WalletAddress: ours, Value: types.NewCurrency64(value)}
+	}
+	upts := []modules.ProcessedTransaction{
+		// A send of 70 from the wallet, with 25 in change and a fee of 5.
+		{
+			TransactionID: types.TransactionID{1},
+			Inputs:        []modules.ProcessedInput{input(100, true)},
+			Outputs: []modules.ProcessedOutput{
+				output(70, false),
+				output(25, true),
+				{FundType: types.SpecifierMinerFee, Value: types.NewCurrency64(5)},
+			},
+		},
+		// An unrelated transaction.
+		{
+			TransactionID: types.TransactionID{2},
+			Inputs:        []modules.ProcessedInput{input(50, false)},
+			Outputs:       []modules.ProcessedOutput{output(50, false)},
+		},
+		// A payment of 30 to the wallet.
+		{
+			TransactionID: types.TransactionID{3},
+			Inputs:        []modules.ProcessedInput{input(40, false)},
+			Outputs:       []modules.ProcessedOutput{output(30, true), output(10, false)},
+		},
+	}
+
+	wu := unconfirmedImpact(upts)
+	if len(wu.Transactions) != 2 || wu.Transactions[0].TransactionID != upts[0].TransactionID || wu.Transactions[1].TransactionID != upts[2].TransactionID {
+		t.Fatal("wrong transactions:", wu.Transactions)
+	}
+	if wu.OutgoingSiacoins.Cmp(types.NewCurrency64(100)) != 0 || wu.IncomingSiacoins.Cmp(types.NewCurrency64(55)) != 0 {
+		t.Fatal("wrong totals:", wu.OutgoingSiacoins, wu.IncomingSiacoins)
+	}
+	if wu.NetOutgoingSiacoins.Cmp(types.NewCurrency64(45)) != 0 || !wu.NetIncomingSiacoins.IsZero() {
+		t.Fatal("wrong net change:", wu.NetOutgoingSiacoins, wu.NetIncomingSiacoins)
+	}
+
+	wu = unconfirmedImpact(upts[1:])
+	if wu.NetIncomingSiacoins.Cmp(types.NewCurrency64(30)) != 0 || !wu.NetOutgoingSiacoins.IsZero() {
+		t.Fatal("wrong net change:", wu.NetOutgoingSiacoins, wu.NetIncomingSiacoins)
+	}
+}
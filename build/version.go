@@ -17,6 +17,17 @@ const (
 	MaxEncodedVersionLength = 100
 )
 
+// GitRevision and BuildTime identify the commit that siad was built from and
+// when it was built. They are set by the linker, e.g.
+//
+//   go install -ldflags "-X github.com/NebulousLabs/Sia/build.GitRevision=abc123"
+//
+// and are empty if the build did not set them.
+var (
+	GitRevision string
+	BuildTime   string
+)
+
 // IsVersion returns whether str is a valid version number.
 func IsVersion(str string) bool {
 	for _, n := range strings.Split(str, ".") {
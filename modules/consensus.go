@@ -2,6 +2,7 @@ package modules
 
 import (
 	"errors"
+	"io"
 
 	"github.com/NebulousLabs/Sia/crypto"
 	"github.com/NebulousLabs/Sia/types"
@@ -160,6 +161,33 @@ type (
 		Adjusted  types.Currency
 	}
 
+	// A ConsensusRefusedReorg describes a heavier chain that the consensus set
+	// refused to switch to because it would have reverted more blocks than
+	// the maximum reorg depth.
+	ConsensusRefusedReorg struct {
+		Time        types.Timestamp   `json:"time"`
+		Height      types.BlockHeight `json:"height"`
+		ForkHeight  types.BlockHeight `json:"forkheight"`
+		Depth       types.BlockHeight `json:"depth"`
+		BlockID     types.BlockID     `json:"blockid"`
+		BlockHeight types.BlockHeight `json:"blockheight"`
+	}
+
+	// A ConsensusVerification reports the progress and findings of a check of
+	// the consensus database. BlocksChecked counts the blocks of the current
+	// path whose linkage has been verified, out of Height+1. Anomalies
+	// describe every problem found so far.
+	ConsensusVerification struct {
+		Active           bool              `json:"active"`
+		Canceled         bool              `json:"canceled"`
+		StartTime        types.Timestamp   `json:"starttime"`
+		EndTime          types.Timestamp   `json:"endtime"`
+		Height           types.BlockHeight `json:"height"`
+		BlocksChecked    types.BlockHeight `json:"blockschecked"`
+		StateRootChecked bool              `json:"staterootchecked"`
+		Anomalies        []string          `json:"anomalies"`
+	}
+
 	// A ConsensusSet accepts blocks and builds an understanding of network
 	// consensus.
 	ConsensusSet interface {
@@ -204,6 +232,29 @@ type (
 		// Synced returns true if the consensus set is synced with the network.
 		Synced() bool
 
+		// PauseSync stops the consensus set from requesting blocks from its
+		// peers. Peer connections are kept open while paused.
+		PauseSync() error
+
+		// ResumeSync resumes requesting blocks from peers after a call to
+		// PauseSync.
+		ResumeSync() error
+
+		// SyncPaused returns true if block synchronization is paused.
+		SyncPaused() bool
+
+		// MaxReorgDepth returns the largest number of blocks that will be
+		// reverted to switch to a heavier chain, or zero if there is no limit.
+		MaxReorgDepth() types.BlockHeight
+
+		// SetMaxReorgDepth sets the largest number of blocks that will be
+		// reverted to switch to a heavier chain. Zero removes the limit.
+		SetMaxReorgDepth(types.BlockHeight) error
+
+		// RefusedReorg returns the most recent reorg that was refused for
+		// exceeding the maximum reorg depth, and false if there is none.
+		RefusedReorg() (ConsensusRefusedReorg, bool)
+
 		// InCurrentPath returns true if the block id presented is found in the
 		// current path, false otherwise.
 		InCurrentPath(types.BlockID) bool
@@ -214,6 +265,11 @@ type (
 		// risk of mining invalid blocks.
 		MinimumValidChildTimestamp(types.BlockID) (types.Timestamp, bool)
 
+		// NextChildTarget projects the target that will result from the next
+		// difficulty adjustment based on recent block times, and returns the
+		// height of the block whose children will use that target.
+		NextChildTarget() (types.Target, types.BlockHeight)
+
 		// StorageProofSegment returns the segment to be used in the storage proof for
 		// a given file contract.
 		StorageProofSegment(types.FileContractID) (uint64, error)
@@ -228,6 +284,31 @@ type (
 		// allowing for garbage collection and rescanning. If the subscriber is
 		// not found in the subscriber database, no action is taken.
 		Unsubscribe(ConsensusSetSubscriber)
+
+		// WriteSnapshot writes a snapshot of the consensus database at the
+		// current block.
+		WriteSnapshot(io.Writer) error
+
+		// LoadSnapshot validates a snapshot written by WriteSnapshot and stages
+		// it to replace the consensus database on the next startup. Only a
+		// consensus set at the genesis block can load a snapshot.
+		LoadSnapshot(io.Reader) error
+
+		// StartVerification begins checking the consensus database for
+		// corruption in the background. Only one check runs at a time.
+		StartVerification() error
+
+		// CancelVerification stops the check started by StartVerification.
+		CancelVerification() error
+
+		// Verification returns the progress of the most recent check of the
+		// consensus database, and false if no check has been started.
+		Verification() (ConsensusVerification, bool)
+
+		// StateRoot returns a Merkle root that commits to every output,
+		// contract, and expiration in the consensus set at the current block.
+		// Consensus sets with the same current block have the same root.
+		StateRoot() (crypto.Hash, error)
 	}
 )
 
@@ -143,8 +143,9 @@ func (cs *ConsensusSet) validateHeader(tx dbTx, h types.BlockHeader) error {
 // addBlockToTree inserts a block into the blockNode tree by adding it to its
 // parent's list of children. If the new blockNode is heavier than the current
 // node, the blockchain is forked to put the new block and its parents at the
-// tip. An error will be returned if block verification fails or if the block
-// does not extend the longest fork.
+// tip. An error will be returned if block verification fails, if the block
+// does not extend the longest fork, or if forking would revert more blocks than
+// the maximum reorg depth allows.
 //
 // addBlockToTree must use its own database update because it might need to
 // modify the database while returning an error on the block. To prevent error
@@ -154,6 +155,7 @@ func (cs *ConsensusSet) validateHeader(tx dbTx, h types.BlockHeader) error {
 // unneeded.
 func (cs *ConsensusSet) addBlockToTree(b types.Block) (ce changeEntry, err error) {
 	var nonExtending bool
+	var reorgErr error
 	err = cs.db.Update(func(tx *bolt.Tx) error {
 		pb, err := getBlockMap(tx, b.ParentID)
 		if build.DEBUG && err != nil {
@@ -170,6 +172,14 @@ func (cs *ConsensusSet) addBlockToTree(b types.Block) (ce changeEntry, err error
 		if nonExtending {
 			return nil
 		}
+		// A block that would cause a reorg deeper than the limit is kept in
+		// the block tree like a non-extending block. Raising the limit does
+		// not switch to its chain by itself; the reorg happens when another
+		// block arrives on that chain while the limit allows it.
+		reorgErr = cs.checkReorgDepth(tx, currentNode, newNode)
+		if reorgErr != nil {
+			return nil
+		}
 		var revertedBlocks, appliedBlocks []*processedBlock
 		revertedBlocks, appliedBlocks, err = cs.forkBlockchain(tx, newNode)
 		if err != nil {
@@ -202,6 +212,9 @@ func (cs *ConsensusSet) addBlockToTree(b types.Block) (ce changeEntry, err error
 	if nonExtending {
 		return changeEntry{}, modules.ErrNonExtendingBlock
 	}
+	if reorgErr != nil {
+		return changeEntry{}, reorgErr
+	}
 	return ce, nil
 }
 
@@ -9,8 +9,10 @@ package consensus
 import (
 	"errors"
 	"fmt"
+	"math/big"
 
 	"github.com/NebulousLabs/Sia/build"
+	"github.com/NebulousLabs/Sia/crypto"
 	"github.com/NebulousLabs/Sia/encoding"
 	"github.com/NebulousLabs/Sia/modules"
 	"github.com/NebulousLabs/Sia/persist"
@@ -72,6 +74,31 @@ type ConsensusSet struct {
 	// whether the consensus set is synced with the network.
 	synced bool
 
+	// syncPaused is true if the operator has paused block downloads. While
+	// paused, the consensus set stays connected to its peers but does not
+	// request any blocks from them.
+	syncPaused bool
+
+	// maxReorgDepth is the largest number of blocks that will be reverted to
+	// switch to a heavier chain, or zero if there is no limit. It is not
+	// persisted, so it is zero until set after every start. refusedReorg is
+	// the most recent reorg that was refused for exceeding the limit.
+	maxReorgDepth types.BlockHeight
+	refusedReorg  *modules.ConsensusRefusedReorg
+
+	// verification is the progress of the most recent check of the consensus
+	// database. verifyCancel is closed to stop the check while it is active.
+	verification *modules.ConsensusVerification
+	verifyCancel chan struct{}
+
+	// stateRoot caches the consensus checksum of the block stateRootBlock, as
+	// reported by StateRoot. The cache is guarded by stateRootMu rather than
+	// mu.
+	stateRootMu     sync.TryMutex
+	stateRoot       crypto.Hash
+	stateRootBlock  types.BlockID
+	stateRootCached bool
+
 	// Interfaces to abstract the dependencies of the ConsensusSet.
 	marshaler       encoding.GenericMarshaler
 	blockRuleHelper blockRuleHelper
@@ -282,6 +309,28 @@ func (cs *ConsensusSet) MinimumValidChildTimestamp(id types.BlockID) (timestamp
 	return timestamp, exists
 }
 
+// NextChildTarget projects the target of the next difficulty adjustment,
+// assuming that blocks keep arriving at the rate observed over the most recent
+// TargetWindow blocks. The adjustment is made when the block at
+// adjustmentHeight is added, and applies to the children of that block.
+func (cs *ConsensusSet) NextChildTarget() (target types.Target, adjustmentHeight types.BlockHeight) {
+	_ = cs.db.View(func(tx *bolt.Tx) error {
+		pb := currentProcessedBlock(tx)
+		interval := types.TargetWindow / 2
+		adjustmentHeight = pb.Height - pb.Height%interval + interval
+		target = pb.ChildTarget
+
+		// The genesis block has no window to measure block times over.
+		if pb.Height == 0 {
+			return nil
+		}
+		adjustment := clampTargetAdjustment(cs.targetAdjustmentBase(tx.Bucket(BlockMap), pb))
+		target = types.RatToTarget(new(big.Rat).Mul(pb.ChildTarget.Rat(), adjustment))
+		return nil
+	})
+	return target, adjustmentHeight
+}
+
 // StorageProofSegment returns the segment to be used in the storage proof for
 // a given file contract.
 func (cs *ConsensusSet) StorageProofSegment(fcid types.FileContractID) (index uint64, err error) {
@@ -68,6 +68,12 @@ func (cs *ConsensusSet) initPersist() error {
 		return err
 	}
 
+	// Swap in a snapshot that was loaded during the previous run, if any.
+	err = cs.installSnapshot()
+	if err != nil {
+		return err
+	}
+
 	// Try to load an existing database from disk - a new one will be created
 	// if one does not exist.
 	err = cs.loadDB()
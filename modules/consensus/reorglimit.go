@@ -0,0 +1,77 @@
+package consensus
+
+import (
+	"errors"
+
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+
+	"github.com/NebulousLabs/bolt"
+)
+
+var (
+	errReorgTooDeep = errors.New("block would cause a reorg deeper than the maximum reorg depth")
+)
+
+// checkReorgDepth returns errReorgTooDeep if moving from currentNode to
+// newNode would revert more than the maximum reorg depth, and records the
+// refused reorg. A maximum depth of zero disables the check.
+func (cs *ConsensusSet) checkReorgDepth(tx *bolt.Tx, currentNode, newNode *processedBlock) error {
+	if cs.maxReorgDepth == 0 {
+		return nil
+	}
+	commonParent := backtrackToCurrentPath(tx, newNode)[0]
+	depth := currentNode.Height - commonParent.Height
+	if depth <= cs.maxReorgDepth {
+		return nil
+	}
+
+	cs.refusedReorg = &modules.ConsensusRefusedReorg{
+		Time:        types.CurrentTimestamp(),
+		Height:      currentNode.Height,
+		ForkHeight:  commonParent.Height,
+		Depth:       depth,
+		BlockID:     newNode.Block.ID(),
+		BlockHeight: newNode.Height,
+	}
+	cs.log.Printf("ALERT: refused to reorg %v blocks (maximum %v) to block %v at height %v; the competing chain forks at height %v. Raise the maximum reorg depth to accept it.",
+		depth, cs.maxReorgDepth, newNode.Block.ID(), newNode.Height, commonParent.Height)
+	return errReorgTooDeep
+}
+
+// MaxReorgDepth returns the largest number of blocks that the consensus set
+// will revert to switch to a heavier chain. Zero means there is no limit.
+func (cs *ConsensusSet) MaxReorgDepth() types.BlockHeight {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return cs.maxReorgDepth
+}
+
+// SetMaxReorgDepth sets the largest number of blocks that the consensus set
+// will revert to switch to a heavier chain. Zero removes the limit. Heavier
+// chains that fork off deeper are refused; after the limit is raised, such a
+// chain is switched to when its next block arrives. The limit is not
+// persisted, and is reset to the --max-reorg-depth flag of siad on restart.
+func (cs *ConsensusSet) SetMaxReorgDepth(depth types.BlockHeight) error {
+	if err := cs.tg.Add(); err != nil {
+		return err
+	}
+	defer cs.tg.Done()
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	cs.maxReorgDepth = depth
+	cs.log.Println("INFO: maximum reorg depth set to", depth)
+	return nil
+}
+
+// RefusedReorg returns the most recent reorg that was refused for exceeding
+// the maximum reorg depth, and false if no reorg has been refused.
+func (cs *ConsensusSet) RefusedReorg() (modules.ConsensusRefusedReorg, bool) {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	if cs.refusedReorg == nil {
+		return modules.ConsensusRefusedReorg{}, false
+	}
+	return *cs.refusedReorg, true
+}
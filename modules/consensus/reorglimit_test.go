@@ -0,0 +1,66 @@
+package consensus
+
+import (
+	"testing"
+
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// TestMaxReorgDepth checks that a consensus set refuses to reorg deeper than
+// its maximum reorg depth, records the refused reorg, and performs the reorg
+// once the limit is removed.
+func TestMaxReorgDepth(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	rs := createReorgSets("TestMaxReorgDepth")
+	defer rs.Close()
+	cstMain, cstAlt := rs.cstMain, rs.cstAlt
+
+	if err := cstMain.cs.SetMaxReorgDepth(1); err != nil {
+		t.Fatal(err)
+	}
+	if _, refused := cstMain.cs.RefusedReorg(); refused {
+		t.Fatal("no reorg should have been refused yet")
+	}
+
+	// cstMain and cstAlt diverge at the genesis block, so switching to the
+	// heavier cstAlt chain reverts every block in cstMain.
+	mainHeight := cstMain.cs.Height()
+	mainTip := cstMain.cs.CurrentBlock().ID()
+	for cstAlt.cs.Height() <= mainHeight {
+		if _, err := cstAlt.miner.AddBlock(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	feed := func() {
+		for i := types.BlockHeight(1); i <= cstAlt.cs.Height(); i++ {
+			b, _ := cstAlt.cs.BlockAtHeight(i)
+			_ = cstMain.cs.AcceptBlock(b)
+		}
+	}
+	feed()
+	if cstMain.cs.CurrentBlock().ID() != mainTip {
+		t.Fatal("consensus set reorged deeper than the maximum reorg depth")
+	}
+	rr, refused := cstMain.cs.RefusedReorg()
+	if !refused {
+		t.Fatal("refused reorg was not recorded")
+	}
+	if rr.Height != mainHeight || rr.ForkHeight != 0 || rr.Depth != mainHeight || rr.BlockID != cstAlt.cs.CurrentBlock().ID() {
+		t.Fatal("refused reorg was recorded incorrectly:", rr)
+	}
+
+	// After removing the limit, the next block on the heavier chain causes
+	// the reorg.
+	if err := cstMain.cs.SetMaxReorgDepth(0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cstAlt.miner.AddBlock(); err != nil {
+		t.Fatal(err)
+	}
+	feed()
+	if cstMain.cs.CurrentBlock().ID() != cstAlt.cs.CurrentBlock().ID() {
+		t.Fatal("consensus set did not reorg after the limit was removed")
+	}
+}
@@ -0,0 +1,222 @@
+package consensus
+
+// snapshot.go contains functions for exporting the consensus database and for
+// staging an exported database to replace the database of a fresh node.
+// Loading a snapshot skips validation of the blocks it contains, so the node
+// trusts whoever produced the snapshot to have followed the consensus rules.
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/NebulousLabs/Sia/crypto"
+	"github.com/NebulousLabs/Sia/encoding"
+	"github.com/NebulousLabs/Sia/persist"
+	"github.com/NebulousLabs/Sia/types"
+
+	"github.com/NebulousLabs/bolt"
+)
+
+const (
+	// SnapshotFilename is the name of the file that a loaded snapshot is
+	// staged in. The staged snapshot replaces the consensus database the next
+	// time the consensus set is started.
+	SnapshotFilename = DatabaseFilename + ".snapshot"
+)
+
+var (
+	errSnapshotBadHeader      = errors.New("snapshot has an unrecognized header")
+	errSnapshotChecksum       = errors.New("snapshot consensus checksum does not match the checksum in its header")
+	errSnapshotGenesis        = errors.New("snapshot has the wrong genesis block")
+	errSnapshotHeightMismatch = errors.New("snapshot height does not match the height in its header")
+	errSnapshotInconsistent   = errors.New("snapshot database is marked as inconsistent")
+	errSnapshotMissingBucket  = errors.New("snapshot database is missing a consensus bucket")
+	errSnapshotNotFresh       = errors.New("snapshots can only be loaded by a consensus set that has not processed any blocks")
+	errSnapshotPath           = errors.New("snapshot block path is corrupt")
+	errSnapshotTipMismatch    = errors.New("snapshot current block does not match the block in its header")
+	errSnapshotUninitialized  = errors.New("snapshot database has not been initialized")
+
+	snapshotMetadata = persist.Metadata{
+		Header:  "Consensus Set Snapshot",
+		Version: "1.0",
+	}
+)
+
+// snapshotHeader precedes the database in a snapshot. The checksum is taken
+// over the consensus set at the time of the export, and is compared against a
+// checksum of the imported database to detect corruption in transit.
+type snapshotHeader struct {
+	Header       string
+	Version      string
+	Height       types.BlockHeight
+	CurrentBlock types.BlockID
+	Checksum     crypto.Hash
+}
+
+// WriteSnapshot writes a snapshot of the consensus database at the current
+// block to w. The snapshot is taken in a single database transaction, so it is
+// internally consistent even if blocks arrive while it is being written.
+func (cs *ConsensusSet) WriteSnapshot(w io.Writer) error {
+	if err := cs.tg.Add(); err != nil {
+		return err
+	}
+	defer cs.tg.Done()
+
+	// The header is read under the lock so that it describes a block that has
+	// been fully applied. The lock is released before the database is
+	// written, because w may be slow to accept it; the read transaction keeps
+	// the view of the database consistent with the header.
+	cs.mu.RLock()
+	tx, err := cs.db.Begin(false)
+	if err != nil {
+		cs.mu.RUnlock()
+		return err
+	}
+	defer tx.Rollback()
+	header := snapshotHeader{
+		Header:       snapshotMetadata.Header,
+		Version:      snapshotMetadata.Version,
+		Height:       blockHeight(tx),
+		CurrentBlock: currentBlockID(tx),
+		Checksum:     consensusChecksum(tx),
+	}
+	cs.mu.RUnlock()
+
+	if err := encoding.NewEncoder(w).Encode(header); err != nil {
+		return err
+	}
+	_, err = tx.WriteTo(w)
+	return err
+}
+
+// LoadSnapshot reads a snapshot written by WriteSnapshot from r, validates it,
+// and stages it to replace the consensus database when the consensus set is
+// next started. Only a consensus set that is still at the genesis block may
+// load a snapshot.
+func (cs *ConsensusSet) LoadSnapshot(r io.Reader) error {
+	if err := cs.tg.Add(); err != nil {
+		return err
+	}
+	defer cs.tg.Done()
+	if cs.Height() != 0 {
+		return errSnapshotNotFresh
+	}
+
+	var header snapshotHeader
+	if err := encoding.NewDecoder(r).Decode(&header); err != nil {
+		return err
+	}
+	if header.Header != snapshotMetadata.Header || header.Version != snapshotMetadata.Version {
+		return errSnapshotBadHeader
+	}
+
+	// Copy the database into a temporary file and validate it before staging
+	// it, so that a failed load never leaves a staged snapshot behind.
+	tmpFilename := filepath.Join(cs.persistDir, SnapshotFilename+".tmp")
+	f, err := os.OpenFile(tmpFilename, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, r)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = cs.validateSnapshot(tmpFilename, header)
+	}
+	if err != nil {
+		os.Remove(tmpFilename)
+		return err
+	}
+	return os.Rename(tmpFilename, filepath.Join(cs.persistDir, SnapshotFilename))
+}
+
+// validateSnapshot opens the snapshot database at filename and checks that it
+// belongs to the same blockchain as the consensus set, that every block in its
+// path is stored under its own id and builds on the previous block, and that
+// its consensus checksum matches the checksum in the snapshot header.
+func (cs *ConsensusSet) validateSnapshot(filename string, header snapshotHeader) error {
+	db, err := persist.OpenDatabase(dbMetadata, filename)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return db.View(func(tx *bolt.Tx) error {
+		if !dbInitialized(tx) {
+			return errSnapshotUninitialized
+		}
+		buckets := [][]byte{
+			BlockHeight,
+			BlockMap,
+			BlockPath,
+			Consistency,
+			SiacoinOutputs,
+			FileContracts,
+			SiafundOutputs,
+			SiafundPool,
+		}
+		for _, bucket := range buckets {
+			if tx.Bucket(bucket) == nil {
+				return errSnapshotMissingBucket
+			}
+		}
+		if inconsistencyDetected(tx) {
+			return errSnapshotInconsistent
+		}
+		if blockHeight(tx) != header.Height {
+			return errSnapshotHeightMismatch
+		}
+
+		var parentID types.BlockID
+		for height := types.BlockHeight(0); height <= header.Height; height++ {
+			id, err := getPath(tx, height)
+			if err != nil {
+				return errSnapshotPath
+			}
+			pb, err := getBlockMap(tx, id)
+			if err != nil || pb.Block.ID() != id || pb.Height != height {
+				return errSnapshotPath
+			}
+			if height == 0 && id != cs.blockRoot.Block.ID() {
+				return errSnapshotGenesis
+			}
+			if height > 0 && pb.Block.ParentID != parentID {
+				return errSnapshotPath
+			}
+			parentID = id
+		}
+		if parentID != header.CurrentBlock {
+			return errSnapshotTipMismatch
+		}
+		if consensusChecksum(tx) != header.Checksum {
+			return errSnapshotChecksum
+		}
+		return nil
+	})
+}
+
+// installSnapshot replaces the consensus database with a snapshot staged by
+// LoadSnapshot, if one exists. The existing database is kept as a backup.
+func (cs *ConsensusSet) installSnapshot() error {
+	snapshotFilename := filepath.Join(cs.persistDir, SnapshotFilename)
+	if _, err := os.Stat(snapshotFilename); os.IsNotExist(err) {
+		return nil
+	}
+	dbFilename := filepath.Join(cs.persistDir, DatabaseFilename)
+	if _, err := os.Stat(dbFilename); err == nil {
+		if err := os.Rename(dbFilename, dbFilename+".bck"); err != nil {
+			return errors.New("error while backing up consensus database: " + err.Error())
+		}
+	}
+	if err := os.Rename(snapshotFilename, dbFilename); err != nil {
+		return err
+	}
+	cs.log.Println("Replaced the consensus database with a loaded snapshot")
+	return nil
+}
@@ -0,0 +1,89 @@
+package consensus
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NebulousLabs/Sia/build"
+	"github.com/NebulousLabs/Sia/encoding"
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/modules/gateway"
+)
+
+// TestSnapshot exports a snapshot from a consensus set with blocks, loads it
+// into a fresh consensus set, and checks that the fresh consensus set matches
+// the original after restarting.
+func TestSnapshot(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	cst, err := createConsensusSetTester("TestSnapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cst.Close()
+
+	var snapshot bytes.Buffer
+	if err := cst.cs.WriteSnapshot(&snapshot); err != nil {
+		t.Fatal(err)
+	}
+
+	// A consensus set with blocks cannot load a snapshot.
+	if err := cst.cs.LoadSnapshot(bytes.NewReader(snapshot.Bytes())); err != errSnapshotNotFresh {
+		t.Fatal("expected errSnapshotNotFresh, got", err)
+	}
+
+	// Create a fresh consensus set.
+	testdir := build.TempDir(modules.ConsensusDir, "TestSnapshot - fresh")
+	g, err := gateway.New("localhost:0", filepath.Join(testdir, modules.GatewayDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer g.Close()
+	csDir := filepath.Join(testdir, modules.ConsensusDir)
+	cs, err := New(g, csDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A snapshot whose database does not match its checksum should be
+	// rejected without staging anything.
+	var header snapshotHeader
+	r := bytes.NewReader(snapshot.Bytes())
+	if err := encoding.NewDecoder(r).Decode(&header); err != nil {
+		t.Fatal(err)
+	}
+	header.Checksum[0]++
+	corrupt := append(encoding.Marshal(header), snapshot.Bytes()[len(snapshot.Bytes())-r.Len():]...)
+	if err := cs.LoadSnapshot(bytes.NewReader(corrupt)); err != errSnapshotChecksum {
+		t.Fatal("expected errSnapshotChecksum, got", err)
+	}
+	if _, err := os.Stat(filepath.Join(csDir, SnapshotFilename)); !os.IsNotExist(err) {
+		t.Fatal("corrupt snapshot was staged")
+	}
+
+	// Load the snapshot and restart the consensus set.
+	if err := cs.LoadSnapshot(bytes.NewReader(snapshot.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+	if cs.Height() != 0 {
+		t.Fatal("snapshot should not take effect until restart")
+	}
+	if err := cs.Close(); err != nil {
+		t.Fatal(err)
+	}
+	cs, err = New(g, csDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cs.Close()
+
+	if cs.Height() != cst.cs.Height() || cs.CurrentBlock().ID() != cst.cs.CurrentBlock().ID() {
+		t.Fatal("loaded snapshot does not match the original consensus set")
+	}
+	if cs.dbConsensusChecksum() != cst.cs.dbConsensusChecksum() {
+		t.Fatal("consensus checksum changed after loading snapshot")
+	}
+}
@@ -0,0 +1,36 @@
+package consensus
+
+import (
+	"github.com/NebulousLabs/Sia/crypto"
+
+	"github.com/NebulousLabs/bolt"
+)
+
+// StateRoot returns a Merkle root that commits to the consensus set at the
+// current block. Computing the root means reading the entire consensus
+// database, so the root is cached until the current block changes.
+//
+// The database is read in a single read transaction, which is a consistent
+// view of the consensus set, so cs.mu is not held and blocks can be accepted
+// while the root is computed. stateRootMu makes concurrent callers wait for
+// one computation instead of each scanning the database.
+func (cs *ConsensusSet) StateRoot() (root crypto.Hash, err error) {
+	if err := cs.tg.Add(); err != nil {
+		return crypto.Hash{}, err
+	}
+	defer cs.tg.Done()
+	cs.stateRootMu.Lock()
+	defer cs.stateRootMu.Unlock()
+
+	err = cs.db.View(func(tx *bolt.Tx) error {
+		id := currentBlockID(tx)
+		if !cs.stateRootCached || cs.stateRootBlock != id {
+			cs.stateRoot = consensusChecksum(tx)
+			cs.stateRootBlock = id
+			cs.stateRootCached = true
+		}
+		root = cs.stateRoot
+		return nil
+	})
+	return root, err
+}
@@ -0,0 +1,71 @@
+package consensus
+
+import (
+	"testing"
+	"time"
+)
+
+// TestStateRoot checks that the state root changes with each block and is the
+// same for consensus sets that share a current block.
+func TestStateRoot(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	cst1, err := createConsensusSetTester("TestStateRoot1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cst1.Close()
+	cst2, err := blankConsensusSetTester("TestStateRoot2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cst2.Close()
+
+	root1, err := cst1.cs.StateRoot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root1 != cst1.cs.dbConsensusChecksum() {
+		t.Fatal("state root does not match the consensus checksum")
+	}
+	root2, err := cst2.cs.StateRoot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root1 == root2 {
+		t.Fatal("consensus sets at different blocks have the same state root")
+	}
+
+	// Once the second consensus set catches up, the roots should match.
+	err = cst2.gateway.Connect(cst1.gateway.Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 50 && cst1.cs.dbCurrentBlockID() != cst2.cs.dbCurrentBlockID(); i++ {
+		time.Sleep(250 * time.Millisecond)
+	}
+	if cst1.cs.dbCurrentBlockID() != cst2.cs.dbCurrentBlockID() {
+		t.Fatal("consensus sets did not synchronize")
+	}
+	root2, err = cst2.cs.StateRoot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root1 != root2 {
+		t.Fatal("consensus sets at the same block have different state roots")
+	}
+
+	// Mining a block should change the root.
+	_, err = cst1.miner.AddBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	newRoot, err := cst1.cs.StateRoot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newRoot == root1 {
+		t.Fatal("state root did not change after a block was mined")
+	}
+}
@@ -84,6 +84,9 @@ var (
 	}()
 
 	errSendBlocksStalled = errors.New("SendBlocks RPC timed and never received any blocks")
+	errSyncPaused        = errors.New("block synchronization is paused")
+	errSyncNotPaused     = errors.New("block synchronization is not paused")
+	errSyncAlreadyPaused = errors.New("block synchronization is already paused")
 )
 
 // blockHistory returns up to 32 block ids, starting with recent blocks and
@@ -143,6 +146,9 @@ func (cs *ConsensusSet) threadedReceiveBlocks(conn modules.PeerConn) (returnErr
 	if err != nil {
 		return err
 	}
+	if cs.managedSyncPaused() {
+		return errSyncPaused
+	}
 	stalled := true
 	defer func() {
 		// TODO: Timeout errors returned by muxado do not conform to the net.Error
@@ -196,9 +202,15 @@ func (cs *ConsensusSet) threadedReceiveBlocks(conn modules.PeerConn) (returnErr
 	}()
 
 	// Read blocks off of the wire and add them to the consensus set until
-	// there are no more blocks available.
+	// there are no more blocks available, or synchronization is paused.
 	moreAvailable := true
 	for moreAvailable {
+		// Stop between batches if synchronization was paused during a long
+		// download; the blocks already accepted are kept.
+		if cs.managedSyncPaused() {
+			return errSyncPaused
+		}
+
 		// Read a slice of blocks from the wire.
 		var newBlocks []types.Block
 		if err := encoding.ReadObject(conn, &newBlocks, uint64(MaxCatchUpBlocks)*types.BlockSizeLimit); err != nil {
@@ -384,6 +396,12 @@ func (cs *ConsensusSet) threadedRPCRelayHeader(conn modules.PeerConn) error {
 		return err
 	}
 
+	// Ignore the header if synchronization is paused; the corresponding block
+	// will be requested when synchronization resumes.
+	if cs.managedSyncPaused() {
+		return nil
+	}
+
 	// Start verification inside of a bolt View tx.
 	cs.mu.RLock()
 	err = cs.db.View(func(tx *bolt.Tx) error {
@@ -459,6 +477,9 @@ func (cs *ConsensusSet) rpcSendBlk(conn modules.PeerConn) error {
 // the function it returns calls the exported method AcceptBlock.
 func (cs *ConsensusSet) threadedReceiveBlock(id types.BlockID) modules.RPCFunc {
 	return func(conn modules.PeerConn) error {
+		if cs.managedSyncPaused() {
+			return errSyncPaused
+		}
 		if err := encoding.WriteObject(conn, id); err != nil {
 			return err
 		}
@@ -488,6 +509,14 @@ func (cs *ConsensusSet) threadedInitialBlockchainDownload() {
 	deadline := time.Now().Add(minIBDWaitTime)
 	numOutboundSynced := 0
 	for {
+		// While synchronization is paused, push the deadline back so that
+		// time spent paused does not count towards minIBDWaitTime.
+		if cs.managedSyncPaused() {
+			deadline = deadline.Add(ibdLoopDelay)
+			time.Sleep(ibdLoopDelay)
+			continue
+		}
+
 		numOutboundSynced = 0
 		for _, p := range cs.gateway.Peers() {
 			// We only sync on outbound peers at first to make IBD less susceptible to
@@ -502,6 +531,9 @@ func (cs *ConsensusSet) threadedInitialBlockchainDownload() {
 				numOutboundSynced++
 				continue
 			}
+			if err == errSyncPaused {
+				break
+			}
 			// TODO: Timeout errors returned by muxado do not conform to the net.Error
 			// interface and therefore we cannot check if the error is a timeout using
 			// the Timeout() method. Once muxado issue #14 is resolved change the below
@@ -541,3 +573,77 @@ func (cs *ConsensusSet) Synced() bool {
 	defer cs.mu.RUnlock()
 	return cs.synced
 }
+
+// managedSyncPaused returns true if block synchronization has been paused.
+func (cs *ConsensusSet) managedSyncPaused() bool {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return cs.syncPaused
+}
+
+// threadedResumeSync requests blocks from each outbound peer, catching the
+// consensus set up on any blocks that were announced while synchronization
+// was paused. If initial blockchain download has not finished, the IBD loop
+// will pick up where it left off and nothing needs to be done here.
+func (cs *ConsensusSet) threadedResumeSync() {
+	if err := cs.tg.Add(); err != nil {
+		return
+	}
+	defer cs.tg.Done()
+
+	if !cs.Synced() {
+		return
+	}
+	for _, p := range cs.gateway.Peers() {
+		if p.Inbound {
+			continue
+		}
+		err := cs.gateway.RPC(p.NetAddress, "SendBlocks", cs.threadedReceiveBlocks)
+		if err != nil {
+			cs.log.Debugf("WARN: failed to resume sync with peer %v: %v", p.NetAddress, err)
+		}
+	}
+}
+
+// PauseSync stops the consensus set from requesting blocks from its peers.
+// Peer connections are left open, and blocks submitted directly via
+// AcceptBlock are still processed.
+func (cs *ConsensusSet) PauseSync() error {
+	if err := cs.tg.Add(); err != nil {
+		return err
+	}
+	defer cs.tg.Done()
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if cs.syncPaused {
+		return errSyncAlreadyPaused
+	}
+	cs.syncPaused = true
+	cs.log.Println("INFO: block synchronization paused")
+	return nil
+}
+
+// ResumeSync undoes a call to PauseSync, allowing the consensus set to
+// request blocks from its peers again.
+func (cs *ConsensusSet) ResumeSync() error {
+	if err := cs.tg.Add(); err != nil {
+		return err
+	}
+	defer cs.tg.Done()
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if !cs.syncPaused {
+		return errSyncNotPaused
+	}
+	cs.syncPaused = false
+	cs.log.Println("INFO: block synchronization resumed")
+	go cs.threadedResumeSync()
+	return nil
+}
+
+// SyncPaused returns true if block synchronization has been paused.
+func (cs *ConsensusSet) SyncPaused() bool {
+	return cs.managedSyncPaused()
+}
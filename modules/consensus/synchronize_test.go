@@ -1501,3 +1501,103 @@ func TestIntegrationSendBlocksStalls(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+// TestPauseSync checks that a paused consensus set does not download blocks
+// from its peers, and that it catches up once synchronization is resumed.
+func TestPauseSync(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	cst1, err := createConsensusSetTester("TestPauseSync1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cst1.Close()
+	cst2, err := createConsensusSetTester("TestPauseSync2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cst2.Close()
+
+	if err := cst1.cs.PauseSync(); err != nil {
+		t.Fatal(err)
+	}
+	if !cst1.cs.SyncPaused() {
+		t.Fatal("consensus set should report that sync is paused")
+	}
+	if err := cst1.cs.PauseSync(); err != errSyncAlreadyPaused {
+		t.Fatal("expected errSyncAlreadyPaused, got", err)
+	}
+
+	// Mine on cst2 until it is ahead of cst1, then connect. cst1 should not
+	// download any blocks, neither on connect nor from relayed headers.
+	for cst1.cs.dbBlockHeight() >= cst2.cs.dbBlockHeight() {
+		b, _ := cst2.miner.FindBlock()
+		err = cst2.cs.AcceptBlock(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = cst1.gateway.Connect(cst2.gateway.Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := cst2.miner.FindBlock()
+	err = cst2.cs.AcceptBlock(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(2 * time.Second)
+	if cst1.cs.dbCurrentBlockID() == cst2.cs.dbCurrentBlockID() {
+		t.Fatal("paused consensus set downloaded blocks")
+	}
+	if len(cst1.gateway.Peers()) != 1 {
+		t.Fatal("paused consensus set should remain connected to its peer")
+	}
+
+	// Resume synchronization; the blockchains should now match.
+	if err := cst1.cs.ResumeSync(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cst1.cs.ResumeSync(); err != errSyncNotPaused {
+		t.Fatal("expected errSyncNotPaused, got", err)
+	}
+	for i := 0; i < 50 && cst1.cs.dbCurrentBlockID() != cst2.cs.dbCurrentBlockID(); i++ {
+		time.Sleep(250 * time.Millisecond)
+	}
+	if cst1.cs.dbCurrentBlockID() != cst2.cs.dbCurrentBlockID() {
+		t.Fatal("consensus set did not catch up after resuming sync")
+	}
+}
+
+// TestReceiveBlocksPausedMidway checks that threadedReceiveBlocks stops
+// reading blocks from a peer if synchronization is paused between batches.
+func TestReceiveBlocksPausedMidway(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	cst, err := createConsensusSetTester("TestReceiveBlocksPausedMidway")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cst.Close()
+
+	p1, p2 := net.Pipe()
+	defer p2.Close()
+	go func() {
+		defer p1.Close()
+		// Pause once the receiver has started, then send a batch that claims
+		// more blocks are available.
+		var history [32]types.BlockID
+		if err := encoding.ReadObject(p1, &history, 32*crypto.HashSize); err != nil {
+			return
+		}
+		cst.cs.PauseSync()
+		encoding.WriteObject(p1, []types.Block{})
+		encoding.WriteObject(p1, true)
+	}()
+	err = cst.cs.threadedReceiveBlocks(mockPeerConn{p2})
+	if err != errSyncPaused {
+		t.Fatal("expected errSyncPaused, got", err)
+	}
+}
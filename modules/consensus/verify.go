@@ -0,0 +1,261 @@
+package consensus
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/NebulousLabs/Sia/crypto"
+	"github.com/NebulousLabs/Sia/encoding"
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+
+	"github.com/NebulousLabs/bolt"
+)
+
+const (
+	// verifyBatchSize is the number of blocks whose linkage is checked in a
+	// single database transaction. Holding a read transaction for the whole
+	// chain would keep the database from growing while blocks are processed.
+	verifyBatchSize = 1000
+
+	// maxVerifyAnomalies is the largest number of anomalies that a
+	// verification records. A badly corrupted database could otherwise report
+	// a problem for every block.
+	maxVerifyAnomalies = 100
+)
+
+var (
+	errVerifyActive   = errors.New("the consensus database is already being verified")
+	errVerifyInactive = errors.New("the consensus database is not being verified")
+)
+
+// readPath returns the block id at 'height' in the block path. Unlike getPath,
+// it returns an error instead of panicking if the entry cannot be decoded,
+// because a corrupted entry is what the verification is looking for.
+func readPath(tx *bolt.Tx, height types.BlockHeight) (id types.BlockID, err error) {
+	idBytes := tx.Bucket(BlockPath).Get(encoding.Marshal(height))
+	if idBytes == nil {
+		return types.BlockID{}, errNilItem
+	}
+	err = encoding.Unmarshal(idBytes, &id)
+	return id, err
+}
+
+// readBlockMap returns the processed block with the given id, like
+// getBlockMap, but returns an error if the block cannot be decoded.
+func readBlockMap(tx *bolt.Tx, id types.BlockID) (pb processedBlock, err error) {
+	pbBytes := tx.Bucket(BlockMap).Get(id[:])
+	if pbBytes == nil {
+		return processedBlock{}, errNilItem
+	}
+	err = encoding.Unmarshal(pbBytes, &pb)
+	return pb, err
+}
+
+// verifyPathBlock checks that the block at the given height of the current
+// path is stored under its own id, records the right height, and builds on
+// the block at the previous height. It returns the id of the block and a
+// description of the problem, if there is one.
+func (cs *ConsensusSet) verifyPathBlock(tx *bolt.Tx, height types.BlockHeight, parentID types.BlockID) (types.BlockID, string) {
+	id, err := readPath(tx, height)
+	if err == errNilItem {
+		return types.BlockID{}, fmt.Sprintf("no block in the current path at height %v", height)
+	} else if err != nil {
+		return types.BlockID{}, fmt.Sprintf("unable to decode the current path at height %v: %v", height, err)
+	}
+	pb, err := readBlockMap(tx, id)
+	if err == errNilItem {
+		return id, fmt.Sprintf("block %v at height %v is missing from the block map", id, height)
+	} else if err != nil {
+		return id, fmt.Sprintf("unable to decode block %v at height %v: %v", id, height, err)
+	}
+	switch {
+	case pb.Block.ID() != id:
+		return id, fmt.Sprintf("block stored as %v at height %v has id %v", id, height, pb.Block.ID())
+	case pb.Height != height:
+		return id, fmt.Sprintf("block %v at height %v records height %v", id, height, pb.Height)
+	case height == 0 && id != cs.blockRoot.Block.ID():
+		return id, fmt.Sprintf("block %v at height 0 is not the genesis block", id)
+	case height > 0 && pb.Block.ParentID != parentID:
+		return id, fmt.Sprintf("block %v at height %v does not build on block %v", id, height, parentID)
+	}
+	return id, ""
+}
+
+// verifyState checks the outputs of the current block against the totals
+// required by the consensus rules, and compares the consensus checksum to the
+// checksum recorded when the current block was applied. Checksums are only
+// recorded by debug builds, so the returned bool reports whether the checksum
+// could be compared.
+func (cs *ConsensusSet) verifyState(tx *bolt.Tx) ([]string, bool) {
+	var anomalies []string
+	if inconsistencyDetected(tx) {
+		anomalies = append(anomalies, "the database is marked as inconsistent")
+	}
+	for _, check := range []func(*bolt.Tx) error{checkDSCOs, checkSiacoinCount, checkSiafundCount} {
+		if err := check(tx); err != nil {
+			anomalies = append(anomalies, err.Error())
+		}
+	}
+
+	id, err := readPath(tx, blockHeight(tx))
+	if err != nil {
+		return append(anomalies, "unable to read the current block from the block path: "+err.Error()), false
+	}
+	pb, err := readBlockMap(tx, id)
+	if err != nil {
+		return append(anomalies, "unable to read the current block from the block map: "+err.Error()), false
+	}
+	if pb.ConsensusChecksum == (crypto.Hash{}) {
+		return anomalies, false
+	}
+	if checksum := consensusChecksum(tx); checksum != pb.ConsensusChecksum {
+		anomalies = append(anomalies, fmt.Sprintf("consensus checksum %v does not match checksum %v recorded for block %v", checksum, pb.ConsensusChecksum, pb.Block.ID()))
+	}
+	return anomalies, true
+}
+
+// recordVerifyProgress adds the progress of a batch to the active
+// verification.
+func (cs *ConsensusSet) recordVerifyProgress(height, checked types.BlockHeight, anomalies []string) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	v := cs.verification
+	v.Height = height
+	v.BlocksChecked = checked
+	for _, a := range anomalies {
+		if len(v.Anomalies) >= maxVerifyAnomalies {
+			break
+		}
+		v.Anomalies = append(v.Anomalies, a)
+	}
+}
+
+// finishVerification marks the active verification as complete.
+func (cs *ConsensusSet) finishVerification(canceled, stateRootChecked bool) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	v := cs.verification
+	v.Active = false
+	v.Canceled = canceled
+	v.StateRootChecked = stateRootChecked
+	v.EndTime = types.CurrentTimestamp()
+	cs.verifyCancel = nil
+	if canceled {
+		cs.log.Printf("INFO: consensus verification canceled after %v of %v blocks", v.BlocksChecked, v.Height+1)
+	} else {
+		cs.log.Printf("INFO: consensus verification finished with %v anomalies", len(v.Anomalies))
+	}
+}
+
+// threadedVerify walks the current path from the genesis block, checking the
+// linkage of each block, and then checks the state at the current block. The
+// path is walked in batches so that the check can be canceled between them.
+// Blocks that arrive during the walk are checked as well.
+func (cs *ConsensusSet) threadedVerify(cancel <-chan struct{}) {
+	defer cs.tg.Done()
+
+	var height types.BlockHeight
+	var parentID types.BlockID
+	for {
+		select {
+		case <-cancel:
+			cs.finishVerification(true, false)
+			return
+		case <-cs.tg.StopChan():
+			cs.finishVerification(true, false)
+			return
+		default:
+		}
+
+		var anomalies []string
+		var tip types.BlockHeight
+		var done, stateRootChecked bool
+		err := cs.db.View(func(tx *bolt.Tx) error {
+			tip = blockHeight(tx)
+			// A reorg between batches can replace the blocks that were
+			// already checked, so the parent is read again.
+			if height > 0 {
+				var err error
+				parentID, err = readPath(tx, height-1)
+				if err != nil {
+					// The chain has become shorter than the walk.
+					height = 0
+				}
+			}
+			for end := height + verifyBatchSize; height < end && height <= tip; height++ {
+				id, anomaly := cs.verifyPathBlock(tx, height, parentID)
+				if anomaly != "" {
+					anomalies = append(anomalies, anomaly)
+				}
+				parentID = id
+			}
+			if height > tip {
+				done = true
+				var stateAnomalies []string
+				stateAnomalies, stateRootChecked = cs.verifyState(tx)
+				anomalies = append(anomalies, stateAnomalies...)
+			}
+			return nil
+		})
+		if err != nil {
+			anomalies = append(anomalies, "unable to read the database: "+err.Error())
+			done = true
+		}
+		cs.recordVerifyProgress(tip, height, anomalies)
+		if done {
+			cs.finishVerification(false, stateRootChecked)
+			return
+		}
+	}
+}
+
+// StartVerification begins checking the consensus database for corruption.
+// The check is read-only and runs in the background; its progress is
+// reported by Verification.
+func (cs *ConsensusSet) StartVerification() error {
+	if err := cs.tg.Add(); err != nil {
+		return err
+	}
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if cs.verification != nil && cs.verification.Active {
+		cs.tg.Done()
+		return errVerifyActive
+	}
+
+	cs.verification = &modules.ConsensusVerification{
+		Active:    true,
+		StartTime: types.CurrentTimestamp(),
+	}
+	cs.verifyCancel = make(chan struct{})
+	cs.log.Println("INFO: consensus verification started")
+	go cs.threadedVerify(cs.verifyCancel)
+	return nil
+}
+
+// CancelVerification stops the active check of the consensus database. The
+// anomalies found before the check was canceled are kept.
+func (cs *ConsensusSet) CancelVerification() error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if cs.verifyCancel == nil {
+		return errVerifyInactive
+	}
+	close(cs.verifyCancel)
+	cs.verifyCancel = nil
+	return nil
+}
+
+// Verification returns the progress of the most recent check of the consensus
+// database, and false if the database has not been checked.
+func (cs *ConsensusSet) Verification() (modules.ConsensusVerification, bool) {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	if cs.verification == nil {
+		return modules.ConsensusVerification{}, false
+	}
+	v := *cs.verification
+	v.Anomalies = append([]string(nil), v.Anomalies...)
+	return v, true
+}
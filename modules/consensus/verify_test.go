@@ -0,0 +1,82 @@
+package consensus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NebulousLabs/Sia/build"
+	"github.com/NebulousLabs/Sia/encoding"
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+
+	"github.com/NebulousLabs/bolt"
+)
+
+// waitForVerification waits for the active verification of the consensus set
+// to finish and returns its result.
+func waitForVerification(t *testing.T, cs *ConsensusSet) modules.ConsensusVerification {
+	for i := 0; i < 100; i++ {
+		v, ok := cs.Verification()
+		if !ok {
+			t.Fatal("no verification was recorded")
+		}
+		if !v.Active {
+			return v
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Fatal("verification did not finish")
+	return modules.ConsensusVerification{}
+}
+
+// TestVerification checks that verifying a consistent consensus set reports
+// no anomalies, and that a corrupted block path is reported.
+func TestVerification(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	cst, err := createConsensusSetTester("TestVerification")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cst.Close()
+
+	if _, ok := cst.cs.Verification(); ok {
+		t.Fatal("verification reported before one was started")
+	}
+	if err := cst.cs.CancelVerification(); err != errVerifyInactive {
+		t.Fatal("expected errVerifyInactive, got", err)
+	}
+
+	if err := cst.cs.StartVerification(); err != nil {
+		t.Fatal(err)
+	}
+	v := waitForVerification(t, cst.cs)
+	if len(v.Anomalies) != 0 {
+		t.Fatal("consistent consensus set reported anomalies:", v.Anomalies)
+	}
+	if v.Canceled || v.BlocksChecked != cst.cs.Height()+1 {
+		t.Fatalf("verification did not check every block: %+v", v)
+	}
+	if v.StateRootChecked != build.DEBUG {
+		t.Fatal("state root should be checked exactly when checksums are recorded")
+	}
+
+	// Point the path at height 1 to a block that does not exist, and then
+	// to an entry that cannot be decoded.
+	for _, entry := range [][]byte{encoding.Marshal(types.BlockID{1}), {1}} {
+		err = cst.cs.db.Update(func(tx *bolt.Tx) error {
+			return tx.Bucket(BlockPath).Put(encoding.Marshal(types.BlockHeight(1)), entry)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := cst.cs.StartVerification(); err != nil {
+			t.Fatal(err)
+		}
+		v = waitForVerification(t, cst.cs)
+		if len(v.Anomalies) == 0 {
+			t.Fatalf("corrupted block path entry %v was not reported", entry)
+		}
+	}
+}
@@ -45,6 +45,30 @@ type (
 		TotalRevisionVolume types.Currency `json:"totalrevisionvolume"`
 	}
 
+	// BlockTimeStats contains statistics about the time between consecutive
+	// blocks in a range of heights. Intervals are in seconds, and are negative
+	// when a block's timestamp is earlier than that of its parent.
+	BlockTimeStats struct {
+		From              types.BlockHeight `json:"from"`
+		To                types.BlockHeight `json:"to"`
+		Intervals         uint64            `json:"intervals"`
+		NegativeIntervals uint64            `json:"negativeintervals"`
+		Min               int64             `json:"min"`
+		Max               int64             `json:"max"`
+		Mean              float64           `json:"mean"`
+		Median            float64           `json:"median"`
+		StdDev            float64           `json:"stddev"`
+		Target            types.BlockHeight `json:"target"`
+	}
+
+	// SiacoinBalance is the confirmed siacoin balance of an unlock hash.
+	// OutputCount is the number of unspent siacoin outputs that make up the
+	// balance.
+	SiacoinBalance struct {
+		Balance     types.Currency `json:"balance"`
+		OutputCount uint64         `json:"outputcount"`
+	}
+
 	// Explorer tracks the blockchain and provides tools for gathering
 	// statistics and finding objects or patterns within the blockchain.
 	Explorer interface {
@@ -60,6 +84,10 @@ type (
 		// in the explorer's database.
 		LatestBlockFacts() BlockFacts
 
+		// BlockTimes returns statistics about the time between consecutive
+		// blocks from height 'from' to height 'to', inclusive.
+		BlockTimes(from, to types.BlockHeight) (BlockTimeStats, error)
+
 		// Transaction returns the block that contains the input transaction
 		// id. The transaction itself is either the block (indicating the miner
 		// payouts are somehow involved), or it is a transaction inside of the
@@ -99,6 +127,10 @@ type (
 		// the provided siafund output id.
 		SiafundOutputID(types.SiafundOutputID) []types.TransactionID
 
+		// SiacoinBalances returns the confirmed siacoin balance of each of
+		// the provided unlock hashes, in the same order.
+		SiacoinBalances([]types.UnlockHash) ([]SiacoinBalance, error)
+
 		Close() error
 	}
 )
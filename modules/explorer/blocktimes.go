@@ -0,0 +1,99 @@
+package explorer
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// maxBlockTimesRange is the largest number of block intervals that
+// BlockTimes will compute statistics over.
+const maxBlockTimesRange = 10e3
+
+var (
+	errBlockTimesHeight = errors.New("block range extends beyond the explorer's height")
+	errBlockTimesOrder  = errors.New("block range must contain at least two blocks")
+	errBlockTimesRange  = fmt.Errorf("block range may span at most %v blocks", maxBlockTimesRange)
+)
+
+// int64Slice sorts int64s in ascending order.
+type int64Slice []int64
+
+func (s int64Slice) Len() int           { return len(s) }
+func (s int64Slice) Less(i, j int) bool { return s[i] < s[j] }
+func (s int64Slice) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// blockTimeStats computes statistics over the intervals between consecutive
+// timestamps. Block timestamps are not monotonic, so an interval may be
+// negative; such intervals are kept as they are. At least two timestamps are
+// required.
+func blockTimeStats(timestamps []types.Timestamp) modules.BlockTimeStats {
+	intervals := make([]int64, len(timestamps)-1)
+	for i := range intervals {
+		intervals[i] = int64(timestamps[i+1]) - int64(timestamps[i])
+	}
+	sort.Sort(int64Slice(intervals))
+
+	stats := modules.BlockTimeStats{
+		Intervals: uint64(len(intervals)),
+		Min:       intervals[0],
+		Max:       intervals[len(intervals)-1],
+		Target:    types.BlockFrequency,
+	}
+	var sum float64
+	for _, interval := range intervals {
+		sum += float64(interval)
+		if interval < 0 {
+			stats.NegativeIntervals++
+		}
+	}
+	stats.Mean = sum / float64(len(intervals))
+	mid := len(intervals) / 2
+	if len(intervals)%2 == 1 {
+		stats.Median = float64(intervals[mid])
+	} else {
+		stats.Median = float64(intervals[mid-1]+intervals[mid]) / 2
+	}
+	var variance float64
+	for _, interval := range intervals {
+		d := float64(interval) - stats.Mean
+		variance += d * d
+	}
+	stats.StdDev = math.Sqrt(variance / float64(len(intervals)))
+	return stats
+}
+
+// BlockTimes returns statistics about the time between consecutive blocks from
+// height 'from' to height 'to', inclusive.
+func (e *Explorer) BlockTimes(from, to types.BlockHeight) (modules.BlockTimeStats, error) {
+	if from >= to {
+		return modules.BlockTimeStats{}, errBlockTimesOrder
+	}
+	if to-from > maxBlockTimesRange {
+		return modules.BlockTimeStats{}, errBlockTimesRange
+	}
+	var height types.BlockHeight
+	err := e.db.View(dbGetInternal(internalBlockHeight, &height))
+	if err != nil {
+		return modules.BlockTimeStats{}, err
+	}
+	if to > height {
+		return modules.BlockTimeStats{}, errBlockTimesHeight
+	}
+
+	timestamps := make([]types.Timestamp, 0, to-from+1)
+	for h := from; h <= to; h++ {
+		block, exists := e.cs.BlockAtHeight(h)
+		if !exists {
+			return modules.BlockTimeStats{}, errBlockTimesHeight
+		}
+		timestamps = append(timestamps, block.Timestamp)
+	}
+	stats := blockTimeStats(timestamps)
+	stats.From, stats.To = from, to
+	return stats, nil
+}
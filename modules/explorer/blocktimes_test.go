@@ -0,0 +1,66 @@
+package explorer
+
+import (
+	"math"
+	"testing"
+
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// TestBlockTimeStats checks the statistics computed over a set of block
+// timestamps, including an interval that goes backwards in time.
+func TestBlockTimeStats(t *testing.T) {
+	// Intervals: 600, -100, 700, 400.
+	stats := blockTimeStats([]types.Timestamp{1000, 1600, 1500, 2200, 2600})
+	if stats.Intervals != 4 || stats.NegativeIntervals != 1 {
+		t.Fatalf("wrong interval counts: %+v", stats)
+	}
+	if stats.Min != -100 || stats.Max != 700 {
+		t.Fatalf("wrong min or max: %+v", stats)
+	}
+	if stats.Mean != 400 || stats.Median != 500 {
+		t.Fatalf("wrong mean or median: %+v", stats)
+	}
+	// The squared deviations are 40000, 250000, 90000 and 0.
+	if math.Abs(stats.StdDev-math.Sqrt(95000)) > 1e-9 {
+		t.Fatal("wrong standard deviation:", stats.StdDev)
+	}
+	if stats.Target != types.BlockFrequency {
+		t.Fatal("wrong target:", stats.Target)
+	}
+
+	// A single interval.
+	stats = blockTimeStats([]types.Timestamp{5, 11})
+	if stats.Min != 6 || stats.Max != 6 || stats.Median != 6 || stats.StdDev != 0 {
+		t.Fatalf("wrong stats for a single interval: %+v", stats)
+	}
+}
+
+// TestBlockTimes checks the range validation of BlockTimes.
+func TestBlockTimes(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	et, err := createExplorerTester("TestBlockTimes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	height := et.cs.Height()
+
+	if _, err := et.explorer.BlockTimes(2, 2); err != errBlockTimesOrder {
+		t.Fatal("expected errBlockTimesOrder, got", err)
+	}
+	if _, err := et.explorer.BlockTimes(0, maxBlockTimesRange+1); err != errBlockTimesRange {
+		t.Fatal("expected errBlockTimesRange, got", err)
+	}
+	if _, err := et.explorer.BlockTimes(0, height+1); err != errBlockTimesHeight {
+		t.Fatal("expected errBlockTimesHeight, got", err)
+	}
+	stats, err := et.explorer.BlockTimes(1, height)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.From != 1 || stats.To != height || stats.Intervals != uint64(height-1) {
+		t.Fatalf("wrong range: %+v", stats)
+	}
+}
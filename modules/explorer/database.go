@@ -12,6 +12,10 @@ import (
 var (
 	errNotExist = errors.New("entry does not exist")
 
+	// errBalancesNotIndexed is returned when balances are requested from a
+	// database that was created before unspent outputs were indexed.
+	errBalancesNotIndexed = errors.New("explorer database predates the balance index; delete explorer.db to rebuild it")
+
 	// database buckets
 	bucketBlockFacts            = []byte("BlockFacts")
 	bucketBlockIDs              = []byte("BlockIDs")
@@ -25,13 +29,15 @@ var (
 	bucketSiafundOutputs        = []byte("SiafundOutputs")
 	bucketTransactionIDs        = []byte("TransactionIDs")
 	bucketUnlockHashes          = []byte("UnlockHashes")
+	bucketUnlockHashOutputs     = []byte("UnlockHashOutputs")
 
 	// bucketInternal is used to store values internal to the explorer
 	bucketInternal = []byte("Internal")
 
 	// keys for bucketInternal
-	internalBlockHeight  = []byte("BlockHeight")
-	internalRecentChange = []byte("RecentChange")
+	internalBalancesIndexed = []byte("BalancesIndexed")
+	internalBlockHeight     = []byte("BlockHeight")
+	internalRecentChange    = []byte("RecentChange")
 )
 
 // These functions all return a 'func(*bolt.Tx) error', which, allows them to
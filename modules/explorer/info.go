@@ -2,6 +2,7 @@ package explorer
 
 import (
 	"github.com/NebulousLabs/Sia/build"
+	"github.com/NebulousLabs/Sia/encoding"
 	"github.com/NebulousLabs/Sia/modules"
 	"github.com/NebulousLabs/Sia/types"
 
@@ -152,3 +153,40 @@ func (e *Explorer) SiafundOutputID(id types.SiafundOutputID) []types.Transaction
 	}
 	return ids
 }
+
+// SiacoinBalances returns the confirmed siacoin balance of each unlock hash,
+// along with the number of unspent siacoin outputs that make up the balance.
+// The balances are read in a single database transaction, so they are
+// consistent with each other.
+func (e *Explorer) SiacoinBalances(uhs []types.UnlockHash) ([]modules.SiacoinBalance, error) {
+	balances := make([]modules.SiacoinBalance, len(uhs))
+	err := e.db.View(func(tx *bolt.Tx) error {
+		var indexed bool
+		if err := dbGetInternal(internalBalancesIndexed, &indexed)(tx); err != nil || !indexed {
+			return errBalancesNotIndexed
+		}
+		for i, uh := range uhs {
+			b := tx.Bucket(bucketUnlockHashOutputs).Bucket(encoding.Marshal(uh))
+			if b == nil {
+				continue
+			}
+			err := b.ForEach(func(_, v []byte) error {
+				var value types.Currency
+				if err := encoding.Unmarshal(v, &value); err != nil {
+					return err
+				}
+				balances[i].Balance = balances[i].Balance.Add(value)
+				balances[i].OutputCount++
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return balances, nil
+}
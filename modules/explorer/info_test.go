@@ -3,7 +3,10 @@ package explorer
 import (
 	"testing"
 
+	"github.com/NebulousLabs/Sia/encoding"
 	"github.com/NebulousLabs/Sia/types"
+
+	"github.com/NebulousLabs/bolt"
 )
 
 // TestImmediateBlockFacts grabs the block facts object from the block explorer
@@ -66,3 +69,108 @@ func TestBlockFacts(t *testing.T) {
 		t.Error("call to 'BlockFacts' has failed")
 	}
 }
+
+// TestSiacoinBalances checks that the explorer tracks the unspent siacoin
+// outputs of each unlock hash as coins are sent and blocks are reverted.
+func TestSiacoinBalances(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	et, err := createExplorerTester("TestSiacoinBalances")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The balances of the wallet's addresses should add up to the wallet's
+	// confirmed balance.
+	balances, err := et.explorer.SiacoinBalances(et.wallet.AllAddresses())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var total types.Currency
+	for _, b := range balances {
+		total = total.Add(b.Balance)
+	}
+	confirmed, _, _ := et.wallet.ConfirmedBalance()
+	if total.Cmp(confirmed) != 0 {
+		t.Fatalf("explorer reports %v for the wallet's addresses, wallet reports %v", total, confirmed)
+	}
+
+	// Send coins to an address outside of the wallet.
+	dest := types.UnlockHash{1}
+	amount := types.SiacoinPrecision.Mul64(100)
+	_, err = et.wallet.SendSiacoins(amount, dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := et.miner.FindBlock()
+	err = et.cs.AcceptBlock(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	balances, err = et.explorer.SiacoinBalances([]types.UnlockHash{dest, {2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balances[0].Balance.Cmp(amount) != 0 || balances[0].OutputCount != 1 {
+		t.Fatal("wrong balance for destination:", balances[0])
+	}
+	if !balances[1].Balance.IsZero() || balances[1].OutputCount != 0 {
+		t.Fatal("unused address has a balance:", balances[1])
+	}
+
+	// Reverting the block should remove the output.
+	err = et.reorgToBlank()
+	if err != nil {
+		t.Fatal(err)
+	}
+	balances, err = et.explorer.SiacoinBalances([]types.UnlockHash{dest})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !balances[0].Balance.IsZero() || balances[0].OutputCount != 0 {
+		t.Fatal("reverted output still counted:", balances[0])
+	}
+}
+
+// TestSpendUnindexedOutput checks that the explorer can process a block that
+// spends an output created before the balance index existed.
+func TestSpendUnindexedOutput(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	et, err := createExplorerTester("TestSpendUnindexedOutput")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Drop the index of every wallet address, as in a database that was
+	// created before the index.
+	err = et.explorer.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketUnlockHashOutputs)
+		for _, uh := range et.wallet.AllAddresses() {
+			if b.Bucket(encoding.Marshal(uh)) != nil {
+				if err := b.DeleteBucket(encoding.Marshal(uh)); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = et.wallet.SendSiacoins(types.SiacoinPrecision.Mul64(100), types.UnlockHash{1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	height := et.explorer.LatestBlockFacts().Height
+	b, _ := et.miner.FindBlock()
+	if err := et.cs.AcceptBlock(b); err != nil {
+		t.Fatal(err)
+	}
+	if et.explorer.LatestBlockFacts().Height != height+1 {
+		t.Fatal("explorer did not process the block spending an unindexed output")
+	}
+}
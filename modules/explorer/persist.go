@@ -34,6 +34,10 @@ func (e *Explorer) initPersist() error {
 
 	// Initialize the database
 	err = e.db.Update(func(tx *bolt.Tx) error {
+		// Unspent outputs are indexed as blocks are processed, so the index
+		// is only complete if it has existed since the database was created.
+		balancesIndexed := tx.Bucket(bucketInternal) == nil
+
 		buckets := [][]byte{
 			bucketBlockFacts,
 			bucketBlockIDs,
@@ -48,6 +52,7 @@ func (e *Explorer) initPersist() error {
 			bucketSiafundOutputs,
 			bucketTransactionIDs,
 			bucketUnlockHashes,
+			bucketUnlockHashOutputs,
 		}
 		for _, b := range buckets {
 			_, err := tx.CreateBucketIfNotExists(b)
@@ -60,6 +65,7 @@ func (e *Explorer) initPersist() error {
 		internalDefaults := []struct {
 			key, val []byte
 		}{
+			{internalBalancesIndexed, encoding.Marshal(balancesIndexed)},
 			{internalBlockHeight, encoding.Marshal(types.BlockHeight(0))},
 			{internalRecentChange, encoding.Marshal(modules.ConsensusChangeID{})},
 		}
@@ -222,6 +222,15 @@ func (e *Explorer) ProcessConsensusChange(cc modules.ConsensusChange) {
 			}
 		}
 
+		// Update the unspent siacoin outputs of each unlock hash.
+		for _, diff := range cc.SiacoinOutputDiffs {
+			if diff.Direction == modules.DiffApply {
+				dbAddUnspentSiacoinOutput(tx, diff.SiacoinOutput.UnlockHash, diff.ID, diff.SiacoinOutput.Value)
+			} else {
+				dbRemoveUnspentSiacoinOutput(tx, diff.SiacoinOutput.UnlockHash, diff.ID)
+			}
+		}
+
 		// Compute the changes in the active set. Note, because this is calculated
 		// at the end instead of in a loop, the historic facts may contain
 		// inaccuracies about the active set. This should not be a problem except
@@ -416,6 +425,25 @@ func dbRemoveUnlockHash(tx *bolt.Tx, uh types.UnlockHash, txid types.Transaction
 	mustDelete(tx.Bucket(bucketUnlockHashes).Bucket(encoding.Marshal(uh)), txid)
 }
 
+// Add/Remove unspent siacoin output
+func dbAddUnspentSiacoinOutput(tx *bolt.Tx, uh types.UnlockHash, id types.SiacoinOutputID, value types.Currency) {
+	b, err := tx.Bucket(bucketUnlockHashOutputs).CreateBucketIfNotExists(encoding.Marshal(uh))
+	assertNil(err)
+	mustPut(b, id, value)
+}
+func dbRemoveUnspentSiacoinOutput(tx *bolt.Tx, uh types.UnlockHash, id types.SiacoinOutputID) {
+	// A database that predates the index has no entries for the outputs
+	// created before the upgrade.
+	b := tx.Bucket(bucketUnlockHashOutputs).Bucket(encoding.Marshal(uh))
+	if b == nil {
+		return
+	}
+	mustDelete(b, id)
+	if k, _ := b.Cursor().First(); k == nil {
+		assertNil(tx.Bucket(bucketUnlockHashOutputs).DeleteBucket(encoding.Marshal(uh)))
+	}
+}
+
 func dbCalculateBlockFacts(tx *bolt.Tx, cs modules.ConsensusSet, block types.Block) blockFacts {
 	// get the parent block facts
 	var bf blockFacts
@@ -2,6 +2,7 @@ package modules
 
 import (
 	"net"
+	"time"
 )
 
 const (
@@ -40,6 +41,64 @@ type (
 		Inbound    bool       `json:"inbound"`
 	}
 
+	// ExternalAddress describes how the gateway is seen by the rest of the
+	// network.
+	ExternalAddress struct {
+		// NetAddress is the address that peers are believed to be able to
+		// dial the gateway on.
+		NetAddress NetAddress `json:"netaddress"`
+
+		// Confirmations is the number of connected peers that reported
+		// seeing the IP of NetAddress.
+		Confirmations int `json:"confirmations"`
+
+		// PortForwarded indicates whether the gateway was able to forward
+		// its port using UPnP.
+		PortForwarded bool `json:"portforwarded"`
+
+		// Reachable indicates whether any peer has successfully dialed the
+		// gateway.
+		Reachable bool `json:"reachable"`
+	}
+
+	// An RPCLimit limits the incoming calls of an RPC from each peer. MaxSize
+	// is the most bytes that a peer may send in a single call, and
+	// CallsPerMinute is the most calls a peer may make per minute. A limit of
+	// zero is no limit.
+	RPCLimit struct {
+		RPC            string `json:"rpc"`
+		MaxSize        uint64 `json:"maxsize"`
+		CallsPerMinute uint64 `json:"callsperminute"`
+	}
+
+	// GatewayRPCViolations counts the calls from a peer that were rejected
+	// for exceeding an RPC's maximum message size or rate limit.
+	// LastViolation is the time of the most recent rejected call.
+	GatewayRPCViolations struct {
+		NetAddress    NetAddress `json:"netaddress"`
+		Oversized     uint64     `json:"oversized"`
+		RateLimited   uint64     `json:"ratelimited"`
+		LastViolation time.Time  `json:"lastviolation"`
+	}
+
+	// GatewayRPCLimits contains the limits on incoming RPCs and the peers
+	// that have violated them. Peers are disconnected after DisconnectAfter
+	// violations, unless DisconnectAfter is zero.
+	GatewayRPCLimits struct {
+		Limits          []RPCLimit             `json:"limits"`
+		DisconnectAfter uint64                 `json:"disconnectafter"`
+		Violations      []GatewayRPCViolations `json:"violations"`
+	}
+
+	// GatewaySettings contains the settings of the gateway that can be
+	// changed while it is running. DiscoveryInterval is the number of seconds
+	// between rounds of peer discovery, in which the gateway asks a peer for
+	// more nodes if it knows too few, and checks that a known node is still
+	// reachable.
+	GatewaySettings struct {
+		DiscoveryInterval uint64 `json:"discoveryinterval"`
+	}
+
 	// A PeerConn is the connection type used when communicating with peers during
 	// an RPC. It is identical to a net.Conn with the additional RPCAddr method.
 	// This method acts as an identifier for peers and is the address that the
@@ -71,6 +130,10 @@ type (
 		// Address returns the Gateway's address.
 		Address() NetAddress
 
+		// ExternalAddress returns the address that the rest of the network
+		// sees the Gateway at, as reported by its peers.
+		ExternalAddress() ExternalAddress
+
 		// Peers returns the addresses that the Gateway is currently connected to.
 		Peers() []Peer
 
@@ -102,6 +165,25 @@ type (
 		// given peers in parallel.
 		Broadcast(name string, obj interface{}, peers []Peer)
 
+		// RPCLimits returns the limits on incoming RPCs and the number of
+		// times each peer has violated them.
+		RPCLimits() GatewayRPCLimits
+
+		// SetRPCLimit sets the maximum message size and call rate of an
+		// incoming RPC. Limits of zero remove the limits of the RPC.
+		SetRPCLimit(RPCLimit)
+
+		// SetRPCDisconnectThreshold sets the number of RPC limit violations
+		// after which a peer is disconnected. Zero disables disconnecting.
+		SetRPCDisconnectThreshold(violations uint64)
+
+		// Settings returns the settings of the Gateway.
+		Settings() GatewaySettings
+
+		// SetSettings changes the settings of the Gateway. The new settings
+		// take effect immediately.
+		SetSettings(GatewaySettings) error
+
 		// Close safely stops the Gateway's listener process.
 		Close() error
 	}
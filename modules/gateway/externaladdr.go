@@ -0,0 +1,96 @@
+package gateway
+
+import (
+	"errors"
+	"net"
+
+	"github.com/NebulousLabs/Sia/encoding"
+	"github.com/NebulousLabs/Sia/modules"
+)
+
+const (
+	// maxEncodedIPLength is the maximum length of a host string sent in
+	// response to the DiscoverIP RPC. The longest textual IPv6 address is 45
+	// characters, plus the 8 byte string length prefix.
+	maxEncodedIPLength = 53
+)
+
+var (
+	errInvalidReportedIP = errors.New("peer reported an invalid IP address")
+)
+
+// discoverIP is the receiving end of the DiscoverIP RPC. It writes the IP
+// address that the caller is connecting from, which lets the caller learn how
+// it is seen by the rest of the network.
+func (g *Gateway) discoverIP(conn modules.PeerConn) error {
+	host, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+	if err != nil {
+		return err
+	}
+	return encoding.WriteObject(conn, host)
+}
+
+// requestIP is the calling end of the DiscoverIP RPC. The IP reported by the
+// peer is recorded so that it can be compared against the reports of other
+// peers, until the peer disconnects.
+func (g *Gateway) requestIP(conn modules.PeerConn) error {
+	var host string
+	if err := encoding.ReadObject(conn, &host, maxEncodedIPLength); err != nil {
+		return err
+	}
+	if net.ParseIP(host) == nil {
+		return errInvalidReportedIP
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	// The peer may have disconnected while the RPC was in progress, in which
+	// case its report would never be removed.
+	if _, ok := g.peers[conn.RPCAddr()]; ok {
+		g.ipReports[conn.RPCAddr()] = host
+	}
+	return nil
+}
+
+// ExternalAddress returns the address that the gateway is believed to be
+// reachable at by the rest of the network. The IP reported by the most
+// connected peers is preferred; if no peers have reported an IP, the address
+// learned by the gateway at startup is used instead.
+func (g *Gateway) ExternalAddress() modules.ExternalAddress {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	// Only count reports from peers that are still connected.
+	counts := make(map[string]int)
+	for addr, host := range g.ipReports {
+		if _, ok := g.peers[addr]; ok {
+			counts[host]++
+		}
+	}
+	var bestHost string
+	var bestCount int
+	for host, n := range counts {
+		// Break ties deterministically so that repeated calls agree.
+		if n > bestCount || (n == bestCount && host < bestHost) {
+			bestHost, bestCount = host, n
+		}
+	}
+
+	ea := modules.ExternalAddress{
+		NetAddress:    g.myAddr,
+		PortForwarded: g.portForwarded,
+	}
+	if bestCount > 0 {
+		ea.NetAddress = modules.NetAddress(net.JoinHostPort(bestHost, g.port))
+		ea.Confirmations = bestCount
+	}
+	// An inbound peer is proof that other nodes are able to dial the
+	// gateway. Without one, the gateway is likely behind a NAT that has not
+	// been configured to forward the RPC port.
+	for _, p := range g.peers {
+		if p.Inbound {
+			ea.Reachable = true
+			break
+		}
+	}
+	return ea
+}
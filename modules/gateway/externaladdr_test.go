@@ -0,0 +1,69 @@
+package gateway
+
+import (
+	"testing"
+	"time"
+)
+
+// TestExternalAddress checks that a gateway learns its external address from
+// the peers it connects to, and that only gateways with inbound peers are
+// reported as reachable.
+func TestExternalAddress(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+
+	g1 := newTestingGateway("TestExternalAddress1", t)
+	defer g1.Close()
+	g2 := newTestingGateway("TestExternalAddress2", t)
+	defer g2.Close()
+
+	// Before connecting, the gateway has no reports and no inbound peers.
+	ea := g1.ExternalAddress()
+	if ea.NetAddress != g1.Address() {
+		t.Fatalf("expected %v, got %v", g1.Address(), ea.NetAddress)
+	}
+	if ea.Confirmations != 0 || ea.Reachable {
+		t.Fatal("unconnected gateway should have no confirmations and be unreachable:", ea)
+	}
+
+	err := g1.Connect(g2.Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The DiscoverIP RPC is called asynchronously after connecting.
+	for i := 0; i < 50; i++ {
+		if g1.ExternalAddress().Confirmations > 0 {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	ea = g1.ExternalAddress()
+	if ea.Confirmations != 1 {
+		t.Fatal("expected 1 confirmation, got", ea.Confirmations)
+	}
+	if ea.NetAddress.Host() != g1.Address().Host() || ea.NetAddress.Port() != g1.Address().Port() {
+		t.Fatalf("expected %v, got %v", g1.Address(), ea.NetAddress)
+	}
+	if ea.Reachable {
+		t.Fatal("gateway with only outbound peers should not be reachable")
+	}
+	if !g2.ExternalAddress().Reachable {
+		t.Fatal("gateway with an inbound peer should be reachable")
+	}
+
+	// Reports from disconnected peers should be removed.
+	err = g1.Disconnect(g2.Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ea = g1.ExternalAddress(); ea.Confirmations != 0 {
+		t.Fatal("expected 0 confirmations after disconnecting, got", ea.Confirmations)
+	}
+	g1.mu.RLock()
+	reports := len(g1.ipReports)
+	g1.mu.RUnlock()
+	if reports != 0 {
+		t.Fatal("report of disconnected peer was kept")
+	}
+}
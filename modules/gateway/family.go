@@ -0,0 +1,47 @@
+package gateway
+
+import (
+	"errors"
+
+	"github.com/NebulousLabs/Sia/modules"
+)
+
+// The IP families that the gateway can be bound to. A dual-stack gateway
+// listens on and dials both IPv4 and IPv6 addresses.
+const (
+	FamilyDual = "dual"
+	FamilyIPv4 = "ipv4"
+	FamilyIPv6 = "ipv6"
+)
+
+var (
+	errBadFamily         = errors.New("IP family must be 'dual', 'ipv4', or 'ipv6'")
+	errUnreachableFamily = errors.New("address is not in the IP family that the gateway is bound to")
+)
+
+// familyNetwork returns the network passed to net.Listen and net.Dial for an
+// IP family.
+func familyNetwork(family string) (string, error) {
+	switch family {
+	case FamilyDual, "":
+		return "tcp", nil
+	case FamilyIPv4:
+		return "tcp4", nil
+	case FamilyIPv6:
+		return "tcp6", nil
+	}
+	return "", errBadFamily
+}
+
+// reachable returns true if the gateway is able to dial the address given the
+// IP family that it is bound to. Nodes of the other family are kept in the node
+// list so that they can still be shared with peers.
+func (g *Gateway) reachable(addr modules.NetAddress) bool {
+	switch g.network {
+	case "tcp4":
+		return !addr.IsIPv6()
+	case "tcp6":
+		return addr.IsIPv6()
+	}
+	return true
+}
@@ -0,0 +1,67 @@
+package gateway
+
+import (
+	"net"
+	"testing"
+
+	"github.com/NebulousLabs/Sia/build"
+	"github.com/NebulousLabs/Sia/modules"
+)
+
+// TestIPv6Peers checks that gateways can listen on and connect to each other
+// over IPv6, and that a gateway bound to IPv4 refuses to dial IPv6 nodes.
+func TestIPv6Peers(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	l, err := net.Listen("tcp6", "[::1]:0")
+	if err != nil {
+		t.Skip("IPv6 is not available:", err)
+	}
+	l.Close()
+
+	g1, err := NewWithFamily("[::1]:0", FamilyIPv6, build.TempDir("gateway", "TestIPv6Peers1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer g1.Close()
+	g2, err := New("[::1]:0", build.TempDir("gateway", "TestIPv6Peers2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer g2.Close()
+	if !g1.Address().IsIPv6() {
+		t.Fatal("gateway address is not IPv6:", g1.Address())
+	}
+
+	if err := g1.Connect(g2.Address()); err != nil {
+		t.Fatal(err)
+	}
+	if len(g1.Peers()) != 1 || len(g2.Peers()) != 1 {
+		t.Fatal("gateways did not connect over IPv6")
+	}
+	if g2.Peers()[0].NetAddress != g1.Address() {
+		t.Fatal("peer was not recorded by its IPv6 dialback address:", g2.Peers()[0].NetAddress)
+	}
+
+	g3, err := NewWithFamily("localhost:0", FamilyIPv4, build.TempDir("gateway", "TestIPv6Peers3"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer g3.Close()
+	if err := g3.Connect(g1.Address()); err != errUnreachableFamily {
+		t.Fatal("expected errUnreachableFamily, got", err)
+	}
+	// IPv6 nodes are kept, but not chosen for dialing.
+	g3.mu.Lock()
+	g3.nodes = map[modules.NetAddress]struct{}{g1.Address(): {}}
+	_, err = g3.randomNode()
+	g3.mu.Unlock()
+	if err != errNoPeers {
+		t.Fatal("expected errNoPeers, got", err)
+	}
+
+	if _, err := NewWithFamily("localhost:0", "ipv5", build.TempDir("gateway", "TestIPv6Peers4")); err != errBadFamily {
+		t.Fatal("expected errBadFamily, got", err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/NebulousLabs/Sia/build"
 	"github.com/NebulousLabs/Sia/modules"
@@ -25,6 +26,10 @@ type Gateway struct {
 	myAddr   modules.NetAddress
 	port     string
 
+	// network is the network used to listen and dial, which restricts the
+	// gateway to one IP family or allows both.
+	network string
+
 	// handlers are the RPCs that the Gateway can handle.
 	handlers map[rpcID]modules.RPCFunc
 	// initRPCs are the RPCs that the Gateway calls upon connecting to a peer.
@@ -37,6 +42,31 @@ type Gateway struct {
 	// network.
 	nodes map[modules.NetAddress]struct{}
 
+	// ipReports maps each connected peer to the IP address that the peer
+	// reported seeing the gateway connect from, as learned via the DiscoverIP
+	// RPC. Reports are removed when the peer disconnects.
+	// portForwarded indicates whether the RPC port was successfully
+	// forwarded using UPnP.
+	ipReports     map[modules.NetAddress]string
+	portForwarded bool
+
+	// rpcLimits limit the size and rate of incoming calls of each RPC.
+	// rpcCalls counts each peer's recent calls for rate limiting, and
+	// rpcViolations counts the times each peer has exceeded a limit. Peers
+	// are disconnected once they reach rpcDisconnectAfter violations.
+	// rpcPruned is when expired call windows and violations were last
+	// removed.
+	rpcLimits          map[rpcID]rpcLimit
+	rpcCalls           map[modules.NetAddress]map[rpcID]*rpcCallWindow
+	rpcViolations      map[modules.NetAddress]modules.GatewayRPCViolations
+	rpcDisconnectAfter uint64
+	rpcPruned          time.Time
+
+	// discoveryInterval is the time between rounds of peer discovery. The
+	// node manager is woken through discoveryIntervalChanged when it changes.
+	discoveryInterval        time.Duration
+	discoveryIntervalChanged chan struct{}
+
 	// threads is used to signal the Gateway's goroutines to shut down and to wait
 	// for all goroutines to exit before returning from Close().
 	threads siasync.ThreadGroup
@@ -66,6 +96,8 @@ func (g *Gateway) Close() error {
 	// but do it anyways as an example for other modules to follow.
 	g.UnregisterRPC("ShareNodes")
 	g.UnregisterConnectCall("ShareNodes")
+	g.UnregisterRPC("DiscoverIP")
+	g.UnregisterConnectCall("DiscoverIP")
 	// save the latest gateway state
 	g.mu.RLock()
 	if err := g.saveSync(); err != nil {
@@ -85,8 +117,19 @@ func (g *Gateway) Close() error {
 	return build.JoinErrors(errs, "; ")
 }
 
-// New returns an initialized Gateway.
-func New(addr string, persistDir string) (g *Gateway, err error) {
+// New returns an initialized dual-stack Gateway.
+func New(addr string, persistDir string) (*Gateway, error) {
+	return NewWithFamily(addr, FamilyDual, persistDir)
+}
+
+// NewWithFamily returns an initialized Gateway that listens on and dials only
+// addresses of the given IP family.
+func NewWithFamily(addr string, family string, persistDir string) (g *Gateway, err error) {
+	network, err := familyNetwork(family)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create the directory if it doesn't exist.
 	err = os.MkdirAll(persistDir, 0700)
 	if err != nil {
@@ -98,7 +141,16 @@ func New(addr string, persistDir string) (g *Gateway, err error) {
 		initRPCs:   make(map[string]modules.RPCFunc),
 		peers:      make(map[modules.NetAddress]*peer),
 		nodes:      make(map[modules.NetAddress]struct{}),
+		ipReports:  make(map[modules.NetAddress]string),
+		network:    network,
 		persistDir: persistDir,
+
+		rpcLimits:     make(map[rpcID]rpcLimit),
+		rpcCalls:      make(map[modules.NetAddress]map[rpcID]*rpcCallWindow),
+		rpcViolations: make(map[modules.NetAddress]modules.GatewayRPCViolations),
+
+		discoveryInterval:        defaultDiscoveryInterval,
+		discoveryIntervalChanged: make(chan struct{}, 1),
 	}
 
 	// Create the logger.
@@ -110,6 +162,8 @@ func New(addr string, persistDir string) (g *Gateway, err error) {
 	// Register RPCs.
 	g.RegisterRPC("ShareNodes", g.shareNodes)
 	g.RegisterConnectCall("ShareNodes", g.requestNodes)
+	g.RegisterRPC("DiscoverIP", g.discoverIP)
+	g.RegisterConnectCall("DiscoverIP", g.requestIP)
 
 	// Load the old node list. If it doesn't exist, no problem, but if it does,
 	// we want to know about any errors preventing us from loading it.
@@ -130,7 +184,7 @@ func New(addr string, persistDir string) (g *Gateway, err error) {
 
 	// Create listener and set address.
 	threadedListenClosedChan := make(chan struct{})
-	g.listener, err = net.Listen("tcp", addr)
+	g.listener, err = net.Listen(g.network, addr)
 	if err != nil {
 		return
 	}
@@ -43,16 +43,18 @@ func (g *Gateway) removeNode(addr modules.NetAddress) error {
 	return nil
 }
 
+// randomNode returns a random node that the gateway is able to dial.
 func (g *Gateway) randomNode() (modules.NetAddress, error) {
-	if len(g.nodes) > 0 {
-		r, _ := crypto.RandIntn(len(g.nodes))
-		for node := range g.nodes {
-			if r <= 0 {
-				return node, nil
-			}
-			r--
+	var nodes []modules.NetAddress
+	for node := range g.nodes {
+		if g.reachable(node) {
+			nodes = append(nodes, node)
 		}
 	}
+	if len(nodes) > 0 {
+		r, _ := crypto.RandIntn(len(nodes))
+		return nodes[r], nil
+	}
 
 	return "", errNoPeers
 }
@@ -93,7 +95,8 @@ func (g *Gateway) requestNodes(conn modules.PeerConn) error {
 // threadedNodeManager tries to keep the Gateway's node list healthy. As long
 // as the Gateway has fewer than minNodeListSize nodes, it asks a random peer
 // for more nodes. It also continually pings nodes in order to establish their
-// connectivity. Unresponsive nodes are aggressively removed.
+// connectivity. Unresponsive nodes are aggressively removed. Each round starts
+// after the discovery interval of the Gateway has passed.
 func (g *Gateway) threadedNodeManager() {
 	if g.threads.Add() != nil {
 		return
@@ -101,8 +104,14 @@ func (g *Gateway) threadedNodeManager() {
 	defer g.threads.Done()
 
 	for {
+		g.mu.RLock()
+		interval := g.discoveryInterval
+		g.mu.RUnlock()
 		select {
-		case <-time.After(5 * time.Second):
+		case <-time.After(interval):
+		case <-g.discoveryIntervalChanged:
+			// Start waiting again using the new interval.
+			continue
 		case <-g.threads.StopChan():
 			return
 		}
@@ -133,7 +142,7 @@ func (g *Gateway) threadedNodeManager() {
 		}
 
 		// try to connect
-		conn, err := net.DialTimeout("tcp", string(node), dialTimeout)
+		conn, err := net.DialTimeout(g.network, string(node), dialTimeout)
 		if err != nil {
 			g.mu.Lock()
 			g.removeNode(node)
@@ -261,7 +261,8 @@ func (g *Gateway) managedAcceptConnNewPeer(conn net.Conn, remoteVersion string)
 func (g *Gateway) acceptPeer(p *peer) {
 	// If we are already fully connected, kick out an old peer to make room
 	// for the new one. Importantly, prioritize kicking a peer with the same
-	// IP as the connecting peer. This protects against Sybil attacks.
+	// IP as the connecting peer, or failing that, a peer in the same subnet.
+	// This protects against Sybil attacks.
 	if len(g.peers) >= fullyConnectedThreshold {
 		// first choose a random peer, preferably inbound. If have only
 		// outbound peers, we'll wind up kicking an outbound peer; but
@@ -271,15 +272,21 @@ func (g *Gateway) acceptPeer(p *peer) {
 		if err != nil {
 			kick, _ = g.randomPeer()
 		}
-		// if another peer shares this IP, choose that one instead
+		// if another peer shares this IP or subnet, choose that one instead
+		sameSubnet := false
 		for addr := range g.peers {
 			if addr.Host() == p.NetAddress.Host() {
 				kick = addr
 				break
 			}
+			if !sameSubnet && addr.Subnet() != "" && addr.Subnet() == p.NetAddress.Subnet() {
+				kick = addr
+				sameSubnet = true
+			}
 		}
 		g.peers[kick].sess.Close()
 		delete(g.peers, kick)
+		delete(g.ipReports, kick)
 		g.log.Printf("INFO: disconnected from %v to make room for %v", kick, p.NetAddress)
 	}
 
@@ -459,6 +466,9 @@ func (g *Gateway) Connect(addr modules.NetAddress) error {
 	if net.ParseIP(addr.Host()) == nil {
 		return errors.New("address must be an IP address")
 	}
+	if !g.reachable(addr) {
+		return errUnreachableFamily
+	}
 
 	g.mu.RLock()
 	_, exists := g.peers[addr]
@@ -467,7 +477,7 @@ func (g *Gateway) Connect(addr modules.NetAddress) error {
 		return errors.New("peer already added")
 	}
 
-	conn, err := net.DialTimeout("tcp", string(addr), dialTimeout)
+	conn, err := net.DialTimeout(g.network, string(addr), dialTimeout)
 	if err != nil {
 		return err
 	}
@@ -525,6 +535,7 @@ func (g *Gateway) Disconnect(addr modules.NetAddress) error {
 	}
 	g.mu.Lock()
 	delete(g.peers, addr)
+	delete(g.ipReports, addr)
 	g.mu.Unlock()
 	if err := p.sess.Close(); err != nil {
 		return err
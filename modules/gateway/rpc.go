@@ -132,6 +132,8 @@ func (g *Gateway) threadedListenPeer(p *peer) {
 		// Can't call Disconnect because it could return sync.ErrStopped.
 		g.mu.Lock()
 		delete(g.peers, p.NetAddress)
+		delete(g.rpcCalls, p.NetAddress)
+		delete(g.ipReports, p.NetAddress)
 		g.mu.Unlock()
 		if err := p.sess.Close(); err != nil {
 			g.log.Debugf("WARN: error disconnecting from peer %q: %v", p.NetAddress, err)
@@ -179,8 +181,18 @@ func (g *Gateway) threadedHandleConn(conn modules.PeerConn) {
 	}
 	g.log.Debugf("INFO: incoming conn %v requested RPC \"%v\"", conn.RPCAddr(), id)
 
+	lc, err := g.managedCheckRPCLimits(conn, id)
+	if err != nil {
+		g.log.Debugf("WARN: incoming RPC \"%v\" from conn %v rejected: %v", id, conn.RPCAddr(), err)
+		return
+	}
+	if lc != nil {
+		conn = lc
+	}
+
 	// call fn
-	err := fn(conn)
+	err = fn(conn)
+	g.managedFinishRPC(lc)
 	// don't log benign errors
 	if err == modules.ErrDuplicateTransactionSet || err == modules.ErrBlockKnown {
 		err = nil
@@ -0,0 +1,224 @@
+package gateway
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/NebulousLabs/Sia/modules"
+)
+
+const (
+	// rpcRateWindow is the window over which RPC calls are counted for rate
+	// limiting.
+	rpcRateWindow = time.Minute
+
+	// rpcViolationExpiry is how long a peer's violations are kept after its
+	// most recent violation. Violations outlive a disconnect, so that a peer
+	// that reconnects is disconnected again after its next violation, but
+	// they are eventually forgotten so that they do not accumulate forever.
+	rpcViolationExpiry = 24 * time.Hour
+)
+
+var (
+	errRPCRateLimited = errors.New("peer exceeded the rate limit of the RPC")
+	errRPCTooLarge    = errors.New("peer exceeded the maximum message size of the RPC")
+)
+
+type (
+	// rpcLimit is the limit configured for an RPC, along with the name that
+	// the RPC was configured under.
+	rpcLimit struct {
+		name string
+		modules.RPCLimit
+	}
+
+	// rpcCallWindow counts the calls a peer has made to an RPC in the current
+	// rate window.
+	rpcCallWindow struct {
+		start time.Time
+		calls uint64
+	}
+
+	// limitedConn is a PeerConn that fails reads once more than max bytes
+	// have been read.
+	limitedConn struct {
+		modules.PeerConn
+		remaining uint64
+		exceeded  bool
+	}
+)
+
+// Read implements the io.Reader interface.
+func (lc *limitedConn) Read(b []byte) (int, error) {
+	if lc.remaining == 0 {
+		lc.exceeded = true
+		return 0, errRPCTooLarge
+	}
+	if uint64(len(b)) > lc.remaining {
+		b = b[:lc.remaining]
+	}
+	n, err := lc.PeerConn.Read(b)
+	lc.remaining -= uint64(n)
+	return n, err
+}
+
+// byRPCName sorts RPC limits by the name of the RPC.
+type byRPCName []modules.RPCLimit
+
+func (b byRPCName) Len() int           { return len(b) }
+func (b byRPCName) Less(i, j int) bool { return b[i].RPC < b[j].RPC }
+func (b byRPCName) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+
+// allowRPCCall counts a call to the RPC by the peer, and returns false if the
+// call exceeds the rate limit of the RPC.
+func (g *Gateway) allowRPCCall(addr modules.NetAddress, id rpcID, limit modules.RPCLimit) bool {
+	if limit.CallsPerMinute == 0 {
+		return true
+	}
+	windows, ok := g.rpcCalls[addr]
+	if !ok {
+		windows = make(map[rpcID]*rpcCallWindow)
+		g.rpcCalls[addr] = windows
+	}
+	w, ok := windows[id]
+	if !ok || time.Since(w.start) > rpcRateWindow {
+		w = &rpcCallWindow{start: time.Now()}
+		windows[id] = w
+	}
+	w.calls++
+	return w.calls <= limit.CallsPerMinute
+}
+
+// pruneRPCState forgets the expired call windows and violations of peers. It
+// does nothing if it has already run within the last rate window.
+func (g *Gateway) pruneRPCState() {
+	if time.Since(g.rpcPruned) < rpcRateWindow {
+		return
+	}
+	g.rpcPruned = time.Now()
+	for addr, windows := range g.rpcCalls {
+		for id, w := range windows {
+			if time.Since(w.start) > rpcRateWindow {
+				delete(windows, id)
+			}
+		}
+		if len(windows) == 0 {
+			delete(g.rpcCalls, addr)
+		}
+	}
+	for addr, v := range g.rpcViolations {
+		if time.Since(v.LastViolation) > rpcViolationExpiry {
+			delete(g.rpcViolations, addr)
+		}
+	}
+}
+
+// recordRPCViolation records that the peer violated a limit, and returns true
+// if the peer has now reached the disconnect threshold.
+func (g *Gateway) recordRPCViolation(addr modules.NetAddress, err error) bool {
+	v, ok := g.rpcViolations[addr]
+	if !ok || time.Since(v.LastViolation) > rpcViolationExpiry {
+		v = modules.GatewayRPCViolations{}
+	}
+	v.NetAddress = addr
+	v.LastViolation = time.Now()
+	if err == errRPCTooLarge {
+		v.Oversized++
+	} else {
+		v.RateLimited++
+	}
+	g.rpcViolations[addr] = v
+	return g.rpcDisconnectAfter > 0 && v.Oversized+v.RateLimited >= g.rpcDisconnectAfter
+}
+
+// managedCheckRPCLimits applies the limits of the RPC to an incoming call. The
+// returned conn enforces the RPC's maximum message size, and must be passed to
+// managedFinishRPC once the call has been handled. An error is returned if the
+// call is rejected.
+func (g *Gateway) managedCheckRPCLimits(conn modules.PeerConn, id rpcID) (*limitedConn, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.pruneRPCState()
+	limit := g.rpcLimits[id].RPCLimit
+	if !g.allowRPCCall(conn.RPCAddr(), id, limit) {
+		if g.recordRPCViolation(conn.RPCAddr(), errRPCRateLimited) {
+			go g.managedDisconnectOffender(conn.RPCAddr())
+		}
+		return nil, errRPCRateLimited
+	}
+	if limit.MaxSize == 0 {
+		return nil, nil
+	}
+	return &limitedConn{PeerConn: conn, remaining: limit.MaxSize}, nil
+}
+
+// managedFinishRPC records a violation if the call read more than the maximum
+// message size of the RPC.
+func (g *Gateway) managedFinishRPC(lc *limitedConn) {
+	if lc == nil || !lc.exceeded {
+		return
+	}
+	g.mu.Lock()
+	disconnect := g.recordRPCViolation(lc.RPCAddr(), errRPCTooLarge)
+	g.mu.Unlock()
+	if disconnect {
+		g.managedDisconnectOffender(lc.RPCAddr())
+	}
+}
+
+// managedDisconnectOffender disconnects from a peer that has repeatedly
+// violated the RPC limits. There is no ban list, so the peer is free to
+// reconnect; its violations are kept for rpcViolationExpiry so that it is
+// disconnected again after its next violation.
+func (g *Gateway) managedDisconnectOffender(addr modules.NetAddress) {
+	if err := g.Disconnect(addr); err == nil {
+		g.log.Printf("WARN: disconnected from peer %v for repeatedly violating RPC limits", addr)
+	}
+}
+
+// RPCLimits returns the limits on incoming RPCs and the number of times each
+// peer has violated them.
+func (g *Gateway) RPCLimits() modules.GatewayRPCLimits {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	rl := modules.GatewayRPCLimits{
+		Limits:          make([]modules.RPCLimit, 0, len(g.rpcLimits)),
+		DisconnectAfter: g.rpcDisconnectAfter,
+		Violations:      make([]modules.GatewayRPCViolations, 0, len(g.rpcViolations)),
+	}
+	for _, l := range g.rpcLimits {
+		limit := l.RPCLimit
+		limit.RPC = l.name
+		rl.Limits = append(rl.Limits, limit)
+	}
+	sort.Sort(byRPCName(rl.Limits))
+	for _, v := range g.rpcViolations {
+		if time.Since(v.LastViolation) > rpcViolationExpiry {
+			continue
+		}
+		rl.Violations = append(rl.Violations, v)
+	}
+	return rl
+}
+
+// SetRPCLimit sets the limits on incoming calls of the named RPC. A limit of
+// zero removes that limit.
+func (g *Gateway) SetRPCLimit(limit modules.RPCLimit) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	id := handlerName(limit.RPC)
+	if limit.MaxSize == 0 && limit.CallsPerMinute == 0 {
+		delete(g.rpcLimits, id)
+		return
+	}
+	g.rpcLimits[id] = rpcLimit{name: limit.RPC, RPCLimit: limit}
+}
+
+// SetRPCDisconnectThreshold sets the number of RPC limit violations after
+// which a peer is disconnected. Zero disables disconnecting.
+func (g *Gateway) SetRPCDisconnectThreshold(violations uint64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.rpcDisconnectAfter = violations
+}
@@ -0,0 +1,104 @@
+package gateway
+
+import (
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/NebulousLabs/Sia/modules"
+)
+
+// TestLimitedConn checks that a limitedConn fails reads past its limit.
+func TestLimitedConn(t *testing.T) {
+	c1, c2 := net.Pipe()
+	defer c1.Close()
+	go func() {
+		c2.Write([]byte("0123456789"))
+		c2.Close()
+	}()
+
+	lc := &limitedConn{PeerConn: peerConn{Conn: c1}, remaining: 4}
+	b, err := ioutil.ReadAll(lc)
+	if err != errRPCTooLarge {
+		t.Fatal("expected errRPCTooLarge, got", err)
+	}
+	if string(b) != "0123" || !lc.exceeded {
+		t.Fatalf("read %q past the limit", b)
+	}
+}
+
+// TestRPCRateLimit checks that calls beyond an RPC's rate limit are rejected
+// and counted as violations.
+func TestRPCRateLimit(t *testing.T) {
+	g := &Gateway{
+		rpcLimits:     make(map[rpcID]rpcLimit),
+		rpcCalls:      make(map[modules.NetAddress]map[rpcID]*rpcCallWindow),
+		rpcViolations: make(map[modules.NetAddress]modules.GatewayRPCViolations),
+	}
+	g.SetRPCLimit(modules.RPCLimit{RPC: "Foo", CallsPerMinute: 2})
+	conn := peerConn{dialbackAddr: "1.2.3.4:9981"}
+	other := peerConn{dialbackAddr: "5.6.7.8:9981"}
+
+	for i := 0; i < 2; i++ {
+		if _, err := g.managedCheckRPCLimits(conn, handlerName("Foo")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := g.managedCheckRPCLimits(conn, handlerName("Foo")); err != errRPCRateLimited {
+		t.Fatal("expected errRPCRateLimited, got", err)
+	}
+	// Other peers and other RPCs are not affected.
+	if _, err := g.managedCheckRPCLimits(other, handlerName("Foo")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.managedCheckRPCLimits(conn, handlerName("Bar")); err != nil {
+		t.Fatal(err)
+	}
+
+	rl := g.RPCLimits()
+	if len(rl.Limits) != 1 || rl.Limits[0].RPC != "Foo" || rl.Limits[0].CallsPerMinute != 2 {
+		t.Fatalf("unexpected limits: %+v", rl.Limits)
+	}
+	if len(rl.Violations) != 1 || rl.Violations[0].NetAddress != conn.RPCAddr() || rl.Violations[0].RateLimited != 1 {
+		t.Fatalf("unexpected violations: %+v", rl.Violations)
+	}
+
+	// Peers reach the disconnect threshold once they have enough violations.
+	g.SetRPCDisconnectThreshold(3)
+	if g.recordRPCViolation(conn.RPCAddr(), errRPCTooLarge) {
+		t.Fatal("peer reached the threshold after 2 violations")
+	}
+	if !g.recordRPCViolation(conn.RPCAddr(), errRPCTooLarge) {
+		t.Fatal("peer did not reach the threshold after 3 violations")
+	}
+
+	// Expired violations are forgotten, and the peer starts counting again.
+	v := g.rpcViolations[conn.RPCAddr()]
+	v.LastViolation = time.Now().Add(-rpcViolationExpiry - time.Minute)
+	g.rpcViolations[conn.RPCAddr()] = v
+	if len(g.RPCLimits().Violations) != 0 {
+		t.Fatal("expired violations were listed")
+	}
+	if g.recordRPCViolation(conn.RPCAddr(), errRPCTooLarge) || g.rpcViolations[conn.RPCAddr()].Oversized != 1 {
+		t.Fatalf("expired violations were counted: %+v", g.rpcViolations[conn.RPCAddr()])
+	}
+	v = g.rpcViolations[conn.RPCAddr()]
+	v.LastViolation = time.Now().Add(-rpcViolationExpiry - time.Minute)
+	g.rpcViolations[conn.RPCAddr()] = v
+	g.rpcCalls[conn.RPCAddr()][handlerName("Foo")].start = time.Now().Add(-2 * rpcRateWindow)
+	g.rpcPruned = time.Time{}
+	g.pruneRPCState()
+	if _, ok := g.rpcViolations[conn.RPCAddr()]; ok {
+		t.Fatal("expired violations were not pruned")
+	}
+	if _, ok := g.rpcCalls[conn.RPCAddr()][handlerName("Foo")]; ok {
+		t.Fatal("expired call window was not pruned")
+	}
+
+	// Removing the limit removes it from the list.
+	g.SetRPCLimit(modules.RPCLimit{RPC: "Foo"})
+	if len(g.RPCLimits().Limits) != 0 {
+		t.Fatal("limit was not removed")
+	}
+}
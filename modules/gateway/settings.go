@@ -0,0 +1,51 @@
+package gateway
+
+import (
+	"errors"
+	"time"
+
+	"github.com/NebulousLabs/Sia/modules"
+)
+
+const (
+	// defaultDiscoveryInterval is the time between rounds of peer discovery
+	// when the interval has not been changed.
+	defaultDiscoveryInterval = 5 * time.Second
+
+	// minDiscoveryInterval and maxDiscoveryInterval bound the discovery
+	// interval. Below the minimum, the gateway would flood its peers with
+	// ShareNodes calls; above the maximum, a node that lost its peers would
+	// take too long to find new ones.
+	minDiscoveryInterval = time.Second
+	maxDiscoveryInterval = time.Hour
+)
+
+var errDiscoveryIntervalBounds = errors.New("discovery interval must be between 1 second and 1 hour")
+
+// Settings returns the settings of the Gateway.
+func (g *Gateway) Settings() modules.GatewaySettings {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return modules.GatewaySettings{
+		DiscoveryInterval: uint64(g.discoveryInterval / time.Second),
+	}
+}
+
+// SetSettings changes the settings of the Gateway. A new discovery interval
+// applies to the round of peer discovery that the Gateway is waiting for.
+func (g *Gateway) SetSettings(settings modules.GatewaySettings) error {
+	if settings.DiscoveryInterval < uint64(minDiscoveryInterval/time.Second) || settings.DiscoveryInterval > uint64(maxDiscoveryInterval/time.Second) {
+		return errDiscoveryIntervalBounds
+	}
+	interval := time.Duration(settings.DiscoveryInterval) * time.Second
+
+	g.mu.Lock()
+	g.discoveryInterval = interval
+	g.mu.Unlock()
+	select {
+	case g.discoveryIntervalChanged <- struct{}{}:
+	default:
+	}
+	g.log.Println("INFO: peer discovery interval set to", interval)
+	return nil
+}
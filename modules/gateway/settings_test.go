@@ -0,0 +1,29 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/NebulousLabs/Sia/modules"
+)
+
+// TestGatewaySettings checks that the discovery interval can be changed
+// within its bounds.
+func TestGatewaySettings(t *testing.T) {
+	g := newTestingGateway("TestGatewaySettings", t)
+	defer g.Close()
+
+	if g.Settings().DiscoveryInterval != 5 {
+		t.Fatal("wrong default discovery interval:", g.Settings().DiscoveryInterval)
+	}
+	for _, interval := range []uint64{0, 3601, 1 << 62} {
+		if err := g.SetSettings(modules.GatewaySettings{DiscoveryInterval: interval}); err != errDiscoveryIntervalBounds {
+			t.Fatalf("expected errDiscoveryIntervalBounds for %v, got %v", interval, err)
+		}
+	}
+	if err := g.SetSettings(modules.GatewaySettings{DiscoveryInterval: 60}); err != nil {
+		t.Fatal(err)
+	}
+	if g.Settings().DiscoveryInterval != 60 {
+		t.Fatal("discovery interval was not changed")
+	}
+}
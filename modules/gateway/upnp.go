@@ -76,6 +76,10 @@ func (g *Gateway) threadedLearnHostname() {
 		g.log.Printf("WARN: discovered hostname %q is invalid: %v", addr, err)
 		return
 	}
+	if !g.reachable(addr) {
+		g.log.Printf("WARN: discovered hostname %q is not in the IP family that the gateway is bound to", addr)
+		return
+	}
 
 	g.mu.Lock()
 	g.myAddr = addr
@@ -108,6 +112,10 @@ func (g *Gateway) threadedForwardPort(port string) {
 		return
 	}
 
+	g.mu.Lock()
+	g.portForwarded = true
+	g.mu.Unlock()
+
 	g.log.Println("INFO: successfully forwarded port", port)
 }
 
@@ -1,6 +1,9 @@
 package modules
 
 import (
+	"time"
+
+	"github.com/NebulousLabs/Sia/crypto"
 	"github.com/NebulousLabs/Sia/types"
 )
 
@@ -65,6 +68,140 @@ type (
 		MinDownloadBandwidthPrice types.Currency `json:"mindownloadbandwidthprice"`
 		MinStoragePrice           types.Currency `json:"minstorageprice"`
 		MinUploadBandwidthPrice   types.Currency `json:"minuploadbandwidthprice"`
+
+		// Contract acceptance rules. A zero value disables the rule.
+		// MinContractSize is measured in bytes, and is compared against the
+		// amount of data that the renter's funds can pay to store for the
+		// full duration of the contract at MinStoragePrice.
+		MinDuration       types.BlockHeight `json:"minduration"`
+		MinContractSize   uint64            `json:"mincontractsize"`
+		MinContractPayout types.Currency    `json:"mincontractpayout"`
+
+		// ProofPrecomputeBlocks is the number of blocks before submission
+		// that storage proofs are built. Zero builds each proof in the block
+		// in which it is submitted.
+		ProofPrecomputeBlocks types.BlockHeight `json:"proofprecomputeblocks"`
+
+		// MaxRenterUploads and MaxRenterDownloads limit the number of upload
+		// (revise) and download RPCs that a single renter may have open at
+		// once. Renters are identified by the public key in their contracts.
+		// Zero is no limit.
+		MaxRenterUploads   uint64 `json:"maxrenteruploads"`
+		MaxRenterDownloads uint64 `json:"maxrenterdownloads"`
+
+		// Automatic pricing. While AutoPricing is set, the host periodically
+		// sets its minimum storage and bandwidth prices to the median prices
+		// of the other active hosts, adjusted by AutoPriceOffset percent and
+		// kept within the Min and Max bounds. A Max of zero is not enforced.
+		AutoPricing                   bool           `json:"autopricing"`
+		AutoPriceOffset               float64        `json:"autopriceoffset"`
+		AutoStoragePriceMin           types.Currency `json:"autostoragepricemin"`
+		AutoStoragePriceMax           types.Currency `json:"autostoragepricemax"`
+		AutoUploadBandwidthPriceMin   types.Currency `json:"autouploadbandwidthpricemin"`
+		AutoUploadBandwidthPriceMax   types.Currency `json:"autouploadbandwidthpricemax"`
+		AutoDownloadBandwidthPriceMin types.Currency `json:"autodownloadbandwidthpricemin"`
+		AutoDownloadBandwidthPriceMax types.Currency `json:"autodownloadbandwidthpricemax"`
+	}
+
+	// HostPriceAdjustment records an attempt by the host to set its prices
+	// from the median prices of the market. If the attempt failed, Error
+	// describes why and the prices were left unchanged.
+	HostPriceAdjustment struct {
+		Height types.BlockHeight `json:"height"`
+		Hosts  uint64            `json:"hosts"`
+		Error  string            `json:"error,omitempty"`
+
+		MedianStoragePrice           types.Currency `json:"medianstorageprice"`
+		MedianUploadBandwidthPrice   types.Currency `json:"medianuploadbandwidthprice"`
+		MedianDownloadBandwidthPrice types.Currency `json:"mediandownloadbandwidthprice"`
+
+		StoragePrice           types.Currency `json:"storageprice"`
+		UploadBandwidthPrice   types.Currency `json:"uploadbandwidthprice"`
+		DownloadBandwidthPrice types.Currency `json:"downloadbandwidthprice"`
+	}
+
+	// A HostMarket reports the settings of the active hosts on the network.
+	// The renter, whose host database tracks them, is a HostMarket.
+	HostMarket interface {
+		ActiveHosts() []HostDBEntry
+	}
+
+	// HostContractRejection records a file contract proposal that the host
+	// turned down during contract formation.
+	HostContractRejection struct {
+		Timestamp time.Time         `json:"timestamp"`
+		Reason    string            `json:"reason"`
+		Duration  types.BlockHeight `json:"duration"`
+		Payout    types.Currency    `json:"payout"`
+	}
+
+	// HostRenterSessions counts the upload (revise) and download RPCs that a
+	// renter has open with the host. RenterKey is the renter's public key,
+	// as "algorithm:hex".
+	HostRenterSessions struct {
+		RenterKey string `json:"renterkey"`
+		Uploads   uint64 `json:"uploads"`
+		Downloads uint64 `json:"downloads"`
+	}
+
+	// HostLatencyBucket counts the RPCs that took at most UpperBoundMs
+	// milliseconds, and longer than the bound of the previous bucket.
+	HostLatencyBucket struct {
+		UpperBoundMs float64 `json:"upperboundms"`
+		Count        uint64  `json:"count"`
+	}
+
+	// HostRPCLatency summarizes how long the host took to handle one type of
+	// RPC since it started. Percentiles are estimated from the histogram
+	// buckets, and are never more than MaxMs. Overflow counts the RPCs that
+	// took longer than the largest bucket.
+	HostRPCLatency struct {
+		RPC       string              `json:"rpc"`
+		Calls     uint64              `json:"calls"`
+		Errors    uint64              `json:"errors"`
+		AverageMs float64             `json:"averagems"`
+		P50Ms     float64             `json:"p50ms"`
+		P90Ms     float64             `json:"p90ms"`
+		P99Ms     float64             `json:"p99ms"`
+		MaxMs     float64             `json:"maxms"`
+		Buckets   []HostLatencyBucket `json:"buckets"`
+		Overflow  uint64              `json:"overflow"`
+	}
+
+	// HostSector describes a sector stored by the host, along with the
+	// unresolved storage obligations that contain it.
+	HostSector struct {
+		Root        crypto.Hash            `json:"root"`
+		Obligations []types.FileContractID `json:"obligations"`
+		StorageSectorUsage
+	}
+
+	// HostProofStatus reports the storage proofs that the host has built
+	// ahead of time, and the storage proofs that are outstanding.
+	HostProofStatus struct {
+		Precomputed uint64 `json:"precomputed"`
+		Pending     uint64 `json:"pending"`
+	}
+
+	// HostDecommissionStatus reports the progress of a host that is winding
+	// down. A decommissioning host accepts no new contracts or renewals, but
+	// keeps storing data and submitting storage proofs for its existing
+	// obligations, so that its collateral is returned as they resolve.
+	HostDecommissionStatus struct {
+		Decommissioning bool              `json:"decommissioning"`
+		StartHeight     types.BlockHeight `json:"startheight"`
+
+		// ObligationsRemaining is the number of storage obligations that have
+		// not yet been resolved, and LockedCollateral is the collateral still
+		// held by them.
+		ObligationsRemaining uint64         `json:"obligationsremaining"`
+		LockedCollateral     types.Currency `json:"lockedcollateral"`
+
+		// EstimatedCompletionHeight is the latest proof deadline of the
+		// remaining obligations, by which point every obligation will have
+		// been resolved.
+		EstimatedCompletionHeight types.BlockHeight `json:"estimatedcompletionheight"`
+		Complete                  bool              `json:"complete"`
 	}
 
 	// HostNetworkMetrics reports the quantity of each type of RPC call that
@@ -79,6 +216,18 @@ type (
 		UnrecognizedCalls uint64 `json:"unrecognizedcalls"`
 	}
 
+	// HostStorageObligation summarizes a file contract that the host has
+	// agreed to store data for. Status is one of "unresolved", "rejected",
+	// "succeeded" or "failed".
+	HostStorageObligation struct {
+		ContractID        types.FileContractID  `json:"contractid"`
+		Expiration        types.BlockHeight     `json:"expiration"`
+		ProofDeadline     types.BlockHeight     `json:"proofdeadline"`
+		Status            string                `json:"status"`
+		Value             types.Currency        `json:"value"`
+		ValidProofOutputs []types.SiacoinOutput `json:"validproofoutputs"`
+	}
+
 	// A Host can take storage from disk and offer it to the network, managing
 	// things such as announcements, settings, and implementing all of the RPCs
 	// of the host protocol.
@@ -89,6 +238,17 @@ type (
 		// AnnounceAddress submits an announcement using the given address.
 		AnnounceAddress(NetAddress) error
 
+		// ContractRejections returns the most recent file contract proposals
+		// that were rejected by the host, oldest first.
+		ContractRejections() []HostContractRejection
+
+		// Decommission stops the host from accepting new contracts and
+		// renewals, while continuing to serve its existing obligations.
+		Decommission() error
+
+		// DecommissionStatus reports the progress of decommissioning.
+		DecommissionStatus() HostDecommissionStatus
+
 		// ExternalSettings returns the settings of the host as seen by an
 		// untrusted node querying the host for settings.
 		ExternalSettings() HostExternalSettings
@@ -104,9 +264,42 @@ type (
 		// have been made to the host.
 		NetworkMetrics() HostNetworkMetrics
 
+		// ProofStatus reports the storage proofs that have been precomputed
+		// and the storage proofs that are outstanding.
+		ProofStatus() HostProofStatus
+
+		// SetMarket sets the source of the market prices used by automatic
+		// pricing.
+		SetMarket(HostMarket)
+
+		// PriceAdjustment returns the most recent attempt at automatic
+		// pricing, and false if there has been none.
+		PriceAdjustment() (HostPriceAdjustment, bool)
+
+		// RenterSessions returns the number of upload and download RPCs that
+		// each renter has open with the host.
+		RenterSessions() []HostRenterSessions
+
+		// RPCLatencies returns the latency histogram of each type of RPC
+		// that the host has handled since it started.
+		RPCLatencies() []HostRPCLatency
+
+		// Sector returns the reference count and location of a sector, and
+		// the storage obligations that contain it.
+		Sector(root crypto.Hash) (HostSector, error)
+
+		// OrphanedSectors returns the sectors that are not contained in any
+		// unresolved storage obligation. They can be deleted to reclaim
+		// space.
+		OrphanedSectors() ([]StorageSectorUsage, error)
+
 		// SetInternalSettings sets the hosting parameters of the host.
 		SetInternalSettings(HostInternalSettings) error
 
+		// StorageObligations returns a summary of every storage obligation
+		// that the host is tracking.
+		StorageObligations() []HostStorageObligation
+
 		// The storage manager provides an interface for adding and removing
 		// storage folders and data sectors to the host.
 		StorageManager
@@ -0,0 +1,163 @@
+package host
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+var (
+	errAutoPriceBounds   = errors.New("automatic pricing bounds must not have a minimum above the maximum")
+	errAutoPriceFewHosts = fmt.Errorf("the prices of at least %v other hosts are needed to follow the market", minAutoPriceHosts)
+	errAutoPriceNoMarket = errors.New("no market is available to take prices from; the renter must be running")
+	errAutoPriceOffset   = errors.New("automatic pricing offset must be greater than -100 and at most 1000 percent")
+)
+
+// byCurrency sorts currencies in ascending order.
+type byCurrency []types.Currency
+
+func (bc byCurrency) Len() int           { return len(bc) }
+func (bc byCurrency) Less(i, j int) bool { return bc[i].Cmp(bc[j]) < 0 }
+func (bc byCurrency) Swap(i, j int)      { bc[i], bc[j] = bc[j], bc[i] }
+
+// medianPrice returns the median of a non-empty set of prices. For an even
+// number of prices, the lower of the two middle prices is used.
+func medianPrice(prices []types.Currency) types.Currency {
+	sort.Sort(byCurrency(prices))
+	return prices[(len(prices)-1)/2]
+}
+
+// autoPrice returns the median price adjusted by offset percent, kept within
+// the given bounds. A max of zero is not enforced.
+func autoPrice(median types.Currency, offset float64, min, max types.Currency) types.Currency {
+	price := median.MulRat(new(big.Rat).SetFloat64(1 + offset/100))
+	if price.Cmp(min) < 0 {
+		price = min
+	}
+	if !max.IsZero() && price.Cmp(max) > 0 {
+		price = max
+	}
+	return price
+}
+
+// checkAutoPriceSettings returns an error if the automatic pricing settings
+// are invalid. The settings are checked even while automatic pricing is
+// disabled, so that it can be enabled later without further changes.
+func checkAutoPriceSettings(settings modules.HostInternalSettings) error {
+	// The comparisons are written to also reject NaN.
+	if !(settings.AutoPriceOffset > -100 && settings.AutoPriceOffset <= 1000) {
+		return errAutoPriceOffset
+	}
+	bounds := [][2]types.Currency{
+		{settings.AutoStoragePriceMin, settings.AutoStoragePriceMax},
+		{settings.AutoUploadBandwidthPriceMin, settings.AutoUploadBandwidthPriceMax},
+		{settings.AutoDownloadBandwidthPriceMin, settings.AutoDownloadBandwidthPriceMax},
+	}
+	for _, b := range bounds {
+		if !b[1].IsZero() && b[0].Cmp(b[1]) > 0 {
+			return errAutoPriceBounds
+		}
+	}
+	return nil
+}
+
+// marketPrices fills in the median prices of the hosts in the market and the
+// prices that the host should charge according to its settings. The host's
+// own entry is left out of the medians.
+func (h *Host) marketPrices(hosts []modules.HostDBEntry, pa *modules.HostPriceAdjustment) error {
+	var storage, upload, download []types.Currency
+	for _, host := range hosts {
+		if bytes.Equal(host.PublicKey.Key, h.publicKey.Key) {
+			continue
+		}
+		storage = append(storage, host.StoragePrice)
+		upload = append(upload, host.UploadBandwidthPrice)
+		download = append(download, host.DownloadBandwidthPrice)
+	}
+	pa.Hosts = uint64(len(storage))
+	if len(storage) < minAutoPriceHosts {
+		return errAutoPriceFewHosts
+	}
+
+	s := h.settings
+	pa.MedianStoragePrice = medianPrice(storage)
+	pa.MedianUploadBandwidthPrice = medianPrice(upload)
+	pa.MedianDownloadBandwidthPrice = medianPrice(download)
+	pa.StoragePrice = autoPrice(pa.MedianStoragePrice, s.AutoPriceOffset, s.AutoStoragePriceMin, s.AutoStoragePriceMax)
+	pa.UploadBandwidthPrice = autoPrice(pa.MedianUploadBandwidthPrice, s.AutoPriceOffset, s.AutoUploadBandwidthPriceMin, s.AutoUploadBandwidthPriceMax)
+	pa.DownloadBandwidthPrice = autoPrice(pa.MedianDownloadBandwidthPrice, s.AutoPriceOffset, s.AutoDownloadBandwidthPriceMin, s.AutoDownloadBandwidthPriceMax)
+	return nil
+}
+
+// threadedAdjustPrices sets the minimum storage and bandwidth prices of the
+// host from the median prices of the market, if automatic pricing is enabled.
+// The outcome is recorded whether or not the prices could be set.
+func (h *Host) threadedAdjustPrices() {
+	if err := h.tg.Add(); err != nil {
+		return
+	}
+	defer h.tg.Done()
+
+	// The market is queried without holding the host's lock, as the renter
+	// may take a while to respond.
+	h.mu.RLock()
+	market := h.market
+	enabled := h.settings.AutoPricing
+	h.mu.RUnlock()
+	if !enabled {
+		return
+	}
+	var hosts []modules.HostDBEntry
+	if market != nil {
+		hosts = market.ActiveHosts()
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.settings.AutoPricing {
+		return
+	}
+	pa := modules.HostPriceAdjustment{Height: h.blockHeight}
+	err := errAutoPriceNoMarket
+	if market != nil {
+		err = h.marketPrices(hosts, &pa)
+	}
+	if err == nil {
+		h.settings.MinStoragePrice = pa.StoragePrice
+		h.settings.MinUploadBandwidthPrice = pa.UploadBandwidthPrice
+		h.settings.MinDownloadBandwidthPrice = pa.DownloadBandwidthPrice
+		h.revisionNumber++
+		err = h.save()
+	}
+	if err != nil {
+		pa.Error = err.Error()
+		h.log.Println("WARN: automatic pricing failed:", err)
+	} else {
+		h.log.Printf("INFO: automatic pricing set the storage price to %v, the upload price to %v, and the download price to %v, following the median prices of %v hosts",
+			pa.StoragePrice, pa.UploadBandwidthPrice, pa.DownloadBandwidthPrice, pa.Hosts)
+	}
+	h.priceAdjustment = &pa
+}
+
+// SetMarket sets the source of the market prices used by automatic pricing.
+func (h *Host) SetMarket(market modules.HostMarket) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.market = market
+}
+
+// PriceAdjustment returns the most recent attempt at automatic pricing, and
+// false if automatic pricing has not run since the host was started.
+func (h *Host) PriceAdjustment() (modules.HostPriceAdjustment, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if h.priceAdjustment == nil {
+		return modules.HostPriceAdjustment{}, false
+	}
+	return *h.priceAdjustment, true
+}
@@ -0,0 +1,119 @@
+package host
+
+import (
+	"testing"
+
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// stubMarket is a HostMarket with a fixed set of hosts.
+type stubMarket []modules.HostDBEntry
+
+func (sm stubMarket) ActiveHosts() []modules.HostDBEntry { return sm }
+
+// TestAutoPrice checks that prices follow the median, adjusted by the offset
+// and kept within the bounds.
+func TestAutoPrice(t *testing.T) {
+	prices := []types.Currency{types.NewCurrency64(40), types.NewCurrency64(10), types.NewCurrency64(30), types.NewCurrency64(20)}
+	if m := medianPrice(prices); m.Cmp(types.NewCurrency64(20)) != 0 {
+		t.Fatal("wrong median:", m)
+	}
+
+	median := types.NewCurrency64(100)
+	tests := []struct {
+		offset   float64
+		min, max uint64
+		price    uint64
+	}{
+		{0, 0, 0, 100},
+		{-10, 0, 0, 90},
+		{50, 0, 0, 150},
+		{50, 0, 120, 120},
+		{-50, 80, 0, 80},
+	}
+	for _, test := range tests {
+		price := autoPrice(median, test.offset, types.NewCurrency64(test.min), types.NewCurrency64(test.max))
+		if price.Cmp(types.NewCurrency64(test.price)) != 0 {
+			t.Errorf("offset %v within [%v, %v]: expected %v, got %v", test.offset, test.min, test.max, test.price, price)
+		}
+	}
+}
+
+// TestAdjustPrices checks that the host sets its prices from the market when
+// automatic pricing is enabled.
+func TestAdjustPrices(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	ht, err := newHostTester("TestAdjustPrices")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ht.Close()
+
+	settings := ht.host.InternalSettings()
+	settings.AutoPriceOffset = -100
+	if err := ht.host.SetInternalSettings(settings); err != errAutoPriceOffset {
+		t.Fatal("expected errAutoPriceOffset, got", err)
+	}
+	settings.AutoPriceOffset = -10
+	settings.AutoStoragePriceMin = types.NewCurrency64(2)
+	settings.AutoStoragePriceMax = types.NewCurrency64(1)
+	if err := ht.host.SetInternalSettings(settings); err != errAutoPriceBounds {
+		t.Fatal("expected errAutoPriceBounds, got", err)
+	}
+	settings.AutoStoragePriceMax = types.ZeroCurrency
+
+	// Too few hosts leave the prices unchanged. The host's own entry is not
+	// counted.
+	own := modules.HostDBEntry{PublicKey: ht.host.publicKey}
+	own.StoragePrice = types.NewCurrency64(1e6)
+	market := stubMarket{own}
+	for i := uint64(1); i <= 2; i++ {
+		var entry modules.HostDBEntry
+		entry.PublicKey.Key = []byte{byte(i)}
+		entry.StoragePrice = types.NewCurrency64(i * 100)
+		entry.UploadBandwidthPrice = types.NewCurrency64(i * 10)
+		entry.DownloadBandwidthPrice = types.NewCurrency64(i * 20)
+		market = append(market, entry)
+	}
+	ht.host.SetMarket(market)
+	settings.AutoPricing = true
+	if err := ht.host.SetInternalSettings(settings); err != nil {
+		t.Fatal(err)
+	}
+	oldPrice := settings.MinStoragePrice
+	ht.host.threadedAdjustPrices()
+	pa, ok := ht.host.PriceAdjustment()
+	if !ok || pa.Error != errAutoPriceFewHosts.Error() || pa.Hosts != 2 {
+		t.Fatalf("unexpected adjustment: %+v", pa)
+	}
+	if ht.host.InternalSettings().MinStoragePrice.Cmp(oldPrice) != 0 {
+		t.Fatal("prices changed without enough hosts")
+	}
+
+	// With a third host, the prices are set 10% below the median.
+	var entry modules.HostDBEntry
+	entry.PublicKey.Key = []byte{3}
+	entry.StoragePrice = types.NewCurrency64(300)
+	entry.UploadBandwidthPrice = types.NewCurrency64(30)
+	entry.DownloadBandwidthPrice = types.NewCurrency64(60)
+	ht.host.SetMarket(append(market, entry))
+	ht.host.threadedAdjustPrices()
+	pa, _ = ht.host.PriceAdjustment()
+	if pa.Error != "" || pa.Hosts != 3 || pa.MedianStoragePrice.Cmp(types.NewCurrency64(200)) != 0 {
+		t.Fatalf("unexpected adjustment: %+v", pa)
+	}
+	is := ht.host.InternalSettings()
+	if is.MinStoragePrice.Cmp(types.NewCurrency64(180)) != 0 {
+		t.Fatal("wrong storage price:", is.MinStoragePrice)
+	}
+	if is.MinUploadBandwidthPrice.Cmp(types.NewCurrency64(18)) != 0 {
+		t.Fatal("wrong upload price:", is.MinUploadBandwidthPrice)
+	}
+	if is.MinDownloadBandwidthPrice.Cmp(types.NewCurrency64(36)) != 0 {
+		t.Fatal("wrong download price:", is.MinDownloadBandwidthPrice)
+	}
+}
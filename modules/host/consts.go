@@ -26,6 +26,14 @@ const (
 	// connection.
 	iteratedConnectionTime = 1200 * time.Second
 
+	// minAutoPriceHosts is the number of other hosts whose prices must be
+	// known before automatic pricing will follow their median.
+	minAutoPriceHosts = 3
+
+	// maxRecentRejections is the number of rejected file contract proposals
+	// that the host keeps in memory for reporting.
+	maxRecentRejections = 50
+
 	// resubmissionTimeout defines the number of blocks that a host will wait
 	// before attempting to resubmit a transaction to the blockchain.
 	// Typically, this transaction will contain either a file contract, a file
@@ -104,6 +112,36 @@ var (
 	// data.
 	defaultUploadBandwidthPrice = types.SiacoinPrecision.Mul64(100).Div(modules.BytesPerTerabyte) // 100 SC / TB
 
+	// autoPriceFrequency is the number of blocks between adjustments of the
+	// host's prices by automatic pricing.
+	autoPriceFrequency = func() types.BlockHeight {
+		if build.Release == "dev" {
+			return 36
+		}
+		if build.Release == "standard" {
+			return 144 // 1 day.
+		}
+		if build.Release == "testing" {
+			return 5
+		}
+		panic("unrecognized release constant in host - autoPriceFrequency")
+	}()
+
+	// tierMigrationFrequency is the number of blocks between migrations of
+	// aged sectors from fast storage folders to slow storage folders.
+	tierMigrationFrequency = func() types.BlockHeight {
+		if build.Release == "dev" {
+			return 6
+		}
+		if build.Release == "standard" {
+			return 6 // 1 hour.
+		}
+		if build.Release == "testing" {
+			return 3
+		}
+		panic("unrecognized release constant in host - tierMigrationFrequency")
+	}()
+
 	// defaultWindowSize is the size of the proof of storage window requested
 	// by the host. The host will not delete any obligations until the window
 	// has closed and buried under several confirmations. For release builds,
@@ -0,0 +1,83 @@
+package host
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/NebulousLabs/Sia/modules"
+
+	"github.com/NebulousLabs/bolt"
+)
+
+var (
+	// errDecommissioning is returned when the host is asked to accept
+	// contracts after decommissioning has started.
+	errDecommissioning = errors.New("host is decommissioning and cannot accept contracts")
+
+	// errAlreadyDecommissioning is returned when decommissioning is requested
+	// for a host that is already decommissioning.
+	errAlreadyDecommissioning = errors.New("host is already decommissioning")
+)
+
+// Decommission puts the host into a wind-down state. The host stops accepting
+// new contracts and renewals, but keeps storing data, serving downloads, and
+// submitting storage proofs for its existing obligations, so that the locked
+// collateral is returned as each obligation resolves. Decommissioning cannot
+// be undone.
+func (h *Host) Decommission() error {
+	err := h.tg.Add()
+	if err != nil {
+		return err
+	}
+	defer h.tg.Done()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.decommissioning {
+		return errAlreadyDecommissioning
+	}
+	h.decommissioning = true
+	h.decommissionHeight = h.blockHeight
+	h.settings.AcceptingContracts = false
+	h.revisionNumber++
+	h.log.Println("Decommissioning the host at height", h.blockHeight)
+	return h.saveSync()
+}
+
+// DecommissionStatus reports how many storage obligations a decommissioning
+// host still has to resolve, and by which height they will be resolved.
+func (h *Host) DecommissionStatus() modules.HostDecommissionStatus {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	ds := modules.HostDecommissionStatus{
+		Decommissioning: h.decommissioning,
+		StartHeight:     h.decommissionHeight,
+	}
+	err := h.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketStorageObligations).ForEach(func(_, soBytes []byte) error {
+			var so storageObligation
+			err := json.Unmarshal(soBytes, &so)
+			if err != nil {
+				return err
+			}
+			if so.ObligationStatus != obligationUnresolved {
+				return nil
+			}
+			ds.ObligationsRemaining++
+			ds.LockedCollateral = ds.LockedCollateral.Add(so.LockedCollateral)
+			if so.proofDeadline() > ds.EstimatedCompletionHeight {
+				ds.EstimatedCompletionHeight = so.proofDeadline()
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		h.log.Println("Unable to read storage obligations:", err)
+	}
+	if ds.ObligationsRemaining == 0 {
+		ds.EstimatedCompletionHeight = h.blockHeight
+	}
+	ds.Complete = h.decommissioning && ds.ObligationsRemaining == 0 && err == nil
+	return ds
+}
@@ -0,0 +1,73 @@
+package host
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/NebulousLabs/Sia/modules"
+)
+
+// TestDecommission checks that a decommissioning host stops accepting
+// contracts, reports its remaining obligations, and stays decommissioning
+// after a restart.
+func TestDecommission(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	ht, err := newHostTester("TestDecommission")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ht.Close()
+
+	// Add an obligation that the host will have to see through.
+	so, err := ht.newTesterStorageObligation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ht.host.managedLockStorageObligation(so.id())
+	err = ht.host.addStorageObligation(so)
+	ht.host.managedUnlockStorageObligation(so.id())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ds := ht.host.DecommissionStatus(); ds.Decommissioning || ds.Complete {
+		t.Fatal("host should not be decommissioning:", ds)
+	}
+	if err := ht.host.Decommission(); err != nil {
+		t.Fatal(err)
+	}
+	if err := ht.host.Decommission(); err != errAlreadyDecommissioning {
+		t.Fatal("expected errAlreadyDecommissioning, got", err)
+	}
+	if ht.host.InternalSettings().AcceptingContracts || ht.host.ExternalSettings().AcceptingContracts {
+		t.Fatal("decommissioning host is still accepting contracts")
+	}
+	settings := ht.host.InternalSettings()
+	settings.AcceptingContracts = true
+	if err := ht.host.SetInternalSettings(settings); err != errDecommissioning {
+		t.Fatal("expected errDecommissioning, got", err)
+	}
+
+	ds := ht.host.DecommissionStatus()
+	if !ds.Decommissioning || ds.StartHeight != ht.host.blockHeight || ds.Complete {
+		t.Fatal("wrong decommissioning state:", ds)
+	}
+	if ds.ObligationsRemaining != 1 || ds.EstimatedCompletionHeight != so.proofDeadline() {
+		t.Fatal("wrong remaining obligations:", ds)
+	}
+
+	// Decommissioning should survive a restart.
+	if err := ht.host.Close(); err != nil {
+		t.Fatal(err)
+	}
+	ht.host, err = New(ht.cs, ht.tpool, ht.wallet, "localhost:0", filepath.Join(ht.persistDir, modules.HostDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ds := ht.host.DecommissionStatus(); !ds.Decommissioning || ds.ObligationsRemaining != 1 {
+		t.Fatal("decommissioning was not persisted:", ds)
+	}
+}
@@ -172,6 +172,35 @@ type Host struct {
 	// be locked separately.
 	lockedStorageObligations map[types.FileContractID]*siasync.TryMutex
 
+	// decommissioning indicates that the host is winding down, and will not
+	// accept contracts or renewals again. decommissionHeight is the height at
+	// which decommissioning started.
+	decommissioning    bool
+	decommissionHeight types.BlockHeight
+
+	// recentRejections holds the most recent file contract proposals that
+	// the host turned down, oldest first. They are not persisted.
+	recentRejections []modules.HostContractRejection
+
+	// precomputedProofs holds the storage proofs that were built before their
+	// submission height, as configured by settings.ProofPrecomputeBlocks.
+	precomputedProofs map[types.FileContractID]precomputedProof
+
+	// renterSessions counts the upload and download RPCs that each renter has
+	// open, keyed by the renter's public key, so that they can be limited by
+	// settings.MaxRenterUploads and settings.MaxRenterDownloads.
+	renterSessions map[string]*modules.HostRenterSessions
+
+	// rpcMetrics records how long each type of RPC takes to handle. It is
+	// not persisted.
+	rpcMetrics rpcMetrics
+
+	// market provides the prices of other hosts for automatic pricing, and
+	// priceAdjustment is the outcome of the most recent attempt to follow
+	// them. It is not persisted.
+	market          modules.HostMarket
+	priceAdjustment *modules.HostPriceAdjustment
+
 	// Utilities.
 	db         *persist.BoltDatabase
 	listener   net.Listener
@@ -230,6 +259,8 @@ func newHost(dependencies dependencies, cs modules.ConsensusSet, tpool modules.T
 		dependencies: dependencies,
 
 		lockedStorageObligations: make(map[types.FileContractID]*siasync.TryMutex),
+		precomputedProofs:        make(map[types.FileContractID]precomputedProof),
+		renterSessions:           make(map[string]*modules.HostRenterSessions),
 
 		persistDir: persistDir,
 	}
@@ -355,6 +386,11 @@ func (h *Host) SetInternalSettings(settings modules.HostInternalSettings) error
 	}
 	defer h.tg.Done()
 
+	// A decommissioning host cannot start accepting file contracts again.
+	if settings.AcceptingContracts && h.decommissioning {
+		return errDecommissioning
+	}
+
 	// The host should not be accepting file contracts if it does not have an
 	// unlock hash.
 	if settings.AcceptingContracts {
@@ -364,6 +400,13 @@ func (h *Host) SetInternalSettings(settings modules.HostInternalSettings) error
 		}
 	}
 
+	if settings.ProofPrecomputeBlocks > resubmissionTimeout {
+		return errProofPrecomputeTooEarly
+	}
+	if err := checkAutoPriceSettings(settings); err != nil {
+		return err
+	}
+
 	if settings.NetAddress != "" {
 		err := settings.NetAddress.IsValid()
 		if err != nil {
@@ -378,6 +421,12 @@ func (h *Host) SetInternalSettings(settings modules.HostInternalSettings) error
 		h.announced = false
 	}
 
+	// Follow the market right away when automatic pricing is enabled,
+	// rather than waiting for the next scheduled adjustment.
+	if settings.AutoPricing && !h.settings.AutoPricing {
+		go h.threadedAdjustPrices()
+	}
+
 	h.settings = settings
 	h.revisionNumber++
 
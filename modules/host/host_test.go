@@ -401,6 +401,36 @@ func TestSetAndGetInternalSettings(t *testing.T) {
 }
 
 /*
+// TestHostCapabilities checks that the host advertises tiered storage only
+// while it has a storage folder in the fast tier.
+func TestHostCapabilities(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	ht, err := newHostTester("TestHostCapabilities")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ht.Close()
+
+	hc := ht.host.ExternalSettings().Capabilities
+	if !hc.Has(modules.SupportedHostCapabilities) || hc.Has(modules.HostCapabilityTieredStorage) {
+		t.Fatal("wrong capabilities for a host without fast storage:", hc)
+	}
+	if err := ht.host.SetStorageFolderTier(0, modules.StorageTierFast); err != nil {
+		t.Fatal(err)
+	}
+	if hc := ht.host.ExternalSettings().Capabilities; !hc.Has(modules.HostCapabilityTieredStorage) {
+		t.Fatal("host with fast storage does not advertise tiered storage:", hc)
+	}
+	if err := ht.host.SetStorageFolderTier(0, modules.StorageTierNone); err != nil {
+		t.Fatal(err)
+	}
+	if hc := ht.host.ExternalSettings().Capabilities; hc.Has(modules.HostCapabilityTieredStorage) {
+		t.Fatal("host without fast storage advertises tiered storage:", hc)
+	}
+}
+
 // TestSetAndGetSettings checks that the functions for interacting with the
 // hosts settings object are working as expected.
 func TestSetAndGetSettings(t *testing.T) {
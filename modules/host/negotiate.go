@@ -104,6 +104,10 @@ var (
 	// settings.
 	errLongDuration = ErrorCommunication("renter proposed a file contract with a too-long duration")
 
+	// errLowContractPayout is returned if the renter proposes a file contract
+	// with a total payout below the host's MinContractPayout.
+	errLowContractPayout = ErrorCommunication("rejected because the contract payout is below the host's minimum")
+
 	// errLowTransactionFees is returned if the renter provides a transaction
 	// that the host does not feel is able to make it onto the blockchain.
 	errLowTransactionFees = ErrorCommunication("rejected for including too few transaction fees")
@@ -125,6 +129,15 @@ var (
 	// formation.
 	errMismatchedHostPayouts = ErrorCommunication("rejected because host valid and missed payouts are not the same value")
 
+	// errShortDuration is returned if the renter proposes a file contract
+	// that ends sooner than the host's MinDuration allows.
+	errShortDuration = ErrorCommunication("renter proposed a file contract with a too-short duration")
+
+	// errSmallContract is returned if the renter's funds in a proposed file
+	// contract cannot pay to store at least MinContractSize bytes for the
+	// duration of the contract.
+	errSmallContract = ErrorCommunication("rejected because the renter's funds cannot cover the host's minimum contract size")
+
 	// errSmallWindow is returned if the renter suggests a storage proof window
 	// that is too small.
 	errSmallWindow = ErrorCommunication("rejected for small window size")
@@ -182,6 +195,7 @@ func (h *Host) managedFinalizeContract(builder modules.TransactionBuilder, rente
 	blockHeight := h.blockHeight
 	hostSPK := h.publicKey
 	hostSK := h.secretKey
+	maxCollateral := h.settings.MaxCollateral
 	h.mu.RUnlock()
 	contractTxn := fullTxnSet[len(fullTxnSet)-1]
 	fc := contractTxn.FileContracts[0]
@@ -223,6 +237,7 @@ func (h *Host) managedFinalizeContract(builder modules.TransactionBuilder, rente
 		LockedCollateral:        hostCollateral,
 		PotentialStorageRevenue: hostInitialRevenue,
 		RiskedCollateral:        hostInitialRisk,
+		MaxCollateral:           maxCollateral,
 
 		OriginTransactionSet:   fullTxnSet,
 		RevisionTransactionSet: []types.Transaction{revisionTransaction},
@@ -214,20 +214,27 @@ func (h *Host) managedRPCDownload(conn net.Conn) error {
 	// Perform the file contract revision exchange, giving the renter the most
 	// recent file contract revision and getting the storage obligation that
 	// will be used to pay for the data.
-	_, so, err := h.managedRPCRecentRevision(conn)
+	_, so, err := h.managedRPCRecentRevision(conn, modules.RPCDownload)
 	if err != nil {
+		h.rpcMetrics.record(modules.RPCDownload, time.Since(startTime), true)
 		return extendErr("failed RPCRecentRevision during RPCDownload: ", err)
 	}
-	// The storage obligation is returned with a lock on it. Defer a call to
-	// unlock the storage obligation.
+	// The storage obligation is returned with a lock on it, and the renter's
+	// download session has been started. Defer calls to unlock the storage
+	// obligation and end the session.
 	defer func() {
 		h.managedUnlockStorageObligation(so.id())
 	}()
+	defer h.managedEndRenterSession(so.renterKey(), modules.RPCDownload)
 
 	// Perform a loop that will allow downloads to happen until the maximum
 	// time for a single connection has been reached.
 	for time.Now().Before(startTime.Add(iteratedConnectionTime)) {
+		// Each iteration is timed as a download of its own, and the renter
+		// ending the session normally is not a failure.
+		iterationStart := time.Now()
 		err := h.managedDownloadIteration(conn, &so)
+		h.rpcMetrics.record(modules.RPCDownload, time.Since(iterationStart), err != nil && err != modules.ErrStopResponse)
 		if err == modules.ErrStopResponse {
 			// The renter has indicated that it has finished downloading the
 			// data, therefore there is no error. Return nil.
@@ -60,6 +60,39 @@ func (h *Host) managedAddCollateral(settings modules.HostInternalSettings, txnSe
 	return builder, newParents, newInputs, newOutputs, nil
 }
 
+// managedRecordRejection records that the file contract at the end of txnSet
+// was rejected for the provided reason.
+func (h *Host) managedRecordRejection(txnSet []types.Transaction, reason error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	rejection := modules.HostContractRejection{
+		Timestamp: time.Now(),
+		Reason:    reason.Error(),
+	}
+	if len(txnSet) > 0 && len(txnSet[len(txnSet)-1].FileContracts) > 0 {
+		fc := txnSet[len(txnSet)-1].FileContracts[0]
+		if fc.WindowStart > h.blockHeight {
+			rejection.Duration = fc.WindowStart - h.blockHeight
+		}
+		rejection.Payout = fc.Payout
+	}
+	h.recentRejections = append(h.recentRejections, rejection)
+	if len(h.recentRejections) > maxRecentRejections {
+		h.recentRejections = h.recentRejections[len(h.recentRejections)-maxRecentRejections:]
+	}
+}
+
+// ContractRejections returns the most recent file contract proposals that were
+// rejected by the host during contract formation, oldest first.
+func (h *Host) ContractRejections() []modules.HostContractRejection {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	rejections := make([]modules.HostContractRejection, len(h.recentRejections))
+	copy(rejections, h.recentRejections)
+	return rejections
+}
+
 // managedRPCFormContract accepts a file contract from a renter, checks the
 // file contract for compliance with the host settings, and then commits to the
 // file contract, creating a storage obligation and submitting the contract to
@@ -112,6 +145,7 @@ func (h *Host) managedRPCFormContract(conn net.Conn) error {
 	if err != nil {
 		// The incoming file contract is not acceptable to the host, indicate
 		// why to the renter.
+		h.managedRecordRejection(txnSet, err)
 		modules.WriteNegotiationRejection(conn, err) // Error ignored to preserve type in extendErr
 		return extendErr("contract verification failed: ", err)
 	}
@@ -239,6 +273,15 @@ func (h *Host) managedVerifyNewContract(txnSet []types.Transaction, renterPK cry
 	if fc.WindowStart > blockHeight+settings.MaxDuration {
 		return errLongDuration
 	}
+	// WindowStart must be at least settings.MinDuration blocks into the
+	// future.
+	if fc.WindowStart < blockHeight+settings.MinDuration {
+		return errShortDuration
+	}
+	// The total payout of the contract must meet the host's minimum.
+	if fc.Payout.Cmp(settings.MinContractPayout) < 0 {
+		return errLowContractPayout
+	}
 
 	// ValidProofOutputs shoud have 2 outputs (renter + host) and missed
 	// outputs should have 3 (renter + host + void)
@@ -263,6 +306,14 @@ func (h *Host) managedVerifyNewContract(txnSet []types.Transaction, renterPK cry
 	if fc.ValidProofOutputs[1].Value.Cmp(settings.MinContractPrice) < 0 {
 		return errLowHostValidOutput
 	}
+	// Check that the renter has enough funds in the contract to store at
+	// least settings.MinContractSize bytes for the duration of the contract.
+	if settings.MinContractSize > 0 && !settings.MinStoragePrice.IsZero() {
+		storageCost := settings.MinStoragePrice.Mul64(settings.MinContractSize).Mul64(uint64(fc.WindowEnd - blockHeight))
+		if fc.ValidProofOutputs[0].Value.Cmp(storageCost) < 0 {
+			return errSmallContract
+		}
+	}
 	// Check that the collateral does not exceed the maximum amount of
 	// collateral allowed.
 	expectedCollateral := contractCollateral(settings, fc)
@@ -0,0 +1,93 @@
+package host
+
+import (
+	"testing"
+
+	"github.com/NebulousLabs/Sia/crypto"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// TestContractAcceptanceRules checks that the host rejects file contracts
+// that violate its minimum duration, payout, and size rules, and that
+// rejections are recorded.
+func TestContractAcceptanceRules(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	ht, err := newHostTester("TestContractAcceptanceRules")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ht.Close()
+
+	ht.host.mu.Lock()
+	err = ht.host.checkUnlockHash()
+	blockHeight := ht.host.blockHeight
+	unlockHash := ht.host.unlockHash
+	ht.host.mu.Unlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	settings := ht.host.InternalSettings()
+	renterPayout := types.SiacoinPrecision.Mul64(10)
+	hostPayout := settings.MinContractPrice
+	fc := types.FileContract{
+		WindowStart: blockHeight + revisionSubmissionBuffer + 10,
+		WindowEnd:   blockHeight + revisionSubmissionBuffer + 10 + settings.WindowSize,
+		Payout:      renterPayout.Add(hostPayout),
+		ValidProofOutputs: []types.SiacoinOutput{
+			{Value: renterPayout},
+			{Value: hostPayout, UnlockHash: unlockHash},
+		},
+		MissedProofOutputs: []types.SiacoinOutput{
+			{Value: renterPayout},
+			{Value: hostPayout, UnlockHash: unlockHash},
+			{},
+		},
+	}
+	txnSet := []types.Transaction{{FileContracts: []types.FileContract{fc}}}
+
+	// Contracts that start sooner than MinDuration should be rejected.
+	settings.MinDuration = revisionSubmissionBuffer + 20
+	if err := ht.host.SetInternalSettings(settings); err != nil {
+		t.Fatal(err)
+	}
+	if err := ht.host.managedVerifyNewContract(txnSet, crypto.PublicKey{}); err != errShortDuration {
+		t.Fatal("expected errShortDuration, got", err)
+	}
+	settings.MinDuration = 0
+
+	// Contracts with a payout below MinContractPayout should be rejected.
+	settings.MinContractPayout = fc.Payout.Add(types.NewCurrency64(1))
+	if err := ht.host.SetInternalSettings(settings); err != nil {
+		t.Fatal(err)
+	}
+	if err := ht.host.managedVerifyNewContract(txnSet, crypto.PublicKey{}); err != errLowContractPayout {
+		t.Fatal("expected errLowContractPayout, got", err)
+	}
+	settings.MinContractPayout = types.ZeroCurrency
+
+	// Contracts that cannot pay for MinContractSize bytes of storage should
+	// be rejected.
+	settings.MinStoragePrice = types.SiacoinPrecision
+	settings.MinContractSize = 1 << 20
+	if err := ht.host.SetInternalSettings(settings); err != nil {
+		t.Fatal(err)
+	}
+	if err := ht.host.managedVerifyNewContract(txnSet, crypto.PublicKey{}); err != errSmallContract {
+		t.Fatal("expected errSmallContract, got", err)
+	}
+
+	// Rejections should be recorded, and only the most recent ones kept.
+	for i := 0; i < maxRecentRejections+5; i++ {
+		ht.host.managedRecordRejection(txnSet, errSmallContract)
+	}
+	rejections := ht.host.ContractRejections()
+	if len(rejections) != maxRecentRejections {
+		t.Fatal("expected", maxRecentRejections, "rejections, got", len(rejections))
+	}
+	if rejections[0].Reason != errSmallContract.Error() || rejections[0].Payout.Cmp(fc.Payout) != 0 || rejections[0].Duration != fc.WindowStart-blockHeight {
+		t.Error("rejection was recorded incorrectly:", rejections[0])
+	}
+}
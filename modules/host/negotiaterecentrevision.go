@@ -86,8 +86,10 @@ func (h *Host) managedVerifyChallengeResponse(fcid types.FileContractID, challen
 // revision, including signatures, to the renter, for the file contract with
 // the id given by the renter.
 //
-// The storage obligation is returned under a storage obligation lock.
-func (h *Host) managedRPCRecentRevision(conn net.Conn) (types.FileContractID, storageObligation, error) {
+// The storage obligation is returned under a storage obligation lock. If rpc is
+// RPCReviseContract or RPCDownload, a session of that type is also started for
+// the renter, and must be ended by the caller with managedEndRenterSession.
+func (h *Host) managedRPCRecentRevision(conn net.Conn, rpc types.Specifier) (types.FileContractID, storageObligation, error) {
 	// Set the negotiation deadline.
 	conn.SetDeadline(time.Now().Add(modules.NegotiateRecentRevisionTime))
 
@@ -130,6 +132,19 @@ func (h *Host) managedRPCRecentRevision(conn net.Conn) (types.FileContractID, st
 		}
 	}()
 
+	// Refuse the RPC if the renter already has as many open as the host
+	// allows.
+	err = h.managedStartRenterSession(so.renterKey(), rpc)
+	if err != nil {
+		modules.WriteNegotiationRejection(conn, err)
+		return types.FileContractID{}, storageObligation{}, extendErr("renter is busy: ", err)
+	}
+	defer func() {
+		if err != nil {
+			h.managedEndRenterSession(so.renterKey(), rpc)
+		}
+	}()
+
 	// Send the file contract revision and the corresponding signatures to the
 	// renter.
 	err = modules.WriteNegotiationAcceptance(conn)
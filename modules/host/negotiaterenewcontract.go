@@ -77,7 +77,7 @@ func (h *Host) managedAddRenewCollateral(so storageObligation, settings modules.
 func (h *Host) managedRPCRenewContract(conn net.Conn) error {
 	// Perform the recent revision protocol to get the file contract being
 	// revised.
-	_, so, err := h.managedRPCRecentRevision(conn)
+	_, so, err := h.managedRPCRecentRevision(conn, modules.RPCRenewContract)
 	if err != nil {
 		return extendErr("RPCRecentRevision failed: ", err)
 	}
@@ -93,6 +93,17 @@ func (h *Host) managedRPCRenewContract(conn net.Conn) error {
 		return extendErr("RPCSettings failed: ", err)
 	}
 
+	// A decommissioning host does not extend its obligations. As with
+	// contract formation, the renter can tell from the host settings that the
+	// connection is going to be closed.
+	h.mu.RLock()
+	decommissioning := h.decommissioning
+	h.mu.RUnlock()
+	if decommissioning {
+		h.log.Debugln("Turning down renewal because the host is decommissioning.")
+		return nil
+	}
+
 	// Set the renewal deadline.
 	conn.SetDeadline(time.Now().Add(modules.NegotiateRenewContractTime))
 
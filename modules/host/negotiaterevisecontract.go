@@ -133,6 +133,11 @@ func (h *Host) managedRevisionIteration(conn net.Conn, so *storageObligation, fi
 			}
 		}
 		newRevenue := storageRevenue.Add(bandwidthRevenue)
+		maxCollateral := so.MaxCollateral
+		if maxCollateral.IsZero() {
+			maxCollateral = settings.MaxCollateral
+		}
+		newCollateral = revisionCollateral(*so, newCollateral, maxCollateral)
 		return extendErr("unable to verify revision: ", verifyRevision(*so, revision, blockHeight, newRevenue, newCollateral))
 	}()
 	if err != nil {
@@ -195,21 +200,28 @@ func (h *Host) managedRPCReviseContract(conn net.Conn) error {
 	// Perform the file contract revision exchange, giving the renter the most
 	// recent file contract revision and getting the storage obligation that
 	// will be used to pay for the data.
-	_, so, err := h.managedRPCRecentRevision(conn)
+	_, so, err := h.managedRPCRecentRevision(conn, modules.RPCReviseContract)
 	if err != nil {
+		h.rpcMetrics.record(modules.RPCReviseContract, time.Since(startTime), true)
 		return extendErr("RPCRecentRevision failed: ", err)
 	}
-	// The storage obligation is received with a lock on it. Defer a call to
-	// unlock the storage obligation.
+	// The storage obligation is received with a lock on it, and the renter's
+	// upload session has been started. Defer calls to unlock the storage
+	// obligation and end the session.
 	defer func() {
 		h.managedUnlockStorageObligation(so.id())
 	}()
+	defer h.managedEndRenterSession(so.renterKey(), modules.RPCReviseContract)
 
 	// Begin the revision loop. The host will process revisions until a
 	// timeout is reached, or until the renter sends a StopResponse.
 	for timeoutReached := false; !timeoutReached; {
 		timeoutReached = time.Since(startTime) > iteratedConnectionTime
+		// Each iteration is timed as an upload of its own, and the renter
+		// ending the session normally is not a failure.
+		iterationStart := time.Now()
 		err := h.managedRevisionIteration(conn, &so, timeoutReached)
+		h.rpcMetrics.record(modules.RPCReviseContract, time.Since(iterationStart), err != nil && err != modules.ErrStopResponse)
 		if err == modules.ErrStopResponse {
 			return nil
 		} else if err != nil {
@@ -219,6 +231,27 @@ func (h *Host) managedRPCReviseContract(conn net.Conn) error {
 	return nil
 }
 
+// revisionCollateral returns the collateral that the host puts up for a
+// revision. The requested collateral is capped so that the collateral moved out
+// of the host's missed output over the life of the contract does not exceed
+// maxCollateral, the host's MaxCollateral when the contract was formed. The
+// renter performs the same calculation when building the revision.
+func revisionCollateral(so storageObligation, requested, maxCollateral types.Currency) types.Currency {
+	originMissed := so.OriginTransactionSet[len(so.OriginTransactionSet)-1].FileContracts[0].MissedProofOutputs[1].Value
+	currentMissed := so.RevisionTransactionSet[len(so.RevisionTransactionSet)-1].FileContractRevisions[0].NewMissedProofOutputs[1].Value
+	var committed types.Currency
+	if originMissed.Cmp(currentMissed) > 0 {
+		committed = originMissed.Sub(currentMissed)
+	}
+	if committed.Cmp(maxCollateral) >= 0 {
+		return types.ZeroCurrency
+	}
+	if remaining := maxCollateral.Sub(committed); requested.Cmp(remaining) > 0 {
+		return remaining
+	}
+	return requested
+}
+
 // verifyRevision checks that the revision pays the host correctly, and that
 // the revision does not attempt any malicious or unexpected changes.
 func verifyRevision(so storageObligation, revision types.FileContractRevision, blockHeight types.BlockHeight, newRevenue, newCollateral types.Currency) error {
@@ -0,0 +1,40 @@
+package host
+
+import (
+	"testing"
+
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// TestRevisionCollateral checks that the collateral put up for a revision is
+// capped by the collateral already moved out of the host's missed output.
+func TestRevisionCollateral(t *testing.T) {
+	so := storageObligation{
+		OriginTransactionSet: []types.Transaction{{
+			FileContracts: []types.FileContract{{
+				MissedProofOutputs: []types.SiacoinOutput{{}, {Value: types.NewCurrency64(1000)}, {}},
+			}},
+		}},
+		RevisionTransactionSet: []types.Transaction{{
+			FileContractRevisions: []types.FileContractRevision{{
+				NewMissedProofOutputs: []types.SiacoinOutput{{}, {Value: types.NewCurrency64(700)}, {}},
+			}},
+		}},
+	}
+
+	// 300 has already been committed.
+	tests := []struct {
+		requested, max, expected uint64
+	}{
+		{50, 1000, 50},
+		{50, 320, 20},
+		{50, 300, 0},
+		{50, 100, 0},
+	}
+	for _, test := range tests {
+		c := revisionCollateral(so, types.NewCurrency64(test.requested), types.NewCurrency64(test.max))
+		if c.Cmp(types.NewCurrency64(test.expected)) != 0 {
+			t.Errorf("requested %v with max %v: got %v, expected %v", test.requested, test.max, c, test.expected)
+		}
+	}
+}
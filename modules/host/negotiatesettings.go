@@ -23,6 +23,19 @@ func (h *Host) capacity() (total, remaining uint64) {
 	return total, remaining
 }
 
+// capabilities returns the capabilities that the host advertises, adding the
+// ones that depend on its configuration to those of every host.
+func (h *Host) capabilities() modules.HostCapabilities {
+	hc := modules.SupportedHostCapabilities
+	for _, sf := range h.StorageFolders() {
+		if sf.Tier == modules.StorageTierFast {
+			hc |= modules.HostCapabilityTieredStorage
+			break
+		}
+	}
+	return hc
+}
+
 // externalSettings compiles and returns the external settings for the host.
 func (h *Host) externalSettings() modules.HostExternalSettings {
 	totalStorage, remainingStorage := h.capacity()
@@ -54,6 +67,7 @@ func (h *Host) externalSettings() modules.HostExternalSettings {
 
 		RevisionNumber: h.revisionNumber,
 		Version:        build.Version,
+		Capabilities:   h.capabilities(),
 	}
 }
 
@@ -130,6 +130,7 @@ func (h *Host) threadedHandleConn(conn net.Conn) {
 		return
 	}
 
+	start := time.Now()
 	switch id {
 	case modules.RPCDownload:
 		atomic.AddUint64(&h.atomicDownloadCalls, 1)
@@ -146,7 +147,7 @@ func (h *Host) threadedHandleConn(conn net.Conn) {
 	case modules.RPCRecentRevision:
 		atomic.AddUint64(&h.atomicRecentRevisionCalls, 1)
 		var so storageObligation
-		_, so, err = h.managedRPCRecentRevision(conn)
+		_, so, err = h.managedRPCRecentRevision(conn, modules.RPCRecentRevision)
 		if err != nil {
 			defer func() {
 				h.managedUnlockStorageObligation(so.id())
@@ -161,6 +162,12 @@ func (h *Host) threadedHandleConn(conn net.Conn) {
 		h.log.Debugf("WARN: incoming conn %v requested unknown RPC \"%v\"", conn.RemoteAddr(), id)
 		atomic.AddUint64(&h.atomicUnrecognizedCalls, 1)
 	}
+	for _, rpc := range timedRPCs {
+		if rpc.id == id && !rpc.iterated {
+			h.rpcMetrics.record(id, time.Since(start), err != nil)
+			break
+		}
+	}
 	if err != nil {
 		atomic.AddUint64(&h.atomicErroredCalls, 1)
 		err = extendErr("error with "+conn.RemoteAddr().String()+": ", err)
@@ -38,6 +38,10 @@ type persistence struct {
 	SecretKey        crypto.SecretKey             `json:"secretkey"`
 	Settings         modules.HostInternalSettings `json:"settings"`
 	UnlockHash       types.UnlockHash             `json:"unlockhash"`
+
+	// Decommissioning.
+	Decommissioning    bool              `json:"decommissioning"`
+	DecommissionHeight types.BlockHeight `json:"decommissionheight"`
 }
 
 // persistData returns the data in the Host that will be saved to disk.
@@ -66,6 +70,10 @@ func (h *Host) persistData() persistence {
 		SecretKey:        h.secretKey,
 		Settings:         h.settings,
 		UnlockHash:       h.unlockHash,
+
+		// Decommissioning.
+		Decommissioning:    h.decommissioning,
+		DecommissionHeight: h.decommissionHeight,
 	}
 }
 
@@ -183,6 +191,10 @@ func (h *Host) load() error {
 	}
 	h.unlockHash = p.UnlockHash
 
+	// Copy over decommissioning.
+	h.decommissioning = p.Decommissioning
+	h.decommissionHeight = p.DecommissionHeight
+
 	// Get the number of storage obligations by looking at the storage
 	// obligation database.
 	err = h.db.View(func(tx *bolt.Tx) error {
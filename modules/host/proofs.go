@@ -0,0 +1,128 @@
+package host
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/NebulousLabs/Sia/crypto"
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+
+	"github.com/NebulousLabs/bolt"
+)
+
+// errProofPrecomputeTooEarly is returned when the host is asked to precompute
+// storage proofs before the segment being proven can be known. The segment is
+// chosen by the block preceding the proof window, and the host waits
+// resubmissionTimeout blocks into the window before submitting the proof.
+var errProofPrecomputeTooEarly = fmt.Errorf("storage proofs can be precomputed at most %v blocks before they are submitted", resubmissionTimeout)
+
+// A precomputedProof is a storage proof that was built before it was due. A
+// reorg can change the block that selects the proven segment, so the proof is
+// only used if it was built for the segment selected when it is submitted.
+type precomputedProof struct {
+	segmentIndex uint64
+	proof        types.StorageProof
+}
+
+// buildStorageProof builds the storage proof of the storage obligation for the
+// segment at segmentIndex.
+func (h *Host) buildStorageProof(so storageObligation, segmentIndex uint64) (types.StorageProof, error) {
+	// Pull the sector containing the segment into memory.
+	sectorIndex := segmentIndex / (modules.SectorSize / crypto.SegmentSize)
+	sectorRoot := so.SectorRoots[sectorIndex]
+	sectorBytes, err := h.ReadSector(sectorRoot)
+	if err != nil {
+		return types.StorageProof{}, err
+	}
+
+	// Build the storage proof for just the sector.
+	sectorSegment := segmentIndex % (modules.SectorSize / crypto.SegmentSize)
+	base, cachedHashSet := crypto.MerkleProof(sectorBytes, sectorSegment)
+
+	// Using the sector, build a cached root.
+	log2SectorSize := uint64(0)
+	for 1<<log2SectorSize < (modules.SectorSize / crypto.SegmentSize) {
+		log2SectorSize++
+	}
+	ct := crypto.NewCachedTree(log2SectorSize)
+	ct.SetIndex(segmentIndex)
+	for _, root := range so.SectorRoots {
+		ct.Push(root)
+	}
+	hashSet := ct.Prove(base, cachedHashSet)
+	sp := types.StorageProof{
+		ParentID: so.id(),
+		HashSet:  hashSet,
+	}
+	copy(sp.Segment[:], base)
+	return sp, nil
+}
+
+// managedPrecomputeProof builds the storage proof of the storage obligation
+// and caches it until the proof is submitted.
+func (h *Host) managedPrecomputeProof(so storageObligation) {
+	segmentIndex, err := h.cs.StorageProofSegment(so.id())
+	if err != nil {
+		h.log.Debugln("Host got an error when fetching a storage proof segment to precompute:", err)
+		return
+	}
+	sp, err := h.buildStorageProof(so, segmentIndex)
+	if err != nil {
+		h.log.Debugln("Host could not precompute a storage proof:", err)
+		return
+	}
+	h.mu.Lock()
+	h.precomputedProofs[so.id()] = precomputedProof{
+		segmentIndex: segmentIndex,
+		proof:        sp,
+	}
+	h.mu.Unlock()
+}
+
+// managedStorageProof returns the storage proof of the storage obligation,
+// using the precomputed proof if it proves the currently selected segment.
+// The precomputed proof is removed from the cache either way.
+func (h *Host) managedStorageProof(so storageObligation) (types.StorageProof, error) {
+	segmentIndex, err := h.cs.StorageProofSegment(so.id())
+	if err != nil {
+		return types.StorageProof{}, err
+	}
+	h.mu.Lock()
+	pp, ok := h.precomputedProofs[so.id()]
+	delete(h.precomputedProofs, so.id())
+	h.mu.Unlock()
+	if ok && pp.segmentIndex == segmentIndex {
+		return pp.proof, nil
+	}
+	return h.buildStorageProof(so, segmentIndex)
+}
+
+// ProofStatus returns the number of storage proofs that have been precomputed,
+// and the number of storage obligations whose proof window is open and whose
+// proof has not been confirmed.
+func (h *Host) ProofStatus() modules.HostProofStatus {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	ps := modules.HostProofStatus{
+		Precomputed: uint64(len(h.precomputedProofs)),
+	}
+	err := h.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketStorageObligations).ForEach(func(_, soBytes []byte) error {
+			var so storageObligation
+			err := json.Unmarshal(soBytes, &so)
+			if err != nil {
+				return err
+			}
+			if so.ObligationStatus == obligationUnresolved && !so.ProofConfirmed && h.blockHeight >= so.expiration() {
+				ps.Pending++
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		h.log.Println("Unable to read storage obligations:", err)
+	}
+	return ps
+}
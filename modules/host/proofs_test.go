@@ -0,0 +1,123 @@
+package host
+
+import (
+	"testing"
+
+	"github.com/NebulousLabs/Sia/crypto"
+	"github.com/NebulousLabs/Sia/types"
+
+	"github.com/NebulousLabs/bolt"
+)
+
+// TestPrecomputeStorageProof checks that the host builds a storage proof
+// ahead of its submission height when configured to, and that the
+// precomputed proof is submitted and confirmed.
+func TestPrecomputeStorageProof(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	ht, err := newHostTester("TestPrecomputeStorageProof")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ht.Close()
+
+	settings := ht.host.InternalSettings()
+	settings.ProofPrecomputeBlocks = resubmissionTimeout + 1
+	if err := ht.host.SetInternalSettings(settings); err != errProofPrecomputeTooEarly {
+		t.Fatal("expected errProofPrecomputeTooEarly, got", err)
+	}
+	settings.ProofPrecomputeBlocks = resubmissionTimeout
+	if err := ht.host.SetInternalSettings(settings); err != nil {
+		t.Fatal(err)
+	}
+
+	// Add a storage obligation holding a single sector.
+	so, err := ht.newTesterStorageObligation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ht.host.managedLockStorageObligation(so.id())
+	err = ht.host.addStorageObligation(so)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ht.host.managedUnlockStorageObligation(so.id())
+	sectorRoot, sectorData, err := randSector()
+	if err != nil {
+		t.Fatal(err)
+	}
+	so.SectorRoots = []crypto.Hash{sectorRoot}
+	validPayouts, missedPayouts := so.payouts()
+	revisionSet := []types.Transaction{{
+		FileContractRevisions: []types.FileContractRevision{{
+			ParentID:          so.id(),
+			UnlockConditions:  types.UnlockConditions{},
+			NewRevisionNumber: 1,
+
+			NewFileSize:           uint64(len(sectorData)),
+			NewFileMerkleRoot:     sectorRoot,
+			NewWindowStart:        so.expiration(),
+			NewWindowEnd:          so.proofDeadline(),
+			NewValidProofOutputs:  validPayouts,
+			NewMissedProofOutputs: missedPayouts,
+			NewUnlockHash:         types.UnlockConditions{}.UnlockHash(),
+		}},
+	}}
+	ht.host.managedLockStorageObligation(so.id())
+	err = ht.host.modifyStorageObligation(so, nil, []crypto.Hash{sectorRoot}, [][]byte{sectorData})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ht.host.managedUnlockStorageObligation(so.id())
+	err = ht.tpool.AcceptTransactionSet(revisionSet)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Mine until the proof window opens. The proof should be built, but not
+	// yet submitted.
+	for ht.host.blockHeight < so.expiration() {
+		_, err := ht.miner.AddBlock()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = ht.host.tg.Flush()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ps := ht.host.ProofStatus(); ps.Precomputed != 1 || ps.Pending != 1 {
+		t.Fatal("proof was not precomputed:", ps)
+	}
+
+	// Mine until the host submits the storage proof, and then confirm it.
+	for ht.host.blockHeight <= so.expiration()+resubmissionTimeout {
+		_, err := ht.miner.AddBlock()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = ht.host.tg.Flush()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = ht.miner.AddBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ps := ht.host.ProofStatus(); ps.Precomputed != 0 || ps.Pending != 0 {
+		t.Fatal("precomputed proof was not submitted:", ps)
+	}
+	err = ht.host.db.View(func(tx *bolt.Tx) error {
+		so, err = getStorageObligation(tx, so.id())
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !so.ProofConfirmed {
+		t.Fatal("precomputed storage proof was not confirmed")
+	}
+}
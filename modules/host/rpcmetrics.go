@@ -0,0 +1,150 @@
+package host
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+var (
+	// rpcLatencyBounds are the upper bounds, in milliseconds, of the buckets
+	// of the RPC latency histograms.
+	rpcLatencyBounds = [...]float64{1, 2, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000, 30000, 60000, 300000}
+
+	// timedRPCs are the RPCs whose latency is recorded, along with the names
+	// they are reported under, in the order they are reported. Iterated RPCs
+	// keep the connection open for many requests, so each request is timed by
+	// the RPC's handler rather than timing the whole connection.
+	timedRPCs = []struct {
+		id       types.Specifier
+		name     string
+		iterated bool
+	}{
+		{modules.RPCSettings, "settings", false},
+		{modules.RPCFormContract, "formcontract", false},
+		{modules.RPCRenewContract, "renewcontract", false},
+		{modules.RPCRecentRevision, "revision", false},
+		{modules.RPCReviseContract, "upload", true},
+		{modules.RPCDownload, "download", true},
+	}
+)
+
+// A latencyHistogram counts the durations of one type of RPC. The last bucket
+// counts the durations beyond the largest of rpcLatencyBounds.
+type latencyHistogram struct {
+	calls   uint64
+	errors  uint64
+	total   time.Duration
+	max     time.Duration
+	buckets [len(rpcLatencyBounds) + 1]uint64
+}
+
+// rpcMetrics holds a latency histogram for each type of RPC. It has its own
+// lock so that recording a duration does not contend with the host's lock.
+type rpcMetrics struct {
+	histograms map[types.Specifier]*latencyHistogram
+	mu         sync.Mutex
+}
+
+// durationMs converts a duration to milliseconds.
+func durationMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// record adds the duration of an RPC to the histogram.
+func (lh *latencyHistogram) record(d time.Duration, failed bool) {
+	lh.calls++
+	if failed {
+		lh.errors++
+	}
+	lh.total += d
+	if d > lh.max {
+		lh.max = d
+	}
+	ms := durationMs(d)
+	i := 0
+	for i < len(rpcLatencyBounds) && ms > rpcLatencyBounds[i] {
+		i++
+	}
+	lh.buckets[i]++
+}
+
+// percentile estimates the duration, in milliseconds, that the fraction q of
+// the RPCs completed within. The estimate is the upper bound of the bucket
+// holding that RPC, capped at the longest duration recorded.
+func (lh *latencyHistogram) percentile(q float64) float64 {
+	if lh.calls == 0 {
+		return 0
+	}
+	rank := uint64(math.Ceil(q * float64(lh.calls)))
+	maxMs := durationMs(lh.max)
+	var seen uint64
+	for i, n := range lh.buckets {
+		seen += n
+		if seen >= rank && i < len(rpcLatencyBounds) {
+			return math.Min(rpcLatencyBounds[i], maxMs)
+		}
+	}
+	return maxMs
+}
+
+// summary reports the histogram under the given RPC name.
+func (lh *latencyHistogram) summary(name string) modules.HostRPCLatency {
+	l := modules.HostRPCLatency{
+		RPC:      name,
+		Calls:    lh.calls,
+		Errors:   lh.errors,
+		P50Ms:    lh.percentile(0.50),
+		P90Ms:    lh.percentile(0.90),
+		P99Ms:    lh.percentile(0.99),
+		MaxMs:    durationMs(lh.max),
+		Buckets:  make([]modules.HostLatencyBucket, len(rpcLatencyBounds)),
+		Overflow: lh.buckets[len(rpcLatencyBounds)],
+	}
+	if lh.calls > 0 {
+		l.AverageMs = durationMs(lh.total) / float64(lh.calls)
+	}
+	for i, bound := range rpcLatencyBounds {
+		l.Buckets[i] = modules.HostLatencyBucket{
+			UpperBoundMs: bound,
+			Count:        lh.buckets[i],
+		}
+	}
+	return l
+}
+
+// record adds the duration of an RPC to the histogram of its type.
+func (rm *rpcMetrics) record(rpc types.Specifier, d time.Duration, failed bool) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	if rm.histograms == nil {
+		rm.histograms = make(map[types.Specifier]*latencyHistogram)
+	}
+	lh, exists := rm.histograms[rpc]
+	if !exists {
+		lh = new(latencyHistogram)
+		rm.histograms[rpc] = lh
+	}
+	lh.record(d, failed)
+}
+
+// RPCLatencies returns the latency histogram of each type of RPC that the
+// host has handled since it started. Every type is listed, including those
+// that have not been called.
+func (h *Host) RPCLatencies() []modules.HostRPCLatency {
+	h.rpcMetrics.mu.Lock()
+	defer h.rpcMetrics.mu.Unlock()
+
+	latencies := make([]modules.HostRPCLatency, 0, len(timedRPCs))
+	for _, rpc := range timedRPCs {
+		lh, exists := h.rpcMetrics.histograms[rpc.id]
+		if !exists {
+			lh = new(latencyHistogram)
+		}
+		latencies = append(latencies, lh.summary(rpc.name))
+	}
+	return latencies
+}
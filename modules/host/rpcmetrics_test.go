@@ -0,0 +1,71 @@
+package host
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NebulousLabs/Sia/modules"
+)
+
+// TestLatencyHistogram checks the bucketing and percentile estimates of the
+// RPC latency histograms.
+func TestLatencyHistogram(t *testing.T) {
+	var lh latencyHistogram
+	if l := lh.summary("download"); l.Calls != 0 || l.P50Ms != 0 || l.MaxMs != 0 || len(l.Buckets) != len(rpcLatencyBounds) {
+		t.Fatalf("unexpected summary of an empty histogram: %+v", l)
+	}
+
+	// 90 fast calls, 9 slower calls, and one failed call beyond the largest
+	// bucket.
+	for i := 0; i < 90; i++ {
+		lh.record(3*time.Millisecond, false)
+	}
+	for i := 0; i < 9; i++ {
+		lh.record(400*time.Millisecond, false)
+	}
+	lh.record(10*time.Minute, true)
+
+	l := lh.summary("download")
+	if l.Calls != 100 || l.Errors != 1 || l.Overflow != 1 {
+		t.Fatalf("wrong counts: %+v", l)
+	}
+	if l.Buckets[2].UpperBoundMs != 5 || l.Buckets[2].Count != 90 || l.Buckets[8].UpperBoundMs != 500 || l.Buckets[8].Count != 9 {
+		t.Fatalf("wrong buckets: %+v", l.Buckets)
+	}
+	if l.P50Ms != 5 || l.P90Ms != 5 || l.P99Ms != 500 || l.MaxMs != 600000 {
+		t.Fatalf("wrong percentiles: %+v", l)
+	}
+	if l.AverageMs != (90*3+9*400+600000)/100.0 {
+		t.Fatal("wrong average:", l.AverageMs)
+	}
+
+	// Percentiles are capped at the longest call.
+	var short latencyHistogram
+	short.record(1500*time.Microsecond, false)
+	if p := short.percentile(0.5); p != 1.5 {
+		t.Fatal("expected the percentile to be capped at 1.5ms, got", p)
+	}
+}
+
+// TestRPCLatencies checks that the host reports a histogram for every type of
+// RPC, in a fixed order.
+func TestRPCLatencies(t *testing.T) {
+	var h Host
+	h.rpcMetrics.record(modules.RPCDownload, time.Millisecond, false)
+	h.rpcMetrics.record(modules.RPCDownload, time.Millisecond, true)
+
+	latencies := h.RPCLatencies()
+	if len(latencies) != len(timedRPCs) {
+		t.Fatal("expected a histogram for each RPC, got", len(latencies))
+	}
+	for i, l := range latencies {
+		if l.RPC != timedRPCs[i].name {
+			t.Fatal("RPCs reported in the wrong order:", l.RPC)
+		}
+		if l.RPC == "download" && (l.Calls != 2 || l.Errors != 1) {
+			t.Fatalf("wrong download histogram: %+v", l)
+		} else if l.RPC != "download" && l.Calls != 0 {
+			t.Fatalf("unexpected calls recorded for %v", l.RPC)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package host
+
+import (
+	"encoding/json"
+
+	"github.com/NebulousLabs/Sia/crypto"
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+
+	"github.com/NebulousLabs/bolt"
+)
+
+// forEachUnresolvedObligation calls fn on every unresolved storage obligation.
+// Sectors of resolved obligations have already been removed from the storage
+// manager.
+func (h *Host) forEachUnresolvedObligation(fn func(so storageObligation)) error {
+	return h.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketStorageObligations).ForEach(func(_, soBytes []byte) error {
+			var so storageObligation
+			err := json.Unmarshal(soBytes, &so)
+			if err != nil {
+				return err
+			}
+			if so.ObligationStatus == obligationUnresolved {
+				fn(so)
+			}
+			return nil
+		})
+	})
+}
+
+// Sector returns the reference count and location of a sector, and the
+// unresolved storage obligations that contain it.
+func (h *Host) Sector(root crypto.Hash) (modules.HostSector, error) {
+	usage, err := h.SectorUsage(root)
+	if err != nil {
+		return modules.HostSector{}, err
+	}
+	sector := modules.HostSector{
+		Root:               root,
+		Obligations:        []types.FileContractID{},
+		StorageSectorUsage: usage,
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	err = h.forEachUnresolvedObligation(func(so storageObligation) {
+		for _, sr := range so.SectorRoots {
+			if sr == root {
+				sector.Obligations = append(sector.Obligations, so.id())
+				return
+			}
+		}
+	})
+	return sector, err
+}
+
+// OrphanedSectors returns the sectors held by the storage manager that are not
+// contained in any unresolved storage obligation. The host lock is held
+// throughout so that sectors being added by an upload are not reported.
+func (h *Host) OrphanedSectors() ([]modules.StorageSectorUsage, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	var referenced []crypto.Hash
+	err := h.forEachUnresolvedObligation(func(so storageObligation) {
+		referenced = append(referenced, so.SectorRoots...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return h.UnreferencedSectors(referenced)
+}
@@ -0,0 +1,95 @@
+package host
+
+import (
+	"encoding/hex"
+	"sort"
+
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+var (
+	// errRenterBusy is returned to a renter that tries to open more upload or
+	// download RPCs at once than the host allows a single renter.
+	errRenterBusy = ErrorCommunication("renter has too many sessions open with the host, try again later")
+)
+
+// renterKey returns the public key of the renter of the storage obligation,
+// which identifies the renter across RPCs. The storage obligation must have
+// been revised at least once, which is the case for any obligation returned
+// by managedRPCRecentRevision.
+func (so storageObligation) renterKey() string {
+	revision := so.RevisionTransactionSet[len(so.RevisionTransactionSet)-1].FileContractRevisions[0]
+	spk := revision.UnlockConditions.PublicKeys[0]
+	return spk.Algorithm.String() + ":" + hex.EncodeToString(spk.Key)
+}
+
+// managedStartRenterSession records that a renter has opened an RPC, or
+// returns errRenterBusy if the renter already has as many RPCs of that type
+// open as the host allows. Only RPCReviseContract (uploads) and RPCDownload
+// are counted; every successful call must be paired with a call to
+// managedEndRenterSession.
+func (h *Host) managedStartRenterSession(key string, rpc types.Specifier) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	rs, exists := h.renterSessions[key]
+	if !exists {
+		rs = &modules.HostRenterSessions{RenterKey: key}
+	}
+	switch rpc {
+	case modules.RPCReviseContract:
+		if h.settings.MaxRenterUploads != 0 && rs.Uploads >= h.settings.MaxRenterUploads {
+			return errRenterBusy
+		}
+		rs.Uploads++
+	case modules.RPCDownload:
+		if h.settings.MaxRenterDownloads != 0 && rs.Downloads >= h.settings.MaxRenterDownloads {
+			return errRenterBusy
+		}
+		rs.Downloads++
+	default:
+		return nil
+	}
+	h.renterSessions[key] = rs
+	return nil
+}
+
+// managedEndRenterSession records that a renter has closed an RPC that was
+// opened with managedStartRenterSession.
+func (h *Host) managedEndRenterSession(key string, rpc types.Specifier) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	rs, exists := h.renterSessions[key]
+	if !exists {
+		return
+	}
+	switch rpc {
+	case modules.RPCReviseContract:
+		rs.Uploads--
+	case modules.RPCDownload:
+		rs.Downloads--
+	}
+	if rs.Uploads == 0 && rs.Downloads == 0 {
+		delete(h.renterSessions, key)
+	}
+}
+
+// RenterSessions returns the number of upload and download RPCs that each
+// renter has open with the host, ordered by renter key.
+func (h *Host) RenterSessions() []modules.HostRenterSessions {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	var keys []string
+	for key := range h.renterSessions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	sessions := make([]modules.HostRenterSessions, 0, len(keys))
+	for _, key := range keys {
+		sessions = append(sessions, *h.renterSessions[key])
+	}
+	return sessions
+}
@@ -0,0 +1,225 @@
+package host
+
+import (
+	"encoding/hex"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/NebulousLabs/Sia/crypto"
+	"github.com/NebulousLabs/Sia/encoding"
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// TestRenterSessions checks that the host limits the number of upload and
+// download RPCs that each renter can have open, and reports them.
+func TestRenterSessions(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	ht, err := blankHostTester("TestRenterSessions")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ht.Close()
+
+	settings := ht.host.InternalSettings()
+	settings.MaxRenterUploads = 1
+	settings.MaxRenterDownloads = 2
+	if err := ht.host.SetInternalSettings(settings); err != nil {
+		t.Fatal(err)
+	}
+
+	renter1, renter2 := "ed25519:01", "ed25519:02"
+	if err := ht.host.managedStartRenterSession(renter1, modules.RPCReviseContract); err != nil {
+		t.Fatal(err)
+	}
+	if err := ht.host.managedStartRenterSession(renter1, modules.RPCReviseContract); err != errRenterBusy {
+		t.Fatal("expected errRenterBusy, got", err)
+	}
+	// The limits apply to each renter and each type of RPC separately.
+	if err := ht.host.managedStartRenterSession(renter2, modules.RPCReviseContract); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := ht.host.managedStartRenterSession(renter1, modules.RPCDownload); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ht.host.managedStartRenterSession(renter1, modules.RPCDownload); err != errRenterBusy {
+		t.Fatal("expected errRenterBusy, got", err)
+	}
+	// Other RPCs are not limited or counted.
+	if err := ht.host.managedStartRenterSession(renter1, modules.RPCRenewContract); err != nil {
+		t.Fatal(err)
+	}
+
+	sessions := ht.host.RenterSessions()
+	if len(sessions) != 2 || sessions[0].RenterKey != renter1 || sessions[0].Uploads != 1 || sessions[0].Downloads != 2 || sessions[1].Uploads != 1 {
+		t.Fatalf("unexpected sessions: %+v", sessions)
+	}
+
+	// Ending a session makes room for another, and renters without open
+	// sessions are no longer reported.
+	ht.host.managedEndRenterSession(renter1, modules.RPCReviseContract)
+	if err := ht.host.managedStartRenterSession(renter1, modules.RPCReviseContract); err != nil {
+		t.Fatal(err)
+	}
+	ht.host.managedEndRenterSession(renter2, modules.RPCReviseContract)
+	if sessions := ht.host.RenterSessions(); len(sessions) != 1 || sessions[0].RenterKey != renter1 {
+		t.Fatalf("unexpected sessions: %+v", sessions)
+	}
+}
+
+// addRenterObligation adds a storage obligation to the host whose revision can
+// be opened by the renter that holds the secret key of pk.
+func (ht *hostTester) addRenterObligation(pk crypto.PublicKey) (types.FileContractID, error) {
+	so, err := ht.newTesterStorageObligation()
+	if err != nil {
+		return types.FileContractID{}, err
+	}
+	ht.host.managedLockStorageObligation(so.id())
+	defer ht.host.managedUnlockStorageObligation(so.id())
+	err = ht.host.addStorageObligation(so)
+	if err != nil {
+		return types.FileContractID{}, err
+	}
+	validPayouts, missedPayouts := so.payouts()
+	so.RevisionTransactionSet = []types.Transaction{{
+		FileContractRevisions: []types.FileContractRevision{{
+			ParentID: so.id(),
+			UnlockConditions: types.UnlockConditions{
+				PublicKeys: []types.SiaPublicKey{
+					{Algorithm: types.SignatureEd25519, Key: pk[:]},
+					{Algorithm: types.SignatureEd25519, Key: ht.host.publicKey.Key},
+				},
+				SignaturesRequired: 2,
+			},
+			NewRevisionNumber:     1,
+			NewWindowStart:        so.expiration(),
+			NewWindowEnd:          so.proofDeadline(),
+			NewValidProofOutputs:  validPayouts,
+			NewMissedProofOutputs: missedPayouts,
+		}},
+	}}
+	err = ht.host.modifyStorageObligation(so, nil, nil, nil)
+	if err != nil {
+		return types.FileContractID{}, err
+	}
+	return so.id(), nil
+}
+
+// openRenterSession opens an RPC with the host for the contract fcid, and
+// returns the connection once the host has accepted the renter's response to
+// its challenge.
+func openRenterSession(addr string, rpc types.Specifier, fcid types.FileContractID, sk crypto.SecretKey) (net.Conn, error) {
+	conn, err := net.DialTimeout("tcp", addr, 10*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	var challenge crypto.Hash
+	err = encoding.WriteObject(conn, rpc)
+	if err == nil {
+		err = encoding.WriteObject(conn, fcid)
+	}
+	if err == nil {
+		err = encoding.ReadObject(conn, &challenge, uint64(len(challenge)))
+	}
+	if err == nil {
+		var sig crypto.Signature
+		sig, err = crypto.SignHash(challenge, sk)
+		if err == nil {
+			err = encoding.WriteObject(conn, sig)
+		}
+	}
+	if err == nil {
+		err = modules.ReadNegotiationAcceptance(conn)
+	}
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// TestRenterSessionsAcrossContracts checks that the limits on open RPCs apply
+// to a renter as a whole, when it opens uploads and downloads at once on
+// several of its contracts.
+func TestRenterSessionsAcrossContracts(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	ht, err := newHostTester("TestRenterSessionsAcrossContracts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ht.Close()
+
+	settings := ht.host.InternalSettings()
+	settings.MaxRenterUploads = 1
+	settings.MaxRenterDownloads = 2
+	if err := ht.host.SetInternalSettings(settings); err != nil {
+		t.Fatal(err)
+	}
+
+	sk, pk, err := crypto.GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rpcs := []types.Specifier{
+		modules.RPCReviseContract, modules.RPCReviseContract, modules.RPCReviseContract,
+		modules.RPCDownload, modules.RPCDownload, modules.RPCDownload,
+	}
+	fcids := make([]types.FileContractID, len(rpcs))
+	for i := range fcids {
+		fcids[i], err = ht.addRenterObligation(pk)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, port, err := net.SplitHostPort(ht.host.listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := net.JoinHostPort("127.0.0.1", port)
+
+	// Open every RPC at once, each on a different contract.
+	var wg sync.WaitGroup
+	conns := make([]net.Conn, len(rpcs))
+	errs := make([]error, len(rpcs))
+	for i := range rpcs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			conns[i], errs[i] = openRenterSession(addr, rpcs[i], fcids[i], sk)
+		}(i)
+	}
+	wg.Wait()
+	var uploads, downloads uint64
+	for i, err := range errs {
+		if err == nil {
+			defer conns[i].Close()
+			if rpcs[i] == modules.RPCReviseContract {
+				uploads++
+			} else {
+				downloads++
+			}
+		} else if err.Error() != errRenterBusy.Error() {
+			t.Fatal("expected errRenterBusy, got", err)
+		}
+	}
+	if uploads != settings.MaxRenterUploads || downloads != settings.MaxRenterDownloads {
+		t.Fatalf("expected %v uploads and %v downloads to be accepted, got %v and %v", settings.MaxRenterUploads, settings.MaxRenterDownloads, uploads, downloads)
+	}
+	// All of the contracts share the renter's key, so they count as one
+	// renter.
+	sessions := ht.host.RenterSessions()
+	renterKey := "ed25519:" + hex.EncodeToString(pk[:])
+	if len(sessions) != 1 || sessions[0].RenterKey != renterKey || sessions[0].Uploads != uploads || sessions[0].Downloads != downloads {
+		t.Fatalf("unexpected sessions: %+v", sessions)
+	}
+}
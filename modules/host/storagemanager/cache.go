@@ -0,0 +1,139 @@
+package storagemanager
+
+import (
+	"container/list"
+
+	"github.com/NebulousLabs/Sia/crypto"
+	"github.com/NebulousLabs/Sia/modules"
+)
+
+// A cachedSector is a sector held in the sector cache.
+type cachedSector struct {
+	root crypto.Hash
+	data []byte
+}
+
+// A sectorCache keeps recently read sectors in memory, so that sectors that
+// are downloaded often are not read from disk every time. Once the cache is
+// full, the least recently used sectors are evicted. A maximum size of zero
+// disables the cache.
+type sectorCache struct {
+	entries map[crypto.Hash]*list.Element
+	lru     *list.List // most recently used at the front
+	size    uint64
+	maxSize uint64
+
+	hits      uint64
+	misses    uint64
+	evictions uint64
+}
+
+// newSectorCache returns an empty sector cache that holds up to maxSize bytes
+// of sectors.
+func newSectorCache(maxSize uint64) *sectorCache {
+	return &sectorCache{
+		entries: make(map[crypto.Hash]*list.Element),
+		lru:     list.New(),
+		maxSize: maxSize,
+	}
+}
+
+// get returns a copy of the sector with the given root if it is cached.
+func (c *sectorCache) get(root crypto.Hash) ([]byte, bool) {
+	if c.maxSize == 0 {
+		return nil, false
+	}
+	e, ok := c.entries[root]
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+	c.hits++
+	c.lru.MoveToFront(e)
+	data := e.Value.(*cachedSector).data
+	return append([]byte(nil), data...), true
+}
+
+// add caches a copy of a sector, evicting the least recently used sectors to
+// make room for it. Sectors larger than the cache are not cached.
+func (c *sectorCache) add(root crypto.Hash, data []byte) {
+	if uint64(len(data)) > c.maxSize {
+		return
+	}
+	if _, ok := c.entries[root]; ok {
+		return
+	}
+	c.entries[root] = c.lru.PushFront(&cachedSector{
+		root: root,
+		data: append([]byte(nil), data...),
+	})
+	c.size += uint64(len(data))
+	c.evict()
+}
+
+// remove drops a sector from the cache, if it is cached.
+func (c *sectorCache) remove(root crypto.Hash) {
+	e, ok := c.entries[root]
+	if !ok {
+		return
+	}
+	c.lru.Remove(e)
+	delete(c.entries, root)
+	c.size -= uint64(len(e.Value.(*cachedSector).data))
+}
+
+// setMaxSize changes the size of the cache, evicting sectors if the cache
+// is now too full.
+func (c *sectorCache) setMaxSize(maxSize uint64) {
+	c.maxSize = maxSize
+	c.evict()
+}
+
+// evict removes the least recently used sectors until the cache is no larger
+// than its maximum size.
+func (c *sectorCache) evict() {
+	for c.size > c.maxSize {
+		cs := c.lru.Back().Value.(*cachedSector)
+		c.remove(cs.root)
+		c.evictions++
+	}
+}
+
+// stats returns the size and effectiveness of the cache.
+func (c *sectorCache) stats() modules.StorageSectorCacheStats {
+	stats := modules.StorageSectorCacheStats{
+		Size:      c.size,
+		MaxSize:   c.maxSize,
+		Sectors:   len(c.entries),
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.evictions,
+	}
+	if c.hits+c.misses > 0 {
+		stats.HitRate = float64(c.hits) / float64(c.hits+c.misses)
+	}
+	return stats
+}
+
+// SectorCacheStats returns the size of the cache of recently read sectors,
+// along with how often reads have been served from it.
+func (sm *StorageManager) SectorCacheStats() modules.StorageSectorCacheStats {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.sectorCache.stats()
+}
+
+// SetSectorCacheSize sets the most memory, in bytes, that the cache of
+// recently read sectors can use. Zero disables the cache.
+func (sm *StorageManager) SetSectorCacheSize(maxSize uint64) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.resourceLock.RLock()
+	defer sm.resourceLock.RUnlock()
+	if sm.closed {
+		return errStorageManagerClosed
+	}
+	sm.sectorCacheSize = maxSize
+	sm.sectorCache.setMaxSize(maxSize)
+	return sm.saveSync()
+}
@@ -0,0 +1,130 @@
+package storagemanager
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/NebulousLabs/Sia/crypto"
+	"github.com/NebulousLabs/Sia/modules"
+)
+
+// TestSectorCacheEviction checks that the sector cache evicts the least
+// recently used sectors once it is full.
+func TestSectorCacheEviction(t *testing.T) {
+	c := newSectorCache(30)
+	roots := []crypto.Hash{{1}, {2}, {3}, {4}}
+	for _, root := range roots[:3] {
+		c.add(root, bytes.Repeat(root[:1], 10))
+	}
+	if _, ok := c.get(roots[0]); !ok {
+		t.Fatal("sector was not cached")
+	}
+
+	// roots[1] is now the least recently used sector.
+	c.add(roots[3], bytes.Repeat(roots[3][:1], 10))
+	if _, ok := c.get(roots[1]); ok {
+		t.Fatal("least recently used sector was not evicted")
+	}
+	data, ok := c.get(roots[0])
+	if !ok || !bytes.Equal(data, bytes.Repeat(roots[0][:1], 10)) {
+		t.Fatal("wrong data for cached sector")
+	}
+	// The cache returns a copy of the sector.
+	data[0] = 0
+	if data, _ := c.get(roots[0]); data[0] != 1 {
+		t.Fatal("cached sector was modified through a returned copy")
+	}
+
+	// Shrinking the cache evicts sectors right away.
+	c.setMaxSize(15)
+	stats := c.stats()
+	if stats.Size != 10 || stats.Sectors != 1 || stats.Evictions != 3 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+	if stats.Hits != 3 || stats.Misses != 1 || stats.HitRate != 0.75 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+
+	// Sectors larger than the cache are not cached, and a cache of size zero
+	// holds nothing.
+	c.add(crypto.Hash{5}, make([]byte, 20))
+	if _, ok := c.get(crypto.Hash{5}); ok {
+		t.Fatal("sector larger than the cache was cached")
+	}
+	c.setMaxSize(0)
+	if stats := c.stats(); stats.Size != 0 || stats.Sectors != 0 {
+		t.Fatalf("disabled cache is not empty: %+v", stats)
+	}
+}
+
+// TestReadSectorCache checks that the storage manager serves repeated reads
+// from the sector cache, and that deleted sectors are dropped from it.
+func TestReadSectorCache(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	smt, err := newStorageManagerTester("TestReadSectorCache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer smt.Close()
+
+	err = smt.sm.AddStorageFolder(smt.persistDir, minimumStorageFolderSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, data, err := createSector()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := smt.sm.AddSector(root, 10, data); err != nil {
+		t.Fatal(err)
+	}
+	if stats := smt.sm.SectorCacheStats(); stats.MaxSize != 0 {
+		t.Fatal("new storage manager has the sector cache enabled:", stats.MaxSize)
+	}
+	if err := smt.sm.SetSectorCacheSize(4 * modules.SectorSize); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		read, err := smt.sm.ReadSector(root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(read, data) {
+			t.Fatal("read the wrong data")
+		}
+	}
+	stats := smt.sm.SectorCacheStats()
+	if stats.Hits != 2 || stats.Misses != 1 || stats.Size != modules.SectorSize {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+
+	if err := smt.sm.DeleteSector(root); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := smt.sm.ReadSector(root); err != errSectorNotFound {
+		t.Fatal("expected errSectorNotFound, got", err)
+	}
+	if stats := smt.sm.SectorCacheStats(); stats.Sectors != 0 {
+		t.Fatal("deleted sector is still cached")
+	}
+
+	// The cache size is saved across restarts.
+	if err := smt.sm.SetSectorCacheSize(modules.SectorSize); err != nil {
+		t.Fatal(err)
+	}
+	if err := smt.sm.Close(); err != nil {
+		t.Fatal(err)
+	}
+	smt.sm, err = New(filepath.Join(smt.persistDir, modules.StorageManagerDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats := smt.sm.SectorCacheStats(); stats.MaxSize != modules.SectorSize {
+		t.Fatal("cache size was not saved:", stats.MaxSize)
+	}
+}
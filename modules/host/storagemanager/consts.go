@@ -11,6 +11,12 @@ const (
 	// also increases as the number of storage folders increase. For this
 	// reason, a limit on the maximum number of storage folders has been set.
 	maximumStorageFolders = 100
+
+	// maximumTierMigration is the number of sectors that MigrateTiers moves
+	// in a single call. The storage manager is locked while sectors are being
+	// migrated, so large migrations are spread over several calls to keep the
+	// host responsive to renters.
+	maximumTierMigration = 256
 )
 
 var (
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 
 	"github.com/NebulousLabs/Sia/crypto"
+	"github.com/NebulousLabs/Sia/modules"
 	"github.com/NebulousLabs/Sia/persist"
 
 	"github.com/NebulousLabs/bolt"
@@ -15,6 +16,11 @@ import (
 type persistence struct {
 	SectorSalt     crypto.Hash
 	StorageFolders []*storageFolder
+	TierPolicy     modules.StorageTierPolicy
+
+	// SectorCacheSize is zero unless the sector cache has been enabled,
+	// including for storage managers saved before the cache was added.
+	SectorCacheSize uint64
 }
 
 // persistData returns the data in the StorageManager that will be saved to
@@ -23,6 +29,9 @@ func (sm *StorageManager) persistData() persistence {
 	return persistence{
 		SectorSalt:     sm.sectorSalt,
 		StorageFolders: sm.storageFolders,
+		TierPolicy:     sm.tierPolicy,
+
+		SectorCacheSize: sm.sectorCacheSize,
 	}
 }
 
@@ -71,6 +80,8 @@ func (sm *StorageManager) load() error {
 
 	sm.sectorSalt = p.SectorSalt
 	sm.storageFolders = p.StorageFolders
+	sm.tierPolicy = p.TierPolicy
+	sm.sectorCacheSize = p.SectorCacheSize
 	return nil
 }
 
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"io/ioutil"
 	"path/filepath"
+	"time"
 
 	"github.com/NebulousLabs/Sia/crypto"
 	"github.com/NebulousLabs/Sia/modules"
@@ -92,6 +93,11 @@ type sectorUsage struct {
 	Corrupted     bool // If the corrupted flag is set, it means the sector is permanently unreachable.
 	Expiry        []types.BlockHeight
 	StorageFolder []byte
+
+	// Added is the time at which the physical sector was added, which is
+	// used to decide when the sector is migrated to a slower storage tier.
+	// Sectors added before the field existed have the zero time.
+	Added time.Time
 }
 
 // sectorID returns the id that should be used when referring to a sector.
@@ -187,7 +193,7 @@ func (sm *StorageManager) AddSector(sectorRoot crypto.Hash, expiryHeight types.B
 		// will try the next storage folder until there is either a success or
 		// until all options have been exhausted.
 		potentialFolders := sm.storageFolders
-		emptiestFolder, emptiestIndex := emptiestStorageFolder(potentialFolders)
+		emptiestFolder, emptiestIndex := placementFolder(potentialFolders)
 		for emptiestFolder != nil {
 			sectorPath := filepath.Join(sm.persistDir, emptiestFolder.uidString(), string(sectorKey))
 			err := sm.dependencies.writeFile(sectorPath, sectorData, 0700)
@@ -206,7 +212,7 @@ func (sm *StorageManager) AddSector(sectorRoot crypto.Hash, expiryHeight types.B
 				potentialFolders = append(potentialFolders[0:emptiestIndex], potentialFolders[emptiestIndex+1:]...)
 
 				// Try the next folder.
-				emptiestFolder, emptiestIndex = emptiestStorageFolder(potentialFolders)
+				emptiestFolder, emptiestIndex = placementFolder(potentialFolders)
 				continue
 			}
 			emptiestFolder.SuccessfulWrites++
@@ -216,6 +222,7 @@ func (sm *StorageManager) AddSector(sectorRoot crypto.Hash, expiryHeight types.B
 			usage := sectorUsage{
 				Expiry:        []types.BlockHeight{expiryHeight},
 				StorageFolder: emptiestFolder.UID,
+				Added:         time.Now(),
 			}
 			emptiestFolder.SizeRemaining -= modules.SectorSize
 			usageBytes, err = json.Marshal(usage)
@@ -235,11 +242,20 @@ func (sm *StorageManager) AddSector(sectorRoot crypto.Hash, expiryHeight types.B
 	return sm.save()
 }
 
-// ReadSector will pull a sector from disk into memory.
+// ReadSector will pull a sector from disk into memory. Recently read sectors
+// are served from the sector cache instead.
 func (sm *StorageManager) ReadSector(sectorRoot crypto.Hash) (sectorBytes []byte, err error) {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
+	if data, ok := sm.sectorCache.get(sectorRoot); ok {
+		return data, nil
+	}
+	defer func() {
+		if err == nil {
+			sm.sectorCache.add(sectorRoot, sectorBytes)
+		}
+	}()
 	err = sm.db.View(func(tx *bolt.Tx) error {
 		bsu := tx.Bucket(bucketSectorUsage)
 		sectorKey := sm.sectorID(sectorRoot[:])
@@ -342,6 +358,7 @@ func (sm *StorageManager) RemoveSector(sectorRoot crypto.Hash, expiryHeight type
 		}
 		folder.SizeRemaining += modules.SectorSize
 		folder.SuccessfulWrites++
+		sm.sectorCache.remove(sectorRoot)
 		err = sm.save()
 		if err != nil {
 			return err
@@ -401,6 +418,7 @@ func (sm *StorageManager) DeleteSector(sectorRoot crypto.Hash) error {
 		}
 		folder.SizeRemaining += modules.SectorSize
 		folder.SuccessfulWrites++
+		sm.sectorCache.remove(sectorRoot)
 		err = sm.save()
 		if err != nil {
 			return err
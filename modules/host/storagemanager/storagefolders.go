@@ -150,12 +150,17 @@ var (
 // of the symlink which points to the folder holding the data for this storage
 // folder.
 //
+// 'Tier' is set by the user to indicate whether the folder is on a fast or a
+// slow drive. New sectors are placed in fast folders, and are later migrated
+// to slow folders according to the tier policy.
+//
 // Statistics are kept on the integrity of reads and writes. Ideally, the
 // filesystem is never returning errors, but if errors are being returned they
 // will be tracked and can be reported to the user.
 type storageFolder struct {
 	Path string
 	UID  []byte
+	Tier modules.StorageTier
 
 	Size          uint64
 	SizeRemaining uint64
@@ -599,6 +604,7 @@ func (sm *StorageManager) StorageFolders() (sfms []modules.StorageFolderMetadata
 			Capacity:          sf.Size,
 			CapacityRemaining: sf.SizeRemaining,
 			Path:              sf.Path,
+			Tier:              sf.Tier,
 
 			FailedReads:      sf.FailedReads,
 			FailedWrites:     sf.FailedWrites,
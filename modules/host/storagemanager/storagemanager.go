@@ -11,6 +11,7 @@ import (
 	"sync"
 
 	"github.com/NebulousLabs/Sia/crypto"
+	"github.com/NebulousLabs/Sia/modules"
 	"github.com/NebulousLabs/Sia/persist"
 )
 
@@ -49,6 +50,13 @@ type StorageManager struct {
 	// Storage management information.
 	sectorSalt     crypto.Hash
 	storageFolders []*storageFolder
+	tierPolicy     modules.StorageTierPolicy
+
+	// The sector cache keeps recently read sectors in memory, using up to
+	// sectorCacheSize bytes. The cache is disabled until the host sets a
+	// size, so that hosts with little memory do not run out of it.
+	sectorCache     *sectorCache
+	sectorCacheSize uint64
 
 	// Utilities.
 	db         *persist.BoltDatabase
@@ -150,6 +158,7 @@ func newStorageManager(dependencies dependencies, persistDir string) (*StorageMa
 		_ = sm.db.Close()
 		return nil, err
 	}
+	sm.sectorCache = newSectorCache(sm.sectorCacheSize)
 	return sm, nil
 }
 
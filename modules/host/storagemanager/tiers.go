@@ -0,0 +1,206 @@
+package storagemanager
+
+// tiers.go places new sectors in fast storage folders, and migrates them to
+// slow storage folders once they have aged past the tier policy. The age of a
+// sector is measured from the time that the physical sector was added, so
+// virtual sectors do not keep old data in the fast tier.
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"time"
+
+	"github.com/NebulousLabs/Sia/modules"
+
+	"github.com/NebulousLabs/bolt"
+)
+
+var (
+	// errBadStorageTier is returned if a storage folder is given a tier that
+	// is not recognized.
+	errBadStorageTier = errors.New("storage tier must be 'fast', 'slow', or empty")
+)
+
+// placementFolder returns the storage folder that should receive a new
+// sector. The emptiest fast folder is chosen while any fast folder has room
+// for the sector, otherwise the emptiest folder of any tier is chosen. The
+// index of the folder within 'sfs' is also returned.
+func placementFolder(sfs []*storageFolder) (*storageFolder, int) {
+	var fast []*storageFolder
+	var fastIndices []int
+	for i, sf := range sfs {
+		if sf.Tier == modules.StorageTierFast {
+			fast = append(fast, sf)
+			fastIndices = append(fastIndices, i)
+		}
+	}
+	if sf, i := emptiestStorageFolder(fast); sf != nil {
+		return sf, fastIndices[i]
+	}
+	return emptiestStorageFolder(sfs)
+}
+
+// agedSectors returns the ids of the sectors in fast storage folders that were
+// added before the cutoff.
+func (sm *StorageManager) agedSectors(cutoff time.Time) (ids [][]byte, err error) {
+	err = sm.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketSectorUsage).ForEach(func(k, v []byte) error {
+			var usage sectorUsage
+			err := json.Unmarshal(v, &usage)
+			if err != nil {
+				return err
+			}
+			sf := sm.storageFolder(usage.StorageFolder)
+			if sf == nil || sf.Tier != modules.StorageTierFast || !usage.Added.Before(cutoff) {
+				return nil
+			}
+			// Keys are only valid for the life of the transaction.
+			ids = append(ids, append([]byte(nil), k...))
+			return nil
+		})
+	})
+	return ids, err
+}
+
+// MigrateTiers moves the sectors that have been in fast storage folders for
+// longer than the tier policy allows into slow storage folders, up to
+// maximumTierMigration sectors per call. Sectors that cannot be read are left
+// where they are, and slow folders that fail a write are not used for the rest
+// of the migration. The number of sectors moved is returned.
+func (sm *StorageManager) MigrateTiers() (migrated uint64, err error) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.resourceLock.RLock()
+	defer sm.resourceLock.RUnlock()
+	if sm.closed {
+		return 0, errStorageManagerClosed
+	}
+	if sm.tierPolicy.MigrateAfter == 0 {
+		return 0, nil
+	}
+
+	var slowFolders []*storageFolder
+	for _, sf := range sm.storageFolders {
+		if sf.Tier == modules.StorageTierSlow {
+			slowFolders = append(slowFolders, sf)
+		}
+	}
+	if len(slowFolders) == 0 {
+		return 0, nil
+	}
+	cutoff := time.Now().Add(-time.Duration(sm.tierPolicy.MigrateAfter) * time.Second)
+	ids, err := sm.agedSectors(cutoff)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(ids) > maximumTierMigration {
+		ids = ids[:maximumTierMigration]
+	}
+	for _, id := range ids {
+		destFolder, destIndex := emptiestStorageFolder(slowFolders)
+		if destFolder == nil {
+			// The slow folders are full.
+			break
+		}
+		err = sm.db.Update(func(tx *bolt.Tx) error {
+			bsu := tx.Bucket(bucketSectorUsage)
+			var usage sectorUsage
+			err := json.Unmarshal(bsu.Get(id), &usage)
+			if err != nil {
+				return err
+			}
+			srcFolder := sm.storageFolder(usage.StorageFolder)
+
+			oldSectorPath := filepath.Join(sm.persistDir, srcFolder.uidString(), string(id))
+			sectorData, err := sm.dependencies.readFile(oldSectorPath)
+			if err != nil {
+				srcFolder.FailedReads++
+				return nil
+			}
+			srcFolder.SuccessfulReads++
+
+			newSectorPath := filepath.Join(sm.persistDir, destFolder.uidString(), string(id))
+			err = sm.dependencies.writeFile(newSectorPath, sectorData, 0700)
+			if err != nil {
+				destFolder.FailedWrites++
+				_ = sm.dependencies.removeFile(newSectorPath)
+				slowFolders = append(slowFolders[:destIndex], slowFolders[destIndex+1:]...)
+				return nil
+			}
+			destFolder.SuccessfulWrites++
+			err = sm.dependencies.removeFile(oldSectorPath)
+			if err != nil {
+				srcFolder.FailedWrites++
+			} else {
+				srcFolder.SuccessfulWrites++
+			}
+
+			srcFolder.SizeRemaining += modules.SectorSize
+			destFolder.SizeRemaining -= modules.SectorSize
+			usage.StorageFolder = destFolder.UID
+			usageBytes, err := json.Marshal(usage)
+			if err != nil {
+				return err
+			}
+			err = bsu.Put(id, usageBytes)
+			if err != nil {
+				return err
+			}
+			migrated++
+			return nil
+		})
+		if err != nil {
+			break
+		}
+	}
+	if migrated > 0 {
+		sm.log.Printf("Migrated %v sectors to slow storage folders\n", migrated)
+	}
+	return migrated, composeErrors(err, sm.saveSync())
+}
+
+// SetStorageFolderTier sets the tier of the storage folder at the given index.
+// Sectors already in the folder are not moved; the tier only affects where
+// new sectors are placed and which sectors are migrated.
+func (sm *StorageManager) SetStorageFolderTier(index int, tier modules.StorageTier) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.resourceLock.RLock()
+	defer sm.resourceLock.RUnlock()
+	if sm.closed {
+		return errStorageManagerClosed
+	}
+
+	if index >= len(sm.storageFolders) || index < 0 {
+		return errBadStorageFolderIndex
+	}
+	switch tier {
+	case modules.StorageTierNone, modules.StorageTierFast, modules.StorageTierSlow:
+	default:
+		return errBadStorageTier
+	}
+	sm.storageFolders[index].Tier = tier
+	return sm.saveSync()
+}
+
+// SetTierPolicy sets the policy for migrating sectors between tiers.
+func (sm *StorageManager) SetTierPolicy(policy modules.StorageTierPolicy) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.resourceLock.RLock()
+	defer sm.resourceLock.RUnlock()
+	if sm.closed {
+		return errStorageManagerClosed
+	}
+	sm.tierPolicy = policy
+	return sm.saveSync()
+}
+
+// TierPolicy returns the policy for migrating sectors between tiers.
+func (sm *StorageManager) TierPolicy() modules.StorageTierPolicy {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.tierPolicy
+}
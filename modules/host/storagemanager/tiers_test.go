@@ -0,0 +1,121 @@
+package storagemanager
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/NebulousLabs/Sia/crypto"
+	"github.com/NebulousLabs/Sia/modules"
+
+	"github.com/NebulousLabs/bolt"
+)
+
+// TestStorageTiers checks that new sectors are placed in the fast storage
+// folder, and that they are moved to the slow storage folder once they are
+// older than the tier policy allows.
+func TestStorageTiers(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	smt, err := newStorageManagerTester("TestStorageTiers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer smt.Close()
+
+	for i := 0; i < 2; i++ {
+		if err := smt.addRandFolder(minimumStorageFolderSize); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := smt.sm.SetStorageFolderTier(0, "medium"); err != errBadStorageTier {
+		t.Fatal("expected errBadStorageTier, got", err)
+	}
+	if err := smt.sm.SetStorageFolderTier(0, modules.StorageTierSlow); err != nil {
+		t.Fatal(err)
+	}
+	if err := smt.sm.SetStorageFolderTier(1, modules.StorageTierFast); err != nil {
+		t.Fatal(err)
+	}
+	slow, fast := smt.sm.storageFolders[0], smt.sm.storageFolders[1]
+
+	// New sectors go to the fast folder, even though the slow folder is just
+	// as empty.
+	var roots []crypto.Hash
+	for i := 0; i < 2; i++ {
+		root, data, err := createSector()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := smt.sm.AddSector(root, 10, data); err != nil {
+			t.Fatal(err)
+		}
+		roots = append(roots, root)
+	}
+	if fast.SizeRemaining != fast.Size-2*modules.SectorSize || slow.SizeRemaining != slow.Size {
+		t.Fatal("sectors were not placed in the fast folder")
+	}
+
+	// Nothing is migrated without a policy, or before the sectors have aged.
+	if n, err := smt.sm.MigrateTiers(); err != nil || n != 0 {
+		t.Fatal("unexpected migration:", n, err)
+	}
+	if err := smt.sm.SetTierPolicy(modules.StorageTierPolicy{MigrateAfter: 3600}); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := smt.sm.MigrateTiers(); err != nil || n != 0 {
+		t.Fatal("unexpected migration:", n, err)
+	}
+
+	// Age the first sector by two hours.
+	err = smt.sm.db.Update(func(tx *bolt.Tx) error {
+		bsu := tx.Bucket(bucketSectorUsage)
+		id := smt.sm.sectorID(roots[0][:])
+		var usage sectorUsage
+		if err := json.Unmarshal(bsu.Get(id), &usage); err != nil {
+			return err
+		}
+		usage.Added = usage.Added.Add(-2 * time.Hour)
+		usageBytes, err := json.Marshal(usage)
+		if err != nil {
+			return err
+		}
+		return bsu.Put(id, usageBytes)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := smt.sm.MigrateTiers(); err != nil || n != 1 {
+		t.Fatal("unexpected migration:", n, err)
+	}
+	if fast.SizeRemaining != fast.Size-modules.SectorSize || slow.SizeRemaining != slow.Size-modules.SectorSize {
+		t.Fatal("sector was not moved to the slow folder")
+	}
+	for _, root := range roots {
+		data, err := smt.sm.ReadSector(root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if crypto.MerkleRoot(data) != root {
+			t.Fatal("sector data changed during migration")
+		}
+	}
+
+	// The tiers and the policy survive a restart.
+	if err := smt.sm.Close(); err != nil {
+		t.Fatal(err)
+	}
+	smt.sm, err = New(smt.sm.persistDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sfs := smt.sm.StorageFolders()
+	if sfs[0].Tier != modules.StorageTierSlow || sfs[1].Tier != modules.StorageTierFast {
+		t.Fatal("storage tiers were not persisted")
+	}
+	if smt.sm.TierPolicy().MigrateAfter != 3600 {
+		t.Fatal("tier policy was not persisted")
+	}
+}
@@ -0,0 +1,83 @@
+package storagemanager
+
+import (
+	"encoding/json"
+
+	"github.com/NebulousLabs/Sia/crypto"
+	"github.com/NebulousLabs/Sia/modules"
+
+	"github.com/NebulousLabs/bolt"
+)
+
+// usageMetadata converts the usage of the sector with the given id to its
+// exported form.
+func (sm *StorageManager) usageMetadata(id []byte, usage sectorUsage) modules.StorageSectorUsage {
+	su := modules.StorageSectorUsage{
+		ID:            string(id),
+		References:    len(usage.Expiry),
+		Expiries:      usage.Expiry,
+		StorageFolder: -1,
+		Corrupted:     usage.Corrupted,
+	}
+	if sf := sm.storageFolder(usage.StorageFolder); sf != nil {
+		su.StorageFolderPath = sf.Path
+		for i := range sm.storageFolders {
+			if sm.storageFolders[i] == sf {
+				su.StorageFolder = i
+			}
+		}
+	}
+	return su
+}
+
+// SectorUsage returns the reference count and location of a sector.
+func (sm *StorageManager) SectorUsage(sectorRoot crypto.Hash) (su modules.StorageSectorUsage, err error) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	err = sm.db.View(func(tx *bolt.Tx) error {
+		id := sm.sectorID(sectorRoot[:])
+		usageBytes := tx.Bucket(bucketSectorUsage).Get(id)
+		if usageBytes == nil {
+			return errSectorNotFound
+		}
+		var usage sectorUsage
+		err := json.Unmarshal(usageBytes, &usage)
+		if err != nil {
+			return err
+		}
+		su = sm.usageMetadata(id, usage)
+		return nil
+	})
+	return su, err
+}
+
+// UnreferencedSectors returns every sector that is not one of the 'referenced'
+// sectors, or that has no references left. Such sectors take up space without
+// backing any storage obligation, and can be deleted.
+func (sm *StorageManager) UnreferencedSectors(referenced []crypto.Hash) (sus []modules.StorageSectorUsage, err error) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	// Sectors are stored by id, which cannot be turned back into a root, so
+	// the referenced roots are converted to ids instead.
+	referencedIDs := make(map[string]struct{}, len(referenced))
+	for _, root := range referenced {
+		referencedIDs[string(sm.sectorID(root[:]))] = struct{}{}
+	}
+	err = sm.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketSectorUsage).ForEach(func(k, v []byte) error {
+			var usage sectorUsage
+			err := json.Unmarshal(v, &usage)
+			if err != nil {
+				return err
+			}
+			if _, ok := referencedIDs[string(k)]; ok && len(usage.Expiry) > 0 {
+				return nil
+			}
+			sus = append(sus, sm.usageMetadata(k, usage))
+			return nil
+		})
+	})
+	return sus, err
+}
@@ -0,0 +1,82 @@
+package storagemanager
+
+import (
+	"testing"
+
+	"github.com/NebulousLabs/Sia/crypto"
+)
+
+// TestSectorUsage checks the reference counts and locations reported for
+// sectors, and which sectors are reported as unreferenced.
+func TestSectorUsage(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	smt, err := newStorageManagerTester("TestSectorUsage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer smt.Close()
+
+	err = smt.sm.AddStorageFolder(smt.persistDir, minimumStorageFolderSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root1, data1, err := createSector()
+	if err != nil {
+		t.Fatal(err)
+	}
+	root2, data2, err := createSector()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := smt.sm.SectorUsage(root1); err != errSectorNotFound {
+		t.Fatal("expected errSectorNotFound, got", err)
+	}
+
+	// Add the first sector twice, making a virtual sector.
+	if err := smt.sm.AddSector(root1, 10, data1); err != nil {
+		t.Fatal(err)
+	}
+	if err := smt.sm.AddSector(root1, 20, data1); err != nil {
+		t.Fatal(err)
+	}
+	if err := smt.sm.AddSector(root2, 10, data2); err != nil {
+		t.Fatal(err)
+	}
+	su, err := smt.sm.SectorUsage(root1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if su.References != 2 || len(su.Expiries) != 2 || su.StorageFolder != 0 || su.StorageFolderPath != smt.persistDir {
+		t.Fatalf("unexpected usage: %+v", su)
+	}
+	if su.ID != string(smt.sm.sectorID(root1[:])) {
+		t.Fatal("wrong sector id:", su.ID)
+	}
+
+	// Removing a reference lowers the count.
+	if err := smt.sm.RemoveSector(root1, 20); err != nil {
+		t.Fatal(err)
+	}
+	if su, err := smt.sm.SectorUsage(root1); err != nil || su.References != 1 {
+		t.Fatalf("unexpected usage: %+v, %v", su, err)
+	}
+
+	// Only the sector that is not referenced should be reported.
+	sus, err := smt.sm.UnreferencedSectors([]crypto.Hash{root1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sus) != 1 || sus[0].ID != string(smt.sm.sectorID(root2[:])) {
+		t.Fatalf("unexpected unreferenced sectors: %+v", sus)
+	}
+	sus, err = smt.sm.UnreferencedSectors([]crypto.Hash{root1, root2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sus) != 0 {
+		t.Fatalf("unexpected unreferenced sectors: %+v", sus)
+	}
+}
@@ -106,6 +106,22 @@ var (
 
 type storageObligationStatus uint64
 
+// String returns the name of the storage obligation status.
+func (s storageObligationStatus) String() string {
+	switch s {
+	case obligationUnresolved:
+		return "unresolved"
+	case obligationRejected:
+		return "rejected"
+	case obligationSucceeded:
+		return "succeeded"
+	case obligationFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
 // storageObligation contains all of the metadata related to a file contract
 // and the storage contained by the file contract.
 type storageObligation struct {
@@ -128,6 +144,12 @@ type storageObligation struct {
 	RiskedCollateral         types.Currency
 	TransactionFeesAdded     types.Currency
 
+	// MaxCollateral is the host's MaxCollateral setting when the contract
+	// was formed or renewed, which caps the collateral added by revisions
+	// for the life of the contract. It is zero for obligations created
+	// before it was recorded, which are capped by the current setting.
+	MaxCollateral types.Currency
+
 	OriginTransactionSet   []types.Transaction
 	RevisionTransactionSet []types.Transaction
 
@@ -391,7 +413,12 @@ func (h *Host) addStorageObligation(so storageObligation) error {
 	err2 := h.queueActionItem(so.expiration()-revisionSubmissionBuffer, soid)
 	// The storage proof should be submitted
 	err3 := h.queueActionItem(so.expiration()+resubmissionTimeout, soid)
-	err = composeErrors(err0, err1, err2, err3)
+	// If enabled, the storage proof should be precomputed first.
+	var err4 error
+	if h.settings.ProofPrecomputeBlocks > 0 {
+		err4 = h.queueActionItem(so.expiration()+resubmissionTimeout-h.settings.ProofPrecomputeBlocks, soid)
+	}
+	err = composeErrors(err0, err1, err2, err3, err4)
 	if err != nil {
 		h.log.Println("Error with transaction set, redacting obligation, id", so.id())
 		return composeErrors(err, h.removeStorageObligation(so, obligationRejected))
@@ -514,6 +541,7 @@ func (h *Host) modifyStorageObligation(so storageObligation, sectorsRemoved []cr
 // removeStorageObligation will remove a storage obligation from the host,
 // either due to failure or success.
 func (h *Host) removeStorageObligation(so storageObligation, sos storageObligationStatus) error {
+	delete(h.precomputedProofs, so.id())
 
 	// Call removeSector for every sector in the storage obligation.
 	for _, root := range so.SectorRoots {
@@ -720,6 +748,27 @@ func (h *Host) threadedHandleActionItem(soid types.FileContractID, wg *sync.Wait
 		// return
 	}
 
+	// Build the storage proof ahead of time if the host is configured to, so
+	// that the proofs of many obligations are not all built in the block in
+	// which they are submitted. The segment to prove is not known until the
+	// proof window opens.
+	h.mu.RLock()
+	precompute := h.settings.ProofPrecomputeBlocks
+	h.mu.RUnlock()
+	proofHeight := so.expiration() + resubmissionTimeout
+	if !so.ProofConfirmed && precompute > 0 && blockHeight >= so.expiration() && blockHeight < proofHeight {
+		if blockHeight+precompute >= proofHeight {
+			h.managedPrecomputeProof(so)
+		} else {
+			h.mu.Lock()
+			err := h.queueActionItem(proofHeight-precompute, so.id())
+			h.mu.Unlock()
+			if err != nil {
+				h.log.Println("Error queuing action item:", err)
+			}
+		}
+	}
+
 	// Check whether a storage proof is ready to be provided, and whether it
 	// has been accepted. Check for death.
 	if !so.ProofConfirmed && blockHeight >= so.expiration()+resubmissionTimeout {
@@ -738,43 +787,13 @@ func (h *Host) threadedHandleActionItem(soid types.FileContractID, wg *sync.Wait
 			return
 		}
 
-		// Get the index of the segment, and the index of the sector containing
-		// the segment.
-		segmentIndex, err := h.cs.StorageProofSegment(so.id())
-		if err != nil {
-			h.log.Debugln("Host got an error when fetching a storage proof segment:", err)
-			return
-		}
-		sectorIndex := segmentIndex / (modules.SectorSize / crypto.SegmentSize)
-		// Pull the corresponding sector into memory.
-		sectorRoot := so.SectorRoots[sectorIndex]
-		sectorBytes, err := h.ReadSector(sectorRoot)
+		// Get the storage proof, which may have been precomputed.
+		sp, err := h.managedStorageProof(so)
 		if err != nil {
-			h.log.Debugln(err)
+			h.log.Debugln("Host could not build a storage proof:", err)
 			return
 		}
 
-		// Build the storage proof for just the sector.
-		sectorSegment := segmentIndex % (modules.SectorSize / crypto.SegmentSize)
-		base, cachedHashSet := crypto.MerkleProof(sectorBytes, sectorSegment)
-
-		// Using the sector, build a cached root.
-		log2SectorSize := uint64(0)
-		for 1<<log2SectorSize < (modules.SectorSize / crypto.SegmentSize) {
-			log2SectorSize++
-		}
-		ct := crypto.NewCachedTree(log2SectorSize)
-		ct.SetIndex(segmentIndex)
-		for _, root := range so.SectorRoots {
-			ct.Push(root)
-		}
-		hashSet := ct.Prove(base, cachedHashSet)
-		sp := types.StorageProof{
-			ParentID: so.id(),
-			HashSet:  hashSet,
-		}
-		copy(sp.Segment[:], base)
-
 		// Create and build the transaction with the storage proof.
 		builder := h.wallet.StartTransaction()
 		_, feeRecommendation := h.tpool.FeeEstimation()
@@ -837,3 +856,34 @@ func (h *Host) threadedHandleActionItem(soid types.FileContractID, wg *sync.Wait
 		h.mu.Unlock()
 	}
 }
+
+// StorageObligations returns a summary of every storage obligation in the
+// host's database.
+func (h *Host) StorageObligations() (sos []modules.HostStorageObligation) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	err := h.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketStorageObligations).ForEach(func(_, soBytes []byte) error {
+			var so storageObligation
+			err := json.Unmarshal(soBytes, &so)
+			if err != nil {
+				return err
+			}
+			valid, _ := so.payouts()
+			sos = append(sos, modules.HostStorageObligation{
+				ContractID:        so.id(),
+				Expiration:        so.expiration(),
+				ProofDeadline:     so.proofDeadline(),
+				Status:            so.ObligationStatus.String(),
+				Value:             so.value(),
+				ValidProofOutputs: valid,
+			})
+			return nil
+		})
+	})
+	if err != nil {
+		h.log.Println("Unable to read storage obligations:", err)
+	}
+	return sos
+}
@@ -309,6 +309,16 @@ func (h *Host) ProcessConsensusChange(cc modules.ConsensusChange) {
 		go h.threadedHandleActionItem(actionItems[i], wg)
 	}
 
+	// Adjust the host's prices periodically if automatic pricing is enabled.
+	if h.settings.AutoPricing && len(cc.AppliedBlocks) > 0 && h.blockHeight%autoPriceFrequency == 0 {
+		go h.threadedAdjustPrices()
+	}
+
+	// Move aged sectors from fast storage folders to slow storage folders.
+	if len(cc.AppliedBlocks) > 0 && h.blockHeight%tierMigrationFrequency == 0 {
+		go h.threadedMigrateTiers()
+	}
+
 	// Update the host's recent change pointer to point to the most recent
 	// change.
 	h.recentChange = cc.ID
@@ -319,3 +329,16 @@ func (h *Host) ProcessConsensusChange(cc modules.ConsensusChange) {
 		h.log.Println("ERROR: could not save during ProcessConsensusChange:", err)
 	}
 }
+
+// threadedMigrateTiers moves aged sectors from the host's fast storage folders
+// to its slow storage folders, according to the storage manager's tier policy.
+func (h *Host) threadedMigrateTiers() {
+	if err := h.tg.Add(); err != nil {
+		return
+	}
+	defer h.tg.Done()
+	_, err := h.MigrateTiers()
+	if err != nil {
+		h.log.Println("WARN: could not migrate sectors between storage tiers:", err)
+	}
+}
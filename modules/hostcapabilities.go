@@ -0,0 +1,131 @@
+package modules
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/NebulousLabs/Sia/encoding"
+)
+
+// HostCapabilities is a set of flags advertised in a host's external
+// settings, naming the optional services that the host offers. Renters should
+// ignore flags that they do not recognize, which allows new capabilities to
+// be added without changing the encoding of the settings.
+//
+// An absent flag means that the host's support is unknown, not that the
+// service is unsupported: hosts that predate a flag do not set it, even if
+// they offer the service. Filtering on a flag therefore skips older hosts.
+type HostCapabilities uint64
+
+const (
+	// HostCapabilityPartialDownloads indicates that the host serves
+	// downloads of a range of a sector, rather than only whole sectors.
+	HostCapabilityPartialDownloads HostCapabilities = 1 << iota
+
+	// HostCapabilityRenewal indicates that the host accepts RPCRenewContract,
+	// so that a contract can be renewed without uploading its data again.
+	HostCapabilityRenewal
+
+	// HostCapabilityModify indicates that the host accepts ActionModify
+	// revisions, which rewrite part of a sector in place.
+	HostCapabilityModify
+
+	// HostCapabilityTieredStorage indicates that the host has storage folders
+	// on fast drives, from which recently stored sectors are served. Unlike
+	// the other capabilities it depends on the host's configuration rather
+	// than its version.
+	HostCapabilityTieredStorage
+)
+
+var (
+	// SupportedHostCapabilities are the capabilities of all hosts running
+	// this version of Sia. Hosts add the capabilities that depend on their
+	// configuration, such as HostCapabilityTieredStorage.
+	SupportedHostCapabilities = HostCapabilityPartialDownloads | HostCapabilityRenewal | HostCapabilityModify
+
+	// hostCapabilityNames are the names that capabilities are given in the
+	// API, in the order of their flags.
+	hostCapabilityNames = []struct {
+		flag HostCapabilities
+		name string
+	}{
+		{HostCapabilityPartialDownloads, "partialdownloads"},
+		{HostCapabilityRenewal, "renewal"},
+		{HostCapabilityModify, "modify"},
+		{HostCapabilityTieredStorage, "tieredstorage"},
+	}
+
+	errUnknownHostCapability = errors.New("unknown host capability")
+)
+
+// Has returns true if hc includes every capability in want.
+func (hc HostCapabilities) Has(want HostCapabilities) bool {
+	return hc&want == want
+}
+
+// String returns the names of the capabilities in hc, separated by commas.
+// Flags without a name are shown by their bit number, such as "bit7".
+func (hc HostCapabilities) String() string {
+	var names []string
+	for _, c := range hostCapabilityNames {
+		if hc.Has(c.flag) {
+			names = append(names, c.name)
+			hc &^= c.flag
+		}
+	}
+	for bit := uint(0); hc != 0; bit++ {
+		if hc&(1<<bit) != 0 {
+			names = append(names, fmt.Sprintf("bit%d", bit))
+			hc &^= 1 << bit
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// ParseHostCapabilities parses a comma separated list of capability names,
+// as returned by HostCapabilities.String. Names are not case sensitive, and
+// an empty string has no capabilities.
+func ParseHostCapabilities(s string) (HostCapabilities, error) {
+	var hc HostCapabilities
+	for _, name := range strings.Split(s, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		var found bool
+		for _, c := range hostCapabilityNames {
+			if c.name == name {
+				hc |= c.flag
+				found = true
+				break
+			}
+		}
+		var bit uint
+		if !found {
+			if _, err := fmt.Sscanf(name, "bit%d", &bit); err != nil || bit > 63 || fmt.Sprintf("bit%d", bit) != name {
+				return 0, fmt.Errorf("%v: %q", errUnknownHostCapability, name)
+			}
+			hc |= 1 << bit
+		}
+	}
+	return hc, nil
+}
+
+// UnmarshalSia implements the encoding.SiaUnmarshaler interface. The
+// capabilities are the last field of the host's external settings, and hosts
+// that predate them send settings without the field, so a missing field is
+// read as no capabilities.
+func (hc *HostCapabilities) UnmarshalSia(r io.Reader) error {
+	b := make([]byte, 8)
+	_, err := io.ReadFull(r, b)
+	if err == io.EOF {
+		*hc = 0
+		return nil
+	} else if err != nil {
+		return err
+	}
+	*hc = HostCapabilities(encoding.DecUint64(b))
+	return nil
+}
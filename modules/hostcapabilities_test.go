@@ -0,0 +1,66 @@
+package modules
+
+import (
+	"testing"
+
+	"github.com/NebulousLabs/Sia/encoding"
+)
+
+// TestHostCapabilitiesNames checks that capabilities are converted to and
+// from their names, including flags that have no name.
+func TestHostCapabilitiesNames(t *testing.T) {
+	hc := HostCapabilityPartialDownloads | HostCapabilityModify | 1<<40
+	if s := hc.String(); s != "partialdownloads,modify,bit40" {
+		t.Fatal("wrong names:", s)
+	}
+	parsed, err := ParseHostCapabilities(" Modify, partialdownloads,bit40,")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed != hc {
+		t.Fatal("parsed the wrong capabilities:", parsed)
+	}
+	if !SupportedHostCapabilities.Has(HostCapabilityRenewal|HostCapabilityModify) || hc.Has(HostCapabilityRenewal) {
+		t.Fatal("Has reported the wrong capabilities")
+	}
+	if parsed, err := ParseHostCapabilities(""); err != nil || parsed != 0 {
+		t.Fatal("expected no capabilities from an empty string:", parsed, err)
+	}
+	for _, s := range []string{"accounts", "bit64", "bit1x", "bit01"} {
+		if _, err := ParseHostCapabilities(s); err == nil {
+			t.Errorf("expected an error parsing %q", s)
+		}
+	}
+}
+
+// TestHostCapabilitiesEncoding checks that settings without capabilities,
+// as sent by older hosts, can still be decoded.
+func TestHostCapabilitiesEncoding(t *testing.T) {
+	hes := HostExternalSettings{
+		NetAddress:   "foo.com:1234",
+		Version:      "1.0.0",
+		Capabilities: SupportedHostCapabilities | 1<<63,
+	}
+	b := encoding.Marshal(hes)
+	var decoded HostExternalSettings
+	if err := encoding.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Capabilities != hes.Capabilities || decoded.Version != hes.Version {
+		t.Fatalf("settings did not survive encoding: %+v", decoded)
+	}
+
+	// Settings from an older host end after Version.
+	decoded = HostExternalSettings{Capabilities: HostCapabilityModify}
+	if err := encoding.Unmarshal(b[:len(b)-8], &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Capabilities != 0 || decoded.Version != hes.Version {
+		t.Fatalf("settings of an older host were decoded wrong: %+v", decoded)
+	}
+
+	// A partial field is still an error.
+	if err := encoding.Unmarshal(b[:len(b)-4], &decoded); err == nil {
+		t.Fatal("expected an error decoding truncated capabilities")
+	}
+}
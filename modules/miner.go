@@ -12,6 +12,13 @@ const (
 	MinerDir = "miner"
 )
 
+// MinerPayoutSplit directs a fraction of the reward of each mined block to an
+// address.
+type MinerPayoutSplit struct {
+	UnlockHash types.UnlockHash `json:"unlockhash"`
+	Fraction   float64          `json:"fraction"`
+}
+
 // BlockManager contains functions that can interface with external miners,
 // providing and receiving blocks that have experienced nonce grinding.
 type BlockManager interface {
@@ -77,4 +84,14 @@ type Miner interface {
 	BlockManager
 	CPUMiner
 	io.Closer
+
+	// PayoutSplit returns the addresses that the rewards of mined blocks are
+	// split between. If the split is empty, the whole reward goes to an
+	// address of the wallet.
+	PayoutSplit() []MinerPayoutSplit
+
+	// SetPayoutSplit sets the addresses that the rewards of mined blocks are
+	// split between. The fractions must be positive and sum to 1. An empty
+	// split sends the whole reward to an address of the wallet.
+	SetPayoutSplit([]MinerPayoutSplit) error
 }
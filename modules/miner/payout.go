@@ -0,0 +1,81 @@
+package miner
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+var (
+	errBadSplitFraction = errors.New("payout split fractions must be greater than 0 and at most 1")
+	errBadSplitSum      = errors.New("payout split fractions must sum to 1")
+)
+
+// minerPayouts splits a block subsidy between the addresses of the payout
+// split, or pays all of it to the miner's address if there is no split.
+// Rounding errors are absorbed by the last address, so that the payouts always
+// sum to exactly the subsidy. Payouts that round down to zero are left out, as
+// consensus rejects blocks with empty payouts.
+func (m *Miner) minerPayouts(subsidy types.Currency) []types.SiacoinOutput {
+	if len(m.persist.PayoutSplit) == 0 {
+		return []types.SiacoinOutput{{Value: subsidy, UnlockHash: m.persist.Address}}
+	}
+
+	var payouts []types.SiacoinOutput
+	remaining := subsidy
+	for i, split := range m.persist.PayoutSplit {
+		value := remaining
+		if i != len(m.persist.PayoutSplit)-1 {
+			// Use the shortest decimal form of the fraction, so that 0.1 is
+			// exactly one tenth.
+			fraction, _ := new(big.Rat).SetString(strconv.FormatFloat(split.Fraction, 'g', -1, 64))
+			value = subsidy.MulRat(fraction)
+			if value.Cmp(remaining) > 0 {
+				value = remaining
+			}
+		}
+		remaining = remaining.Sub(value)
+		if value.IsZero() {
+			continue
+		}
+		payouts = append(payouts, types.SiacoinOutput{Value: value, UnlockHash: split.UnlockHash})
+	}
+	return payouts
+}
+
+// PayoutSplit returns the addresses that the rewards of mined blocks are split
+// between.
+func (m *Miner) PayoutSplit() []modules.MinerPayoutSplit {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]modules.MinerPayoutSplit(nil), m.persist.PayoutSplit...)
+}
+
+// SetPayoutSplit sets the addresses that the rewards of mined blocks are split
+// between. Blocks that are already being worked on keep their old payouts.
+func (m *Miner) SetPayoutSplit(split []modules.MinerPayoutSplit) error {
+	if err := m.tg.Add(); err != nil {
+		return err
+	}
+	defer m.tg.Done()
+
+	var sum float64
+	for _, s := range split {
+		if !(s.Fraction > 0 && s.Fraction <= 1) {
+			return errBadSplitFraction
+		}
+		sum += s.Fraction
+	}
+	if len(split) > 0 && math.Abs(sum-1) > 1e-9 {
+		return errBadSplitSum
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.persist.PayoutSplit = append([]modules.MinerPayoutSplit(nil), split...)
+	return m.saveSync()
+}
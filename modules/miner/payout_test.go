@@ -0,0 +1,78 @@
+package miner
+
+import (
+	"testing"
+
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// TestIntegrationPayoutSplit checks that mined blocks split their reward
+// between the addresses of the payout split, and that the blocks are accepted
+// by consensus.
+func TestIntegrationPayoutSplit(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	mt, err := createMinerTester("TestIntegrationPayoutSplit")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bad := [][]modules.MinerPayoutSplit{
+		{{UnlockHash: types.UnlockHash{1}, Fraction: 0.5}},
+		{{UnlockHash: types.UnlockHash{1}, Fraction: 0}, {UnlockHash: types.UnlockHash{2}, Fraction: 1}},
+		{{UnlockHash: types.UnlockHash{1}, Fraction: -0.5}, {UnlockHash: types.UnlockHash{2}, Fraction: 1.5}},
+	}
+	for _, split := range bad {
+		if err := mt.miner.SetPayoutSplit(split); err == nil {
+			t.Errorf("expected an error for %v", split)
+		}
+	}
+
+	split := []modules.MinerPayoutSplit{
+		{UnlockHash: types.UnlockHash{1}, Fraction: 0.1},
+		{UnlockHash: types.UnlockHash{2}, Fraction: 0.2},
+		{UnlockHash: types.UnlockHash{3}, Fraction: 0.7},
+	}
+	if err := mt.miner.SetPayoutSplit(split); err != nil {
+		t.Fatal(err)
+	}
+	if got := mt.miner.PayoutSplit(); len(got) != len(split) || got[2] != split[2] {
+		t.Fatal("payout split was not set:", got)
+	}
+
+	b, err := mt.miner.AddBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b.MinerPayouts) != len(split) {
+		t.Fatal("expected 3 miner payouts, got", len(b.MinerPayouts))
+	}
+	var sum types.Currency
+	for i, payout := range b.MinerPayouts {
+		if payout.UnlockHash != split[i].UnlockHash {
+			t.Error("payout", i, "went to the wrong address")
+		}
+		sum = sum.Add(payout.Value)
+	}
+	subsidy := b.CalculateSubsidy(mt.cs.Height())
+	if sum.Cmp(subsidy) != 0 {
+		t.Fatal("payouts do not sum to the subsidy")
+	}
+	if b.MinerPayouts[0].Value.Cmp(subsidy.Div64(10)) != 0 {
+		t.Error("first payout is not a tenth of the subsidy")
+	}
+
+	// Clearing the split pays the wallet again.
+	if err := mt.miner.SetPayoutSplit(nil); err != nil {
+		t.Fatal(err)
+	}
+	b, err = mt.miner.AddBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b.MinerPayouts) != 1 || b.MinerPayouts[0].UnlockHash == split[0].UnlockHash {
+		t.Fatal("block was not paid to the wallet")
+	}
+}
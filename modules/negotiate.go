@@ -263,6 +263,11 @@ type (
 		// which is the most recent.
 		RevisionNumber uint64 `json:"revisionnumber"`
 		Version        string `json:"version"`
+
+		// Capabilities are the optional services that the host offers. The
+		// field must stay last, since settings from hosts that predate it end
+		// after Version.
+		Capabilities HostCapabilities `json:"capabilities"`
 	}
 
 	// A RevisionAction is a description of an edit to be performed on a file
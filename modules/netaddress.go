@@ -45,6 +45,32 @@ func (na NetAddress) Port() string {
 	return port
 }
 
+// IsIPv6 returns true if the host of the NetAddress is an IPv6 address.
+// IPv4-mapped IPv6 addresses (e.g. "[::ffff:1.2.3.4]:9981") are treated as
+// IPv4, since they are reached over IPv4.
+func (na NetAddress) IsIPv6() bool {
+	ip := net.ParseIP(na.Host())
+	return ip != nil && ip.To4() == nil
+}
+
+// Subnet returns the network that the host of the NetAddress belongs to, in
+// CIDR notation. Addresses in the same subnet are likely to be controlled by
+// the same operator. IPv4 hosts are grouped by their /16, and IPv6 hosts by
+// their /32, which is the smallest block usually assigned to a provider. The
+// empty string is returned if the host is not an IP address.
+func (na NetAddress) Subnet() string {
+	ip := net.ParseIP(na.Host())
+	if ip == nil {
+		return ""
+	}
+	mask := net.CIDRMask(32, 8*net.IPv6len)
+	if ip4 := ip.To4(); ip4 != nil {
+		ip, mask = ip4, net.CIDRMask(16, 8*net.IPv4len)
+	}
+	subnet := net.IPNet{IP: ip.Mask(mask), Mask: mask}
+	return subnet.String()
+}
+
 // IsLoopback returns true for IP addresses that are on the same machine.
 func (na NetAddress) IsLoopback() bool {
 	host, _, err := net.SplitHostPort(string(na))
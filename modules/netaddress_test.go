@@ -177,6 +177,37 @@ func TestIsLoopback(t *testing.T) {
 	}
 }
 
+// TestSubnet tests the IsIPv6 and Subnet methods of the NetAddress type.
+func TestSubnet(t *testing.T) {
+	t.Parallel()
+
+	testSet := []struct {
+		query  NetAddress
+		ipv6   bool
+		subnet string
+	}{
+		{"12.34.45.64:7777", false, "12.34.0.0/16"},
+		{"12.34.200.1:7777", false, "12.34.0.0/16"},
+		{"[::ffff:12.34.45.64]:7777", false, "12.34.0.0/16"},
+		{"[2001:db8:1234::1]:7777", true, "2001:db8::/32"},
+		{"[2001:db8:ffff:1::2]:7777", true, "2001:db8::/32"},
+		{"[::1]:7124", true, "::/32"},
+
+		// Hostnames and garbage have no subnet.
+		{"hn.com:8811", false, ""},
+		{"2001:db8::1", false, ""},
+		{"", false, ""},
+	}
+	for _, test := range testSet {
+		if test.query.IsIPv6() != test.ipv6 {
+			t.Error("IsIPv6 failed:", test, test.query.IsIPv6())
+		}
+		if test.query.Subnet() != test.subnet {
+			t.Error("Subnet failed:", test, test.query.Subnet())
+		}
+	}
+}
+
 // TestIsValid tests that IsValid only returns nil for valid addresses.
 func TestIsValid(t *testing.T) {
 	t.Parallel()
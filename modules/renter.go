@@ -42,6 +42,10 @@ type FileUploadParams struct {
 	Source      string
 	SiaPath     string
 	ErasureCode ErasureCoder
+
+	// DeleteAfterSuccess deletes Source once every piece of the file has
+	// been uploaded.
+	DeleteAfterSuccess bool
 }
 
 // FileInfo provides information about a file.
@@ -53,6 +57,45 @@ type FileInfo struct {
 	Redundancy     float64           `json:"redundancy"`
 	UploadProgress float64           `json:"uploadprogress"`
 	Expiration     types.BlockHeight `json:"expiration"`
+
+	// Cost is an estimate of the amount spent on the file, apportioned from
+	// the spending of the contracts that store its pieces. UploadTime is the
+	// time at which the upload was started, and is zero for files that were
+	// loaded rather than uploaded.
+	Cost       types.Currency `json:"cost"`
+	UploadTime time.Time      `json:"uploadtime"`
+
+	// DeleteAfterSuccess indicates that the local source of the file will be
+	// deleted once every piece has been uploaded, and SourceDeleted that it
+	// has been.
+	DeleteAfterSuccess bool `json:"deleteaftersuccess"`
+	SourceDeleted      bool `json:"sourcedeleted"`
+}
+
+// A FileVerification is the result of downloading a file in full, without
+// writing it anywhere, to confirm that it can be recovered. Elapsed is in
+// seconds. SourceChecked indicates that the downloaded data was also compared
+// to the local copy of the file, which is only done if the local copy has not
+// changed since the upload started.
+type FileVerification struct {
+	SiaPath       string                 `json:"siapath"`
+	Success       bool                   `json:"success"`
+	Error         string                 `json:"error,omitempty"`
+	Filesize      uint64                 `json:"filesize"`
+	BytesVerified uint64                 `json:"bytesverified"`
+	Hash          crypto.Hash            `json:"hash"`
+	SourceChecked bool                   `json:"sourcechecked"`
+	StartTime     time.Time              `json:"starttime"`
+	Elapsed       float64                `json:"elapsed"`
+	Hosts         []FileVerificationHost `json:"hosts"`
+}
+
+// A FileVerificationHost reports how many pieces a host contributed to a file
+// verification, and how many it failed to provide.
+type FileVerificationHost struct {
+	NetAddress   NetAddress `json:"netaddress"`
+	Pieces       uint64     `json:"pieces"`
+	FailedPieces uint64     `json:"failedpieces"`
 }
 
 // DownloadInfo provides information about a file that has been requested for
@@ -65,18 +108,84 @@ type DownloadInfo struct {
 	StartTime   time.Time `json:"starttime"`
 }
 
+// DownloadAccess records a single download of a file, as stored in the
+// renter's access log.
+type DownloadAccess struct {
+	SiaPath     string    `json:"siapath"`
+	Timestamp   time.Time `json:"timestamp"`
+	Length      uint64    `json:"length"`
+	BytesServed uint64    `json:"bytesserved"`
+	Completed   bool      `json:"completed"`
+}
+
+const (
+	// AllowanceExpiryRenew renews the renter's contracts with the full
+	// allowance at the end of each period. It is the default expiry policy.
+	AllowanceExpiryRenew = "renew"
+
+	// AllowanceExpiryRenewReduced renews the renter's contracts at the end of
+	// each period, but only with enough funds to keep the data already stored
+	// in them.
+	AllowanceExpiryRenewReduced = "renewreduced"
+
+	// AllowanceExpiryStop lets the renter's contracts expire at the end of the
+	// period without renewing or replacing them.
+	AllowanceExpiryStop = "stop"
+)
+
 // An Allowance dictates how much the Renter is allowed to spend in a given
 // period. Note that funds are spent on both storage and bandwidth.
+// ExpiryPolicy controls what happens to the renter's contracts at the end of
+// the period; the empty string means AllowanceExpiryRenew.
 type Allowance struct {
-	Funds       types.Currency    `json:"funds"`
-	Hosts       uint64            `json:"hosts"`
-	Period      types.BlockHeight `json:"period"`
-	RenewWindow types.BlockHeight `json:"renewwindow"`
+	Funds        types.Currency    `json:"funds"`
+	Hosts        uint64            `json:"hosts"`
+	Period       types.BlockHeight `json:"period"`
+	RenewWindow  types.BlockHeight `json:"renewwindow"`
+	ExpiryPolicy string            `json:"expirypolicy"`
 }
 
 // RenterSettings control the behavior of the Renter.
 type RenterSettings struct {
 	Allowance Allowance `json:"allowance"`
+
+	// AccessLogging enables recording each download in the renter's access
+	// log.
+	AccessLogging bool `json:"accesslogging"`
+}
+
+// A RenterWorker is one of the workers that the renter uses to upload pieces
+// to hosts concurrently. Status is either "idle" or "busy"; a busy worker
+// reports the host it is uploading to, and the number of jobs waiting for a
+// worker to upload to that host.
+type RenterWorker struct {
+	ID         int        `json:"id"`
+	Status     string     `json:"status"`
+	Host       NetAddress `json:"host"`
+	QueuedJobs int        `json:"queuedjobs"`
+}
+
+// RenterWorkerPool describes the size of the renter's worker pool and the
+// state of its workers.
+type RenterWorkerPool struct {
+	Size       int            `json:"size"`
+	QueuedJobs int            `json:"queuedjobs"`
+	Workers    []RenterWorker `json:"workers"`
+}
+
+// A RenterUploadEstimate projects how long an upload will take from the
+// throughput recently measured to the renter's hosts. TotalBytes is the
+// amount of data that will be sent, including redundancy. Optimistic and
+// Pessimistic bound the duration, in seconds. Hosts is the number of hosts
+// with measured throughput, and Workers the number of pieces that are
+// expected to be uploaded concurrently.
+type RenterUploadEstimate struct {
+	TotalBytes  uint64  `json:"totalbytes"`
+	Redundancy  float64 `json:"redundancy"`
+	Optimistic  uint64  `json:"optimistic"`
+	Pessimistic uint64  `json:"pessimistic"`
+	Hosts       int     `json:"hosts"`
+	Workers     int     `json:"workers"`
 }
 
 // RenterFinancialMetrics contains metrics about how much the Renter has
@@ -101,6 +210,59 @@ type HostDBEntry struct {
 	PublicKey types.SiaPublicKey `json:"publickey"`
 }
 
+// A RenterHostUsage describes how much of the renter's data is stored on a
+// host. Bytes counts whole pieces, including the padding of each file's last
+// chunk. Share is the fraction of all of the renter's stored bytes that the
+// host holds. PublicKey is empty if the host is no longer in the hostdb.
+type RenterHostUsage struct {
+	NetAddress NetAddress         `json:"netaddress"`
+	PublicKey  types.SiaPublicKey `json:"publickey"`
+	Bytes      uint64             `json:"bytes"`
+	Pieces     uint64             `json:"pieces"`
+	Chunks     uint64             `json:"chunks"`
+	Files      int                `json:"files"`
+	Share      float64            `json:"share"`
+}
+
+// A RenterMigration describes the progress of moving all of the renter's data
+// off of a single host.
+type RenterMigration struct {
+	Host       types.SiaPublicKey `json:"host"`
+	NetAddress NetAddress         `json:"netaddress"`
+	Active     bool               `json:"active"`
+
+	// Files lists the files that had data stored on the host. SkippedFiles
+	// lists the files that could not be migrated because the renter does not
+	// have a local copy to upload from.
+	Files        []string `json:"files"`
+	SkippedFiles []string `json:"skippedfiles"`
+
+	// ChunksTotal is the number of chunks that had pieces on the host, and
+	// ChunksMigrated is the number of those chunks that no longer depend on
+	// the host.
+	ChunksTotal    int `json:"chunkstotal"`
+	ChunksMigrated int `json:"chunksmigrated"`
+}
+
+// A RenterLostFile is a file that would become unrecoverable if a set of hosts
+// failed. LostChunks of its TotalChunks chunks would have fewer pieces left
+// than the erasure code needs. Redundancy is the redundancy of the file's
+// least redundant chunk on the surviving hosts.
+type RenterLostFile struct {
+	SiaPath     string  `json:"siapath"`
+	LostChunks  uint64  `json:"lostchunks"`
+	TotalChunks uint64  `json:"totalchunks"`
+	Redundancy  float64 `json:"redundancy"`
+}
+
+// A RenterFailureSimulation lists the files that would be lost if every host
+// in Hosts failed at once.
+type RenterFailureSimulation struct {
+	Hosts        []NetAddress     `json:"hosts"`
+	FilesChecked int              `json:"fileschecked"`
+	LostFiles    []RenterLostFile `json:"lostfiles"`
+}
+
 // A RenterContract contains all the metadata necessary to revise or renew a
 // file contract.
 type RenterContract struct {
@@ -111,6 +273,21 @@ type RenterContract struct {
 	MerkleRoots     []crypto.Hash              `json:"merkleroots"`
 	NetAddress      NetAddress                 `json:"netaddress"`
 	SecretKey       crypto.SecretKey           `json:"secretkey"`
+
+	// StartHeight is the height at which the contract was formed or renewed.
+	// It is zero for contracts formed before it was recorded.
+	StartHeight types.BlockHeight `json:"startheight"`
+
+	// MaxCollateral is the host's MaxCollateral when the contract was formed
+	// or renewed, which caps the collateral that the host adds in revisions.
+	// It is zero for contracts formed before it was recorded, which are
+	// capped by the host's current setting.
+	MaxCollateral types.Currency `json:"maxcollateral"`
+
+	// Retired contracts are no longer used for new uploads and are not
+	// renewed. Data already stored under the contract can still be
+	// downloaded until the contract expires.
+	Retired bool `json:"retired"`
 }
 
 // EndHeight returns the height at which the host is no longer obligated to
@@ -125,9 +302,53 @@ func (rc *RenterContract) RenterFunds() types.Currency {
 	return rc.LastRevision.NewValidProofOutputs[0].Value
 }
 
+// A RenterExpiredContract is a contract that has passed its end height, along
+// with a summary of how its funds were spent.
+type RenterExpiredContract struct {
+	ID         types.FileContractID `json:"id"`
+	NetAddress NetAddress           `json:"netaddress"`
+	EndHeight  types.BlockHeight    `json:"endheight"`
+
+	// Funds is the amount the renter put into the contract. Spent is the
+	// amount paid to the host for storage and bandwidth, and Refunded is the
+	// remainder that is returned to the renter.
+	Funds    types.Currency `json:"funds"`
+	Spent    types.Currency `json:"spent"`
+	Refunded types.Currency `json:"refunded"`
+
+	// DependentFiles are the files with pieces that were stored under the
+	// contract and have not been uploaded to another host. The contract
+	// cannot be cleared while any files depend on it.
+	DependentFiles []string `json:"dependentfiles"`
+}
+
+// A RenterRedundancyChange describes the work caused by changing the
+// redundancy of a file. Sizes are in bytes.
+type RenterRedundancyChange struct {
+	SiaPath         string `json:"siapath"`
+	DataPieces      int    `json:"datapieces"`
+	OldParityPieces int    `json:"oldparitypieces"`
+	NewParityPieces int    `json:"newparitypieces"`
+
+	// PiecesToUpload are the parity pieces that will be uploaded to new
+	// hosts by the repair loop.
+	PiecesToUpload uint64 `json:"piecestoupload"`
+	UploadSize     uint64 `json:"uploadsize"`
+
+	// ExcessPieces are the uploaded pieces that are no longer needed. They
+	// are deleted from their hosts in the background.
+	ExcessPieces uint64 `json:"excesspieces"`
+	ExcessSize   uint64 `json:"excesssize"`
+}
+
 // A Renter uploads, tracks, repairs, and downloads a set of files for the
 // user.
 type Renter interface {
+	// AccessLog returns the recorded downloads of the file at the given path,
+	// or of all files if the path is empty. Downloads are only recorded
+	// while access logging is enabled in the renter's settings.
+	AccessLog(siapath string) []DownloadAccess
+
 	// ActiveHosts provides the list of hosts that the renter is selecting,
 	// sorted by preference.
 	ActiveHosts() []HostDBEntry
@@ -147,15 +368,46 @@ type Renter interface {
 	// Download downloads a file to the given destination.
 	Download(path, destination string) error
 
+	// DownloadCost estimates the cost of downloading length bytes of the
+	// file at path, starting at offset. A length of zero covers the rest of
+	// the file.
+	DownloadCost(path string, offset, length uint64) (types.Currency, error)
+
+	// VerifyFile downloads the file at path in full and discards the data,
+	// confirming that the file can be recovered from its hosts.
+	VerifyFile(path string) (FileVerification, error)
+
 	// DownloadQueue lists all the files that have been scheduled for download.
 	DownloadQueue() []DownloadInfo
 
+	// EstimateUpload estimates how long it would take to upload a file of
+	// the given size at the given redundancy. A redundancy of zero uses the
+	// default erasure code.
+	EstimateUpload(size uint64, redundancy float64) (RenterUploadEstimate, error)
+
+	// ExpiredContracts returns the contracts that have passed their end
+	// height and have not yet been cleared.
+	ExpiredContracts() []RenterExpiredContract
+
+	// ClearExpiredContracts forgets the expired contracts that no files
+	// depend on, and returns the ids of the contracts that were cleared.
+	ClearExpiredContracts() ([]types.FileContractID, error)
+
+	// ExpiryWarning returns a description of what the expiry policy of the
+	// allowance will do at the end of the current period, or the empty
+	// string if the end of the period is not near.
+	ExpiryWarning() string
+
 	// FileList returns information on all of the files stored by the renter.
 	FileList() []FileInfo
 
 	// FinancialMetrics returns the financial metrics of the Renter.
 	FinancialMetrics() RenterFinancialMetrics
 
+	// HostUsage returns how much of the renter's data is stored on each
+	// host, sorted by the number of bytes stored, largest first.
+	HostUsage() []RenterHostUsage
+
 	// LoadSharedFiles loads a '.sia' file into the renter. A .sia file may
 	// contain multiple files. The paths of the added files are returned.
 	LoadSharedFiles(source string) ([]string, error)
@@ -164,15 +416,36 @@ type Renter interface {
 	// renter.
 	LoadSharedFilesAscii(asciiSia string) ([]string, error)
 
+	// MigrateHost starts moving every piece stored on the host with the
+	// given public key to other hosts. The contract with the host is retired
+	// so that it is not used for new uploads or renewed.
+	MigrateHost(types.SiaPublicKey) error
+
+	// Migration returns the progress of the most recent host migration.
+	Migration() RenterMigration
+
 	// RenameFile changes the path of a file.
 	RenameFile(path, newPath string) error
 
+	// SimulateHostFailure reports which files would become unrecoverable
+	// if the hosts with the given public keys all failed at once. Nothing
+	// is changed.
+	SimulateHostFailure(hosts []types.SiaPublicKey) (RenterFailureSimulation, error)
+
 	// Settings returns the Renter's current settings.
 	Settings() RenterSettings
 
+	// SetFileRedundancy changes the number of parity pieces of a file so
+	// that it reaches the given redundancy, reusing the pieces that have
+	// already been uploaded.
+	SetFileRedundancy(path string, redundancy float64) (RenterRedundancyChange, error)
+
 	// SetSettings sets the Renter's settings.
 	SetSettings(RenterSettings) error
 
+	// SetWorkerPool sets the number of workers that upload pieces to hosts.
+	SetWorkerPool(size int) error
+
 	// ShareFiles creates a '.sia' file that can be shared with others.
 	ShareFiles(paths []string, shareDest string) error
 
@@ -181,4 +454,13 @@ type Renter interface {
 
 	// Upload uploads a file using the input parameters.
 	Upload(FileUploadParams) error
+
+	// UploadURL fetches the content at a remote URL and uploads it to
+	// siaPath. At most maxSize bytes are fetched, unless maxSize is zero, and
+	// the fetch is abandoned after timeout.
+	UploadURL(url, siaPath string, maxSize uint64, timeout time.Duration) error
+
+	// WorkerPool returns the limits of the renter's worker pool and the state
+	// of each of its workers.
+	WorkerPool() RenterWorkerPool
 }
@@ -0,0 +1,97 @@
+package renter
+
+import (
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/persist"
+)
+
+const (
+	accessLogFilename = "accesslog.json"
+
+	// maxAccessLogEntries is the number of downloads that the access log
+	// keeps. Once the log is full, the oldest entries are discarded.
+	maxAccessLogEntries = 1000
+
+	// accessLogSaveInterval is the least time between saves of the access
+	// log. Downloads recorded in between are saved with the next download
+	// after the interval has passed, or when the renter is closed.
+	accessLogSaveInterval = time.Minute
+)
+
+var accessLogMetadata = persist.Metadata{
+	Header:  "Renter Access Log",
+	Version: "1.0",
+}
+
+// logAccess appends a record of the download to the access log, if access
+// logging is enabled. The access log is bounded to maxAccessLogEntries, and
+// is saved at most once per accessLogSaveInterval. Downloads always fetch the
+// whole file, so the length of the download is the size of the file.
+func (r *Renter) logAccess(d *download, downloadErr error) {
+	if !r.accessLogging {
+		return
+	}
+	entry := modules.DownloadAccess{
+		SiaPath:     d.siapath,
+		Timestamp:   d.startTime,
+		Length:      d.fileSize,
+		BytesServed: atomic.LoadUint64(&d.received),
+		Completed:   downloadErr == nil,
+	}
+	r.accessLog = append(r.accessLog, entry)
+	if len(r.accessLog) > maxAccessLogEntries {
+		r.accessLog = r.accessLog[len(r.accessLog)-maxAccessLogEntries:]
+	}
+	r.accessLogUnsaved = true
+	if time.Since(r.accessLogSaved) < accessLogSaveInterval {
+		return
+	}
+	if err := r.saveAccessLog(); err != nil {
+		r.log.Println("WARN: could not save access log:", err)
+	}
+}
+
+// flushAccessLog saves the access log if it has entries that have not been
+// saved.
+func (r *Renter) flushAccessLog() error {
+	if !r.accessLogUnsaved {
+		return nil
+	}
+	return r.saveAccessLog()
+}
+
+// saveAccessLog writes the access log to disk.
+func (r *Renter) saveAccessLog() error {
+	err := persist.SaveFile(accessLogMetadata, r.accessLog, filepath.Join(r.persistDir, accessLogFilename))
+	if err != nil {
+		return err
+	}
+	r.accessLogSaved = time.Now()
+	r.accessLogUnsaved = false
+	return nil
+}
+
+// loadAccessLog reads the access log from disk.
+func (r *Renter) loadAccessLog() error {
+	return persist.LoadFile(accessLogMetadata, &r.accessLog, filepath.Join(r.persistDir, accessLogFilename))
+}
+
+// AccessLog returns the recorded downloads of the file at siapath, ordered
+// from oldest to newest. If siapath is empty, the downloads of all files are
+// returned.
+func (r *Renter) AccessLog(siapath string) []modules.DownloadAccess {
+	lockID := r.mu.RLock()
+	defer r.mu.RUnlock(lockID)
+
+	entries := []modules.DownloadAccess{}
+	for _, entry := range r.accessLog {
+		if siapath == "" || entry.SiaPath == siapath {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
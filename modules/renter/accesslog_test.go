@@ -0,0 +1,103 @@
+package renter
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestAccessLog checks that downloads are only recorded while access logging
+// is enabled, that the log is bounded, and that it persists across reloads
+// once flushed.
+func TestAccessLog(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	rt, err := newRenterTester("TestAccessLog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rt.Close()
+
+	d := &download{
+		received:  100,
+		startTime: time.Now(),
+		siapath:   "foo",
+		fileSize:  100,
+	}
+
+	// Access logging is disabled by default.
+	id := rt.renter.mu.Lock()
+	rt.renter.logAccess(d, nil)
+	rt.renter.mu.Unlock(id)
+	if len(rt.renter.AccessLog("")) != 0 {
+		t.Fatal("download was recorded while access logging was disabled")
+	}
+
+	settings := rt.renter.Settings()
+	settings.AccessLogging = true
+	if err := rt.renter.SetSettings(settings); err != nil {
+		t.Fatal(err)
+	}
+	if !rt.renter.Settings().AccessLogging {
+		t.Fatal("access logging was not enabled")
+	}
+
+	id = rt.renter.mu.Lock()
+	rt.renter.logAccess(d, nil)
+	d.siapath = "bar"
+	d.received = 50
+	rt.renter.logAccess(d, errors.New("failed"))
+	rt.renter.mu.Unlock(id)
+
+	if len(rt.renter.AccessLog("")) != 2 {
+		t.Fatal("expected 2 entries, got", len(rt.renter.AccessLog("")))
+	}
+	entries := rt.renter.AccessLog("bar")
+	if len(entries) != 1 {
+		t.Fatal("expected 1 entry for 'bar', got", len(entries))
+	}
+	if entries[0].BytesServed != 50 || entries[0].Length != 100 || entries[0].Completed {
+		t.Error("entry was recorded incorrectly:", entries[0])
+	}
+
+	// Only the first download was saved right away; the second waits for
+	// the next save.
+	id = rt.renter.mu.Lock()
+	unsaved := rt.renter.accessLogUnsaved
+	err = rt.renter.flushAccessLog()
+	rt.renter.mu.Unlock(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !unsaved {
+		t.Error("second download was saved within the save interval")
+	}
+
+	// The log and the setting should survive a reload.
+	id = rt.renter.mu.Lock()
+	rt.renter.accessLog = nil
+	rt.renter.accessLogging = false
+	err = rt.renter.load()
+	rt.renter.mu.Unlock(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rt.renter.Settings().AccessLogging {
+		t.Error("access logging setting was not persisted")
+	}
+	if len(rt.renter.AccessLog("")) != 2 {
+		t.Error("access log was not persisted")
+	}
+
+	// The log should not grow beyond maxAccessLogEntries.
+	id = rt.renter.mu.Lock()
+	for i := 0; i < maxAccessLogEntries; i++ {
+		rt.renter.accessLog = append(rt.renter.accessLog, rt.renter.accessLog[0])
+	}
+	rt.renter.logAccess(d, nil)
+	rt.renter.mu.Unlock(id)
+	if len(rt.renter.AccessLog("")) != maxAccessLogEntries {
+		t.Error("access log exceeded maxAccessLogEntries:", len(rt.renter.AccessLog("")))
+	}
+}
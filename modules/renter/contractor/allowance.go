@@ -13,6 +13,7 @@ var (
 	errAllowanceNoHosts    = errors.New("hosts must be non-zero")
 	errAllowanceZeroPeriod = errors.New("period must be non-zero")
 	errAllowanceWindowSize = errors.New("renew window must be less than period")
+	errAllowanceExpiry     = errors.New("expiry policy must be 'renew', 'renewreduced', or 'stop'")
 
 	// ErrAllowanceZeroWindow is returned when the caller requests a
 	// zero-length renewal window. This will happen if the caller sets the
@@ -66,6 +67,11 @@ func (c *Contractor) SetAllowance(a modules.Allowance) error {
 	} else if a.RenewWindow >= a.Period {
 		return errAllowanceWindowSize
 	}
+	switch a.ExpiryPolicy {
+	case "", modules.AllowanceExpiryRenew, modules.AllowanceExpiryRenewReduced, modules.AllowanceExpiryStop:
+	default:
+		return errAllowanceExpiry
+	}
 
 	// check that allowance is sufficient to store at least one sector
 	numSectors, err := maxSectors(a, c.hdb)
@@ -99,6 +105,9 @@ func (c *Contractor) SetAllowance(a modules.Allowance) error {
 	// gather contracts to renew
 	var renewSet []modules.RenterContract
 	for _, contract := range c.contracts {
+		if contract.Retired {
+			continue
+		}
 		renewSet = append(renewSet, contract)
 	}
 
@@ -166,7 +175,16 @@ func (c *Contractor) SetAllowance(a modules.Allowance) error {
 // need to be renewed when setting the allowance.
 func (c *Contractor) managedFormAllowanceContracts(n int, numSectors uint64, a modules.Allowance) error {
 	if n <= 0 {
-		return nil
+		// nothing to form, but settings such as the expiry policy may have
+		// changed
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		old := c.allowance
+		if a.Funds.Cmp(old.Funds) == 0 && a.Hosts == old.Hosts && a.Period == old.Period && a.RenewWindow == old.RenewWindow && a.ExpiryPolicy == old.ExpiryPolicy {
+			return nil
+		}
+		c.allowance = a
+		return c.saveSync()
 	}
 
 	// if we're forming contracts but not renewing, the new contracts should
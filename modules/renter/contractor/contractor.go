@@ -16,6 +16,8 @@ var (
 	errNilCS     = errors.New("cannot create contractor with nil consensus set")
 	errNilWallet = errors.New("cannot create contractor with nil wallet")
 	errNilTpool  = errors.New("cannot create contractor with nil transaction pool")
+
+	errUnknownContract = errors.New("no record of that contract")
 )
 
 // A cachedRevision contains changes that would be applied to a RenterContract
@@ -45,6 +47,17 @@ type Contractor struct {
 	lastChange      modules.ConsensusChangeID
 	renewHeight     types.BlockHeight // height at which to renew contracts
 
+	// expiryWarned is the end height of the most recent period that a warning
+	// about the expiry policy has been logged for. expiryWarning describes
+	// what the expiry policy will do while the end of the period is near, and
+	// is empty otherwise.
+	expiryWarned  types.BlockHeight
+	expiryWarning string
+
+	// expiredContracts holds contracts that have passed their end height
+	// until they are cleared by the user.
+	expiredContracts map[types.FileContractID]modules.RenterContract
+
 	financialMetrics modules.RenterFinancialMetrics
 
 	mu sync.RWMutex
@@ -86,6 +99,55 @@ func (c *Contractor) Contracts() (cs []modules.RenterContract) {
 	return
 }
 
+// RetireContract marks a contract as retired. Retired contracts are not
+// renewed, and the renter will not use them for new uploads.
+func (c *Contractor) RetireContract(id types.FileContractID) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	contract, ok := c.contracts[id]
+	if !ok {
+		return errUnknownContract
+	}
+	contract.Retired = true
+	c.contracts[id] = contract
+	return c.saveSync()
+}
+
+// ExpiredContracts returns the contracts that have passed their end height and
+// have not yet been cleared.
+func (c *Contractor) ExpiredContracts() (cs []modules.RenterContract) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, c := range c.expiredContracts {
+		cs = append(cs, c)
+	}
+	return
+}
+
+// ExpiryWarning returns a description of what the expiry policy of the
+// allowance will do at the end of the current period, or the empty string if
+// the end of the period is not near.
+func (c *Contractor) ExpiryWarning() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.expiryWarning
+}
+
+// ClearExpiredContracts forgets the expired contracts with the provided ids.
+func (c *Contractor) ClearExpiredContracts(ids []types.FileContractID) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, id := range ids {
+		if _, ok := c.expiredContracts[id]; !ok {
+			return errUnknownContract
+		}
+	}
+	for _, id := range ids {
+		delete(c.expiredContracts, id)
+	}
+	return c.saveSync()
+}
+
 // New returns a new Contractor.
 func New(cs consensusSet, wallet walletShim, tpool transactionPool, hdb hostDB, persistDir string) (*Contractor, error) {
 	// Check for nil inputs.
@@ -125,8 +187,9 @@ func newContractor(cs consensusSet, w wallet, tp transactionPool, hdb hostDB, p
 		tpool:   tp,
 		wallet:  w,
 
-		cachedRevisions: make(map[types.FileContractID]cachedRevision),
-		contracts:       make(map[types.FileContractID]modules.RenterContract),
+		cachedRevisions:  make(map[types.FileContractID]cachedRevision),
+		contracts:        make(map[types.FileContractID]modules.RenterContract),
+		expiredContracts: make(map[types.FileContractID]modules.RenterContract),
 	}
 
 	// Load the prior persistence structures.
@@ -67,7 +67,9 @@ func (c *Contractor) managedNewContract(host modules.HostDBEntry, numSectors uin
 	if host.StoragePrice.Cmp(maxStoragePrice) > 0 {
 		return modules.RenterContract{}, errTooExpensive
 	}
-	// cap host.MaxCollateral
+	// cap host.MaxCollateral, remembering the host's own cap, which is the
+	// one the host applies to revisions
+	hostMaxCollateral := host.MaxCollateral
 	if host.MaxCollateral.Cmp(maxCollateral) > 0 {
 		host.MaxCollateral = maxCollateral
 	}
@@ -97,6 +99,7 @@ func (c *Contractor) managedNewContract(host modules.HostDBEntry, numSectors uin
 		txnBuilder.Drop()
 		return modules.RenterContract{}, err
 	}
+	contract.MaxCollateral = hostMaxCollateral
 
 	contractValue := contract.RenterFunds()
 	c.log.Printf("Formed contract with %v for %v SC", host.NetAddress, contractValue.Div(types.SiacoinPrecision))
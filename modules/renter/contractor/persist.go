@@ -12,6 +12,7 @@ type contractorPersist struct {
 	BlockHeight      types.BlockHeight
 	CachedRevisions  []cachedRevision
 	Contracts        []modules.RenterContract
+	ExpiredContracts []modules.RenterContract
 	LastChange       modules.ConsensusChangeID
 	RenewHeight      types.BlockHeight
 	FinancialMetrics modules.RenterFinancialMetrics
@@ -32,6 +33,9 @@ func (c *Contractor) persistData() contractorPersist {
 	for _, contract := range c.contracts {
 		data.Contracts = append(data.Contracts, contract)
 	}
+	for _, contract := range c.expiredContracts {
+		data.ExpiredContracts = append(data.ExpiredContracts, contract)
+	}
 	return data
 }
 
@@ -50,6 +54,9 @@ func (c *Contractor) load() error {
 	for _, contract := range data.Contracts {
 		c.contracts[contract.ID] = contract
 	}
+	for _, contract := range data.ExpiredContracts {
+		c.expiredContracts[contract.ID] = contract
+	}
 	c.lastChange = data.LastChange
 	c.renewHeight = data.RenewHeight
 	c.financialMetrics = data.FinancialMetrics
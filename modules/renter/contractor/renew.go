@@ -20,7 +20,9 @@ func (c *Contractor) managedRenew(contract modules.RenterContract, numSectors ui
 	} else if host.StoragePrice.Cmp(maxStoragePrice) > 0 {
 		return modules.RenterContract{}, errTooExpensive
 	}
-	// cap host.MaxCollateral
+	// cap host.MaxCollateral, remembering the host's own cap, which is the
+	// one the host applies to revisions
+	hostMaxCollateral := host.MaxCollateral
 	if host.MaxCollateral.Cmp(maxCollateral) > 0 {
 		host.MaxCollateral = maxCollateral
 	}
@@ -50,17 +52,25 @@ func (c *Contractor) managedRenew(contract modules.RenterContract, numSectors ui
 		txnBuilder.Drop() // return unused outputs to wallet
 		return modules.RenterContract{}, err
 	}
+	newContract.MaxCollateral = hostMaxCollateral
 
 	return newContract, nil
 }
 
 // managedRenewContracts renews any contracts that are up for renewal, using
-// the current allowance.
+// the current allowance and expiry policy.
 func (c *Contractor) managedRenewContracts() error {
 	c.mu.RLock()
+	if c.allowance.ExpiryPolicy == modules.AllowanceExpiryStop {
+		c.mu.RUnlock()
+		return nil
+	}
 	// Renew contracts when they enter the renew window.
 	var renewSet []modules.RenterContract
 	for _, contract := range c.contracts {
+		if contract.Retired {
+			continue
+		}
 		if c.blockHeight+c.allowance.RenewWindow >= contract.EndHeight() {
 			renewSet = append(renewSet, contract)
 		}
@@ -73,6 +83,7 @@ func (c *Contractor) managedRenewContracts() error {
 
 	c.mu.RLock()
 	endHeight := c.blockHeight + c.allowance.Period
+	reduced := c.allowance.ExpiryPolicy == modules.AllowanceExpiryRenewReduced
 	numSectors, err := maxSectors(c.allowance, c.hdb)
 	c.mu.RUnlock()
 	if err != nil {
@@ -84,7 +95,11 @@ func (c *Contractor) managedRenewContracts() error {
 	// map old ID to new contract, for easy replacement later
 	newContracts := make(map[types.FileContractID]modules.RenterContract)
 	for _, contract := range renewSet {
-		newContract, err := c.managedRenew(contract, numSectors, endHeight)
+		sectors := numSectors
+		if reduced {
+			sectors = reducedSectors(contract, numSectors)
+		}
+		newContract, err := c.managedRenew(contract, sectors, endHeight)
 		if err != nil {
 			c.log.Printf("WARN: failed to renew contract with %v: %v", contract.NetAddress, err)
 		} else {
@@ -106,3 +121,17 @@ func (c *Contractor) managedRenewContracts() error {
 	c.mu.Unlock()
 	return err
 }
+
+// reducedSectors returns the number of sectors to renew a contract with under
+// the renewreduced expiry policy: enough to hold the data already stored in
+// the contract, but no more than the full allowance would pay for.
+func reducedSectors(contract modules.RenterContract, numSectors uint64) uint64 {
+	stored := uint64(len(contract.MerkleRoots))
+	if stored == 0 {
+		stored = 1
+	}
+	if stored > numSectors {
+		return numSectors
+	}
+	return stored
+}
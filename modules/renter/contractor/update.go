@@ -1,10 +1,17 @@
 package contractor
 
 import (
+	"fmt"
+
 	"github.com/NebulousLabs/Sia/modules"
 	"github.com/NebulousLabs/Sia/types"
 )
 
+// expiryWarningBlocks is how many blocks before the contractor acts on the
+// expiry policy that a warning describing the policy is given. 144 blocks is
+// about one day.
+const expiryWarningBlocks = 144
+
 // ProcessConsensusChange will be called by the consensus set every time there
 // is a change in the blockchain. Updates will always be called in order.
 func (c *Contractor) ProcessConsensusChange(cc modules.ConsensusChange) {
@@ -20,7 +27,8 @@ func (c *Contractor) ProcessConsensusChange(cc modules.ConsensusChange) {
 		}
 	}
 
-	// delete expired contracts
+	// move expired contracts out of the active set; they are kept until the
+	// user clears them
 	var expired []types.FileContractID
 	for id, contract := range c.contracts {
 		// TODO: offset this by some sort of confirmation height?
@@ -29,10 +37,13 @@ func (c *Contractor) ProcessConsensusChange(cc modules.ConsensusChange) {
 		}
 	}
 	for _, id := range expired {
+		c.expiredContracts[id] = c.contracts[id]
 		delete(c.contracts, id)
-		c.log.Debugln("INFO: deleted expired contract", id)
+		c.log.Debugln("INFO: contract expired", id)
 	}
 
+	c.warnExpiry()
+
 	c.lastChange = cc.ID
 	err := c.save()
 	if err != nil {
@@ -53,15 +64,61 @@ func (c *Contractor) ProcessConsensusChange(cc modules.ConsensusChange) {
 		c.mu.RLock()
 		a := c.allowance
 		remaining := int(a.Hosts) - len(c.contracts)
+		if a.ExpiryPolicy == modules.AllowanceExpiryStop && len(c.contracts) == 0 {
+			// the period has ended; don't replace the expired contracts
+			remaining = 0
+		}
 		numSectors, err := maxSectors(a, c.hdb)
 		c.mu.RUnlock()
 		if err != nil {
 			c.log.Debugln("ERROR: couldn't calculate numSectors:", err)
 			return
 		}
+		if remaining <= 0 {
+			// nothing to form; the allowance read above may already be
+			// stale, so it must not be written back
+			return
+		}
 		err = c.managedFormAllowanceContracts(remaining, numSectors, a)
 		if err != nil {
 			c.log.Debugln("WARN: failed to form contracts:", err)
 		}
 	}
 }
+
+// warnExpiry sets the expiry warning from expiryWarningBlocks before the
+// first contract enters the renew window until the contracts are renewed or
+// expire, describing what the expiry policy will do to the contracts. The
+// warning is also logged, once per period.
+func (c *Contractor) warnExpiry() {
+	c.expiryWarning = ""
+	var endHeight types.BlockHeight
+	for _, contract := range c.contracts {
+		if endHeight == 0 || contract.EndHeight() < endHeight {
+			endHeight = contract.EndHeight()
+		}
+	}
+	if endHeight == 0 {
+		return
+	}
+	var renewHeight types.BlockHeight
+	if endHeight > c.allowance.RenewWindow {
+		renewHeight = endHeight - c.allowance.RenewWindow
+	}
+	if c.blockHeight+expiryWarningBlocks < renewHeight {
+		return
+	}
+
+	switch c.allowance.ExpiryPolicy {
+	case modules.AllowanceExpiryStop:
+		c.expiryWarning = fmt.Sprintf("the allowance period ends at height %v; contracts will not be renewed, and files will become unavailable when the contracts expire", endHeight)
+	case modules.AllowanceExpiryRenewReduced:
+		c.expiryWarning = fmt.Sprintf("the allowance period ends at height %v; starting at height %v, contracts will be renewed with only enough funds to keep the data already stored", endHeight, renewHeight)
+	default:
+		c.expiryWarning = fmt.Sprintf("the allowance period ends at height %v; starting at height %v, contracts will be renewed with the full allowance", endHeight, renewHeight)
+	}
+	if endHeight != c.expiryWarned {
+		c.expiryWarned = endHeight
+		c.log.Println("WARN:", c.expiryWarning)
+	}
+}
@@ -10,8 +10,8 @@ import (
 	"github.com/NebulousLabs/Sia/types"
 )
 
-// TestProcessConsensusUpdate tests that contracts are removed at the expected
-// block height.
+// TestProcessConsensusUpdate tests that contracts are moved to the expired
+// contracts at the expected block height.
 func TestProcessConsensusUpdate(t *testing.T) {
 	// create contractor with a contract ending at height 20
 	var stub newStub
@@ -24,8 +24,9 @@ func TestProcessConsensusUpdate(t *testing.T) {
 		contracts: map[types.FileContractID]modules.RenterContract{
 			rc.ID: rc,
 		},
-		persist: new(memPersist),
-		log:     persist.NewLogger(ioutil.Discard),
+		expiredContracts: make(map[types.FileContractID]modules.RenterContract),
+		persist:          new(memPersist),
+		log:              persist.NewLogger(ioutil.Discard),
 	}
 
 	// process 20 blocks; contract should remain
@@ -40,11 +41,25 @@ func TestProcessConsensusUpdate(t *testing.T) {
 		t.Error("expected 1 contract, got", len(c.contracts))
 	}
 
-	// process one more block; contract should be removed
+	// process one more block; contract should be expired
 	c.ProcessConsensusChange(cc)
 	if len(c.contracts) != 0 {
 		t.Error("expected 0 contracts, got", len(c.contracts))
 	}
+	if len(c.ExpiredContracts()) != 1 {
+		t.Error("expected 1 expired contract, got", len(c.ExpiredContracts()))
+	}
+
+	// clearing the contract should forget it
+	if err := c.ClearExpiredContracts([]types.FileContractID{rc.ID}); err != nil {
+		t.Fatal(err)
+	}
+	if len(c.ExpiredContracts()) != 0 {
+		t.Error("expected 0 expired contracts, got", len(c.ExpiredContracts()))
+	}
+	if err := c.ClearExpiredContracts([]types.FileContractID{rc.ID}); err != errUnknownContract {
+		t.Error("expected errUnknownContract, got", err)
+	}
 }
 
 // TestIntegrationAutoRenew tests that contracts are automatically renwed at
@@ -121,3 +136,76 @@ func TestIntegrationAutoRenew(t *testing.T) {
 		t.Fatal(contract.FileContract.WindowStart)
 	}
 }
+
+// TestIntegrationExpiryStop tests that contracts are neither renewed nor
+// replaced at the end of the period when the expiry policy is "stop".
+func TestIntegrationExpiryStop(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	t.Parallel()
+	// create testing trio
+	_, c, m, err := newTestingTrio("TestIntegrationExpiryStop")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := modules.Allowance{
+		Funds:        types.SiacoinPrecision.Mul64(100), // 100 SC
+		Hosts:        1,
+		Period:       50,
+		RenewWindow:  10,
+		ExpiryPolicy: "never",
+	}
+	if err := c.SetAllowance(a); err != errAllowanceExpiry {
+		t.Fatal("expected errAllowanceExpiry, got", err)
+	}
+	a.ExpiryPolicy = modules.AllowanceExpiryStop
+	err = c.SetAllowance(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	contract := c.Contracts()[0]
+
+	// mine past the end of the contract
+	var warned bool
+	for c.blockHeight <= contract.EndHeight() {
+		_, err := m.AddBlock()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.ExpiryWarning() != "" {
+			warned = true
+		}
+	}
+	if len(c.Contracts()) != 0 {
+		t.Fatal("contract was renewed or replaced:", c.Contracts())
+	}
+	if expired := c.ExpiredContracts(); len(expired) != 1 || expired[0].ID != contract.ID {
+		t.Fatal("contract did not expire:", expired)
+	}
+	if c.expiryWarned != contract.EndHeight() || !warned {
+		t.Error("no warning was given ahead of the end of the period")
+	}
+	if w := c.ExpiryWarning(); w != "" {
+		t.Error("warning was not cleared after the contracts expired:", w)
+	}
+}
+
+// TestReducedSectors tests the number of sectors that contracts are renewed
+// with under the "renewreduced" expiry policy.
+func TestReducedSectors(t *testing.T) {
+	tests := []struct {
+		stored, max, exp uint64
+	}{
+		{0, 10, 1},
+		{3, 10, 3},
+		{12, 10, 10},
+	}
+	for _, test := range tests {
+		contract := modules.RenterContract{MerkleRoots: make([]crypto.Hash, test.stored)}
+		if n := reducedSectors(contract, test.max); n != test.exp {
+			t.Errorf("%v sectors stored, %v allowed: expected %v, got %v", test.stored, test.max, test.exp, n)
+		}
+	}
+}
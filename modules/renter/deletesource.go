@@ -0,0 +1,84 @@
+package renter
+
+import (
+	"errors"
+	"os"
+
+	"github.com/NebulousLabs/Sia/crypto"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+var (
+	errSourceChanged   = errors.New("source file has changed since the upload started")
+	errUnverifiedPiece = errors.New("a piece is not covered by an active contract")
+)
+
+// verifyUploaded checks that every piece of the file has been uploaded, and
+// that the Merkle root of every piece is part of the latest revision of an
+// active contract with the host storing it. contractRoots maps the ids of the
+// active contracts to their Merkle roots.
+func (f *file) verifyUploaded(contractRoots map[types.FileContractID]map[crypto.Hash]struct{}) error {
+	if len(f.incompleteChunks()) != 0 {
+		return errors.New("file has not been fully uploaded")
+	}
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	for id, fc := range f.contracts {
+		roots, ok := contractRoots[id]
+		for _, p := range fc.Pieces {
+			if _, stored := roots[p.MerkleRoot]; !ok || !stored {
+				return errUnverifiedPiece
+			}
+		}
+	}
+	return nil
+}
+
+// managedDeleteSource deletes the local source of a fully uploaded file that
+// was uploaded with DeleteAfterSuccess set. The source is only deleted if the
+// upload can be verified against the renter's contracts and the source has
+// not changed since the upload started; otherwise the deletion is abandoned
+// and the file is repaired from the source as usual.
+func (r *Renter) managedDeleteSource(f *file, meta trackedFile) {
+	contractRoots := make(map[types.FileContractID]map[crypto.Hash]struct{})
+	for _, c := range r.hostContractor.Contracts() {
+		roots := make(map[crypto.Hash]struct{}, len(c.MerkleRoots))
+		for _, root := range c.MerkleRoots {
+			roots[root] = struct{}{}
+		}
+		contractRoots[c.ID] = roots
+	}
+	err := f.verifyUploaded(contractRoots)
+	if err == errUnverifiedPiece {
+		// The contracts may not have caught up with the upload yet; try again
+		// on the next repair cycle.
+		r.log.Debugf("not deleting the source of %v yet: %v", f.name, err)
+		return
+	} else if err != nil {
+		return
+	}
+
+	stat, err := os.Stat(meta.RepairPath)
+	if err == nil && (uint64(stat.Size()) != f.size || !stat.ModTime().Equal(meta.SourceModTime)) {
+		err = errSourceChanged
+	}
+	if err == nil {
+		err = os.Remove(meta.RepairPath)
+	}
+
+	id := r.mu.Lock()
+	defer r.mu.Unlock(id)
+	tf, ok := r.tracking[f.name]
+	if !ok || tf.RepairPath != meta.RepairPath {
+		return
+	}
+	if err != nil {
+		r.log.Printf("not deleting the source of %v: %v", f.name, err)
+		tf.DeleteAfterSuccess = false
+	} else {
+		r.log.Printf("deleted the source of %v at %v after a successful upload", f.name, meta.RepairPath)
+		tf.SourceDeleted = true
+	}
+	r.tracking[f.name] = tf
+	r.save()
+}
@@ -0,0 +1,105 @@
+package renter
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NebulousLabs/Sia/build"
+	"github.com/NebulousLabs/Sia/crypto"
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// rootsContractor is a hostContractor with a single contract holding the
+// given Merkle roots.
+type rootsContractor struct {
+	stubContractor
+	roots []crypto.Hash
+}
+
+func (rc *rootsContractor) Contracts() []modules.RenterContract {
+	return []modules.RenterContract{{
+		ID:          types.FileContractID{1},
+		MerkleRoots: rc.roots,
+	}}
+}
+
+// TestDeleteSource checks that the source of an upload is only deleted once
+// every piece is part of an active contract, and only if the source has not
+// changed since the upload started.
+func TestDeleteSource(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	hc := new(rootsContractor)
+	rt, err := newContractorTester("TestDeleteSource", nil, hc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rt.Close()
+
+	source := filepath.Join(build.TempDir("renter", "TestDeleteSource"), "source")
+	if err := ioutil.WriteFile(source, []byte{1}, 0600); err != nil {
+		t.Fatal(err)
+	}
+	stat, err := os.Stat(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Add a file with both of its pieces uploaded under contract 1.
+	rsc, _ := NewRSCode(1, 1)
+	f := newFile("foo", rsc, 1, 1)
+	roots := []crypto.Hash{{1}, {2}}
+	f.contracts[types.FileContractID{1}] = fileContract{
+		ID: types.FileContractID{1},
+		Pieces: []pieceData{
+			{Chunk: 0, Piece: 0, MerkleRoot: roots[0]},
+			{Chunk: 0, Piece: 1, MerkleRoot: roots[1]},
+		},
+	}
+	meta := trackedFile{
+		RepairPath:         source,
+		DeleteAfterSuccess: true,
+		SourceModTime:      stat.ModTime(),
+	}
+	id := rt.renter.mu.Lock()
+	rt.renter.files["foo"] = f
+	rt.renter.tracking["foo"] = meta
+	rt.renter.mu.Unlock(id)
+
+	// The contract does not yet hold the second piece.
+	hc.roots = roots[:1]
+	rt.renter.managedDeleteSource(f, meta)
+	if _, err := os.Stat(source); err != nil {
+		t.Fatal("source was deleted before the upload was verified:", err)
+	}
+	if files := rt.renter.FileList(); !files[0].DeleteAfterSuccess || files[0].SourceDeleted {
+		t.Fatal("wrong file status:", files[0])
+	}
+
+	// Once the contract holds every piece, the source is deleted.
+	hc.roots = roots
+	rt.renter.managedDeleteSource(f, meta)
+	if _, err := os.Stat(source); !os.IsNotExist(err) {
+		t.Fatal("source was not deleted:", err)
+	}
+	if files := rt.renter.FileList(); !files[0].SourceDeleted {
+		t.Fatal("file does not report the deleted source:", files[0])
+	}
+
+	// A source that has changed since the upload started is kept.
+	if err := ioutil.WriteFile(source, []byte{1, 2}, 0600); err != nil {
+		t.Fatal(err)
+	}
+	rt.renter.tracking["foo"] = meta
+	rt.renter.managedDeleteSource(f, meta)
+	if _, err := os.Stat(source); err != nil {
+		t.Fatal("changed source was deleted:", err)
+	}
+	if files := rt.renter.FileList(); files[0].DeleteAfterSuccess || files[0].SourceDeleted {
+		t.Fatal("deletion was not abandoned:", files[0])
+	}
+}
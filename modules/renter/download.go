@@ -12,11 +12,13 @@ import (
 	"github.com/NebulousLabs/Sia/crypto"
 	"github.com/NebulousLabs/Sia/modules"
 	"github.com/NebulousLabs/Sia/modules/renter/contractor"
+	"github.com/NebulousLabs/Sia/types"
 )
 
 var (
 	errInsufficientHosts  = errors.New("insufficient hosts to recover file")
 	errInsufficientPieces = errors.New("couldn't fetch enough pieces to recover data")
+	errInvalidRange       = errors.New("range is outside of the file")
 )
 
 // A fetcher fetches pieces from a host. This interface exists to facilitate
@@ -35,6 +37,12 @@ type hostFetcher struct {
 	downloader contractor.Downloader
 	pieceMap   map[uint64][]pieceData
 	masterKey  crypto.TwofishKey
+	address    modules.NetAddress
+
+	// fetched and failed count the pieces that were and could not be
+	// downloaded from the host.
+	fetched uint64
+	failed  uint64
 }
 
 // pieces returns the pieces stored on this host that are part of a given
@@ -48,6 +56,7 @@ func (hf *hostFetcher) fetch(p pieceData) ([]byte, error) {
 	// request piece
 	data, err := hf.downloader.Sector(p.MerkleRoot)
 	if err != nil {
+		hf.failed++
 		return nil, err
 	}
 
@@ -55,11 +64,17 @@ func (hf *hostFetcher) fetch(p pieceData) ([]byte, error) {
 	key := deriveKey(hf.masterKey, p.Chunk, p.Piece)
 
 	// decrypt and return
-	return key.DecryptBytes(data)
+	data, err = key.DecryptBytes(data)
+	if err != nil {
+		hf.failed++
+		return nil, err
+	}
+	hf.fetched++
+	return data, nil
 }
 
 // newHostFetcher creates a new hostFetcher.
-func newHostFetcher(d contractor.Downloader, pieces []pieceData, masterKey crypto.TwofishKey) *hostFetcher {
+func newHostFetcher(d contractor.Downloader, pieces []pieceData, masterKey crypto.TwofishKey, address modules.NetAddress) *hostFetcher {
 	// make piece map
 	pieceMap := make(map[uint64][]pieceData)
 	for _, p := range pieces {
@@ -69,9 +84,19 @@ func newHostFetcher(d contractor.Downloader, pieces []pieceData, masterKey crypt
 		downloader: d,
 		pieceMap:   pieceMap,
 		masterKey:  masterKey,
+		address:    address,
 	}
 }
 
+// fetchers returns the hostFetchers as a slice of fetchers.
+func fetchers(hosts []*hostFetcher) []fetcher {
+	fs := make([]fetcher, len(hosts))
+	for i := range hosts {
+		fs[i] = hosts[i]
+	}
+	return fs
+}
+
 // checkHosts checks that a set of hosts is sufficient to download a file.
 func checkHosts(hosts []fetcher, minPieces int, numChunks uint64) error {
 	for i := uint64(0); i < numChunks; i++ {
@@ -178,17 +203,10 @@ func (f *file) newDownload(hosts []fetcher, destination string) *download {
 	}
 }
 
-// Download downloads a file, identified by its path, to the destination
-// specified.
-func (r *Renter) Download(path, destination string) error {
-	// Lookup the file associated with the nickname.
-	lockID := r.mu.Lock()
-	file, exists := r.files[path]
-	r.mu.Unlock(lockID)
-	if !exists {
-		return errors.New("no file with that path")
-	}
-
+// managedDownloadHosts connects to the hosts storing the pieces of a file and
+// checks that enough of them are reachable to recover every chunk. The
+// returned function closes the connections.
+func (r *Renter) managedDownloadHosts(file *file) ([]*hostFetcher, func(), error) {
 	// Look up the most recent contract for each host.
 	// NOTE: this assumes that only one contract is made with each host.
 	var contractPieces []struct {
@@ -209,14 +227,19 @@ func (r *Renter) Download(path, destination string) error {
 	r.log.Debugf("Starting Download, found %v contracts\n", len(contractPieces))
 
 	if len(contractPieces) == 0 {
-		return errors.New("no record of that file's contracts")
+		return nil, nil, errors.New("no record of that file's contracts")
 	} else if len(contractPieces) < file.erasureCode.MinPieces() {
-		return fmt.Errorf("not enough contracts: needed %v, found %v", file.erasureCode.MinPieces(), len(contractPieces))
+		return nil, nil, fmt.Errorf("not enough contracts: needed %v, found %v", file.erasureCode.MinPieces(), len(contractPieces))
 	}
 
 	// Initiate connections to each host.
-	var hosts []fetcher
+	var hosts []*hostFetcher
 	var errs []string
+	closeHosts := func() {
+		for _, h := range hosts {
+			h.downloader.Close()
+		}
+	}
 	for _, cp := range contractPieces {
 		// TODO: connect in parallel
 		d, err := r.hostContractor.Downloader(cp.contract)
@@ -224,18 +247,38 @@ func (r *Renter) Download(path, destination string) error {
 			errs = append(errs, fmt.Sprintf("\t%v: %v", cp.contract.NetAddress, err))
 			continue
 		}
-		defer d.Close()
-		hosts = append(hosts, newHostFetcher(d, cp.pieces, file.masterKey))
+		hosts = append(hosts, newHostFetcher(d, cp.pieces, file.masterKey, cp.contract.NetAddress))
 	}
 	if len(hosts) < file.erasureCode.MinPieces() {
-		return errors.New("Could not connect to enough hosts:\n" + strings.Join(errs, "\n"))
+		closeHosts()
+		return nil, nil, errors.New("Could not connect to enough hosts:\n" + strings.Join(errs, "\n"))
 	}
 
 	// Check that this host set is sufficient to download the file.
-	err := checkHosts(hosts, file.erasureCode.MinPieces(), file.numChunks())
+	err := checkHosts(fetchers(hosts), file.erasureCode.MinPieces(), file.numChunks())
+	if err != nil {
+		closeHosts()
+		return nil, nil, err
+	}
+	return hosts, closeHosts, nil
+}
+
+// Download downloads a file, identified by its path, to the destination
+// specified.
+func (r *Renter) Download(path, destination string) error {
+	// Lookup the file associated with the nickname.
+	lockID := r.mu.Lock()
+	file, exists := r.files[path]
+	r.mu.Unlock(lockID)
+	if !exists {
+		return errors.New("no file with that path")
+	}
+
+	hosts, closeHosts, err := r.managedDownloadHosts(file)
 	if err != nil {
 		return err
 	}
+	defer closeHosts()
 
 	// Create file on disk with the correct permissions.
 	perm := os.FileMode(file.mode)
@@ -250,7 +293,7 @@ func (r *Renter) Download(path, destination string) error {
 	defer f.Close()
 
 	// Create the download object.
-	d := file.newDownload(hosts, destination)
+	d := file.newDownload(fetchers(hosts), destination)
 
 	// Add the download to the download queue.
 	lockID = r.mu.Lock()
@@ -259,6 +302,9 @@ func (r *Renter) Download(path, destination string) error {
 
 	// Perform download.
 	err = d.run(f)
+	lockID = r.mu.Lock()
+	r.logAccess(d, err)
+	r.mu.Unlock(lockID)
 	if err != nil {
 		// File could not be downloaded; delete the copy on disk.
 		os.Remove(destination)
@@ -268,6 +314,70 @@ func (r *Renter) Download(path, destination string) error {
 	return nil
 }
 
+// DownloadCost estimates how much it would cost to download length bytes of
+// the file at path, starting at offset. A length of zero means the rest of
+// the file. Hosts charge for every sector that is fetched, and a download
+// fetches MinPieces randomly chosen pieces of each chunk it covers, so the
+// estimate for each chunk is MinPieces times the average sector price of the
+// hosts storing that chunk. Prices are taken from the hostdb.
+func (r *Renter) DownloadCost(path string, offset, length uint64) (types.Currency, error) {
+	lockID := r.mu.RLock()
+	file, exists := r.files[path]
+	r.mu.RUnlock(lockID)
+	if !exists {
+		return types.Currency{}, errors.New("no file with that path")
+	}
+
+	sectorPrices := make(map[modules.NetAddress]types.Currency)
+	for _, host := range r.hostDB.AllHosts() {
+		sectorPrices[host.NetAddress] = host.DownloadBandwidthPrice.Mul64(modules.SectorSize)
+	}
+
+	file.mu.RLock()
+	defer file.mu.RUnlock()
+	if file.size == 0 {
+		return types.ZeroCurrency, nil
+	}
+	if offset >= file.size {
+		return types.Currency{}, errInvalidRange
+	}
+	if length == 0 || length > file.size-offset {
+		length = file.size - offset
+	}
+	firstChunk := offset / file.chunkSize()
+	lastChunk := (offset + length - 1) / file.chunkSize()
+
+	// Collect the sector price of every piece in the range. Pieces on hosts
+	// that are unknown to the hostdb are not counted.
+	chunkPrices := make(map[uint64][]types.Currency)
+	for _, fc := range file.contracts {
+		price, ok := sectorPrices[fc.IP]
+		if !ok {
+			continue
+		}
+		for _, p := range fc.Pieces {
+			if p.Chunk >= firstChunk && p.Chunk <= lastChunk {
+				chunkPrices[p.Chunk] = append(chunkPrices[p.Chunk], price)
+			}
+		}
+	}
+
+	minPieces := uint64(file.erasureCode.MinPieces())
+	var cost types.Currency
+	for chunk := firstChunk; chunk <= lastChunk; chunk++ {
+		prices := chunkPrices[chunk]
+		if uint64(len(prices)) < minPieces {
+			return types.Currency{}, errInsufficientPieces
+		}
+		var total types.Currency
+		for _, price := range prices {
+			total = total.Add(price)
+		}
+		cost = cost.Add(total.Mul64(minPieces).Div64(uint64(len(prices))))
+	}
+	return cost, nil
+}
+
 // DownloadQueue returns the list of downloads in the queue.
 func (r *Renter) DownloadQueue() []modules.DownloadInfo {
 	lockID := r.mu.RLock()
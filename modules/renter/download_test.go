@@ -2,6 +2,7 @@ package renter
 
 import (
 	"bytes"
+	"fmt"
 	"io"
 	"testing"
 	"time"
@@ -184,3 +185,72 @@ func TestDownloadContracts(t *testing.T) {
 		t.Fatalf("expected Downloader to be called %v times, got %v", nContracts, hc.downloaders)
 	}
 }
+
+// pricedHostDB is a hostDB that reports a fixed set of hosts.
+type pricedHostDB struct {
+	stubHostDB
+	hosts []modules.HostDBEntry
+}
+
+func (hdb pricedHostDB) AllHosts() []modules.HostDBEntry { return hdb.hosts }
+
+// TestDownloadCost checks that DownloadCost averages the sector prices of the
+// hosts storing each chunk in the requested range.
+func TestDownloadCost(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+
+	// Two hosts, one twice as expensive as the other.
+	var hdb pricedHostDB
+	for i, price := range []uint64{1, 2} {
+		var entry modules.HostDBEntry
+		entry.NetAddress = modules.NetAddress(fmt.Sprintf("host%v:1", i))
+		entry.DownloadBandwidthPrice = types.NewCurrency64(price)
+		hdb.hosts = append(hdb.hosts, entry)
+	}
+	rt, err := newContractorTester("TestDownloadCost", hdb, stubContractor{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A file with two chunks, each of which has a piece on both hosts.
+	rsc, _ := NewRSCode(1, 1)
+	f := newFile("foo", rsc, 100, 150)
+	for i, host := range hdb.hosts {
+		f.contracts[types.FileContractID{byte(i)}] = fileContract{
+			IP: host.NetAddress,
+			Pieces: []pieceData{
+				{Chunk: 0, Piece: uint64(i)},
+				{Chunk: 1, Piece: uint64(i)},
+			},
+		}
+	}
+	id := rt.renter.mu.Lock()
+	rt.renter.files["foo"] = f
+	rt.renter.mu.Unlock(id)
+
+	// Each chunk needs one piece, fetched from either host.
+	avgSectorPrice := types.NewCurrency64(3).Mul64(modules.SectorSize).Div64(2)
+	cost, err := rt.renter.DownloadCost("foo", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cost.Cmp(avgSectorPrice.Mul64(2)) != 0 {
+		t.Error("wrong cost for the whole file:", cost)
+	}
+	cost, err = rt.renter.DownloadCost("foo", 100, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cost.Cmp(avgSectorPrice) != 0 {
+		t.Error("wrong cost for the second chunk:", cost)
+	}
+
+	if _, err := rt.renter.DownloadCost("foo", 150, 0); err != errInvalidRange {
+		t.Error("expected errInvalidRange, got", err)
+	}
+	if _, err := rt.renter.DownloadCost("bar", 0, 0); err == nil {
+		t.Error("expected an error for an unknown file")
+	}
+}
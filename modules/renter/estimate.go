@@ -0,0 +1,106 @@
+package renter
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/NebulousLabs/Sia/modules"
+)
+
+var (
+	errNoThroughput = errors.New("no uploads have been measured to the hosts of the renter's contracts")
+)
+
+// hostSpeed is the mean and the slowest measured throughput of a host, in
+// bytes per second.
+type hostSpeed struct {
+	mean    float64
+	slowest float64
+}
+
+// hostSpeeds sorts hosts from fastest to slowest by their mean throughput.
+type hostSpeeds []hostSpeed
+
+func (hs hostSpeeds) Len() int           { return len(hs) }
+func (hs hostSpeeds) Less(i, j int) bool { return hs[i].mean > hs[j].mean }
+func (hs hostSpeeds) Swap(i, j int)      { hs[i], hs[j] = hs[j], hs[i] }
+
+// EstimateUpload estimates how long it would take to upload a file of the
+// given size at the given redundancy, using the throughput measured during
+// recent uploads to the hosts of the renter's contracts.
+//
+// The pieces of a chunk are uploaded to different hosts, up to the size of the
+// worker pool at once, and the next chunk is not started until every piece of
+// the current one is done. The optimistic estimate assumes that the fastest
+// hosts are used and the pool stays busy; the pessimistic estimate assumes
+// that every chunk waits on the slowest upload measured to any of those hosts.
+func (r *Renter) EstimateUpload(size uint64, redundancy float64) (modules.RenterUploadEstimate, error) {
+	dataPieces, parityPieces := defaultDataPieces, defaultParityPieces
+	if redundancy != 0 {
+		if redundancy <= 1 {
+			return modules.RenterUploadEstimate{}, errRedundancyTooLow
+		}
+		parityPieces = int(math.Ceil(redundancy*float64(dataPieces))) - dataPieces
+	}
+	numPieces := dataPieces + parityPieces
+	chunkSize := pieceSize * uint64(dataPieces)
+	numChunks := size / chunkSize
+	if size%chunkSize != 0 || size == 0 {
+		numChunks++
+	}
+
+	throughput := r.workers.hostThroughput()
+	var speeds hostSpeeds
+	for _, c := range r.hostContractor.Contracts() {
+		samples := throughput[c.NetAddress]
+		if c.Retired || len(samples) == 0 {
+			continue
+		}
+		hs := hostSpeed{slowest: samples[0]}
+		for _, s := range samples {
+			hs.mean += s / float64(len(samples))
+			if s < hs.slowest {
+				hs.slowest = s
+			}
+		}
+		speeds = append(speeds, hs)
+	}
+	if len(speeds) == 0 {
+		return modules.RenterUploadEstimate{}, errNoThroughput
+	}
+	sort.Sort(speeds)
+	numHosts := len(speeds)
+
+	// Only as many hosts as there are pieces in a chunk are used.
+	if len(speeds) > numPieces {
+		speeds = speeds[:numPieces]
+	}
+	poolSize := r.workers.poolSize()
+	workers := len(speeds)
+	if workers > poolSize {
+		workers = poolSize
+	}
+
+	var bandwidth float64
+	slowest := speeds[0].slowest
+	for i, hs := range speeds {
+		if i < workers {
+			bandwidth += hs.mean
+		}
+		if hs.slowest < slowest {
+			slowest = hs.slowest
+		}
+	}
+	totalBytes := numChunks * uint64(numPieces) * modules.SectorSize
+	rounds := uint64((numPieces + workers - 1) / workers)
+
+	return modules.RenterUploadEstimate{
+		TotalBytes:  totalBytes,
+		Redundancy:  float64(numPieces) / float64(dataPieces),
+		Optimistic:  uint64(math.Ceil(float64(totalBytes) / bandwidth)),
+		Pessimistic: uint64(math.Ceil(float64(numChunks*rounds*modules.SectorSize) / slowest)),
+		Hosts:       numHosts,
+		Workers:     workers,
+	}, nil
+}
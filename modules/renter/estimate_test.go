@@ -0,0 +1,70 @@
+package renter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// estimateContractor is a hostContractor with two active contracts and one
+// retired contract.
+type estimateContractor struct {
+	stubContractor
+}
+
+func (estimateContractor) Contracts() []modules.RenterContract {
+	return []modules.RenterContract{
+		{ID: types.FileContractID{1}, NetAddress: "fast:1"},
+		{ID: types.FileContractID{2}, NetAddress: "slow:1"},
+		{ID: types.FileContractID{3}, NetAddress: "retired:1", Retired: true},
+	}
+}
+
+// TestEstimateUpload checks the upload estimates computed from the measured
+// throughput of the renter's hosts.
+func TestEstimateUpload(t *testing.T) {
+	r := &Renter{
+		hostContractor: estimateContractor{},
+		workers:        newWorkerPool(2),
+	}
+	if _, err := r.EstimateUpload(1, 0); err != errNoThroughput {
+		t.Fatal("expected errNoThroughput, got", err)
+	}
+
+	r.workers.recordUpload("fast:1", 100, time.Second)
+	r.workers.recordUpload("fast:1", 300, time.Second)
+	r.workers.recordUpload("slow:1", 50, time.Second)
+	// Hosts of retired contracts are ignored.
+	r.workers.recordUpload("retired:1", 1e6, time.Second)
+
+	// A one byte file is a single chunk of defaultDataPieces+defaultParityPieces
+	// pieces. The two workers upload to both hosts at a combined 250 bytes
+	// per second in the best case, and the slowest upload was 50 bytes per
+	// second.
+	numPieces := uint64(defaultDataPieces + defaultParityPieces)
+	est, err := r.EstimateUpload(1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rounds := (numPieces + 1) / 2
+	if est.TotalBytes != numPieces*modules.SectorSize || est.Hosts != 2 || est.Workers != 2 {
+		t.Fatalf("unexpected estimate: %+v", est)
+	}
+	if est.Optimistic != (est.TotalBytes+249)/250 || est.Pessimistic != (rounds*modules.SectorSize+49)/50 {
+		t.Fatalf("unexpected estimate: %+v", est)
+	}
+
+	// Overriding the redundancy changes the number of pieces.
+	est, err = r.EstimateUpload(1, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if est.Redundancy != 3 || est.TotalBytes != uint64(3*defaultDataPieces)*modules.SectorSize {
+		t.Fatalf("unexpected estimate: %+v", est)
+	}
+	if _, err := r.EstimateUpload(1, 0.5); err != errRedundancyTooLow {
+		t.Fatal("expected errRedundancyTooLow, got", err)
+	}
+}
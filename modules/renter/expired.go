@@ -0,0 +1,146 @@
+package renter
+
+import (
+	"sort"
+
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// byEndHeight sorts expired contracts by their end height.
+type byEndHeight []modules.RenterExpiredContract
+
+func (b byEndHeight) Len() int           { return len(b) }
+func (b byEndHeight) Less(i, j int) bool { return b[i].EndHeight < b[j].EndHeight }
+func (b byEndHeight) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+
+// dependsOn returns true if the file has pieces stored under the contract
+// with the given id that are not also stored under a contract outside of
+// expired. Such pieces have not been migrated to another host.
+func (f *file) dependsOn(id types.FileContractID, expired map[types.FileContractID]struct{}) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	fc, ok := f.contracts[id]
+	if !ok || len(fc.Pieces) == 0 {
+		return false
+	}
+	type pieceID struct {
+		chunk, piece uint64
+	}
+	elsewhere := make(map[pieceID]struct{})
+	for otherID, other := range f.contracts {
+		if _, exp := expired[otherID]; exp {
+			continue
+		}
+		for _, p := range other.Pieces {
+			elsewhere[pieceID{p.Chunk, p.Piece}] = struct{}{}
+		}
+	}
+	for _, p := range fc.Pieces {
+		if _, ok := elsewhere[pieceID{p.Chunk, p.Piece}]; !ok {
+			return true
+		}
+	}
+	return false
+}
+
+// dropContract removes the contract with the given id from the file, and
+// returns true if the file had a record of the contract.
+func (f *file) dropContract(id types.FileContractID) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, ok := f.contracts[id]
+	delete(f.contracts, id)
+	return ok
+}
+
+// expiredSet returns the ids of the provided contracts as a set.
+func expiredSet(contracts []modules.RenterContract) map[types.FileContractID]struct{} {
+	set := make(map[types.FileContractID]struct{}, len(contracts))
+	for _, c := range contracts {
+		set[c.ID] = struct{}{}
+	}
+	return set
+}
+
+// ExpiredContracts returns the contracts that have passed their end height and
+// have not yet been cleared, sorted by end height.
+func (r *Renter) ExpiredContracts() []modules.RenterExpiredContract {
+	contracts := r.hostContractor.ExpiredContracts()
+	expired := expiredSet(contracts)
+
+	lockID := r.mu.RLock()
+	defer r.mu.RUnlock(lockID)
+	ecs := make([]modules.RenterExpiredContract, 0, len(contracts))
+	for _, c := range contracts {
+		ec := modules.RenterExpiredContract{
+			ID:         c.ID,
+			NetAddress: c.NetAddress,
+			EndHeight:  c.EndHeight(),
+			Refunded:   c.RenterFunds(),
+			Spent:      contractSpent(c),
+		}
+		if len(c.FileContract.ValidProofOutputs) > 0 {
+			ec.Funds = c.FileContract.ValidProofOutputs[0].Value
+		}
+		for name, f := range r.files {
+			if f.dependsOn(c.ID, expired) {
+				ec.DependentFiles = append(ec.DependentFiles, name)
+			}
+		}
+		sort.Strings(ec.DependentFiles)
+		ecs = append(ecs, ec)
+	}
+	sort.Sort(byEndHeight(ecs))
+	return ecs
+}
+
+// ClearExpiredContracts forgets the expired contracts that no files depend on.
+// The records of the cleared contracts are also removed from the renter's
+// files. The ids of the cleared contracts are returned.
+func (r *Renter) ClearExpiredContracts() ([]types.FileContractID, error) {
+	contracts := r.hostContractor.ExpiredContracts()
+	expired := expiredSet(contracts)
+
+	lockID := r.mu.Lock()
+	var cleared []types.FileContractID
+	changed := make(map[*file]struct{})
+	for _, c := range contracts {
+		dependent := false
+		for _, f := range r.files {
+			if f.dependsOn(c.ID, expired) {
+				dependent = true
+				break
+			}
+		}
+		if dependent {
+			continue
+		}
+		for _, f := range r.files {
+			if f.dropContract(c.ID) {
+				changed[f] = struct{}{}
+			}
+		}
+		cleared = append(cleared, c.ID)
+	}
+	var err error
+	for f := range changed {
+		if saveErr := r.saveFile(f); saveErr != nil && err == nil {
+			err = saveErr
+		}
+	}
+	r.mu.Unlock(lockID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cleared) == 0 {
+		return nil, nil
+	}
+	if err := r.hostContractor.ClearExpiredContracts(cleared); err != nil {
+		return nil, err
+	}
+	r.log.Println("Cleared", len(cleared), "expired contracts")
+	return cleared, nil
+}
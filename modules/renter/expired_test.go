@@ -0,0 +1,64 @@
+package renter
+
+import (
+	"testing"
+
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// TestFileDependsOn checks that a file only depends on an expired contract
+// while it has pieces under that contract that are not stored under an active
+// contract.
+func TestFileDependsOn(t *testing.T) {
+	expiredID := types.FileContractID{0}
+	otherExpiredID := types.FileContractID{1}
+	activeID := types.FileContractID{2}
+	f := &file{
+		contracts: map[types.FileContractID]fileContract{
+			expiredID: {
+				ID: expiredID,
+				Pieces: []pieceData{
+					{Chunk: 0, Piece: 0},
+					{Chunk: 1, Piece: 0},
+				},
+			},
+			otherExpiredID: {
+				ID: otherExpiredID,
+				Pieces: []pieceData{
+					{Chunk: 1, Piece: 0},
+				},
+			},
+			activeID: {
+				ID: activeID,
+				Pieces: []pieceData{
+					{Chunk: 0, Piece: 0},
+				},
+			},
+		},
+	}
+	expired := map[types.FileContractID]struct{}{
+		expiredID:      {},
+		otherExpiredID: {},
+	}
+
+	// Chunk 1 is only stored under expired contracts.
+	if !f.dependsOn(expiredID, expired) {
+		t.Fatal("file should depend on the expired contract")
+	}
+
+	// Once chunk 1 is stored under an active contract, the file no longer
+	// depends on either expired contract.
+	fc := f.contracts[activeID]
+	fc.Pieces = append(fc.Pieces, pieceData{Chunk: 1, Piece: 0})
+	f.contracts[activeID] = fc
+	if f.dependsOn(expiredID, expired) || f.dependsOn(otherExpiredID, expired) {
+		t.Fatal("file should not depend on migrated contracts")
+	}
+
+	if !f.dropContract(expiredID) || f.dropContract(expiredID) {
+		t.Fatal("dropContract should only report contracts the file has")
+	}
+	if f.dependsOn(expiredID, expired) {
+		t.Fatal("file should not depend on a dropped contract")
+	}
+}
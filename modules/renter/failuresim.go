@@ -0,0 +1,98 @@
+package renter
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+var errNoFailedHosts = errors.New("no hosts were given to simulate the failure of")
+
+// lostFiles sorts lost files by their path.
+type lostFiles []modules.RenterLostFile
+
+func (lf lostFiles) Len() int           { return len(lf) }
+func (lf lostFiles) Swap(i, j int)      { lf[i], lf[j] = lf[j], lf[i] }
+func (lf lostFiles) Less(i, j int) bool { return lf[i].SiaPath < lf[j].SiaPath }
+
+// survivingChunks counts the distinct pieces of each chunk that are stored on
+// hosts outside of failed. It returns the number of chunks left with fewer
+// pieces than the erasure code needs, and the redundancy of the least
+// redundant chunk. A piece stored on several hosts is counted once.
+func (f *file) survivingChunks(failed map[modules.NetAddress]struct{}) (lost uint64, redundancy float64) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	pieces := make([]map[uint64]struct{}, f.numChunks())
+	for _, fc := range f.contracts {
+		if _, ok := failed[fc.IP]; ok {
+			continue
+		}
+		for _, p := range fc.Pieces {
+			if p.Chunk >= uint64(len(pieces)) {
+				continue
+			}
+			if pieces[p.Chunk] == nil {
+				pieces[p.Chunk] = make(map[uint64]struct{})
+			}
+			pieces[p.Chunk][p.Piece] = struct{}{}
+		}
+	}
+	minPieces := -1
+	for _, chunk := range pieces {
+		if len(chunk) < f.erasureCode.MinPieces() {
+			lost++
+		}
+		if minPieces == -1 || len(chunk) < minPieces {
+			minPieces = len(chunk)
+		}
+	}
+	return lost, float64(minPieces) / float64(f.erasureCode.MinPieces())
+}
+
+// SimulateHostFailure reports which files would become unrecoverable if the
+// hosts with the given public keys all failed at once, because some chunk
+// would be left with fewer pieces than its erasure code needs. Pieces are
+// attributed to hosts by the net address recorded with the file. Empty files
+// have no pieces to lose and are not reported.
+func (r *Renter) SimulateHostFailure(hosts []types.SiaPublicKey) (modules.RenterFailureSimulation, error) {
+	if len(hosts) == 0 {
+		return modules.RenterFailureSimulation{}, errNoFailedHosts
+	}
+	var sim modules.RenterFailureSimulation
+	failed := make(map[modules.NetAddress]struct{})
+	for _, pk := range hosts {
+		addr, err := r.hostAddress(pk)
+		if err != nil {
+			return modules.RenterFailureSimulation{}, err
+		}
+		if _, ok := failed[addr]; !ok {
+			failed[addr] = struct{}{}
+			sim.Hosts = append(sim.Hosts, addr)
+		}
+	}
+
+	lockID := r.mu.RLock()
+	defer r.mu.RUnlock(lockID)
+	sim.LostFiles = []modules.RenterLostFile{}
+	for _, f := range r.files {
+		if f.size == 0 {
+			continue
+		}
+		sim.FilesChecked++
+		lost, redundancy := f.survivingChunks(failed)
+		if lost == 0 {
+			continue
+		}
+		sim.LostFiles = append(sim.LostFiles, modules.RenterLostFile{
+			SiaPath:     f.name,
+			LostChunks:  lost,
+			TotalChunks: f.numChunks(),
+			Redundancy:  redundancy,
+		})
+	}
+	sort.Sort(lostFiles(sim.LostFiles))
+	return sim, nil
+}
@@ -0,0 +1,83 @@
+package renter
+
+import (
+	"testing"
+
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// TestSimulateHostFailure checks that a file is reported as lost only when a
+// chunk has too few pieces left on the surviving hosts.
+func TestSimulateHostFailure(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+
+	var hdb pricedHostDB
+	var keys []types.SiaPublicKey
+	for i, addr := range []modules.NetAddress{"host0:1", "host1:1"} {
+		var entry modules.HostDBEntry
+		entry.NetAddress = addr
+		entry.PublicKey = types.SiaPublicKey{Algorithm: types.SignatureEd25519, Key: []byte{byte(i)}}
+		hdb.hosts = append(hdb.hosts, entry)
+		keys = append(keys, entry.PublicKey)
+	}
+	rt, err := newContractorTester("TestSimulateHostFailure", hdb, stubContractor{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Both chunks of foo are on host0, and its first chunk is also on host1.
+	// bar is only on host0.
+	rsc, _ := NewRSCode(1, 1)
+	foo := newFile("foo", rsc, 100, 150)
+	foo.contracts[types.FileContractID{0}] = fileContract{
+		IP:     "host0:1",
+		Pieces: []pieceData{{Chunk: 0, Piece: 0}, {Chunk: 1, Piece: 0}},
+	}
+	foo.contracts[types.FileContractID{1}] = fileContract{
+		IP:     "host1:1",
+		Pieces: []pieceData{{Chunk: 0, Piece: 1}},
+	}
+	bar := newFile("bar", rsc, 100, 50)
+	bar.contracts[types.FileContractID{0}] = fileContract{
+		IP:     "host0:1",
+		Pieces: []pieceData{{Chunk: 0, Piece: 0}},
+	}
+	id := rt.renter.mu.Lock()
+	rt.renter.files["foo"] = foo
+	rt.renter.files["bar"] = bar
+	rt.renter.files["empty"] = newFile("empty", rsc, 100, 0)
+	rt.renter.mu.Unlock(id)
+
+	sim, err := rt.renter.SimulateHostFailure(keys[1:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sim.FilesChecked != 2 || len(sim.LostFiles) != 0 || len(sim.Hosts) != 1 || sim.Hosts[0] != "host1:1" {
+		t.Fatalf("no files should be lost with host1: %+v", sim)
+	}
+
+	sim, err = rt.renter.SimulateHostFailure(keys[:1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sim.LostFiles) != 2 {
+		t.Fatalf("expected both files to be lost with host0: %+v", sim)
+	}
+	if lf := sim.LostFiles[0]; lf.SiaPath != "bar" || lf.LostChunks != 1 || lf.TotalChunks != 1 || lf.Redundancy != 0 {
+		t.Errorf("wrong report for bar: %+v", lf)
+	}
+	if lf := sim.LostFiles[1]; lf.SiaPath != "foo" || lf.LostChunks != 1 || lf.TotalChunks != 2 || lf.Redundancy != 0 {
+		t.Errorf("wrong report for foo: %+v", lf)
+	}
+
+	if _, err := rt.renter.SimulateHostFailure(nil); err != errNoFailedHosts {
+		t.Error("expected errNoFailedHosts, got", err)
+	}
+	unknown := types.SiaPublicKey{Algorithm: types.SignatureEd25519, Key: []byte{9}}
+	if _, err := rt.renter.SimulateHostFailure([]types.SiaPublicKey{unknown}); err != errUnknownHostKey {
+		t.Error("expected errUnknownHostKey, got", err)
+	}
+}
@@ -179,6 +179,12 @@ func (r *Renter) DeleteFile(nickname string) error {
 	}
 	delete(r.files, nickname)
 	os.RemoveAll(filepath.Join(r.persistDir, f.name+ShareExtension))
+	// Files uploaded from a URL are repaired from a staged copy, which is no
+	// longer needed.
+	if tf, ok := r.tracking[nickname]; ok && r.isStaged(tf.RepairPath) {
+		delete(r.tracking, nickname)
+		os.Remove(tf.RepairPath)
+	}
 	r.saveSync()
 	r.mu.Unlock(lockID)
 
@@ -209,8 +215,41 @@ func (r *Renter) DeleteFile(nickname string) error {
 	return nil
 }
 
+// contractSpent returns the funds that the renter has spent on a contract.
+func contractSpent(c modules.RenterContract) types.Currency {
+	if len(c.FileContract.ValidProofOutputs) == 0 {
+		return types.ZeroCurrency
+	}
+	funds := c.FileContract.ValidProofOutputs[0].Value
+	if funds.Cmp(c.RenterFunds()) <= 0 {
+		return types.ZeroCurrency
+	}
+	return funds.Sub(c.RenterFunds())
+}
+
+// cost estimates the amount that has been spent on the file. The spending of
+// each contract is split evenly between the sectors stored under it, and the
+// file is charged for the sectors that hold its pieces.
+func (f *file) cost(contracts map[types.FileContractID]modules.RenterContract) (cost types.Currency) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	for id, fc := range f.contracts {
+		c, ok := contracts[id]
+		if !ok || len(c.MerkleRoots) == 0 {
+			continue
+		}
+		cost = cost.Add(contractSpent(c).Mul64(uint64(len(fc.Pieces))).Div64(uint64(len(c.MerkleRoots))))
+	}
+	return cost
+}
+
 // FileList returns all of the files that the renter has.
 func (r *Renter) FileList() []modules.FileInfo {
+	contracts := make(map[types.FileContractID]modules.RenterContract)
+	for _, c := range r.hostContractor.Contracts() {
+		contracts[c.ID] = c
+	}
+
 	lockID := r.mu.RLock()
 	defer r.mu.RUnlock(lockID)
 
@@ -219,14 +258,19 @@ func (r *Renter) FileList() []modules.FileInfo {
 		// _, renewing := r.tracking[f.name]
 		// TODO: bring back per-file renewing
 		renewing := true
+		tf := r.tracking[f.name]
 		files = append(files, modules.FileInfo{
-			SiaPath:        f.name,
-			Filesize:       f.size,
-			Available:      f.available(),
-			Redundancy:     f.redundancy(),
-			Renewing:       renewing,
-			UploadProgress: f.uploadProgress(),
-			Expiration:     f.expiration(),
+			SiaPath:            f.name,
+			Filesize:           f.size,
+			Available:          f.available(),
+			Redundancy:         f.redundancy(),
+			Renewing:           renewing,
+			UploadProgress:     f.uploadProgress(),
+			Expiration:         f.expiration(),
+			Cost:               f.cost(contracts),
+			UploadTime:         tf.UploadTime,
+			DeleteAfterSuccess: tf.DeleteAfterSuccess,
+			SourceDeleted:      tf.SourceDeleted,
 		})
 	}
 	return files
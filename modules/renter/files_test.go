@@ -5,6 +5,8 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/NebulousLabs/Sia/crypto"
+	"github.com/NebulousLabs/Sia/modules"
 	"github.com/NebulousLabs/Sia/types"
 )
 
@@ -180,6 +182,34 @@ func TestFileExpiration(t *testing.T) {
 	}
 }
 
+// TestFileCost checks that a file is charged for its share of the sectors
+// stored under each contract.
+func TestFileCost(t *testing.T) {
+	contract := func(funds, remaining uint64, sectors int) modules.RenterContract {
+		var c modules.RenterContract
+		c.FileContract.ValidProofOutputs = []types.SiacoinOutput{{Value: types.NewCurrency64(funds)}}
+		c.LastRevision.NewValidProofOutputs = []types.SiacoinOutput{{Value: types.NewCurrency64(remaining)}}
+		c.MerkleRoots = make([]crypto.Hash, sectors)
+		return c
+	}
+	contracts := map[types.FileContractID]modules.RenterContract{
+		{0}: contract(1000, 600, 4), // 100 per sector
+		{1}: contract(1000, 900, 2), // 50 per sector
+		{2}: contract(1000, 500, 0), // no sectors
+	}
+	f := &file{
+		contracts: map[types.FileContractID]fileContract{
+			{0}: {Pieces: make([]pieceData, 2)},
+			{1}: {Pieces: make([]pieceData, 1)},
+			{2}: {Pieces: make([]pieceData, 1)},
+			{3}: {Pieces: make([]pieceData, 1)}, // unknown contract
+		},
+	}
+	if cost := f.cost(contracts); cost.Cmp(types.NewCurrency64(250)) != 0 {
+		t.Fatal("expected a cost of 250, got", cost)
+	}
+}
+
 // TestRenterDeleteFile probes the DeleteFile method of the renter type.
 func TestRenterDeleteFile(t *testing.T) {
 	if testing.Short() {
@@ -0,0 +1,87 @@
+package renter
+
+import (
+	"sort"
+
+	"github.com/NebulousLabs/Sia/modules"
+)
+
+// hostUsages sorts host usage by the number of bytes stored, largest first,
+// breaking ties by net address.
+type hostUsages []modules.RenterHostUsage
+
+func (hu hostUsages) Len() int      { return len(hu) }
+func (hu hostUsages) Swap(i, j int) { hu[i], hu[j] = hu[j], hu[i] }
+func (hu hostUsages) Less(i, j int) bool {
+	if hu[i].Bytes != hu[j].Bytes {
+		return hu[i].Bytes > hu[j].Bytes
+	}
+	return hu[i].NetAddress < hu[j].NetAddress
+}
+
+// hostUsage adds the pieces of the file stored on each host to usage, keyed
+// by the host's net address.
+func (f *file) hostUsage(usage map[modules.NetAddress]*modules.RenterHostUsage) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	chunks := make(map[modules.NetAddress]map[uint64]struct{})
+	for _, fc := range f.contracts {
+		if len(fc.Pieces) == 0 {
+			continue
+		}
+		hu, ok := usage[fc.IP]
+		if !ok {
+			hu = &modules.RenterHostUsage{NetAddress: fc.IP}
+			usage[fc.IP] = hu
+		}
+		if _, ok := chunks[fc.IP]; !ok {
+			chunks[fc.IP] = make(map[uint64]struct{})
+			hu.Files++
+		}
+		for _, p := range fc.Pieces {
+			chunks[fc.IP][p.Chunk] = struct{}{}
+		}
+		hu.Pieces += uint64(len(fc.Pieces))
+		hu.Bytes += uint64(len(fc.Pieces)) * f.pieceSize
+	}
+	for addr, cs := range chunks {
+		usage[addr].Chunks += uint64(len(cs))
+	}
+}
+
+// HostUsage returns how much of the renter's data is stored on each host,
+// largest first. Pieces are attributed to hosts by the net address recorded
+// with the file, and public keys are looked up in the hostdb.
+func (r *Renter) HostUsage() []modules.RenterHostUsage {
+	lockID := r.mu.RLock()
+	files := make([]*file, 0, len(r.files))
+	for _, f := range r.files {
+		files = append(files, f)
+	}
+	r.mu.RUnlock(lockID)
+
+	usage := make(map[modules.NetAddress]*modules.RenterHostUsage)
+	for _, f := range files {
+		f.hostUsage(usage)
+	}
+	var total uint64
+	for _, hu := range usage {
+		total += hu.Bytes
+	}
+	for _, host := range r.hostDB.AllHosts() {
+		if hu, ok := usage[host.NetAddress]; ok {
+			hu.PublicKey = host.PublicKey
+		}
+	}
+
+	hus := make(hostUsages, 0, len(usage))
+	for _, hu := range usage {
+		if total > 0 {
+			hu.Share = float64(hu.Bytes) / float64(total)
+		}
+		hus = append(hus, *hu)
+	}
+	sort.Sort(hus)
+	return hus
+}
@@ -0,0 +1,67 @@
+package renter
+
+import (
+	"testing"
+
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// TestHostUsage checks that the pieces of every file are attributed to the
+// hosts storing them.
+func TestHostUsage(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+
+	// Only the first host is still in the hostdb.
+	var hdb pricedHostDB
+	var entry modules.HostDBEntry
+	entry.NetAddress = "host0:1"
+	entry.PublicKey = types.SiaPublicKey{Algorithm: types.SignatureEd25519, Key: []byte{1}}
+	hdb.hosts = append(hdb.hosts, entry)
+	rt, err := newContractorTester("TestHostUsage", hdb, stubContractor{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// host0 stores three pieces across two files, host1 stores one.
+	rsc, _ := NewRSCode(1, 1)
+	foo := newFile("foo", rsc, 100, 150)
+	foo.contracts[types.FileContractID{0}] = fileContract{
+		IP:     "host0:1",
+		Pieces: []pieceData{{Chunk: 0, Piece: 0}, {Chunk: 1, Piece: 0}},
+	}
+	foo.contracts[types.FileContractID{1}] = fileContract{
+		IP:     "host1:1",
+		Pieces: []pieceData{{Chunk: 0, Piece: 1}},
+	}
+	bar := newFile("bar", rsc, 100, 50)
+	bar.contracts[types.FileContractID{0}] = fileContract{
+		IP:     "host0:1",
+		Pieces: []pieceData{{Chunk: 0, Piece: 0}},
+	}
+	id := rt.renter.mu.Lock()
+	rt.renter.files["foo"] = foo
+	rt.renter.files["bar"] = bar
+	rt.renter.mu.Unlock(id)
+
+	usage := rt.renter.HostUsage()
+	if len(usage) != 2 {
+		t.Fatal("expected usage for 2 hosts, got", len(usage))
+	}
+	hu := usage[0]
+	if hu.NetAddress != "host0:1" || hu.Bytes != 300 || hu.Pieces != 3 || hu.Chunks != 3 || hu.Files != 2 || hu.Share != 0.75 {
+		t.Errorf("wrong usage for host0: %+v", hu)
+	}
+	if string(hu.PublicKey.Key) != string(entry.PublicKey.Key) {
+		t.Error("public key of host0 was not looked up")
+	}
+	hu = usage[1]
+	if hu.NetAddress != "host1:1" || hu.Bytes != 100 || hu.Chunks != 1 || hu.Files != 1 || hu.Share != 0.25 {
+		t.Errorf("wrong usage for host1: %+v", hu)
+	}
+	if len(hu.PublicKey.Key) != 0 {
+		t.Error("expected no public key for a host missing from the hostdb")
+	}
+}
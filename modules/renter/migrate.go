@@ -0,0 +1,204 @@
+package renter
+
+import (
+	"bytes"
+	"errors"
+	"os"
+
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+var (
+	errMigrationInProgress = errors.New("a host migration is already in progress")
+	errNoHostContract      = errors.New("renter does not have a contract with that host")
+	errUnknownHostKey      = errors.New("no host with that public key is known to the hostdb")
+)
+
+// hostChunks returns the chunks of the file that have pieces stored on the
+// host at addr, mapped to the indices of those pieces.
+func (f *file) hostChunks(addr modules.NetAddress) map[uint64][]uint64 {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	chunks := make(map[uint64][]uint64)
+	for _, fc := range f.contracts {
+		if fc.IP != addr {
+			continue
+		}
+		for _, p := range fc.Pieces {
+			chunks[p.Chunk] = append(chunks[p.Chunk], p.Piece)
+		}
+	}
+	return chunks
+}
+
+// dropMigratedPieces removes the record of pieces stored on the host at addr
+// that have since been uploaded to another host. Contracts that are left
+// without any pieces are removed from the file. The number of chunks that
+// still have pieces stored only on addr is returned.
+func (f *file) dropMigratedPieces(addr modules.NetAddress) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	type pieceID struct {
+		chunk, piece uint64
+	}
+	elsewhere := make(map[pieceID]struct{})
+	for _, fc := range f.contracts {
+		if fc.IP == addr {
+			continue
+		}
+		for _, p := range fc.Pieces {
+			elsewhere[pieceID{p.Chunk, p.Piece}] = struct{}{}
+		}
+	}
+
+	remaining := make(map[uint64]struct{})
+	for id, fc := range f.contracts {
+		if fc.IP != addr {
+			continue
+		}
+		var kept []pieceData
+		for _, p := range fc.Pieces {
+			if _, ok := elsewhere[pieceID{p.Chunk, p.Piece}]; !ok {
+				kept = append(kept, p)
+				remaining[p.Chunk] = struct{}{}
+			}
+		}
+		if len(kept) == 0 {
+			delete(f.contracts, id)
+			continue
+		}
+		fc.Pieces = kept
+		f.contracts[id] = fc
+	}
+	return len(remaining)
+}
+
+// hostAddress returns the net address of the host with the given public key,
+// as known to the hostdb.
+func (r *Renter) hostAddress(pk types.SiaPublicKey) (modules.NetAddress, error) {
+	for _, host := range r.hostDB.AllHosts() {
+		if host.PublicKey.Algorithm == pk.Algorithm && bytes.Equal(host.PublicKey.Key, pk.Key) {
+			return host.NetAddress, nil
+		}
+	}
+	return "", errUnknownHostKey
+}
+
+// MigrateHost starts moving every piece stored on the host with the given
+// public key to other hosts. The contract with the host is retired first, so
+// that neither the migration nor the repair loop place new data on it. The
+// migration runs in the background; its progress is reported by Migration.
+func (r *Renter) MigrateHost(pk types.SiaPublicKey) error {
+	addr, err := r.hostAddress(pk)
+	if err != nil {
+		return err
+	}
+	contract, ok := r.hostContractor.Contract(addr)
+	if !ok {
+		return errNoHostContract
+	}
+
+	id := r.mu.Lock()
+	if r.migration.Active {
+		r.mu.Unlock(id)
+		return errMigrationInProgress
+	}
+	r.migration = modules.RenterMigration{
+		Host:       pk,
+		NetAddress: addr,
+		Active:     true,
+	}
+	r.mu.Unlock(id)
+
+	if err := r.hostContractor.RetireContract(contract.ID); err != nil {
+		id = r.mu.Lock()
+		r.migration.Active = false
+		r.mu.Unlock(id)
+		return err
+	}
+	go r.threadedMigrateHost(addr)
+	return nil
+}
+
+// threadedMigrateHost re-uploads every chunk that has pieces on the host at
+// addr to other hosts, then forgets the pieces on addr that were replaced.
+// Files without a local copy are skipped, as there is nothing to upload from.
+func (r *Renter) threadedMigrateHost(addr modules.NetAddress) {
+	defer func() {
+		id := r.mu.Lock()
+		r.migration.Active = false
+		r.mu.Unlock(id)
+	}()
+
+	// Determine which files have data on the host.
+	var migrating []*file
+	id := r.mu.Lock()
+	for name, f := range r.files {
+		chunks := f.hostChunks(addr)
+		if len(chunks) == 0 {
+			continue
+		}
+		r.migration.Files = append(r.migration.Files, name)
+		r.migration.ChunksTotal += len(chunks)
+		if _, ok := r.tracking[name]; !ok {
+			r.migration.SkippedFiles = append(r.migration.SkippedFiles, name)
+			continue
+		}
+		migrating = append(migrating, f)
+	}
+	r.mu.Unlock(id)
+
+	pool := r.newHostPool()
+	pool.blacklist = append(pool.blacklist, addr)
+	defer pool.Close()
+	for _, f := range migrating {
+		id := r.mu.RLock()
+		meta, ok := r.tracking[f.name]
+		r.mu.RUnlock(id)
+		if !ok {
+			continue
+		}
+		handle, err := os.Open(meta.RepairPath)
+		if err != nil {
+			r.log.Printf("skipping migration of %v: %v", f.name, err)
+			id := r.mu.Lock()
+			r.migration.SkippedFiles = append(r.migration.SkippedFiles, f.name)
+			r.mu.Unlock(id)
+			continue
+		}
+
+		chunks := f.hostChunks(addr)
+		r.repairChunks(f, handle, chunks, pool)
+		handle.Close()
+
+		remaining := f.dropMigratedPieces(addr)
+		f.mu.RLock()
+		err = r.saveFile(f)
+		f.mu.RUnlock()
+		if err != nil {
+			r.log.Printf("failed to save migrated file %v: %v", f.name, err)
+		}
+
+		id = r.mu.Lock()
+		r.migration.ChunksMigrated += len(chunks) - remaining
+		r.mu.Unlock(id)
+	}
+
+	id = r.mu.RLock()
+	r.log.Printf("migration off of host %v finished: %v of %v chunks migrated", addr, r.migration.ChunksMigrated, r.migration.ChunksTotal)
+	r.mu.RUnlock(id)
+}
+
+// Migration returns the progress of the most recent host migration.
+func (r *Renter) Migration() modules.RenterMigration {
+	lockID := r.mu.RLock()
+	defer r.mu.RUnlock(lockID)
+
+	m := r.migration
+	m.Files = append([]string(nil), r.migration.Files...)
+	m.SkippedFiles = append([]string(nil), r.migration.SkippedFiles...)
+	return m
+}
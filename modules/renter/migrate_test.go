@@ -0,0 +1,85 @@
+package renter
+
+import (
+	"testing"
+
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// TestDropMigratedPieces checks that only the pieces that have been copied to
+// another host are forgotten after a migration.
+func TestDropMigratedPieces(t *testing.T) {
+	oldHost := modules.NetAddress("old:1")
+	newHost := modules.NetAddress("new:1")
+	f := &file{
+		contracts: map[types.FileContractID]fileContract{
+			{0}: {
+				ID: types.FileContractID{0},
+				IP: oldHost,
+				Pieces: []pieceData{
+					{Chunk: 0, Piece: 0},
+					{Chunk: 1, Piece: 0},
+				},
+			},
+			{1}: {
+				ID: types.FileContractID{1},
+				IP: newHost,
+				Pieces: []pieceData{
+					{Chunk: 0, Piece: 1},
+				},
+			},
+		},
+	}
+
+	chunks := f.hostChunks(oldHost)
+	if len(chunks) != 2 || len(chunks[0]) != 1 || len(chunks[1]) != 1 {
+		t.Fatal("wrong chunks reported for the old host:", chunks)
+	}
+
+	// Nothing has been copied yet, so nothing should be dropped.
+	if remaining := f.dropMigratedPieces(oldHost); remaining != 2 {
+		t.Fatal("expected 2 chunks to remain on the old host, got", remaining)
+	}
+
+	// Copy the first chunk's piece to the new host.
+	fc := f.contracts[types.FileContractID{1}]
+	fc.Pieces = append(fc.Pieces, pieceData{Chunk: 0, Piece: 0})
+	f.contracts[fc.ID] = fc
+	if remaining := f.dropMigratedPieces(oldHost); remaining != 1 {
+		t.Fatal("expected 1 chunk to remain on the old host, got", remaining)
+	}
+	if chunks := f.hostChunks(oldHost); len(chunks) != 1 || chunks[1] == nil {
+		t.Fatal("wrong chunks reported for the old host:", chunks)
+	}
+
+	// Once every piece has been copied the contract should be removed.
+	fc.Pieces = append(fc.Pieces, pieceData{Chunk: 1, Piece: 0})
+	f.contracts[fc.ID] = fc
+	if remaining := f.dropMigratedPieces(oldHost); remaining != 0 {
+		t.Fatal("expected no chunks to remain on the old host, got", remaining)
+	}
+	if _, ok := f.contracts[types.FileContractID{0}]; ok {
+		t.Fatal("contract with the old host was not removed")
+	}
+}
+
+// TestMigrateHostUnknown checks that migrating off of an unknown host fails.
+func TestMigrateHostUnknown(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	rt, err := newRenterTester("TestMigrateHostUnknown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rt.Close()
+
+	pk := types.SiaPublicKey{Algorithm: types.SignatureEd25519, Key: []byte("foo")}
+	if err := rt.renter.MigrateHost(pk); err != errUnknownHostKey {
+		t.Fatal("expected errUnknownHostKey, got", err)
+	}
+	if rt.renter.Migration().Active {
+		t.Fatal("migration should not be active")
+	}
+}
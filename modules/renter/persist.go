@@ -181,16 +181,20 @@ func (r *Renter) saveFile(f *file) error {
 // save stores the current renter data to disk.
 func (r *Renter) save() error {
 	data := struct {
-		Tracking map[string]trackedFile
-	}{r.tracking}
+		Tracking       map[string]trackedFile
+		AccessLogging  bool
+		WorkerPoolSize int
+	}{r.tracking, r.accessLogging, r.workers.poolSize()}
 	return persist.SaveFile(saveMetadata, data, filepath.Join(r.persistDir, PersistFilename))
 }
 
 // saveSync stores the current renter data to disk and then syncs to disk.
 func (r *Renter) saveSync() error {
 	data := struct {
-		Tracking map[string]trackedFile
-	}{r.tracking}
+		Tracking       map[string]trackedFile
+		AccessLogging  bool
+		WorkerPoolSize int
+	}{r.tracking, r.accessLogging, r.workers.poolSize()}
 	return persist.SaveFileSync(saveMetadata, data, filepath.Join(r.persistDir, PersistFilename))
 }
 
@@ -232,8 +236,10 @@ func (r *Renter) load() error {
 
 	// Load contracts, repair set, and entropy.
 	data := struct {
-		Tracking  map[string]trackedFile
-		Repairing map[string]string // COMPATv0.4.8
+		Tracking       map[string]trackedFile
+		Repairing      map[string]string // COMPATv0.4.8
+		AccessLogging  bool
+		WorkerPoolSize int
 	}{}
 	err = persist.LoadFile(saveMetadata, &data, filepath.Join(r.persistDir, PersistFilename))
 	if err != nil {
@@ -242,6 +248,19 @@ func (r *Renter) load() error {
 	if data.Tracking != nil {
 		r.tracking = data.Tracking
 	}
+	r.accessLogging = data.AccessLogging
+	// The worker pool size is absent from older persist files, in which case
+	// the default is kept.
+	if checkWorkerPoolSize(data.WorkerPoolSize) == nil {
+		r.workers.setSize(data.WorkerPoolSize)
+	}
+
+	// Load the access log. The log is not created until the first download
+	// is recorded.
+	err = r.loadAccessLog()
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
 
 	return nil
 }
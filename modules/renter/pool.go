@@ -92,7 +92,7 @@ func (p *hostPool) uniqueHosts(n int, exclude []modules.NetAddress) (hosts []con
 
 	// Next try to reuse existing contracts.
 	for _, contract := range p.hostContractor.Contracts() {
-		if _, ok := excludeSet[contract.NetAddress]; ok {
+		if _, ok := excludeSet[contract.NetAddress]; ok || contract.Retired {
 			continue
 		}
 		hu, err := p.add(contract)
@@ -117,6 +117,24 @@ func (he *Editor) Upload(data []byte) (modules.RenterContract, crypto.Hash, erro
 		return modules.RenterContract{}, crypto.Hash{}, errors.New("contract has insufficient funds to support upload")
 	}
 	sectorCollateral := he.host.Collateral.Mul(blockBytes)
+	// the host puts up no more than the MaxCollateral in force when the
+	// contract was formed over the life of the contract; once the cap is
+	// reached, sectors are stored without additional collateral
+	maxCollateral := he.contract.MaxCollateral
+	if maxCollateral.IsZero() {
+		maxCollateral = he.host.MaxCollateral
+	}
+	originMissed := he.contract.FileContract.MissedProofOutputs[1].Value
+	currentMissed := he.contract.LastRevision.NewMissedProofOutputs[1].Value
+	var committed types.Currency
+	if originMissed.Cmp(currentMissed) > 0 {
+		committed = originMissed.Sub(currentMissed)
+	}
+	if committed.Cmp(maxCollateral) >= 0 {
+		sectorCollateral = types.ZeroCurrency
+	} else if remaining := maxCollateral.Sub(committed); sectorCollateral.Cmp(remaining) > 0 {
+		sectorCollateral = remaining
+	}
 	if he.contract.LastRevision.NewMissedProofOutputs[1].Value.Cmp(sectorCollateral) < 0 {
 		return modules.RenterContract{}, crypto.Hash{}, errors.New("contract has insufficient collateral to support upload")
 	}
@@ -260,5 +260,6 @@ func FormContract(params ContractParams, txnBuilder transactionBuilder, tpool tr
 		LastRevisionTxn: revisionTxn,
 		NetAddress:      host.NetAddress,
 		SecretKey:       ourSK,
+		StartHeight:     startHeight,
 	}, nil
 }
@@ -255,5 +255,6 @@ func Renew(contract modules.RenterContract, params ContractParams, txnBuilder tr
 		MerkleRoots:     contract.MerkleRoots,
 		NetAddress:      host.NetAddress,
 		SecretKey:       ourSK,
+		StartHeight:     startHeight,
 	}, nil
 }
@@ -0,0 +1,137 @@
+package renter
+
+import (
+	"errors"
+	"math"
+
+	"github.com/NebulousLabs/Sia/crypto"
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+var (
+	errNoRepairSource   = errors.New("file has no local copy to upload new pieces from")
+	errRedundancyTooLow = errors.New("redundancy must be greater than 1")
+)
+
+// setErasureCode replaces the erasure code of the file. The parity pieces of a
+// Reed-Solomon code are the same for any number of parity pieces, so pieces
+// that have already been uploaded remain valid under the new code. Records of
+// pieces with an index beyond the new code are removed from the file, and
+// their Merkle roots are returned by contract. Contracts that are left without
+// any pieces are removed from the file.
+func (f *file) setErasureCode(code modules.ErasureCoder) map[types.FileContractID][]crypto.Hash {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.erasureCode = code
+	excess := make(map[types.FileContractID][]crypto.Hash)
+	for id, fc := range f.contracts {
+		var kept []pieceData
+		for _, p := range fc.Pieces {
+			if p.Piece < uint64(code.NumPieces()) {
+				kept = append(kept, p)
+			} else {
+				excess[id] = append(excess[id], p.MerkleRoot)
+			}
+		}
+		if len(kept) == len(fc.Pieces) {
+			continue
+		}
+		if len(kept) == 0 {
+			delete(f.contracts, id)
+			continue
+		}
+		fc.Pieces = kept
+		f.contracts[id] = fc
+	}
+	return excess
+}
+
+// SetFileRedundancy changes the redundancy of the file at siapath by changing
+// the number of parity pieces in each chunk. The existing pieces are kept;
+// when the redundancy is raised, the repair loop uploads the additional
+// parity pieces to new hosts, which requires a local copy of the file. When
+// the redundancy is lowered, the excess pieces are forgotten and deleted from
+// their hosts in the background.
+func (r *Renter) SetFileRedundancy(siapath string, redundancy float64) (modules.RenterRedundancyChange, error) {
+	if redundancy <= 1 {
+		return modules.RenterRedundancyChange{}, errRedundancyTooLow
+	}
+
+	lockID := r.mu.Lock()
+	defer r.mu.Unlock(lockID)
+	f, ok := r.files[siapath]
+	if !ok {
+		return modules.RenterRedundancyChange{}, ErrUnknownPath
+	}
+
+	f.mu.RLock()
+	dataPieces := f.erasureCode.MinPieces()
+	oldParity := f.erasureCode.NumPieces() - dataPieces
+	numChunks := f.numChunks()
+	f.mu.RUnlock()
+	newParity := int(math.Ceil(redundancy*float64(dataPieces))) - dataPieces
+	change := modules.RenterRedundancyChange{
+		SiaPath:         siapath,
+		DataPieces:      dataPieces,
+		OldParityPieces: oldParity,
+		NewParityPieces: newParity,
+	}
+	if newParity == oldParity {
+		return change, nil
+	}
+	if tf, tracked := r.tracking[siapath]; newParity > oldParity && (!tracked || tf.SourceDeleted) {
+		return modules.RenterRedundancyChange{}, errNoRepairSource
+	}
+	code, err := NewRSCode(dataPieces, newParity)
+	if err != nil {
+		return modules.RenterRedundancyChange{}, err
+	}
+
+	excess := f.setErasureCode(code)
+	f.mu.RLock()
+	err = r.saveFile(f)
+	f.mu.RUnlock()
+	if err != nil {
+		return modules.RenterRedundancyChange{}, err
+	}
+
+	if newParity > oldParity {
+		change.PiecesToUpload = numChunks * uint64(newParity-oldParity)
+		change.UploadSize = change.PiecesToUpload * modules.SectorSize
+	}
+	for _, roots := range excess {
+		change.ExcessPieces += uint64(len(roots))
+	}
+	change.ExcessSize = change.ExcessPieces * modules.SectorSize
+	if len(excess) != 0 {
+		go r.threadedDeleteExcessPieces(excess)
+	}
+	r.log.Printf("changed parity of %v from %v to %v pieces: %v pieces to upload, %v excess pieces", siapath, oldParity, newParity, change.PiecesToUpload, change.ExcessPieces)
+	return change, nil
+}
+
+// threadedDeleteExcessPieces deletes pieces that are no longer part of any
+// file from the hosts storing them. Deletion is best effort; pieces on hosts
+// that cannot be reached are left in place until their contract ends.
+func (r *Renter) threadedDeleteExcessPieces(excess map[types.FileContractID][]crypto.Hash) {
+	for _, c := range r.hostContractor.Contracts() {
+		roots, ok := excess[c.ID]
+		if !ok {
+			continue
+		}
+		editor, err := r.hostContractor.Editor(c)
+		if err != nil {
+			r.log.Printf("unable to delete %v excess pieces from host %v: %v", len(roots), c.NetAddress, err)
+			continue
+		}
+		for _, root := range roots {
+			if err := editor.Delete(root); err != nil {
+				r.log.Printf("unable to delete excess piece from host %v: %v", c.NetAddress, err)
+				break
+			}
+		}
+		editor.Close()
+	}
+}
@@ -0,0 +1,147 @@
+package renter
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// TestRSParityPrefix checks that the parity pieces of a Reed-Solomon code do
+// not depend on the number of parity pieces, which allows the redundancy of a
+// file to be changed without reuploading its existing pieces.
+func TestRSParityPrefix(t *testing.T) {
+	small, err := NewRSCode(4, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	large, err := NewRSCode(4, 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := make([]byte, 4096)
+	rand.Read(data)
+
+	smallPieces, err := small.Encode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	largePieces, err := large.Encode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range smallPieces {
+		if !bytes.Equal(smallPieces[i], largePieces[i]) {
+			t.Fatalf("piece %v differs between codes", i)
+		}
+	}
+
+	// The large code should recover the data from the pieces of the small
+	// code and a single additional parity piece.
+	pieces := make([][]byte, large.NumPieces())
+	pieces[1] = smallPieces[1]
+	pieces[4] = smallPieces[4]
+	pieces[5] = smallPieces[5]
+	pieces[9] = largePieces[9]
+	var buf bytes.Buffer
+	if err := large.Recover(pieces, uint64(len(data)), &buf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), data) {
+		t.Fatal("recovered data does not match")
+	}
+}
+
+// TestFileSetErasureCode checks that shrinking the erasure code of a file
+// removes the pieces beyond the new code, and that growing it leaves the new
+// pieces to be repaired.
+func TestFileSetErasureCode(t *testing.T) {
+	rsc, _ := NewRSCode(1, 3)
+	f := &file{
+		size:        1,
+		pieceSize:   1,
+		erasureCode: rsc,
+		contracts: map[types.FileContractID]fileContract{
+			{0}: {ID: types.FileContractID{0}, Pieces: []pieceData{{Piece: 0}, {Piece: 3}}},
+			{1}: {ID: types.FileContractID{1}, Pieces: []pieceData{{Piece: 1}}},
+			{2}: {ID: types.FileContractID{2}, Pieces: []pieceData{{Piece: 2}}},
+		},
+	}
+
+	smaller, _ := NewRSCode(1, 1)
+	excess := f.setErasureCode(smaller)
+	if len(excess[types.FileContractID{0}]) != 1 || len(excess[types.FileContractID{2}]) != 1 || len(excess) != 2 {
+		t.Fatal("wrong excess pieces:", excess)
+	}
+	if _, ok := f.contracts[types.FileContractID{2}]; ok {
+		t.Fatal("contract without pieces should have been removed")
+	}
+	if len(f.contracts[types.FileContractID{0}].Pieces) != 1 {
+		t.Fatal("piece within the code should have been kept")
+	}
+	if len(f.incompleteChunks()) != 0 {
+		t.Fatal("file should be complete under the smaller code")
+	}
+
+	larger, _ := NewRSCode(1, 4)
+	if excess := f.setErasureCode(larger); len(excess) != 0 {
+		t.Fatal("growing the code should not create excess pieces")
+	}
+	if missing := f.incompleteChunks()[0]; len(missing) != 3 || missing[0] != 2 {
+		t.Fatal("wrong missing pieces:", missing)
+	}
+}
+
+// TestRenterSetFileRedundancy tests the SetFileRedundancy method of the
+// renter.
+func TestRenterSetFileRedundancy(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	rt, err := newRenterTester("TestRenterSetFileRedundancy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rt.Close()
+
+	if _, err := rt.renter.SetFileRedundancy("foo", 2); err != ErrUnknownPath {
+		t.Fatal("expected ErrUnknownPath, got", err)
+	}
+	rsc, _ := NewRSCode(2, 4)
+	f := newFile("foo", rsc, 10, 100)
+	rt.renter.files["foo"] = f
+	if _, err := rt.renter.SetFileRedundancy("foo", 1); err != errRedundancyTooLow {
+		t.Fatal("expected errRedundancyTooLow, got", err)
+	}
+
+	// Raising the redundancy requires a local copy to repair from.
+	if _, err := rt.renter.SetFileRedundancy("foo", 4); err != errNoRepairSource {
+		t.Fatal("expected errNoRepairSource, got", err)
+	}
+	rt.renter.tracking["foo"] = trackedFile{RepairPath: "/foo"}
+	change, err := rt.renter.SetFileRedundancy("foo", 3.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 5 chunks gain 1 parity piece each.
+	if change.DataPieces != 2 || change.OldParityPieces != 4 || change.NewParityPieces != 5 || change.PiecesToUpload != 5 {
+		t.Fatalf("unexpected change: %+v", change)
+	}
+	if change.UploadSize != 5*modules.SectorSize {
+		t.Fatal("wrong upload size:", change.UploadSize)
+	}
+	if f.erasureCode.NumPieces() != 7 {
+		t.Fatal("erasure code was not changed")
+	}
+
+	// Lowering the redundancy does not upload anything.
+	change, err = rt.renter.SetFileRedundancy("foo", 1.2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if change.NewParityPieces != 1 || change.PiecesToUpload != 0 || change.ExcessPieces != 0 {
+		t.Fatalf("unexpected change: %+v", change)
+	}
+}
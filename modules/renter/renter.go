@@ -1,6 +1,8 @@
 package renter
 
 import (
+	"time"
+
 	"github.com/NebulousLabs/Sia/modules"
 	"github.com/NebulousLabs/Sia/modules/renter/contractor"
 	"github.com/NebulousLabs/Sia/modules/renter/hostdb"
@@ -58,6 +60,22 @@ type hostContractor interface {
 	// Downloader creates a Downloader from the specified contract, allowing
 	// the retrieval of sectors.
 	Downloader(modules.RenterContract) (contractor.Downloader, error)
+
+	// ExpiredContracts returns the contracts that have passed their end
+	// height and have not yet been cleared.
+	ExpiredContracts() []modules.RenterContract
+
+	// ExpiryWarning describes what the expiry policy of the allowance will
+	// do at the end of the current period, if the end is near.
+	ExpiryWarning() string
+
+	// ClearExpiredContracts forgets the expired contracts with the provided
+	// ids.
+	ClearExpiredContracts([]types.FileContractID) error
+
+	// RetireContract marks a contract as retired, preventing it from being
+	// used for new uploads or renewed.
+	RetireContract(types.FileContractID) error
 }
 
 // A trackedFile contains metadata about files being tracked by the Renter.
@@ -67,6 +85,18 @@ type hostContractor interface {
 type trackedFile struct {
 	// location of original file on disk
 	RepairPath string
+
+	// DeleteAfterSuccess indicates that the original file should be deleted
+	// once every piece has been uploaded. SourceModTime is the modification
+	// time of the original file when the upload started; the file is not
+	// deleted if it has changed since. SourceDeleted is set once the
+	// original file has been deleted, after which the file is not repaired.
+	DeleteAfterSuccess bool
+	SourceModTime      time.Time
+	SourceDeleted      bool
+
+	// UploadTime is the time at which the upload was started.
+	UploadTime time.Time
 }
 
 // A Renter is responsible for tracking all of the files that a user has
@@ -85,6 +115,20 @@ type Renter struct {
 	tracking      map[string]trackedFile // map from nickname to metadata
 	downloadQueue []*download
 
+	// accessLogging indicates whether downloads are recorded in accessLog.
+	// accessLogSaved is when the log was last saved, and accessLogUnsaved
+	// indicates that it has entries that have not been saved since.
+	accessLogging    bool
+	accessLog        []modules.DownloadAccess
+	accessLogSaved   time.Time
+	accessLogUnsaved bool
+
+	// migration tracks the progress of the most recent host migration.
+	migration modules.RenterMigration
+
+	// workers limits the number of pieces uploaded to hosts concurrently.
+	workers *workerPool
+
 	// constants
 	persistDir string
 
@@ -113,6 +157,7 @@ func newRenter(cs modules.ConsensusSet, tpool modules.TransactionPool, hdb hostD
 
 		files:    make(map[string]*file),
 		tracking: make(map[string]trackedFile),
+		workers:  newWorkerPool(defaultWorkerPoolSize),
 
 		persistDir: persistDir,
 		mu:         sync.New(modules.SafeMutexDelay, 1),
@@ -128,6 +173,12 @@ func newRenter(cs modules.ConsensusSet, tpool modules.TransactionPool, hdb hostD
 
 // Close closes the Renter and its dependencies
 func (r *Renter) Close() error {
+	lockID := r.mu.Lock()
+	err := r.flushAccessLog()
+	r.mu.Unlock(lockID)
+	if err != nil {
+		r.log.Println("WARN: could not save access log:", err)
+	}
 	return r.hostDB.Close()
 }
 
@@ -137,16 +188,37 @@ func (r *Renter) AllHosts() []modules.HostDBEntry    { return r.hostDB.AllHosts(
 
 // contractor passthroughs
 func (r *Renter) Contracts() []modules.RenterContract { return r.hostContractor.Contracts() }
+func (r *Renter) ExpiryWarning() string               { return r.hostContractor.ExpiryWarning() }
 func (r *Renter) FinancialMetrics() modules.RenterFinancialMetrics {
 	return r.hostContractor.FinancialMetrics()
 }
 func (r *Renter) Settings() modules.RenterSettings {
+	lockID := r.mu.RLock()
+	accessLogging := r.accessLogging
+	r.mu.RUnlock(lockID)
 	return modules.RenterSettings{
-		Allowance: r.hostContractor.Allowance(),
+		Allowance:     r.hostContractor.Allowance(),
+		AccessLogging: accessLogging,
 	}
 }
+
+// SetSettings updates the renter's settings. The allowance is only passed to
+// the contractor if it differs from the current allowance.
 func (r *Renter) SetSettings(s modules.RenterSettings) error {
-	return r.hostContractor.SetAllowance(s.Allowance)
+	a := r.hostContractor.Allowance()
+	if s.Allowance.Funds.Cmp(a.Funds) != 0 || s.Allowance.Hosts != a.Hosts || s.Allowance.Period != a.Period || s.Allowance.RenewWindow != a.RenewWindow || s.Allowance.ExpiryPolicy != a.ExpiryPolicy {
+		if err := r.hostContractor.SetAllowance(s.Allowance); err != nil {
+			return err
+		}
+	}
+
+	lockID := r.mu.Lock()
+	defer r.mu.Unlock(lockID)
+	if r.accessLogging == s.AccessLogging {
+		return nil
+	}
+	r.accessLogging = s.AccessLogging
+	return r.saveSync()
 }
 
 // enforce that Renter satisfies the modules.Renter interface
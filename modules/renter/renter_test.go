@@ -187,3 +187,7 @@ func (stubContractor) Editor(modules.RenterContract) (contractor.Editor, error)
 func (stubContractor) Downloader(modules.RenterContract) (contractor.Downloader, error) {
 	return nil, nil
 }
+func (stubContractor) RetireContract(types.FileContractID) error          { return nil }
+func (stubContractor) ExpiredContracts() []modules.RenterContract         { return nil }
+func (stubContractor) ExpiryWarning() string                              { return "" }
+func (stubContractor) ClearExpiredContracts([]types.FileContractID) error { return nil }
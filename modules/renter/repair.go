@@ -54,8 +54,8 @@ func (hs hostErrs) Error() string {
 }
 
 // repair attempts to repair a file chunk by uploading its pieces to more
-// hosts.
-func (f *file) repair(chunkIndex uint64, missingPieces []uint64, r io.ReaderAt, hosts []contractor.Editor) error {
+// hosts. Each piece is uploaded by a worker from the provided pool.
+func (f *file) repair(chunkIndex uint64, missingPieces []uint64, r io.ReaderAt, hosts []contractor.Editor, workers *workerPool) error {
 	// read chunk data and encode
 	chunk := make([]byte, f.chunkSize())
 	_, err := r.ReadAt(chunk, int64(chunkIndex*f.chunkSize()))
@@ -75,6 +75,16 @@ func (f *file) repair(chunkIndex uint64, missingPieces []uint64, r io.ReaderAt,
 		}
 	}
 
+	// The erasure code may have shrunk since the missing pieces were
+	// determined; pieces beyond it are no longer needed.
+	var needed []uint64
+	for _, pieceIndex := range missingPieces {
+		if pieceIndex < uint64(len(pieces)) {
+			needed = append(needed, pieceIndex)
+		}
+	}
+	missingPieces = needed
+
 	// upload one piece per host
 	numPieces := len(missingPieces)
 	if len(hosts) < numPieces {
@@ -84,11 +94,15 @@ func (f *file) repair(chunkIndex uint64, missingPieces []uint64, r io.ReaderAt,
 	for i := 0; i < numPieces; i++ {
 		go func(pieceIndex uint64, host contractor.Editor) {
 			// upload data to host
+			id := workers.acquire(host.Address())
+			start := time.Now()
 			root, err := host.Upload(pieces[pieceIndex])
+			workers.release(id)
 			if err != nil {
 				errChan <- &hostErr{host.Address(), err}
 				return
 			}
+			workers.recordUpload(host.Address(), uint64(len(pieces[pieceIndex])), time.Since(start))
 
 			// create contract entry, if necessary
 			f.mu.Lock()
@@ -138,7 +152,10 @@ func (f *file) incompleteChunks() map[uint64][]uint64 {
 	}
 	for _, fc := range f.contracts {
 		for _, p := range fc.Pieces {
-			present[p.Chunk][p.Piece] = true
+			// pieces uploaded under a larger erasure code are not counted
+			if p.Piece < uint64(len(present[p.Chunk])) {
+				present[p.Chunk][p.Piece] = true
+			}
 		}
 	}
 
@@ -259,9 +276,17 @@ func (r *Renter) threadedRepairFile(name string, meta trackedFile, pool *hostPoo
 		return
 	}
 
+	// there is nothing to repair from once the source has been deleted
+	if meta.SourceDeleted {
+		return
+	}
+
 	// determine if there is any work to do
 	incChunks := f.incompleteChunks()
 	if len(incChunks) == 0 {
+		if meta.DeleteAfterSuccess {
+			r.managedDeleteSource(f, meta)
+		}
 		return
 	}
 
@@ -291,7 +316,7 @@ func (r *Renter) repairChunks(f *file, handle io.ReaderAt, chunks map[uint64][]u
 			return
 		}
 		// upload to new hosts
-		err := f.repair(chunk, pieces, handle, hosts)
+		err := f.repair(chunk, pieces, handle, hosts, r.workers)
 		if err != nil {
 			if he, ok := err.(hostErrs); ok {
 				// if a specific host failed, remove it from the pool
@@ -98,7 +98,7 @@ func TestRepair(t *testing.T) {
 	f := newFile("foo", rsc, pieceSize, dataSize)
 	r := bytes.NewReader(data)
 	for chunk, pieces := range f.incompleteChunks() {
-		err = f.repair(chunk, pieces, r, hosts)
+		err = f.repair(chunk, pieces, r, hosts, newWorkerPool(defaultWorkerPoolSize))
 		// hostErrs are non-fatal
 		if _, ok := err.(hostErrs); ok {
 			continue
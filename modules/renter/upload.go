@@ -4,6 +4,7 @@ import (
 	"errors"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/NebulousLabs/Sia/build"
 	"github.com/NebulousLabs/Sia/crypto"
@@ -77,7 +78,10 @@ func (r *Renter) Upload(up modules.FileUploadParams) error {
 	lockID = r.mu.Lock()
 	r.files[up.SiaPath] = f
 	r.tracking[up.SiaPath] = trackedFile{
-		RepairPath: up.Source,
+		RepairPath:         up.Source,
+		DeleteAfterSuccess: up.DeleteAfterSuccess,
+		SourceModTime:      fileInfo.ModTime(),
+		UploadTime:         time.Now(),
 	}
 	r.saveSync()
 	r.mu.Unlock(lockID)
@@ -0,0 +1,136 @@
+package renter
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/persist"
+)
+
+const (
+	// stagingDir is the directory within the renter's persist directory that
+	// holds the content fetched for uploads from a URL. The staged copy is
+	// the file's repair source, so it is kept until the file is deleted.
+	stagingDir = "staging"
+
+	// defaultURLUploadTimeout is the time allowed for fetching a remote file
+	// unless a timeout is given.
+	defaultURLUploadTimeout = 1 * time.Hour
+
+	// maxURLUploadRedirects is the number of redirects that are followed when
+	// fetching a remote file.
+	maxURLUploadRedirects = 5
+)
+
+var (
+	errRemoteTooLarge     = errors.New("remote file exceeds the maximum upload size")
+	errTooManyRedirects   = fmt.Errorf("remote server redirected more than %v times", maxURLUploadRedirects)
+	errUnsupportedURLType = errors.New("only http and https URLs can be uploaded")
+)
+
+// fetchURL downloads the content at rawurl into dst. At most maxSize bytes
+// are accepted; a maxSize of zero means there is no limit.
+func fetchURL(rawurl string, dst io.Writer, maxSize uint64, timeout time.Duration) error {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errUnsupportedURLType
+	}
+
+	client := &http.Client{
+		Timeout: timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > maxURLUploadRedirects {
+				return errTooManyRedirects
+			}
+			return nil
+		},
+	}
+	resp, err := client.Get(u.String())
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.New("remote server returned " + resp.Status)
+	}
+	if maxSize == 0 {
+		_, err = io.Copy(dst, resp.Body)
+		return err
+	}
+	if resp.ContentLength > 0 && uint64(resp.ContentLength) > maxSize {
+		return errRemoteTooLarge
+	}
+	// Read one byte past the limit so that content without a Content-Length
+	// header is caught when it is too large.
+	n, err := io.Copy(dst, io.LimitReader(resp.Body, int64(maxSize)+1))
+	if err != nil {
+		return err
+	}
+	if uint64(n) > maxSize {
+		return errRemoteTooLarge
+	}
+	return nil
+}
+
+// UploadURL fetches the content at the provided URL into the renter's staging
+// directory and uploads it to siapath. The fetched content is kept as the
+// repair source of the file until the file is deleted.
+func (r *Renter) UploadURL(rawurl, siapath string, maxSize uint64, timeout time.Duration) error {
+	if strings.HasPrefix(siapath, "/") {
+		return errors.New("nicknames cannot begin with /")
+	}
+	if siapath == "" {
+		return ErrEmptyFilename
+	}
+	lockID := r.mu.RLock()
+	_, exists := r.files[siapath]
+	r.mu.RUnlock(lockID)
+	if exists {
+		return ErrPathOverload
+	}
+	if timeout == 0 {
+		timeout = defaultURLUploadTimeout
+	}
+
+	dir := filepath.Join(r.persistDir, stagingDir)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	source := filepath.Join(dir, persist.RandomSuffix())
+	f, err := os.OpenFile(source, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	err = fetchURL(rawurl, f, maxSize, timeout)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = r.Upload(modules.FileUploadParams{
+			Source:  source,
+			SiaPath: siapath,
+		})
+	}
+	if err != nil {
+		os.Remove(source)
+		return err
+	}
+	r.log.Printf("Fetched %v for upload to %v", rawurl, siapath)
+	return nil
+}
+
+// isStaged returns true if path is a file in the renter's staging directory.
+func (r *Renter) isStaged(path string) bool {
+	return filepath.Dir(path) == filepath.Join(r.persistDir, stagingDir)
+}
@@ -0,0 +1,62 @@
+package renter
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestFetchURL checks that fetchURL follows redirects, enforces the size
+// limit, and reports non-200 responses and redirect loops.
+func TestFetchURL(t *testing.T) {
+	content := []byte("remote content")
+	mux := http.NewServeMux()
+	mux.HandleFunc("/file", func(w http.ResponseWriter, req *http.Request) {
+		w.Write(content)
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, req *http.Request) {
+		http.Redirect(w, req, "/file", http.StatusFound)
+	})
+	mux.HandleFunc("/loop", func(w http.ResponseWriter, req *http.Request) {
+		http.Redirect(w, req, "/loop", http.StatusFound)
+	})
+	mux.HandleFunc("/chunked", func(w http.ResponseWriter, req *http.Request) {
+		// Flushing before writing the body prevents a Content-Length header
+		// from being sent.
+		w.(http.Flusher).Flush()
+		w.Write(content)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	if err := fetchURL(srv.URL+"/redirect", &buf, 0, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), content) {
+		t.Fatal("fetched content does not match the remote content")
+	}
+	buf.Reset()
+	if err := fetchURL(srv.URL+"/file", &buf, uint64(len(content)), time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := fetchURL(srv.URL+"/file", &buf, uint64(len(content)-1), time.Minute); err != errRemoteTooLarge {
+		t.Fatal("expected errRemoteTooLarge, got", err)
+	}
+	if err := fetchURL(srv.URL+"/chunked", &buf, uint64(len(content)-1), time.Minute); err != errRemoteTooLarge {
+		t.Fatal("expected errRemoteTooLarge, got", err)
+	}
+	if err := fetchURL(srv.URL+"/missing", &buf, 0, time.Minute); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Fatal("expected a 404 error, got", err)
+	}
+	if err := fetchURL(srv.URL+"/loop", &buf, 0, time.Minute); err == nil || !strings.Contains(err.Error(), errTooManyRedirects.Error()) {
+		t.Fatal("expected errTooManyRedirects, got", err)
+	}
+	if err := fetchURL("ftp://example.com/file", &buf, 0, time.Minute); err != errUnsupportedURLType {
+		t.Fatal("expected errUnsupportedURLType, got", err)
+	}
+}
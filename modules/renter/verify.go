@@ -0,0 +1,113 @@
+package renter
+
+import (
+	"errors"
+	"io"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/NebulousLabs/Sia/crypto"
+	"github.com/NebulousLabs/Sia/modules"
+)
+
+var errSourceMismatch = errors.New("downloaded data does not match the local copy of the file")
+
+// hashSource returns the hash of the local copy of a file, and false if there
+// is no local copy or it has changed since the upload started.
+func hashSource(meta trackedFile, size uint64) (crypto.Hash, bool) {
+	if meta.RepairPath == "" || meta.SourceDeleted {
+		return crypto.Hash{}, false
+	}
+	f, err := os.Open(meta.RepairPath)
+	if err != nil {
+		return crypto.Hash{}, false
+	}
+	defer f.Close()
+	stat, err := f.Stat()
+	if err != nil || uint64(stat.Size()) != size || !stat.ModTime().Equal(meta.SourceModTime) {
+		return crypto.Hash{}, false
+	}
+	h := crypto.NewHash()
+	if _, err := io.Copy(h, f); err != nil {
+		return crypto.Hash{}, false
+	}
+	var sum crypto.Hash
+	copy(sum[:], h.Sum(nil))
+	return sum, true
+}
+
+// VerifyFile downloads the file at path in full and discards the data. Every
+// piece is checked against its Merkle root as it is downloaded, and every
+// chunk is decoded, so a successful verification shows that the whole file
+// can be recovered. The hash of the downloaded data is compared to the local
+// copy of the file if it is still unchanged. A file that cannot be recovered
+// is reported through the result rather than as an error.
+func (r *Renter) VerifyFile(path string) (modules.FileVerification, error) {
+	lockID := r.mu.RLock()
+	file, exists := r.files[path]
+	meta, tracked := r.tracking[path]
+	r.mu.RUnlock(lockID)
+	if !exists {
+		return modules.FileVerification{}, ErrUnknownPath
+	}
+	file.mu.RLock()
+	size := file.size
+	file.mu.RUnlock()
+
+	fv := modules.FileVerification{
+		SiaPath:   path,
+		Filesize:  size,
+		StartTime: time.Now(),
+		Hosts:     []modules.FileVerificationHost{},
+	}
+	err := func() error {
+		hosts, closeHosts, err := r.managedDownloadHosts(file)
+		if err != nil {
+			return err
+		}
+		defer closeHosts()
+		defer func() {
+			for _, h := range hosts {
+				fv.Hosts = append(fv.Hosts, modules.FileVerificationHost{
+					NetAddress:   h.address,
+					Pieces:       h.fetched,
+					FailedPieces: h.failed,
+				})
+			}
+		}()
+
+		d := file.newDownload(fetchers(hosts), "")
+		h := crypto.NewHash()
+		err = d.run(h)
+		lockID := r.mu.Lock()
+		r.logAccess(d, err)
+		r.mu.Unlock(lockID)
+		fv.BytesVerified = atomic.LoadUint64(&d.received)
+		if err != nil {
+			return err
+		}
+		copy(fv.Hash[:], h.Sum(nil))
+
+		if !tracked {
+			return nil
+		}
+		sourceHash, ok := hashSource(meta, size)
+		if !ok {
+			return nil
+		}
+		fv.SourceChecked = true
+		if sourceHash != fv.Hash {
+			return errSourceMismatch
+		}
+		return nil
+	}()
+	fv.Elapsed = time.Since(fv.StartTime).Seconds()
+	if err != nil {
+		fv.Error = err.Error()
+		r.log.Printf("verification of %v failed: %v", path, err)
+	} else {
+		fv.Success = true
+	}
+	return fv, nil
+}
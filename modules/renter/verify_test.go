@@ -0,0 +1,143 @@
+package renter
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NebulousLabs/Sia/build"
+	"github.com/NebulousLabs/Sia/crypto"
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/modules/renter/contractor"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// sectorDownloader is a contractor.Downloader that serves sectors from
+// memory.
+type sectorDownloader map[crypto.Hash][]byte
+
+func (sd sectorDownloader) Sector(root crypto.Hash) ([]byte, error) {
+	data, ok := sd[root]
+	if !ok {
+		return nil, errors.New("no such sector")
+	}
+	return data, nil
+}
+func (sd sectorDownloader) Close() error { return nil }
+
+// sectorContractor is a hostContractor whose contracts are all served by the
+// same sectorDownloader.
+type sectorContractor struct {
+	stubContractor
+	sectors sectorDownloader
+}
+
+func (sc *sectorContractor) Contract(addr modules.NetAddress) (modules.RenterContract, bool) {
+	return modules.RenterContract{NetAddress: addr}, true
+}
+func (sc *sectorContractor) Downloader(modules.RenterContract) (contractor.Downloader, error) {
+	return sc.sectors, nil
+}
+
+// TestVerifyFile checks that a file is downloaded in full and compared to its
+// local copy, and that an unrecoverable file is reported as such.
+func TestVerifyFile(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	sc := &sectorContractor{sectors: make(sectorDownloader)}
+	rt, err := newContractorTester("TestVerifyFile", nil, sc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rt.Close()
+
+	if _, err := rt.renter.VerifyFile("foo"); err != ErrUnknownPath {
+		t.Fatal("expected ErrUnknownPath, got", err)
+	}
+
+	// Erasure code and encrypt the file onto two hosts, one piece of each
+	// chunk per host.
+	data, err := crypto.RandBytes(1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc, _ := NewRSCode(1, 1)
+	f := newFile("foo", rsc, 100, uint64(len(data)))
+	hosts := []modules.NetAddress{"host0:1", "host1:1"}
+	for chunk := uint64(0); chunk < f.numChunks(); chunk++ {
+		pieces, err := rsc.Encode(data[chunk*100 : (chunk+1)*100])
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, piece := range pieces {
+			ciphertext, err := deriveKey(f.masterKey, chunk, uint64(i)).EncryptBytes(piece)
+			if err != nil {
+				t.Fatal(err)
+			}
+			root := crypto.MerkleRoot(ciphertext)
+			sc.sectors[root] = ciphertext
+			id := types.FileContractID{byte(i)}
+			fc := f.contracts[id]
+			fc.ID, fc.IP = id, hosts[i]
+			fc.Pieces = append(fc.Pieces, pieceData{Chunk: chunk, Piece: uint64(i), MerkleRoot: root})
+			f.contracts[id] = fc
+		}
+	}
+	source := filepath.Join(build.TempDir("renter", "TestVerifyFile"), "source")
+	if err := ioutil.WriteFile(source, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	stat, err := os.Stat(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := rt.renter.mu.Lock()
+	rt.renter.files["foo"] = f
+	rt.renter.tracking["foo"] = trackedFile{RepairPath: source, SourceModTime: stat.ModTime()}
+	rt.renter.mu.Unlock(id)
+
+	fv, err := rt.renter.VerifyFile("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fv.Success || !fv.SourceChecked || fv.BytesVerified != uint64(len(data)) {
+		t.Fatalf("unexpected verification: %+v", fv)
+	}
+	if fv.Hash != crypto.HashBytes(data) {
+		t.Fatal("wrong hash of downloaded data")
+	}
+	var pieces uint64
+	for _, h := range fv.Hosts {
+		pieces += h.Pieces
+	}
+	if len(fv.Hosts) != 2 || pieces != f.numChunks() {
+		t.Fatalf("wrong host contributions: %+v", fv.Hosts)
+	}
+
+	// A changed local copy is not compared.
+	if err := ioutil.WriteFile(source, data[:10], 0600); err != nil {
+		t.Fatal(err)
+	}
+	fv, err = rt.renter.VerifyFile("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fv.Success || fv.SourceChecked {
+		t.Fatalf("unexpected verification: %+v", fv)
+	}
+
+	// Losing both pieces of a chunk makes the file unrecoverable.
+	for _, fc := range f.contracts {
+		delete(sc.sectors, fc.Pieces[3].MerkleRoot)
+	}
+	fv, err = rt.renter.VerifyFile("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fv.Success || fv.Error != errInsufficientPieces.Error() || fv.BytesVerified != 300 {
+		t.Fatalf("unexpected verification: %+v", fv)
+	}
+}
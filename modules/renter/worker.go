@@ -0,0 +1,199 @@
+package renter
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/NebulousLabs/Sia/modules"
+)
+
+const (
+	// defaultWorkerPoolSize is the number of pieces that the renter uploads
+	// concurrently unless configured otherwise.
+	defaultWorkerPoolSize = 32
+
+	// maxWorkerPoolSize is the largest worker pool that can be configured.
+	maxWorkerPoolSize = 256
+
+	// throughputSamples is the number of recent uploads to each host whose
+	// throughput is remembered.
+	throughputSamples = 16
+)
+
+var (
+	errWorkerPoolBounds = errors.New("worker pool size must be between 1 and 256")
+)
+
+// A workerPool limits the number of uploads that the renter performs
+// concurrently. Each upload is performed by a worker, which is identified by
+// its index in the pool. There is no limit per host, as a chunk is repaired by
+// uploading at most one piece to each host.
+type workerPool struct {
+	size int
+
+	// active maps busy workers to the host they are uploading to. queued
+	// counts the jobs waiting for a worker, by host.
+	active map[int]modules.NetAddress
+	queued map[modules.NetAddress]int
+
+	// throughput holds the speed of the most recent uploads to each host, in
+	// bytes per second.
+	throughput map[modules.NetAddress][]float64
+
+	mu   sync.Mutex
+	cond *sync.Cond
+}
+
+// idleWorker returns the index of an idle worker, or -1 if every worker is
+// busy.
+func (p *workerPool) idleWorker() int {
+	for id := 0; id < p.size; id++ {
+		if _, busy := p.active[id]; !busy {
+			return id
+		}
+	}
+	return -1
+}
+
+// acquire blocks until a worker is available to upload to the host at addr,
+// and returns the worker. The worker must be given back with release.
+func (p *workerPool) acquire(addr modules.NetAddress) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.queued[addr]++
+	id := p.idleWorker()
+	for id == -1 {
+		p.cond.Wait()
+		id = p.idleWorker()
+	}
+	p.queued[addr]--
+	if p.queued[addr] == 0 {
+		delete(p.queued, addr)
+	}
+	p.active[id] = addr
+	return id
+}
+
+// release marks a worker as idle.
+func (p *workerPool) release(id int) {
+	p.mu.Lock()
+	delete(p.active, id)
+	p.mu.Unlock()
+	p.cond.Broadcast()
+}
+
+// recordUpload records the throughput of an upload of n bytes to the host at
+// addr that took d.
+func (p *workerPool) recordUpload(addr modules.NetAddress, n uint64, d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	samples := append(p.throughput[addr], float64(n)/d.Seconds())
+	if len(samples) > throughputSamples {
+		samples = samples[len(samples)-throughputSamples:]
+	}
+	p.throughput[addr] = samples
+}
+
+// hostThroughput returns a copy of the throughput samples of each host.
+func (p *workerPool) hostThroughput() map[modules.NetAddress][]float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	throughput := make(map[modules.NetAddress][]float64, len(p.throughput))
+	for addr, samples := range p.throughput {
+		throughput[addr] = append([]float64(nil), samples...)
+	}
+	return throughput
+}
+
+// setSize changes the size of the pool. Workers that are busy when the pool
+// shrinks finish their current job before going away.
+func (p *workerPool) setSize(size int) {
+	p.mu.Lock()
+	p.size = size
+	p.mu.Unlock()
+	p.cond.Broadcast()
+}
+
+// poolSize returns the size of the pool.
+func (p *workerPool) poolSize() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.size
+}
+
+// status reports the size of the pool and the state of each of its workers.
+func (p *workerPool) status() modules.RenterWorkerPool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	wp := modules.RenterWorkerPool{
+		Size: p.size,
+	}
+	for _, n := range p.queued {
+		wp.QueuedJobs += n
+	}
+	// Include workers beyond the pool size that are still finishing a job
+	// after the pool was shrunk.
+	numWorkers := p.size
+	for id := range p.active {
+		if id >= numWorkers {
+			numWorkers = id + 1
+		}
+	}
+	for id := 0; id < numWorkers; id++ {
+		w := modules.RenterWorker{ID: id, Status: "idle"}
+		if host, busy := p.active[id]; busy {
+			w.Status = "busy"
+			w.Host = host
+			w.QueuedJobs = p.queued[host]
+		} else if id >= p.size {
+			continue
+		}
+		wp.Workers = append(wp.Workers, w)
+	}
+	return wp
+}
+
+// newWorkerPool returns a worker pool of the provided size.
+func newWorkerPool(size int) *workerPool {
+	p := &workerPool{
+		size:       size,
+		active:     make(map[int]modules.NetAddress),
+		queued:     make(map[modules.NetAddress]int),
+		throughput: make(map[modules.NetAddress][]float64),
+	}
+	p.cond = sync.NewCond(&p.mu)
+	return p
+}
+
+// checkWorkerPoolSize returns an error if the worker pool size is out of
+// bounds.
+func checkWorkerPoolSize(size int) error {
+	if size < 1 || size > maxWorkerPoolSize {
+		return errWorkerPoolBounds
+	}
+	return nil
+}
+
+// SetWorkerPool sets the number of workers that upload pieces to hosts.
+func (r *Renter) SetWorkerPool(size int) error {
+	if err := checkWorkerPoolSize(size); err != nil {
+		return err
+	}
+	r.workers.setSize(size)
+
+	lockID := r.mu.Lock()
+	defer r.mu.Unlock(lockID)
+	return r.saveSync()
+}
+
+// WorkerPool returns the size of the renter's worker pool and the state of
+// each of its workers.
+func (r *Renter) WorkerPool() modules.RenterWorkerPool {
+	return r.workers.status()
+}
@@ -0,0 +1,89 @@
+package renter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NebulousLabs/Sia/modules"
+)
+
+// TestWorkerPoolLimits checks that the worker pool never runs more jobs than
+// its size.
+func TestWorkerPoolLimits(t *testing.T) {
+	p := newWorkerPool(2)
+	host1 := modules.NetAddress("host1:1")
+	host2 := modules.NetAddress("host2:1")
+
+	id1 := p.acquire(host1)
+	id2 := p.acquire(host2)
+	if id2 == id1 {
+		t.Fatal("two jobs were given the same worker")
+	}
+	acquired := make(chan int)
+	go func() { acquired <- p.acquire(host2) }()
+
+	// The third job must wait, as every worker is busy.
+	select {
+	case <-acquired:
+		t.Fatal("acquired a third worker from a pool of two")
+	case <-time.After(50 * time.Millisecond):
+	}
+	wp := p.status()
+	if wp.QueuedJobs != 1 || len(wp.Workers) != 2 || wp.Workers[id2].Status != "busy" || wp.Workers[id2].QueuedJobs != 1 {
+		t.Fatalf("unexpected pool status: %+v", wp)
+	}
+
+	// Releasing a worker lets the queued job run.
+	p.release(id1)
+	id3 := <-acquired
+	if wp := p.status(); wp.QueuedJobs != 0 {
+		t.Fatal("expected no queued jobs, got", wp.QueuedJobs)
+	}
+
+	// Shrinking the pool keeps busy workers listed until they are released.
+	p.setSize(1)
+	p.release(id2)
+	wp = p.status()
+	if len(wp.Workers) != id3+1 || wp.Workers[id3].Status != "busy" {
+		t.Fatalf("unexpected pool status after shrinking: %+v", wp)
+	}
+	p.release(id3)
+	if wp := p.status(); len(wp.Workers) != 1 || wp.Workers[0].Status != "idle" {
+		t.Fatalf("unexpected pool status after releasing: %+v", wp)
+	}
+}
+
+// TestSetWorkerPool checks that the worker pool size is validated and
+// persists across restarts.
+func TestSetWorkerPool(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	rt, err := newRenterTester("TestSetWorkerPool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rt.Close()
+
+	if err := rt.renter.SetWorkerPool(0); err != errWorkerPoolBounds {
+		t.Fatal("expected errWorkerPoolBounds, got", err)
+	}
+	if err := rt.renter.SetWorkerPool(maxWorkerPoolSize + 1); err != errWorkerPoolBounds {
+		t.Fatal("expected errWorkerPoolBounds, got", err)
+	}
+	if err := rt.renter.SetWorkerPool(4); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reload the renter's persist data.
+	rt.renter.workers = newWorkerPool(defaultWorkerPoolSize)
+	id := rt.renter.mu.Lock()
+	err = rt.renter.load()
+	rt.renter.mu.Unlock(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wp := rt.renter.WorkerPool(); wp.Size != 4 || len(wp.Workers) != 4 {
+		t.Fatalf("worker pool size was not persisted: %+v", wp)
+	}
+}
@@ -9,15 +9,32 @@ const (
 	// StorageManagerDir is standard name used for the directory that contains
 	// all of the storage manager files.
 	StorageManagerDir = "storagemanager"
+
+	// StorageTierNone is the tier of a storage folder that has not been given
+	// a tier. New sectors only go to such folders once the fast folders are
+	// full, and sectors are not migrated out of them.
+	StorageTierNone StorageTier = ""
+
+	// StorageTierFast is the tier of storage folders on fast drives. New
+	// sectors are placed in fast folders while they have room.
+	StorageTierFast StorageTier = "fast"
+
+	// StorageTierSlow is the tier of storage folders on slow drives. Sectors
+	// are migrated from fast folders to slow folders as they age.
+	StorageTierSlow StorageTier = "slow"
 )
 
 type (
+	// A StorageTier labels the speed of the drive behind a storage folder.
+	StorageTier string
+
 	// StorageFolderMetadata contains metadata about a storage folder that is
 	// tracked by the storage folder manager.
 	StorageFolderMetadata struct {
-		Capacity          uint64 `json:"capacity"`
-		CapacityRemaining uint64 `json:"capacityremaining"`
-		Path              string `json:"path"`
+		Capacity          uint64      `json:"capacity"`
+		CapacityRemaining uint64      `json:"capacityremaining"`
+		Path              string      `json:"path"`
+		Tier              StorageTier `json:"tier"`
 
 		// Below are statistics about the filesystem. FailedReads and
 		// FailedWrites are only incremented if the filesystem is returning
@@ -31,6 +48,42 @@ type (
 		SuccessfulWrites uint64 `json:"successfulwrites"`
 	}
 
+	// StorageSectorUsage describes a sector held by the storage manager. ID
+	// is the name of the sector's file in its storage folder. References is
+	// the number of times the sector has been added and not yet removed, with
+	// the expiry height of each reference in Expiries. StorageFolder is the
+	// index of the folder that holds the sector, or -1 if the folder no longer
+	// exists.
+	StorageSectorUsage struct {
+		ID                string              `json:"id"`
+		References        int                 `json:"references"`
+		Expiries          []types.BlockHeight `json:"expiries"`
+		StorageFolder     int                 `json:"storagefolder"`
+		StorageFolderPath string              `json:"storagefolderpath"`
+		Corrupted         bool                `json:"corrupted"`
+	}
+
+	// StorageSectorCacheStats describes the cache of recently read sectors.
+	// Size and MaxSize are in bytes. HitRate is the fraction of reads that
+	// were served from the cache.
+	StorageSectorCacheStats struct {
+		Size      uint64  `json:"size"`
+		MaxSize   uint64  `json:"maxsize"`
+		Sectors   int     `json:"sectors"`
+		Hits      uint64  `json:"hits"`
+		Misses    uint64  `json:"misses"`
+		HitRate   float64 `json:"hitrate"`
+		Evictions uint64  `json:"evictions"`
+	}
+
+	// StorageTierPolicy controls the migration of sectors between storage
+	// tiers. Sectors that have been in a fast folder for MigrateAfter seconds
+	// are moved to a slow folder. Sectors are not migrated if MigrateAfter is
+	// zero.
+	StorageTierPolicy struct {
+		MigrateAfter uint64 `json:"migrateafter"`
+	}
+
 	// A StorageManager is responsible for managing storage folders and
 	// sectors. Sectors are the base unit of storage that gets moved between
 	// renters and hosts, and primarily is stored on the hosts.
@@ -77,6 +130,11 @@ type (
 		// data, an error will be returned and the operation will be stopped.
 		RemoveStorageFolder(index int, force bool) error
 
+		// MigrateTiers moves the sectors that have aged past the tier policy
+		// from fast storage folders to slow storage folders, returning the
+		// number of sectors that were moved.
+		MigrateTiers() (uint64, error)
+
 		// ResetStorageFolderHealth will reset the health statistics on a
 		// storage folder.
 		ResetStorageFolderHealth(index int) error
@@ -91,8 +149,33 @@ type (
 		// and the operation will be stopped.
 		ResizeStorageFolder(index int, newSize uint64) error
 
+		// SectorCacheStats returns the size and hit rate of the cache of
+		// recently read sectors.
+		SectorCacheStats() StorageSectorCacheStats
+
+		// SetSectorCacheSize sets the most memory, in bytes, that the cache
+		// of recently read sectors can use. Zero disables the cache.
+		SetSectorCacheSize(uint64) error
+
+		// SetStorageFolderTier sets the tier of a storage folder. Sectors
+		// already in the folder are not moved.
+		SetStorageFolderTier(index int, tier StorageTier) error
+
+		// SetTierPolicy sets the policy for migrating sectors between tiers.
+		SetTierPolicy(StorageTierPolicy) error
+
+		// SectorUsage returns the reference count and location of a sector.
+		SectorUsage(sectorRoot crypto.Hash) (StorageSectorUsage, error)
+
 		// StorageFolders will return a list of storage folders tracked by the
 		// manager.
 		StorageFolders() []StorageFolderMetadata
+
+		// TierPolicy returns the policy for migrating sectors between tiers.
+		TierPolicy() StorageTierPolicy
+
+		// UnreferencedSectors returns every sector that is not one of the
+		// 'referenced' sectors, or that has no references left.
+		UnreferencedSectors(referenced []crypto.Hash) ([]StorageSectorUsage, error)
 	}
 )
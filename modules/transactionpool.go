@@ -51,6 +51,60 @@ var (
 	TransactionPoolDir = "transactionpool"
 )
 
+// TransactionPoolStats describes the size limits of the transaction pool and
+// how much of them is in use.
+type TransactionPoolStats struct {
+	// Transactions and Size are the number of transactions in the pool and
+	// their combined encoded size in bytes. LocalTransactions is the number
+	// of those transactions that were submitted by this node.
+	Transactions      int `json:"transactions"`
+	Size              int `json:"size"`
+	LocalTransactions int `json:"localtransactions"`
+
+	// MaxTransactions and MaxSize are the limits of the pool. A limit of
+	// zero means that the pool is not limited in that dimension.
+	MaxTransactions int `json:"maxtransactions"`
+	MaxSize         int `json:"maxsize"`
+
+	// Evictions is the number of transaction sets that have been dropped
+	// from the pool to make room for transaction sets paying higher fees.
+	Evictions uint64 `json:"evictions"`
+
+	// MinRelayFeePerByte is the lowest fee per byte that the transactions
+	// received from peers in a set must pay together for the set to be
+	// accepted and relayed. Transactions submitted by this node are left out
+	// of the total. RelayFeeFiltered is
+	// the number of transaction sets that were turned away for paying less.
+	MinRelayFeePerByte types.Currency `json:"minrelayfeeperbyte"`
+	RelayFeeFiltered   uint64         `json:"relayfeefiltered"`
+}
+
+// A TransactionOrigin records where the transaction pool first saw a
+// transaction. Peer is the address of the peer that relayed the transaction,
+// and is empty if the transaction was submitted by this node.
+type TransactionOrigin struct {
+	Peer      NetAddress      `json:"peer"`
+	Local     bool            `json:"local"`
+	FirstSeen types.Timestamp `json:"firstseen"`
+}
+
+// A TransactionFeeRank describes how the fee of a transaction in the pool
+// compares with the fees of the other transactions in the pool.
+type TransactionFeeRank struct {
+	// FeePerByte is the miner fee of the set that the transaction belongs to
+	// divided by the encoded size of the set.
+	FeePerByte types.Currency `json:"feeperbyte"`
+
+	// Percentile is the percentage of the other transactions in the pool
+	// that pay a lower fee per byte, and is 100 if the transaction is alone
+	// in the pool. Rank is the position of the transaction when the pool is
+	// ordered by fee per byte, highest first, counting from 1; transactions
+	// paying the same fee per byte share a rank.
+	Percentile   float64 `json:"percentile"`
+	Rank         int     `json:"rank"`
+	Transactions int     `json:"transactions"`
+}
+
 // A TransactionPoolSubscriber receives updates about the confirmed and
 // unconfirmed set from the transaction pool. Generally, there is no need to
 // subscribe to both the consensus set and the transaction pool.
@@ -70,6 +124,12 @@ type TransactionPool interface {
 	// Close is necessary for clean shutdown (e.g. during testing).
 	Close() error
 
+	// FeeRank returns how the fee per byte of the transaction with the
+	// given id compares with the other transactions in the pool, and false if
+	// the transaction is not in the pool. Transactions are ranked by the fee
+	// per byte of their whole set.
+	FeeRank(types.TransactionID) (TransactionFeeRank, bool)
+
 	// FeeEstimation returns an estimation for how high the transaction fee
 	// needs to be per byte. The minimum recommended targets getting accepted
 	// in ~3 blocks, and the maximum recommended targets getting accepted
@@ -89,6 +149,26 @@ type TransactionPool interface {
 	// that make this condition necessary.
 	PurgeTransactionPool()
 
+	// SetLimits sets the maximum number of transactions and the maximum
+	// encoded size in bytes of the transaction pool. A limit of zero disables
+	// that limit. When a limit is reached, the transaction sets paying the
+	// lowest fees per byte are evicted, except for those submitted by this
+	// node.
+	SetLimits(maxTransactions, maxSize int) error
+
+	// SetMinRelayFee sets the minimum fee per byte that the transactions
+	// received from peers in a set must pay together to be accepted and
+	// relayed. A fee of zero disables the filter.
+	SetMinRelayFee(feePerByte types.Currency)
+
+	// Stats returns the limits of the transaction pool and their utilization.
+	Stats() TransactionPoolStats
+
+	// TransactionOrigin returns where the transaction pool first saw the
+	// transaction with the given id, and false if the transaction has not
+	// been seen recently.
+	TransactionOrigin(types.TransactionID) (TransactionOrigin, bool)
+
 	// TransactionList returns a list of all transactions in the transaction
 	// pool. The transactions are provided in an order that can acceptably be
 	// put into a block.
@@ -15,14 +15,10 @@ import (
 )
 
 const (
-	// The TransactionPoolSizeLimit is first checked, and then a transaction
-	// set is added. The current transaction pool does not do any priority
-	// ordering, so the size limit is such that the transaction pool will never
-	// exceed the size of a block.
-	//
-	// TODO: Add a priority structure that will allow the transaction pool to
-	// fill up beyond the size of a single block, without being subject to
-	// manipulation.
+	// TransactionPoolSizeLimit is the default maximum size of the transaction
+	// pool, chosen so that the transaction pool will never exceed the size of
+	// a block. Once the limit is reached, transaction sets paying the lowest
+	// fees per byte are evicted to make room for sets paying higher fees.
 	//
 	// The first ~1/4 of the transaction pool can be filled for free. This is
 	// mostly to preserve compatibility with clients that do not add fees.
@@ -76,12 +72,6 @@ func relatedObjectIDs(ts []types.Transaction) []ObjectID {
 // checkMinerFees checks that the total amount of transaction fees in the
 // transaction set is sufficient to earn a spot in the transaction pool.
 func (tp *TransactionPool) checkMinerFees(ts []types.Transaction) error {
-	// Transactions cannot be added after the TransactionPoolSizeLimit has been
-	// hit.
-	if tp.transactionListSize > TransactionPoolSizeLimit {
-		return errFullTransactionPool
-	}
-
 	// The first TransactionPoolSizeForFee transactions do not need fees.
 	if tp.transactionListSize > TransactionPoolSizeForFee {
 		// Currently required fees are set on a per-transaction basis. 2 coins
@@ -208,6 +198,13 @@ func (tp *TransactionPool) handleConflicts(ts []types.Transaction, conflicts []T
 		return modules.NewConsensusConflict(err.Error())
 	}
 
+	// Make room for the superset, evicting cheaper transaction sets if the
+	// pool is full.
+	err = tp.makeRoom(superset, supersetMap)
+	if err != nil {
+		return err
+	}
+
 	// Remove the conflicts from the transaction pool. The diffs do not need to
 	// be removed, they will be overwritten later in the function.
 	for _, conflict := range conflictMap {
@@ -285,6 +282,10 @@ func (tp *TransactionPool) acceptTransactionSet(ts []types.Transaction) error {
 	if err != nil {
 		return modules.NewConsensusConflict(err.Error())
 	}
+	err = tp.makeRoom(ts, nil)
+	if err != nil {
+		return err
+	}
 
 	// Add the transaction set to the pool.
 	setID := TransactionSetID(crypto.HashObject(ts))
@@ -299,13 +300,49 @@ func (tp *TransactionPool) acceptTransactionSet(ts []types.Transaction) error {
 
 // AcceptTransaction adds a transaction to the unconfirmed set of
 // transactions. If the transaction is accepted, it will be relayed to
-// connected peers.
+// connected peers. Transactions submitted through AcceptTransactionSet are
+// considered local, and will not be evicted when the pool is full.
 func (tp *TransactionPool) AcceptTransactionSet(ts []types.Transaction) error {
+	return tp.managedAcceptTransactionSet(ts, true)
+}
+
+// managedAcceptTransactionSet adds a transaction set to the pool, marking its
+// transactions as local if they were submitted by this node, and relays the
+// set to peers if it is accepted.
+func (tp *TransactionPool) managedAcceptTransactionSet(ts []types.Transaction, local bool) error {
 	tp.mu.Lock()
 	defer tp.mu.Unlock()
 
+	if local {
+		tp.recordOrigins(ts, modules.TransactionOrigin{
+			Local:     true,
+			FirstSeen: types.CurrentTimestamp(),
+		})
+	}
+
+	// Local transactions are marked before being accepted so that they are
+	// not considered for eviction while making room for themselves.
+	var marked []types.TransactionID
+	if local {
+		for _, txn := range ts {
+			if _, exists := tp.localTransactions[txn.ID()]; !exists {
+				tp.localTransactions[txn.ID()] = struct{}{}
+				marked = append(marked, txn.ID())
+			}
+		}
+	}
+	// Transaction sets from peers whose transactions pay too little together
+	// are neither added to the pool nor relayed. Local transactions were
+	// marked above, and are left out of the total.
+	if tp.belowMinRelayFee(ts) {
+		tp.relayFeeFiltered++
+		return errBelowMinRelayFee
+	}
 	err := tp.acceptTransactionSet(ts)
 	if err != nil {
+		for _, txid := range marked {
+			delete(tp.localTransactions, txid)
+		}
 		return err
 	}
 
@@ -324,5 +361,11 @@ func (tp *TransactionPool) relayTransactionSet(conn modules.PeerConn) error {
 	if err != nil {
 		return err
 	}
-	return tp.AcceptTransactionSet(ts)
+	tp.mu.Lock()
+	tp.recordOrigins(ts, modules.TransactionOrigin{
+		Peer:      conn.RPCAddr(),
+		FirstSeen: types.CurrentTimestamp(),
+	})
+	tp.mu.Unlock()
+	return tp.managedAcceptTransactionSet(ts, false)
 }
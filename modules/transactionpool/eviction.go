@@ -0,0 +1,190 @@
+package transactionpool
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/NebulousLabs/Sia/encoding"
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+var (
+	errNegativeLimit = errors.New("transaction pool limits cannot be negative")
+)
+
+// An evictionCandidate is a transaction set that may be evicted from the pool
+// to make room for a transaction set paying a higher fee.
+type evictionCandidate struct {
+	id    TransactionSetID
+	fees  types.Currency
+	size  int
+	count int
+}
+
+// evictionCandidates sorts candidates by the fee they pay per byte, lowest
+// first.
+type evictionCandidates []evictionCandidate
+
+func (ec evictionCandidates) Len() int           { return len(ec) }
+func (ec evictionCandidates) Swap(i, j int)      { ec[i], ec[j] = ec[j], ec[i] }
+func (ec evictionCandidates) Less(i, j int) bool { return lowerFeeRate(ec[i], ec[j]) }
+
+// lowerFeeRate returns true if a pays a lower fee per byte than b.
+func lowerFeeRate(a, b evictionCandidate) bool {
+	return a.fees.Mul64(uint64(b.size)).Cmp(b.fees.Mul64(uint64(a.size))) < 0
+}
+
+// setFees returns the total miner fees paid by a transaction set.
+func setFees(ts []types.Transaction) (fees types.Currency) {
+	for _, txn := range ts {
+		for _, fee := range txn.MinerFees {
+			fees = fees.Add(fee)
+		}
+	}
+	return fees
+}
+
+// isLocalSet returns true if the transaction set contains a transaction that
+// was submitted by this node.
+func (tp *TransactionPool) isLocalSet(ts []types.Transaction) bool {
+	for _, txn := range ts {
+		if _, exists := tp.localTransactions[txn.ID()]; exists {
+			return true
+		}
+	}
+	return false
+}
+
+// transactionCount returns the number of transactions in the pool.
+func (tp *TransactionPool) transactionCount() (n int) {
+	for _, ts := range tp.transactionSets {
+		n += len(ts)
+	}
+	return n
+}
+
+// withinLimits returns true if a pool with the given number of transactions
+// and size respects the pool's limits.
+func (tp *TransactionPool) withinLimits(count, size int) bool {
+	if tp.maxTransactions > 0 && count > tp.maxTransactions {
+		return false
+	}
+	if tp.maxSize > 0 && size > tp.maxSize {
+		return false
+	}
+	return true
+}
+
+// removeTransactionSet removes a transaction set from the pool.
+func (tp *TransactionPool) removeTransactionSet(id TransactionSetID) {
+	tp.transactionListSize -= len(encoding.Marshal(tp.transactionSets[id]))
+	delete(tp.transactionSets, id)
+	delete(tp.transactionSetDiffs, id)
+	for oid, setID := range tp.knownObjects {
+		if setID == id {
+			delete(tp.knownObjects, oid)
+		}
+	}
+}
+
+// makeRoom ensures that the transaction set ts can be added to the pool
+// without exceeding the pool's limits, evicting the transaction sets that pay
+// the lowest fee per byte if necessary. Only sets paying a lower fee per byte
+// than ts are evicted, unless ts was submitted by this node, and sets
+// submitted by this node are never evicted. The sets in 'replaced' are about
+// to be removed from the pool, and are neither counted nor evicted. If enough
+// room cannot be made, nothing is evicted and errFullTransactionPool is
+// returned.
+func (tp *TransactionPool) makeRoom(ts []types.Transaction, replaced map[TransactionSetID]struct{}) error {
+	count := tp.transactionCount() + len(ts)
+	size := tp.transactionListSize + len(encoding.Marshal(ts))
+	for id := range replaced {
+		count -= len(tp.transactionSets[id])
+		size -= len(encoding.Marshal(tp.transactionSets[id]))
+	}
+	if tp.withinLimits(count, size) {
+		return nil
+	}
+
+	incoming := evictionCandidate{
+		fees: setFees(ts),
+		size: len(encoding.Marshal(ts)),
+	}
+	local := tp.isLocalSet(ts)
+	var candidates evictionCandidates
+	for id, set := range tp.transactionSets {
+		if _, exists := replaced[id]; exists || tp.isLocalSet(set) {
+			continue
+		}
+		c := evictionCandidate{
+			id:    id,
+			fees:  setFees(set),
+			size:  len(encoding.Marshal(set)),
+			count: len(set),
+		}
+		if !local && !lowerFeeRate(c, incoming) {
+			continue
+		}
+		candidates = append(candidates, c)
+	}
+	sort.Sort(candidates)
+
+	var evicted []TransactionSetID
+	for _, c := range candidates {
+		if tp.withinLimits(count, size) {
+			break
+		}
+		evicted = append(evicted, c.id)
+		count -= c.count
+		size -= c.size
+	}
+	if !tp.withinLimits(count, size) {
+		return errFullTransactionPool
+	}
+	for _, id := range evicted {
+		tp.removeTransactionSet(id)
+	}
+	tp.evictions += uint64(len(evicted))
+	return nil
+}
+
+// SetLimits sets the maximum number of transactions and the maximum encoded
+// size of the transaction pool. A limit of zero disables that limit. The new
+// limits apply to transaction sets added after the call; transaction sets that
+// are already in the pool are not evicted.
+func (tp *TransactionPool) SetLimits(maxTransactions, maxSize int) error {
+	if maxTransactions < 0 || maxSize < 0 {
+		return errNegativeLimit
+	}
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	tp.maxTransactions = maxTransactions
+	tp.maxSize = maxSize
+	return nil
+}
+
+// Stats returns the limits of the transaction pool and their utilization.
+func (tp *TransactionPool) Stats() modules.TransactionPoolStats {
+	tp.mu.RLock()
+	defer tp.mu.RUnlock()
+
+	stats := modules.TransactionPoolStats{
+		Size:            tp.transactionListSize,
+		MaxTransactions: tp.maxTransactions,
+		MaxSize:         tp.maxSize,
+		Evictions:       tp.evictions,
+
+		MinRelayFeePerByte: tp.minRelayFeePerByte,
+		RelayFeeFiltered:   tp.relayFeeFiltered,
+	}
+	for _, ts := range tp.transactionSets {
+		stats.Transactions += len(ts)
+		for _, txn := range ts {
+			if _, exists := tp.localTransactions[txn.ID()]; exists {
+				stats.LocalTransactions++
+			}
+		}
+	}
+	return stats
+}
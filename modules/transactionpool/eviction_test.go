@@ -0,0 +1,105 @@
+package transactionpool
+
+import (
+	"crypto/rand"
+	"sort"
+	"testing"
+
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// arbTxnSet returns a transaction set containing a single unique transaction
+// that pays no fees.
+func arbTxnSet(t *testing.T) []types.Transaction {
+	arbData := make([]byte, 100)
+	copy(arbData, modules.PrefixNonSia[:])
+	_, err := rand.Read(arbData[16:32])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return []types.Transaction{{ArbitraryData: [][]byte{arbData}}}
+}
+
+// TestEvictionCandidatesSort checks that eviction candidates are sorted by
+// the fee they pay per byte.
+func TestEvictionCandidatesSort(t *testing.T) {
+	ec := evictionCandidates{
+		{fees: types.NewCurrency64(300), size: 100}, // 3 per byte
+		{fees: types.NewCurrency64(100), size: 100}, // 1 per byte
+		{fees: types.NewCurrency64(400), size: 200}, // 2 per byte
+		{fees: types.ZeroCurrency, size: 10},        // 0 per byte
+	}
+	sort.Sort(ec)
+	expected := []uint64{0, 100, 400, 300}
+	for i, c := range ec {
+		if c.fees.Cmp(types.NewCurrency64(expected[i])) != 0 {
+			t.Fatalf("candidate %v has fees %v, expected %v", i, c.fees, expected[i])
+		}
+	}
+}
+
+// TestIntegrationEviction checks that a full transaction pool evicts remote
+// transaction sets to make room for local ones, and never evicts local
+// transaction sets.
+func TestIntegrationEviction(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	tpt, err := createTpoolTester("TestIntegrationEviction")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tpt.Close()
+
+	if err := tpt.tpool.SetLimits(-1, 0); err != errNegativeLimit {
+		t.Fatal("expected errNegativeLimit, got", err)
+	}
+	if err := tpt.tpool.SetLimits(3, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	// Fill the pool with transactions relayed by peers.
+	for i := 0; i < 3; i++ {
+		err := tpt.tpool.managedAcceptTransactionSet(arbTxnSet(t), false)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A remote transaction paying the same fees should be rejected.
+	err = tpt.tpool.managedAcceptTransactionSet(arbTxnSet(t), false)
+	if err != errFullTransactionPool {
+		t.Fatal("expected errFullTransactionPool, got", err)
+	}
+
+	// A local transaction should evict a remote transaction.
+	err = tpt.tpool.AcceptTransactionSet(arbTxnSet(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := tpt.tpool.Stats()
+	if stats.Transactions != 3 || stats.LocalTransactions != 1 || stats.Evictions != 1 {
+		t.Fatalf("unexpected stats after eviction: %+v", stats)
+	}
+
+	// Once only local transactions would remain, no more room can be made.
+	if err := tpt.tpool.SetLimits(1, 0); err != nil {
+		t.Fatal(err)
+	}
+	err = tpt.tpool.AcceptTransactionSet(arbTxnSet(t))
+	if err != errFullTransactionPool {
+		t.Fatal("expected errFullTransactionPool, got", err)
+	}
+	stats = tpt.tpool.Stats()
+	if stats.Transactions != 3 || stats.LocalTransactions != 1 || stats.Evictions != 1 {
+		t.Fatalf("failed insertion should not evict transactions: %+v", stats)
+	}
+
+	// Purging the pool should forget the local transactions.
+	tpt.tpool.PurgeTransactionPool()
+	stats = tpt.tpool.Stats()
+	if stats.Transactions != 0 || stats.LocalTransactions != 0 || stats.Size != 0 {
+		t.Fatalf("unexpected stats after purge: %+v", stats)
+	}
+}
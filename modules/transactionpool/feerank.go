@@ -0,0 +1,55 @@
+package transactionpool
+
+import (
+	"github.com/NebulousLabs/Sia/encoding"
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// FeeRank returns how the fee per byte of the transaction with the given id
+// compares with the other transactions in the pool, and false if the
+// transaction is not in the pool. Each transaction is ranked by the fees and
+// size of the whole set it was submitted in, since miners only take the set
+// together: a parent paying no fee is mined as quickly as the child paying for
+// it.
+func (tp *TransactionPool) FeeRank(id types.TransactionID) (modules.TransactionFeeRank, bool) {
+	tp.mu.RLock()
+	defer tp.mu.RUnlock()
+
+	rates := make(map[types.TransactionID]evictionCandidate)
+	for _, set := range tp.transactionSets {
+		rate := evictionCandidate{
+			fees: setFees(set),
+			size: len(encoding.Marshal(set)),
+		}
+		for _, txn := range set {
+			rates[txn.ID()] = rate
+		}
+	}
+	target, exists := rates[id]
+	if !exists {
+		return modules.TransactionFeeRank{}, false
+	}
+
+	rank := modules.TransactionFeeRank{
+		FeePerByte:   target.fees.Div64(uint64(target.size)),
+		Percentile:   100,
+		Rank:         1,
+		Transactions: len(rates),
+	}
+	var lower int
+	for otherID, other := range rates {
+		if otherID == id {
+			continue
+		}
+		if lowerFeeRate(other, target) {
+			lower++
+		} else if lowerFeeRate(target, other) {
+			rank.Rank++
+		}
+	}
+	if len(rates) > 1 {
+		rank.Percentile = 100 * float64(lower) / float64(len(rates)-1)
+	}
+	return rank, true
+}
@@ -0,0 +1,72 @@
+package transactionpool
+
+import (
+	"testing"
+
+	"github.com/NebulousLabs/Sia/encoding"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// TestFeeRank checks the fee per byte, rank, and percentile reported for the
+// transactions in the pool.
+func TestFeeRank(t *testing.T) {
+	// Transactions of equal size paying 10, 0, 50, 30, and 40 thousand
+	// hastings. The second and third share a set, which pays less per byte
+	// than the 30 thousand transaction but more than the 10 thousand one.
+	var txns []types.Transaction
+	for i, fee := range []uint64{10e3, 0, 50e3, 30e3, 40e3} {
+		txns = append(txns, types.Transaction{
+			MinerFees:     []types.Currency{types.NewCurrency64(fee)},
+			ArbitraryData: [][]byte{{byte(i)}},
+		})
+	}
+	tp := &TransactionPool{
+		transactionSets: map[TransactionSetID][]types.Transaction{
+			{1}: {txns[0]},
+			{2}: {txns[1], txns[2]},
+			{3}: {txns[3]},
+			{4}: {txns[4]},
+		},
+	}
+
+	if _, ok := tp.FeeRank(types.TransactionID{}); ok {
+		t.Fatal("expected no rank for a transaction outside the pool")
+	}
+	tests := []struct {
+		txn        types.Transaction
+		rank       int
+		percentile float64
+	}{
+		{txns[4], 1, 100},
+		{txns[3], 2, 75},
+		{txns[1], 3, 25},
+		{txns[2], 3, 25},
+		{txns[0], 5, 0},
+	}
+	for _, test := range tests {
+		fr, ok := tp.FeeRank(test.txn.ID())
+		if !ok {
+			t.Fatal("transaction in the pool was not ranked")
+		}
+		if fr.Rank != test.rank || fr.Percentile != test.percentile || fr.Transactions != 5 {
+			t.Errorf("wrong rank for fee %v: %+v", test.txn.MinerFees[0], fr)
+		}
+	}
+
+	// Both transactions of the set report the fee per byte of the set.
+	set := tp.transactionSets[TransactionSetID{2}]
+	setRate := types.NewCurrency64(50e3).Div64(uint64(len(encoding.Marshal(set))))
+	for _, txn := range set {
+		fr, _ := tp.FeeRank(txn.ID())
+		if fr.FeePerByte.Cmp(setRate) != 0 {
+			t.Errorf("expected fee per byte %v for the set, got %v", setRate, fr.FeePerByte)
+		}
+	}
+
+	// A transaction alone in the pool is at the top.
+	tp.transactionSets = map[TransactionSetID][]types.Transaction{{1}: {txns[0]}}
+	fr, ok := tp.FeeRank(txns[0].ID())
+	if !ok || fr.Rank != 1 || fr.Percentile != 100 || fr.Transactions != 1 {
+		t.Fatalf("wrong rank for a lone transaction: %+v", fr)
+	}
+}
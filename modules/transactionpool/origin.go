@@ -0,0 +1,39 @@
+package transactionpool
+
+import (
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// maxOrigins is the number of transactions whose origin is remembered. Once
+// the limit is reached, the oldest origins are forgotten first.
+const maxOrigins = 10e3
+
+// recordOrigins records origin as the origin of every transaction in ts that
+// has not been seen before. Transactions are recorded whether or not they are
+// accepted into the pool, so that the source of invalid transactions can be
+// found as well.
+func (tp *TransactionPool) recordOrigins(ts []types.Transaction, origin modules.TransactionOrigin) {
+	for _, txn := range ts {
+		id := txn.ID()
+		if _, exists := tp.origins[id]; exists {
+			continue
+		}
+		tp.origins[id] = origin
+		tp.originOrder = append(tp.originOrder, id)
+	}
+	for len(tp.originOrder) > maxOrigins {
+		delete(tp.origins, tp.originOrder[0])
+		tp.originOrder = tp.originOrder[1:]
+	}
+}
+
+// TransactionOrigin returns where the transaction pool first saw the
+// transaction with the given id, and false if the transaction has not been
+// seen recently.
+func (tp *TransactionPool) TransactionOrigin(id types.TransactionID) (modules.TransactionOrigin, bool) {
+	tp.mu.RLock()
+	defer tp.mu.RUnlock()
+	origin, exists := tp.origins[id]
+	return origin, exists
+}
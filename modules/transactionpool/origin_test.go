@@ -0,0 +1,33 @@
+package transactionpool
+
+import (
+	"testing"
+
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// TestRecordOrigins checks that only the first origin of a transaction is
+// kept, and that the oldest origins are forgotten once maxOrigins is reached.
+func TestRecordOrigins(t *testing.T) {
+	tp := &TransactionPool{
+		origins: make(map[types.TransactionID]modules.TransactionOrigin),
+	}
+	first := arbTxnSet(t)
+	tp.recordOrigins(first, modules.TransactionOrigin{Peer: "1.2.3.4:9981"})
+	tp.recordOrigins(first, modules.TransactionOrigin{Peer: "5.6.7.8:9981"})
+	origin, ok := tp.TransactionOrigin(first[0].ID())
+	if !ok || origin.Peer != "1.2.3.4:9981" {
+		t.Fatal("expected the first peer to be recorded, got", origin.Peer)
+	}
+
+	for i := 0; i < maxOrigins; i++ {
+		tp.recordOrigins(arbTxnSet(t), modules.TransactionOrigin{Local: true})
+	}
+	if len(tp.origins) != maxOrigins || len(tp.originOrder) != maxOrigins {
+		t.Fatal("origins exceed the limit:", len(tp.origins), len(tp.originOrder))
+	}
+	if _, ok := tp.TransactionOrigin(first[0].ID()); ok {
+		t.Fatal("oldest origin was not forgotten")
+	}
+}
@@ -0,0 +1,46 @@
+package transactionpool
+
+import (
+	"errors"
+
+	"github.com/NebulousLabs/Sia/encoding"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+var (
+	errBelowMinRelayFee = errors.New("transaction set pays less than the minimum relay fee")
+)
+
+// belowMinRelayFee returns true if the transactions in the set that were not
+// submitted by this node pay, together, a lower fee per byte than the pool's
+// minimum relay fee. The fees and sizes are summed so that a child can pay for
+// a parent without fees, while local transactions are left out of the sum so
+// that a peer cannot relay free transactions by bundling them with a local
+// transaction.
+func (tp *TransactionPool) belowMinRelayFee(ts []types.Transaction) bool {
+	if tp.minRelayFeePerByte.IsZero() {
+		return false
+	}
+	var remote []types.Transaction
+	for _, txn := range ts {
+		if _, local := tp.localTransactions[txn.ID()]; !local {
+			remote = append(remote, txn)
+		}
+	}
+	if len(remote) == 0 {
+		return false
+	}
+	size := len(encoding.Marshal(remote))
+	return setFees(remote).Cmp(tp.minRelayFeePerByte.Mul64(uint64(size))) < 0
+}
+
+// SetMinRelayFee sets the minimum fee per byte that the transactions received
+// from peers in a set must pay together for the set to be added to the pool
+// and relayed.
+// Transactions submitted by this node are exempt. A fee of zero disables the
+// filter.
+func (tp *TransactionPool) SetMinRelayFee(feePerByte types.Currency) {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	tp.minRelayFeePerByte = feePerByte
+}
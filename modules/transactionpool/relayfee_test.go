@@ -0,0 +1,70 @@
+package transactionpool
+
+import (
+	"testing"
+
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// TestIntegrationMinRelayFee checks that transaction sets from peers paying
+// less than the minimum relay fee are filtered, that a child can pay for a
+// parent without fees, and that local transactions are exempt without
+// exempting the rest of a set that includes them.
+func TestIntegrationMinRelayFee(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	tpt, err := createTpoolTester("TestIntegrationMinRelayFee")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tpt.Close()
+
+	// Without a minimum, free transaction sets are accepted.
+	err = tpt.tpool.managedAcceptTransactionSet(arbTxnSet(t), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tpt.tpool.SetMinRelayFee(types.NewCurrency64(1))
+	err = tpt.tpool.managedAcceptTransactionSet(arbTxnSet(t), false)
+	if err != errBelowMinRelayFee {
+		t.Fatal("expected errBelowMinRelayFee, got", err)
+	}
+	local := arbTxnSet(t)
+	err = tpt.tpool.AcceptTransactionSet(local)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A peer cannot relay a free transaction by bundling it with a local one.
+	bundled := append(append([]types.Transaction{}, local...), arbTxnSet(t)...)
+	err = tpt.tpool.managedAcceptTransactionSet(bundled, false)
+	if err != errBelowMinRelayFee {
+		t.Fatal("expected errBelowMinRelayFee for a bundled set, got", err)
+	}
+
+	// A parent without fees is accepted when its child pays for both.
+	fund := types.NewCurrency64(30e6)
+	txnBuilder := tpt.wallet.StartTransaction()
+	err = txnBuilder.FundSiacoins(fund)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txnBuilder.AddMinerFee(fund)
+	txnSet, err := txnBuilder.Sign(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txnSet) < 2 || len(txnSet[0].MinerFees) != 0 {
+		t.Fatal("test is invalid unless the set has a parent without fees")
+	}
+	err = tpt.tpool.managedAcceptTransactionSet(txnSet, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := tpt.tpool.Stats()
+	if stats.Transactions != 2+len(txnSet) || stats.RelayFeeFiltered != 2 || stats.MinRelayFeePerByte.Cmp(types.NewCurrency64(1)) != 0 {
+		t.Fatalf("unexpected stats after filtering: %+v", stats)
+	}
+}
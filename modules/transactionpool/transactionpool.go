@@ -56,12 +56,38 @@ type (
 		transactionSets     map[TransactionSetID][]types.Transaction
 		transactionSetDiffs map[TransactionSetID]modules.ConsensusChange
 		transactionListSize int
+
 		// TODO: Write a consistency check comparing transactionSets,
 		// transactionSetDiffs.
 		//
 		// TODO: Write a consistency check making sure that all unconfirmedIDs
 		// point to the right place, and that all UnconfirmedIDs are accounted for.
 
+		// localTransactions contains the ids of the transactions that were
+		// submitted by this node rather than relayed by peers. Transaction
+		// sets containing a local transaction are never evicted.
+		localTransactions map[types.TransactionID]struct{}
+
+		// origins records the peer that each recently seen transaction was
+		// first received from. originOrder holds the same ids, oldest first,
+		// so that the oldest origins can be forgotten.
+		origins     map[types.TransactionID]modules.TransactionOrigin
+		originOrder []types.TransactionID
+
+		// maxTransactions and maxSize limit the number of transactions and
+		// the encoded size of the pool. A limit of zero is no limit.
+		// evictions counts the transaction sets evicted to respect the limits.
+		maxTransactions int
+		maxSize         int
+		evictions       uint64
+
+		// minRelayFeePerByte is the lowest fee per byte that the
+		// transactions from a peer in a set must pay together to be
+		// accepted. relayFeeFiltered counts the transaction sets that were
+		// turned away for paying less.
+		minRelayFeePerByte types.Currency
+		relayFeeFiltered   uint64
+
 		// The consensus change index tracks how many consensus changes have
 		// been sent to the transaction pool. When a new subscriber joins the
 		// transaction pool, all prior consensus changes are sent to the new
@@ -93,6 +119,10 @@ func New(cs modules.ConsensusSet, g modules.Gateway, persistDir string) (*Transa
 		knownObjects:        make(map[ObjectID]TransactionSetID),
 		transactionSets:     make(map[TransactionSetID][]types.Transaction),
 		transactionSetDiffs: make(map[TransactionSetID]modules.ConsensusChange),
+		localTransactions:   make(map[types.TransactionID]struct{}),
+		origins:             make(map[types.TransactionID]modules.TransactionOrigin),
+
+		maxSize: TransactionPoolSizeLimit,
 
 		persistDir: persistDir,
 	}
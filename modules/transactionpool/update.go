@@ -111,6 +111,19 @@ func (tp *TransactionPool) ProcessConsensusChange(cc modules.ConsensusChange) {
 		tp.acceptTransactionSet(set) // Error is not checked.
 	}
 
+	// Forget the local transactions that were confirmed or dropped.
+	pooled := make(map[types.TransactionID]struct{})
+	for _, ts := range tp.transactionSets {
+		for _, txn := range ts {
+			pooled[txn.ID()] = struct{}{}
+		}
+	}
+	for txid := range tp.localTransactions {
+		if _, exists := pooled[txid]; !exists {
+			delete(tp.localTransactions, txid)
+		}
+	}
+
 	// Inform subscribers that an update has executed.
 	tp.mu.Demote()
 	tp.updateSubscribersTransactions()
@@ -121,5 +134,6 @@ func (tp *TransactionPool) ProcessConsensusChange(cc modules.ConsensusChange) {
 func (tp *TransactionPool) PurgeTransactionPool() {
 	tp.mu.Lock()
 	tp.purge()
+	tp.localTransactions = make(map[types.TransactionID]struct{})
 	tp.mu.Unlock()
 }
@@ -3,6 +3,7 @@ package modules
 import (
 	"bytes"
 	"errors"
+	"time"
 
 	"github.com/NebulousLabs/entropy-mnemonics"
 
@@ -25,6 +26,24 @@ const (
 	// WalletSeedPreloadDepth is the number of addresses that get automatically
 	// loaded by the wallet at startup.
 	WalletSeedPreloadDepth = 25
+
+	// ConsolidationSafeDepth is the number of confirmations an output needs
+	// before the wallet will consolidate it by default. Outputs that are
+	// confirmed less deeply could be undone by a reorg.
+	ConsolidationSafeDepth = 6
+)
+
+const (
+	// WalletLocked means that the wallet holds no keys in memory.
+	WalletLocked WalletUnlockLevel = "locked"
+
+	// WalletViewUnlocked means that the wallet tracks its addresses and
+	// balances, but holds no seeds or secret keys and cannot spend.
+	WalletViewUnlocked WalletUnlockLevel = "view"
+
+	// WalletFullyUnlocked means that the wallet holds its seeds and secret
+	// keys, and can spend.
+	WalletFullyUnlocked WalletUnlockLevel = "full"
 )
 
 var (
@@ -52,6 +71,9 @@ type (
 	// addresses.
 	Seed [crypto.EntropySize]byte
 
+	// WalletUnlockLevel describes how far the wallet is unlocked.
+	WalletUnlockLevel string
+
 	// WalletTransactionID is a unique identifier for a wallet transaction.
 	WalletTransactionID crypto.Hash
 
@@ -102,6 +124,184 @@ type (
 		Outputs []ProcessedOutput `json:"outputs"`
 	}
 
+	// A WalletReindexReport lists the discrepancies that were found and
+	// repaired when the wallet rebuilt its output set and transaction index
+	// from its confirmed transaction history.
+	WalletReindexReport struct {
+		// Outputs that were tracked by the wallet but have been spent or do
+		// not belong to the wallet.
+		RemovedSiacoinOutputs []types.SiacoinOutputID `json:"removedsiacoinoutputs"`
+		RemovedSiafundOutputs []types.SiafundOutputID `json:"removedsiafundoutputs"`
+
+		// Unspent outputs that were missing from the wallet.
+		RestoredSiacoinOutputs []types.SiacoinOutputID `json:"restoredsiacoinoutputs"`
+		RestoredSiafundOutputs []types.SiafundOutputID `json:"restoredsiafundoutputs"`
+
+		// Outputs whose tracked value or address disagreed with the history.
+		CorrectedSiacoinOutputs []types.SiacoinOutputID `json:"correctedsiacoinoutputs"`
+		CorrectedSiafundOutputs []types.SiafundOutputID `json:"correctedsiafundoutputs"`
+
+		// RepairedTransactionIndexEntries is the number of entries in the
+		// transaction lookup index that were missing, stale, or pointing at
+		// the wrong transaction.
+		RepairedTransactionIndexEntries int `json:"repairedtransactionindexentries"`
+
+		SiacoinBalanceBefore types.Currency `json:"siacoinbalancebefore"`
+		SiacoinBalanceAfter  types.Currency `json:"siacoinbalanceafter"`
+		SiafundBalanceBefore types.Currency `json:"siafundbalancebefore"`
+		SiafundBalanceAfter  types.Currency `json:"siafundbalanceafter"`
+	}
+
+	// A BalanceBucket is a group of wallet outputs, summarized by their total
+	// value and the number of outputs in the group.
+	BalanceBucket struct {
+		Value types.Currency `json:"value"`
+		Count int            `json:"count"`
+	}
+
+	// A WalletBalanceBreakdown splits the siacoin balance of the wallet by how
+	// soon the outputs can be spent.
+	WalletBalanceBreakdown struct {
+		// Spendable contains the confirmed outputs that can be spent now.
+		Spendable BalanceBucket `json:"spendable"`
+
+		// Maturing contains the miner payouts and siafund claims that have
+		// been confirmed but are still waiting out types.MaturityDelay.
+		Maturing BalanceBucket `json:"maturing"`
+
+		// Timelocked contains the confirmed outputs sent to timelocked
+		// wallet addresses whose timelock has not yet passed.
+		Timelocked BalanceBucket `json:"timelocked"`
+
+		// Unconfirmed contains the incoming outputs, including refunds, that
+		// are in the transaction pool but not yet in a block.
+		Unconfirmed BalanceBucket `json:"unconfirmed"`
+	}
+
+	// A WalletSendInput is an output that the wallet would spend to fund a
+	// send. Confirmations is zero for unconfirmed outputs and for outputs
+	// whose origin is not in the wallet's history.
+	WalletSendInput struct {
+		ID            types.SiacoinOutputID `json:"id"`
+		Value         types.Currency        `json:"value"`
+		Confirmations types.BlockHeight     `json:"confirmations"`
+		Unconfirmed   bool                  `json:"unconfirmed"`
+	}
+
+	// A WalletSendPreview describes the transactions that SendSiacoins would
+	// create for a send. Size is the encoded size of the signed transaction
+	// set in bytes.
+	WalletSendPreview struct {
+		Inputs []WalletSendInput `json:"inputs"`
+		Amount types.Currency    `json:"amount"`
+		Fee    types.Currency    `json:"fee"`
+		Change types.Currency    `json:"change"`
+		Size   uint64            `json:"size"`
+	}
+
+	// A WalletConsolidation describes a transaction that combines many
+	// wallet outputs into one. Inputs and Value count the outputs that were
+	// spent, and Output is the value of the new output after the fee.
+	//
+	// The Skipped fields count the outputs that were not eligible: outputs
+	// that are still maturing (miner payouts, siafund claims, and timelocked
+	// outputs), outputs in unconfirmed transactions, and confirmed outputs
+	// with fewer than the required number of confirmations.
+	WalletConsolidation struct {
+		TransactionID types.TransactionID `json:"transactionid"`
+		Inputs        int                 `json:"inputs"`
+		Value         types.Currency      `json:"value"`
+		Fee           types.Currency      `json:"fee"`
+		Output        types.Currency      `json:"output"`
+
+		SkippedImmature    int `json:"skippedimmature"`
+		SkippedUnconfirmed int `json:"skippedunconfirmed"`
+		SkippedShallow     int `json:"skippedshallow"`
+	}
+
+	// A WalletFeeBump records a child transaction that the wallet created to
+	// raise the fee paid by an unconfirmed send.
+	WalletFeeBump struct {
+		Height        types.BlockHeight   `json:"height"`
+		TransactionID types.TransactionID `json:"transactionid"`
+		Fee           types.Currency      `json:"fee"`
+	}
+
+	// A WalletAutoBump tracks a send whose fee is raised automatically while
+	// it remains unconfirmed. Status is one of "pending", "capped",
+	// "confirmed" or "failed".
+	WalletAutoBump struct {
+		TransactionID types.TransactionID `json:"transactionid"`
+		StartHeight   types.BlockHeight   `json:"startheight"`
+		Fee           types.Currency      `json:"fee"`
+		MaxFee        types.Currency      `json:"maxfee"`
+		Status        string              `json:"status"`
+		Error         string              `json:"error,omitempty"`
+		Bumps         []WalletFeeBump     `json:"bumps"`
+	}
+
+	// A WalletScheduledSend is a send that the wallet has signed and will
+	// broadcast at BroadcastAt. Its inputs are reserved until then. Status is
+	// one of "scheduled", "broadcast", "cancelled" or "failed".
+	WalletScheduledSend struct {
+		TransactionID types.TransactionID   `json:"transactionid"`
+		Outputs       []types.SiacoinOutput `json:"outputs"`
+		Fee           types.Currency        `json:"fee"`
+		BroadcastAt   time.Time             `json:"broadcastat"`
+		Status        string                `json:"status"`
+		Error         string                `json:"error,omitempty"`
+	}
+
+	// WalletClaimSettings control the automatic collection of siafund
+	// claims. While Enabled, the wallet spends its siafunds back to itself
+	// whenever their claim balance reaches Threshold, which turns the claim
+	// into siacoins.
+	WalletClaimSettings struct {
+		Enabled   bool           `json:"enabled"`
+		Threshold types.Currency `json:"threshold"`
+	}
+
+	// A WalletClaimCollection records the last transaction that the wallet
+	// made to collect its siafund claims. Status is one of "pending",
+	// "confirmed" or "failed". Claim is the claim balance at the time of
+	// collection; the siacoins are spendable once they have matured.
+	WalletClaimCollection struct {
+		Height        types.BlockHeight   `json:"height"`
+		TransactionID types.TransactionID `json:"transactionid"`
+		Siafunds      types.Currency      `json:"siafunds"`
+		Claim         types.Currency      `json:"claim"`
+		Status        string              `json:"status"`
+		Error         string              `json:"error,omitempty"`
+	}
+
+	// WalletSyncStatus reports how far the wallet is behind the consensus set.
+	// The wallet only catches up after it has been unlocked for the first
+	// time, and balances are incomplete until it has.
+	WalletSyncStatus struct {
+		Synced          bool              `json:"synced"`
+		Height          types.BlockHeight `json:"height"`
+		ConsensusHeight types.BlockHeight `json:"consensusheight"`
+		BlocksBehind    types.BlockHeight `json:"blocksbehind"`
+
+		// BlocksPerSecond is the rate at which the wallet has processed
+		// blocks since it started catching up. EstimatedSecondsRemaining is
+		// derived from it, and is zero when the rate is unknown.
+		BlocksPerSecond           float64 `json:"blockspersecond"`
+		EstimatedSecondsRemaining uint64  `json:"estimatedsecondsremaining"`
+	}
+
+	// WalletSeedVerification reports whether the first addresses derived from
+	// a seed are tracked by the wallet. Checked is the number of addresses
+	// derived, and Matched is how many of them the wallet tracks. Primary is
+	// true if the seed is the wallet's primary seed.
+	WalletSeedVerification struct {
+		Match            bool               `json:"match"`
+		Primary          bool               `json:"primary"`
+		Checked          uint64             `json:"checked"`
+		Matched          uint64             `json:"matched"`
+		MissingAddresses []types.UnlockHash `json:"missingaddresses"`
+	}
+
 	// TransactionBuilder is used to construct custom transactions. A transaction
 	// builder is initialized via 'RegisterTransaction' and then can be modified by
 	// adding funds or other fields. The transaction is completed by calling
@@ -248,8 +448,18 @@ type (
 		Unlock(masterKey crypto.TwofishKey) error
 
 		// Unlocked returns true if the wallet is currently unlocked, false
-		// otherwise.
+		// otherwise. A wallet that is only unlocked for viewing is not
+		// considered unlocked.
 		Unlocked() bool
+
+		// ViewUnlock unlocks the wallet for viewing. The wallet tracks its
+		// addresses and balances, but its seeds and secret keys stay
+		// encrypted, so nothing can be spent until Unlock is called. The
+		// wallet must have been fully unlocked at least once before.
+		ViewUnlock(masterKey crypto.TwofishKey) error
+
+		// UnlockLevel returns how far the wallet is unlocked.
+		UnlockLevel() WalletUnlockLevel
 	}
 
 	// KeyManager manages wallet keys, including the use of seeds, creating and
@@ -272,10 +482,24 @@ type (
 		// consumed.
 		PrimarySeed() (Seed, uint64, error)
 
+		// VerifySeed derives the first n addresses of a seed and checks them
+		// against the addresses tracked by the wallet, without modifying the
+		// wallet. n is capped at the number of addresses the wallet tracks
+		// for its primary seed, and zero checks all of them.
+		VerifySeed(seed Seed, n uint64) (WalletSeedVerification, error)
+
 		// NextAddress returns a new coin addresses generated from the
 		// primary seed.
 		NextAddress() (types.UnlockConditions, error)
 
+		// NextAddresses returns n new coin addresses generated from the
+		// primary seed, advancing the seed progress by n.
+		NextAddresses(n uint64) ([]types.UnlockConditions, error)
+
+		// NextTimelockedAddress returns new unlock conditions generated from
+		// the primary seed that cannot be spent until the given height.
+		NextTimelockedAddress(timelock types.BlockHeight) (types.UnlockConditions, error)
+
 		// CreateBackup will create a backup of the wallet at the provided
 		// filepath. The backup will have all seeds and keys.
 		CreateBackup(string) error
@@ -323,6 +547,10 @@ type (
 		// not considered in the unconfirmed balance.
 		UnconfirmedBalance() (outgoingSiacoins types.Currency, incomingSiacoins types.Currency)
 
+		// BalanceBreakdown returns the siacoin balance of the wallet split
+		// into spendable, maturing, timelocked, and unconfirmed outputs.
+		BalanceBreakdown() WalletBalanceBreakdown
+
 		// AddressTransactions returns all of the transactions that are related
 		// to a given address.
 		AddressTransactions(types.UnlockHash) []ProcessedTransaction
@@ -359,11 +587,71 @@ type (
 		// are also returned to the caller.
 		SendSiacoins(amount types.Currency, dest types.UnlockHash) ([]types.Transaction, error)
 
+		// SendSiacoinsSplit sends siacoins like SendSiacoins, but splits a
+		// send that would be too large for a single transaction into several
+		// transactions that together pay 'amount' to 'dest'. Each returned
+		// transaction set was submitted to the transaction pool separately.
+		SendSiacoinsSplit(amount types.Currency, dest types.UnlockHash) ([][]types.Transaction, error)
+
 		// SendSiafunds is a tool for sending siafunds from the wallet to an
 		// address. Sending money usually results in multiple transactions. The
 		// transactions are automatically given to the transaction pool, and
 		// are also returned to the caller.
 		SendSiafunds(amount types.Currency, dest types.UnlockHash) ([]types.Transaction, error)
+
+		// PreviewSiacoinSend returns the outputs, fee, and change that
+		// SendSiacoins would use to send 'amount', without creating or
+		// broadcasting any transactions.
+		PreviewSiacoinSend(amount types.Currency) (WalletSendPreview, error)
+
+		// ConsolidateOutputs spends up to maxInputs of the wallet's smallest
+		// outputs to a single new wallet address. Only confirmed, mature
+		// outputs with at least minConfirmations confirmations are spent.
+		ConsolidateOutputs(maxInputs int, minConfirmations types.BlockHeight) (WalletConsolidation, error)
+
+		// SendSiacoinsAutoBump sends siacoins like SendSiacoins, and then
+		// raises the fee paid by the transaction if it remains unconfirmed,
+		// never paying more than maxFee in total.
+		SendSiacoinsAutoBump(amount types.Currency, dest types.UnlockHash, maxFee types.Currency) ([]types.Transaction, error)
+
+		// AutoBumps returns the sends whose fees are being raised
+		// automatically, along with the bumps that have been made so far.
+		AutoBumps() []WalletAutoBump
+
+		// ScheduleSend creates and signs a transaction paying 'outputs', and
+		// broadcasts it at time 'at'. The inputs of the transaction are
+		// reserved until the send is broadcast or cancelled.
+		ScheduleSend(outputs []types.SiacoinOutput, at time.Time) (WalletScheduledSend, error)
+
+		// ScheduledSends returns the sends that have been scheduled, oldest
+		// first.
+		ScheduledSends() []WalletScheduledSend
+
+		// CancelScheduledSend cancels a scheduled send that has not been
+		// broadcast, releasing its inputs.
+		CancelScheduledSend(id types.TransactionID) error
+
+		// ClaimSettings returns the settings for the automatic collection of
+		// siafund claims.
+		ClaimSettings() WalletClaimSettings
+
+		// SetClaimSettings sets the settings for the automatic collection of
+		// siafund claims.
+		SetClaimSettings(WalletClaimSettings) error
+
+		// LastClaimCollection returns the last automatic collection of
+		// siafund claims. The bool is false if no collection has been made.
+		LastClaimCollection() (WalletClaimCollection, bool)
+
+		// SyncStatus reports whether the wallet has caught up with the
+		// consensus set, and estimates how long catching up will take.
+		SyncStatus() WalletSyncStatus
+
+		// Reindex rebuilds the wallet's unspent outputs and transaction index
+		// from its confirmed transaction history, repairing any drift between
+		// the two without rescanning the consensus set. The wallet must be
+		// unlocked.
+		Reindex() (WalletReindexReport, error)
 	}
 )
 
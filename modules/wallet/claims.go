@@ -0,0 +1,156 @@
+package wallet
+
+import (
+	"errors"
+
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+var (
+	errClaimNotConfirmed    = errors.New("claim collection was not confirmed")
+	errClaimThresholdTooLow = errors.New("claim threshold must be greater than the fee paid to collect the claim")
+)
+
+// collectableSiafunds returns the siafunds that the wallet can spend at the
+// current height, along with the claim balance of those siafunds. Outputs are
+// filtered the same way as in FundSiafunds.
+func (w *Wallet) collectableSiafunds() (siafunds, claim types.Currency) {
+	allowedHeight := w.consensusSetHeight - RespendTimeout
+	if w.consensusSetHeight < RespendTimeout {
+		allowedHeight = 0
+	}
+	for sfoid, sfo := range w.siafundOutputs {
+		if w.spentOutputs[types.OutputID(sfoid)] > allowedHeight {
+			continue
+		}
+		if w.consensusSetHeight < w.keys[sfo.UnlockHash].UnlockConditions.Timelock {
+			continue
+		}
+		siafunds = siafunds.Add(sfo.Value)
+		claim = claim.Add(w.siafundPool.Sub(sfo.ClaimStart).Mul(sfo.Value).Div(types.SiafundCount))
+	}
+	return siafunds, claim
+}
+
+// claimCollectionDue returns true if collection is enabled and the claim
+// balance of the collectable siafunds has reached the threshold. After a
+// collection that has not been confirmed, the wallet waits until the spent
+// outputs can be respent before trying again.
+func (w *Wallet) claimCollectionDue() bool {
+	if !w.persist.ClaimSettings.Enabled || !w.unlocked || w.collectingClaims {
+		return false
+	}
+	lc := w.persist.LastClaimCollection
+	if lc != nil && lc.Status != "confirmed" && w.consensusSetHeight < lc.Height+RespendTimeout {
+		return false
+	}
+	siafunds, claim := w.collectableSiafunds()
+	return !siafunds.IsZero() && claim.Cmp(w.persist.ClaimSettings.Threshold) >= 0
+}
+
+// updateClaimCollection marks the last claim collection as confirmed, or as
+// failed if it has not been confirmed in time, and returns true if another
+// collection is due.
+func (w *Wallet) updateClaimCollection() bool {
+	lc := w.persist.LastClaimCollection
+	if lc != nil && lc.Status == "pending" {
+		if _, exists := w.processedTransactionMap[lc.TransactionID]; exists {
+			lc.Status = "confirmed"
+		} else if w.consensusSetHeight >= lc.Height+RespendTimeout {
+			lc.Status = "failed"
+			lc.Error = errClaimNotConfirmed.Error()
+		}
+		if lc.Status != "pending" {
+			if err := w.saveSettings(); err != nil {
+				w.log.Println("WARN: could not save claim collection status:", err)
+			}
+		}
+	}
+	return w.claimCollectionDue()
+}
+
+// threadedCollectClaims spends all of the wallet's collectable siafunds to a
+// new wallet address. Spending a siafund output pays out its claim, so the
+// siacoins accrued by the siafunds become spendable once they mature.
+func (w *Wallet) threadedCollectClaims() {
+	if err := w.tg.Add(); err != nil {
+		return
+	}
+	defer w.tg.Done()
+
+	w.mu.Lock()
+	if !w.claimCollectionDue() {
+		w.mu.Unlock()
+		return
+	}
+	siafunds, claim := w.collectableSiafunds()
+	height := w.consensusSetHeight
+	uc, err := w.nextPrimarySeedAddress()
+	w.collectingClaims = err == nil
+	w.mu.Unlock()
+
+	// SendSiafunds submits the transaction to the transaction pool, so the
+	// wallet must not be locked.
+	var txnSet []types.Transaction
+	if err == nil {
+		txnSet, err = w.SendSiafunds(siafunds, uc.UnlockHash())
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.collectingClaims = false
+	lc := &modules.WalletClaimCollection{
+		Height:   height,
+		Siafunds: siafunds,
+		Claim:    claim,
+		Status:   "pending",
+	}
+	if err != nil {
+		lc.Status = "failed"
+		lc.Error = err.Error()
+		w.log.Println("WARN: siafund claim collection failed:", err)
+	} else {
+		lc.TransactionID = txnSet[len(txnSet)-1].ID()
+	}
+	w.persist.LastClaimCollection = lc
+	if err := w.saveSettingsSync(); err != nil {
+		w.log.Println("WARN: could not save claim collection:", err)
+	}
+}
+
+// ClaimSettings returns the settings for the automatic collection of siafund
+// claims.
+func (w *Wallet) ClaimSettings() modules.WalletClaimSettings {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.persist.ClaimSettings
+}
+
+// SetClaimSettings sets the settings for the automatic collection of siafund
+// claims. The claims are checked against the threshold after each block.
+func (w *Wallet) SetClaimSettings(settings modules.WalletClaimSettings) error {
+	if err := w.tg.Add(); err != nil {
+		return err
+	}
+	defer w.tg.Done()
+	if settings.Enabled && settings.Threshold.Cmp(sendFee) <= 0 {
+		return errClaimThresholdTooLow
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.persist.ClaimSettings = settings
+	return w.saveSettingsSync()
+}
+
+// LastClaimCollection returns the last automatic collection of siafund
+// claims, and false if no collection has been made.
+func (w *Wallet) LastClaimCollection() (modules.WalletClaimCollection, bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.persist.LastClaimCollection == nil {
+		return modules.WalletClaimCollection{}, false
+	}
+	return *w.persist.LastClaimCollection, true
+}
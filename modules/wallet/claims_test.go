@@ -0,0 +1,87 @@
+package wallet
+
+import (
+	"testing"
+
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// TestCollectClaims checks that the wallet spends its siafunds back to itself
+// once their claim balance reaches the threshold, and that the collection is
+// reported as confirmed once it makes it into a block.
+func TestCollectClaims(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester("TestCollectClaims")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+	err = wt.wallet.LoadSiagKeys(wt.walletMasterKey, []string{"../../types/siag0of1of1.siakey"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	settings := modules.WalletClaimSettings{Enabled: true, Threshold: sendFee}
+	if err := wt.wallet.SetClaimSettings(settings); err != errClaimThresholdTooLow {
+		t.Fatal("expected errClaimThresholdTooLow, got", err)
+	}
+	settings.Threshold = sendFee.Mul64(2)
+	if err := wt.wallet.SetClaimSettings(settings); err != nil {
+		t.Fatal(err)
+	}
+	if cs := wt.wallet.ClaimSettings(); !cs.Enabled || cs.Threshold.Cmp(settings.Threshold) != 0 {
+		t.Fatal("settings were not saved")
+	}
+
+	// Nothing is collected while the claim balance is below the threshold.
+	wt.wallet.threadedCollectClaims()
+	if _, ok := wt.wallet.LastClaimCollection(); ok {
+		t.Fatal("claims collected below the threshold")
+	}
+
+	// Raise the wallet's view of the siafund pool so that the claim of its
+	// 2000 siafunds exceeds the threshold.
+	wt.wallet.mu.Lock()
+	wt.wallet.siafundPool = sendFee.Mul64(20)
+	wt.wallet.mu.Unlock()
+	wt.wallet.threadedCollectClaims()
+	lc, ok := wt.wallet.LastClaimCollection()
+	if !ok || lc.Status != "pending" || lc.Siafunds.Cmp(types.NewCurrency64(2000)) != 0 || lc.Claim.Cmp(sendFee.Mul64(4)) != 0 {
+		t.Fatalf("unexpected collection: %+v", lc)
+	}
+
+	// A pending collection is not repeated.
+	wt.wallet.mu.Lock()
+	due := wt.wallet.claimCollectionDue()
+	wt.wallet.mu.Unlock()
+	if due {
+		t.Fatal("collection due while another is pending")
+	}
+
+	if _, err := wt.miner.AddBlock(); err != nil {
+		t.Fatal(err)
+	}
+	lc, _ = wt.wallet.LastClaimCollection()
+	if lc.Status != "confirmed" {
+		t.Fatalf("unexpected collection: %+v", lc)
+	}
+	if _, siafundBal, _ := wt.wallet.ConfirmedBalance(); siafundBal.Cmp(types.NewCurrency64(2000)) != 0 {
+		t.Fatal("siafunds were not sent back to the wallet:", siafundBal)
+	}
+
+	// The settings and the collection survive a restart.
+	w, err := New(wt.cs, wt.tpool, wt.wallet.persistDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if cs := w.ClaimSettings(); !cs.Enabled || cs.Threshold.Cmp(settings.Threshold) != 0 {
+		t.Fatal("settings were not persisted")
+	}
+	if lc2, ok := w.LastClaimCollection(); !ok || lc2.TransactionID != lc.TransactionID {
+		t.Fatal("collection was not persisted")
+	}
+}
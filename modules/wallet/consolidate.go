@@ -0,0 +1,162 @@
+package wallet
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/NebulousLabs/Sia/crypto"
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// maxConsolidationInputs is the largest number of outputs spent by a single
+// consolidation. A signed input takes roughly 250 bytes, so this keeps the
+// transaction well below modules.TransactionSizeLimit.
+const maxConsolidationInputs = 50
+
+var (
+	errConsolidationTooFewInputs = errors.New("wallet has fewer than two outputs that can be consolidated")
+	errConsolidationBelowFee     = errors.New("value of the outputs to consolidate does not cover the fee")
+)
+
+// consolidationCandidates returns the outputs that can be consolidated,
+// smallest first, along with counts of the outputs that were skipped. Only
+// confirmed outputs that have matured, are not timelocked, and have at least
+// minConfirmations confirmations are eligible. Outputs whose confirmation
+// height is unknown are only eligible if minConfirmations is zero. Outputs
+// that were spent recently are left out without being counted. The wallet
+// must be locked by the caller.
+func (w *Wallet) consolidationCandidates(minConfirmations types.BlockHeight) (sortedOutputs, modules.WalletConsolidation) {
+	var report modules.WalletConsolidation
+	allowedHeight := w.consensusSetHeight - RespendTimeout
+	if w.consensusSetHeight < RespendTimeout {
+		allowedHeight = 0
+	}
+	heights := w.outputConfirmationHeights()
+
+	var so sortedOutputs
+	for scoid, sco := range w.siacoinOutputs {
+		if w.spentOutputs[types.OutputID(scoid)] > allowedHeight {
+			continue
+		}
+		if w.consensusSetHeight < w.keys[sco.UnlockHash].UnlockConditions.Timelock {
+			report.SkippedImmature++
+			continue
+		}
+		if minConfirmations > 0 {
+			height, ok := heights[scoid]
+			if !ok || w.consensusSetHeight-height+1 < minConfirmations {
+				report.SkippedShallow++
+				continue
+			}
+		}
+		so.ids = append(so.ids, scoid)
+		so.outputs = append(so.outputs, sco)
+	}
+	sort.Sort(so)
+
+	// Miner payouts and siafund claims only appear in the wallet's outputs
+	// once they mature, so the maturing ones are counted from the history.
+	for _, pt := range w.processedTransactions {
+		for _, po := range pt.Outputs {
+			if po.FundType != types.SpecifierMinerPayout && po.FundType != types.SpecifierClaimOutput {
+				continue
+			}
+			if po.WalletAddress && po.MaturityHeight > w.consensusSetHeight {
+				report.SkippedImmature++
+			}
+		}
+	}
+	for _, upt := range w.unconfirmedProcessedTransactions {
+		for _, po := range upt.Outputs {
+			if po.FundType == types.SpecifierSiacoinOutput && po.WalletAddress {
+				report.SkippedUnconfirmed++
+			}
+		}
+	}
+	return so, report
+}
+
+// ConsolidateOutputs spends up to maxInputs of the wallet's smallest eligible
+// outputs to a new address of the primary seed, in a single transaction that
+// pays the standard send fee. Unconfirmed and immature outputs are never
+// spent, so the transaction is valid as soon as it is submitted. Passing a
+// minConfirmations lower than modules.ConsolidationSafeDepth also spends
+// outputs that could still be undone by a reorg.
+func (w *Wallet) ConsolidateOutputs(maxInputs int, minConfirmations types.BlockHeight) (modules.WalletConsolidation, error) {
+	if err := w.tg.Add(); err != nil {
+		return modules.WalletConsolidation{}, err
+	}
+	defer w.tg.Done()
+	if maxInputs <= 0 || maxInputs > maxConsolidationInputs {
+		maxInputs = maxConsolidationInputs
+	}
+
+	w.mu.Lock()
+	if !w.unlocked {
+		w.mu.Unlock()
+		return modules.WalletConsolidation{}, modules.ErrLockedWallet
+	}
+	so, report := w.consolidationCandidates(minConfirmations)
+	if len(so.ids) > maxInputs {
+		so.ids = so.ids[:maxInputs]
+		so.outputs = so.outputs[:maxInputs]
+	}
+	if len(so.ids) < 2 {
+		w.mu.Unlock()
+		return report, errConsolidationTooFewInputs
+	}
+	for _, sco := range so.outputs {
+		report.Value = report.Value.Add(sco.Value)
+	}
+	if report.Value.Cmp(sendFee) <= 0 {
+		w.mu.Unlock()
+		return report, errConsolidationBelowFee
+	}
+	uc, err := w.nextPrimarySeedAddress()
+	if err != nil {
+		w.mu.Unlock()
+		return report, err
+	}
+	report.Inputs = len(so.ids)
+	report.Fee = sendFee
+	report.Output = report.Value.Sub(sendFee)
+
+	txn := types.Transaction{
+		SiacoinOutputs: []types.SiacoinOutput{{
+			Value:      report.Output,
+			UnlockHash: uc.UnlockHash(),
+		}},
+		MinerFees: []types.Currency{sendFee},
+	}
+	for i, scoid := range so.ids {
+		txn.SiacoinInputs = append(txn.SiacoinInputs, types.SiacoinInput{
+			ParentID:         scoid,
+			UnlockConditions: w.keys[so.outputs[i].UnlockHash].UnlockConditions,
+		})
+	}
+	for _, sci := range txn.SiacoinInputs {
+		_, err := addSignatures(&txn, types.FullCoveredFields, sci.UnlockConditions, crypto.Hash(sci.ParentID), w.keys[sci.UnlockConditions.UnlockHash()])
+		if err != nil {
+			w.mu.Unlock()
+			return report, err
+		}
+	}
+	for _, scoid := range so.ids {
+		w.spentOutputs[types.OutputID(scoid)] = w.consensusSetHeight
+	}
+	w.mu.Unlock()
+
+	// The transaction pool calls back into the wallet, so the transaction is
+	// submitted without holding the lock.
+	if err := w.tpool.AcceptTransactionSet([]types.Transaction{txn}); err != nil {
+		w.mu.Lock()
+		for _, scoid := range so.ids {
+			delete(w.spentOutputs, types.OutputID(scoid))
+		}
+		w.mu.Unlock()
+		return report, err
+	}
+	report.TransactionID = txn.ID()
+	return report, nil
+}
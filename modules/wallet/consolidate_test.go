@@ -0,0 +1,64 @@
+package wallet
+
+import (
+	"testing"
+
+	"github.com/NebulousLabs/Sia/modules"
+)
+
+// TestConsolidateOutputs checks that consolidation only spends confirmed,
+// mature outputs past the requested depth, and reports the outputs it skips.
+func TestConsolidateOutputs(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester("TestConsolidateOutputs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	// Mine enough blocks that some miner payouts are past the safe depth,
+	// some have matured recently, and some are still maturing.
+	for i := 0; i < modules.ConsolidationSafeDepth+2; i++ {
+		if _, err := wt.miner.AddBlock(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bd := wt.wallet.BalanceBreakdown()
+	report, err := wt.wallet.ConsolidateOutputs(0, modules.ConsolidationSafeDepth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Inputs < 2 || report.SkippedShallow == 0 || report.Inputs+report.SkippedShallow != bd.Spendable.Count {
+		t.Fatalf("wrong outputs consolidated: %+v (spendable %v)", report, bd.Spendable.Count)
+	}
+	if report.SkippedImmature != bd.Maturing.Count || report.SkippedImmature == 0 {
+		t.Fatalf("maturing outputs were not skipped: %+v (maturing %v)", report, bd.Maturing.Count)
+	}
+	if report.Output.Cmp(report.Value.Sub(sendFee)) != 0 {
+		t.Fatal("consolidated output does not match the inputs minus the fee")
+	}
+
+	// Overriding the depth spends the shallow outputs, but not the output of
+	// the unconfirmed consolidation.
+	shallow := report.SkippedShallow
+	report, err = wt.wallet.ConsolidateOutputs(0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Inputs != shallow || report.SkippedShallow != 0 || report.SkippedUnconfirmed == 0 {
+		t.Fatalf("unexpected report with depth override: %+v", report)
+	}
+
+	// Nothing is left to consolidate until the transactions confirm.
+	if _, err := wt.wallet.ConsolidateOutputs(0, 0); err != errConsolidationTooFewInputs {
+		t.Fatal("expected errConsolidationTooFewInputs, got", err)
+	}
+	if _, err := wt.miner.AddBlock(); err != nil {
+		t.Fatal(err)
+	}
+	if _, exists := wt.wallet.Transaction(report.TransactionID); !exists {
+		t.Fatal("consolidation was not confirmed")
+	}
+}
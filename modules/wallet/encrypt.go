@@ -87,11 +87,9 @@ func (w *Wallet) initEncryption(masterKey crypto.TwofishKey) (modules.Seed, erro
 // after loading, the structures are kept encrypted, but some data such as
 // addresses are decrypted so that the wallet knows what to track.
 func (w *Wallet) managedUnlock(masterKey crypto.TwofishKey) error {
-	var subscribed bool
 	err := func() error {
 		w.mu.Lock()
 		defer w.mu.Unlock()
-		subscribed = w.subscribed
 
 		// Wallet should only be unlocked once.
 		if w.unlocked {
@@ -115,6 +113,9 @@ func (w *Wallet) managedUnlock(masterKey crypto.TwofishKey) error {
 			return err
 		}
 
+		// Load the timelocked addresses of the primary seed.
+		w.initTimelockedKeys()
+
 		// Load all wallet seeds that are not used to generate new addresses.
 		err = w.initAuxiliarySeeds(masterKey)
 		if err != nil {
@@ -122,44 +123,71 @@ func (w *Wallet) managedUnlock(masterKey crypto.TwofishKey) error {
 		}
 
 		// Load all keys that were not generated by a seed.
-		return w.initUnseededKeys(masterKey)
+		err = w.initUnseededKeys(masterKey)
+		if err != nil {
+			return err
+		}
+
+		// Save the unlock conditions of the keys, so that the wallet can
+		// later be unlocked for viewing without loading the seeds.
+		w.viewKey = viewEncryptionKey(masterKey, w.persist.UID)
+		return w.saveSettings()
 	}()
 	if err != nil {
 		return err
 	}
 
-	// Subscribe to the consensus set if this is the first unlock for the
-	// wallet object.
-	if !subscribed {
-		// During rescan, print height every 3 seconds.
-		if build.Release != "testing" {
-			go func() {
-				println("Rescanning consensus set...")
-				for range time.Tick(time.Second * 3) {
-					w.mu.RLock()
-					height := w.consensusSetHeight
-					done := w.subscribed
-					w.mu.RUnlock()
-					if done {
-						println("\nDone!")
-						break
-					}
-					print("\rScanned to height ", height, "...")
-				}
-			}()
-		}
-		err = w.cs.ConsensusSetSubscribe(w, modules.ConsensusChangeBeginning)
-		if err != nil {
-			return errors.New("wallet subscription failed: " + err.Error())
-		}
-		w.tpool.TransactionPoolSubscribe(w)
-		w.mu.Lock()
-		w.subscribed = true
-		w.mu.Unlock()
+	err = w.managedSubscribe()
+	if err != nil {
+		return err
 	}
 
 	w.mu.Lock()
 	w.unlocked = true
+	w.viewUnlocked = false
+	w.restoreScheduledSends()
+	w.mu.Unlock()
+	return nil
+}
+
+// managedSubscribe subscribes the wallet to the consensus set and the
+// transaction pool if this is the first unlock for the wallet object.
+func (w *Wallet) managedSubscribe() error {
+	w.mu.RLock()
+	subscribed := w.subscribed
+	w.mu.RUnlock()
+	if subscribed {
+		return nil
+	}
+
+	// During rescan, print height every 3 seconds.
+	if build.Release != "testing" {
+		go func() {
+			println("Rescanning consensus set...")
+			for range time.Tick(time.Second * 3) {
+				w.mu.RLock()
+				height := w.consensusSetHeight
+				done := w.subscribed
+				w.mu.RUnlock()
+				if done {
+					println("\nDone!")
+					break
+				}
+				print("\rScanned to height ", height, "...")
+			}
+		}()
+	}
+	w.mu.Lock()
+	w.scanStart = time.Now()
+	w.scanStartHeight = w.consensusSetHeight
+	w.mu.Unlock()
+	err := w.cs.ConsensusSetSubscribe(w, modules.ConsensusChangeBeginning)
+	if err != nil {
+		return errors.New("wallet subscription failed: " + err.Error())
+	}
+	w.tpool.TransactionPoolSubscribe(w)
+	w.mu.Lock()
+	w.subscribed = true
 	w.mu.Unlock()
 	return nil
 }
@@ -177,6 +205,7 @@ func (w *Wallet) wipeSecrets() {
 		crypto.SecureWipe(w.seeds[i][:])
 	}
 	crypto.SecureWipe(w.primarySeed[:])
+	crypto.SecureWipe(w.viewKey[:])
 	w.seeds = w.seeds[:0]
 }
 
@@ -221,7 +250,7 @@ func (w *Wallet) Unlocked() bool {
 func (w *Wallet) Lock() error {
 	w.mu.Lock()
 	defer w.mu.Unlock()
-	if !w.unlocked {
+	if !w.unlocked && !w.viewUnlocked {
 		return modules.ErrLockedWallet
 	}
 	w.log.Println("INFO: Locking wallet.")
@@ -230,6 +259,7 @@ func (w *Wallet) Lock() error {
 	// calling 'Unlock' again.
 	w.wipeSecrets()
 	w.unlocked = false
+	w.viewUnlocked = false
 	return nil
 }
 
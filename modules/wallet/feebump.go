@@ -0,0 +1,256 @@
+package wallet
+
+import (
+	"errors"
+
+	"github.com/NebulousLabs/Sia/build"
+	"github.com/NebulousLabs/Sia/crypto"
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+var (
+	// autoBumpInterval is the number of blocks that a send may remain
+	// unconfirmed before its fee is raised.
+	autoBumpInterval = func() types.BlockHeight {
+		if build.Release == "dev" {
+			return 3
+		}
+		if build.Release == "standard" {
+			return 6 // 1 hour.
+		}
+		if build.Release == "testing" {
+			return 1
+		}
+		panic("unrecognized release constant in wallet - autoBumpInterval")
+	}()
+
+	errMaxFeeTooLow   = errors.New("maximum fee must be greater than the fee paid by the transaction")
+	errNoBumpOutput   = errors.New("transaction has no unspent wallet output that can pay a higher fee")
+	errNoTransactions = errors.New("transaction set is empty")
+)
+
+// autoBump is a send whose fee is raised by the wallet while the send remains
+// unconfirmed. Fees are raised using child-pays-for-parent: each bump is a
+// transaction that spends a wallet output created by the send (or by the
+// previous bump) and pays the additional fee, so that the transaction pool
+// and miners consider the send and its bumps as a single set.
+type autoBump struct {
+	modules.WalletAutoBump
+
+	// lastHeight is the height at which the send was made or last bumped.
+	// change is the wallet output that will fund the next bump.
+	lastHeight types.BlockHeight
+	changeID   types.SiacoinOutputID
+	change     types.SiacoinOutput
+	hasChange  bool
+}
+
+// setFee returns the total miner fees paid by a transaction set.
+func setFee(txns []types.Transaction) (fee types.Currency) {
+	for _, txn := range txns {
+		for _, mf := range txn.MinerFees {
+			fee = fee.Add(mf)
+		}
+	}
+	return fee
+}
+
+// findChange returns a siacoin output in txns that belongs to the wallet and
+// has not been spent.
+func (w *Wallet) findChange(txns []types.Transaction) (types.SiacoinOutputID, types.SiacoinOutput, bool) {
+	for _, txn := range txns {
+		for i, sco := range txn.SiacoinOutputs {
+			if _, exists := w.keys[sco.UnlockHash]; !exists {
+				continue
+			}
+			id := txn.SiacoinOutputID(uint64(i))
+			if _, spent := w.spentOutputs[types.OutputID(id)]; spent {
+				continue
+			}
+			return id, sco, true
+		}
+	}
+	return types.SiacoinOutputID{}, types.SiacoinOutput{}, false
+}
+
+// SendSiacoinsAutoBump creates a transaction sending 'amount' to 'dest', and
+// raises the fee paid by the transaction every autoBumpInterval blocks while
+// it remains unconfirmed. Each bump doubles the total fee, up to maxFee.
+func (w *Wallet) SendSiacoinsAutoBump(amount types.Currency, dest types.UnlockHash, maxFee types.Currency) ([]types.Transaction, error) {
+	if err := w.tg.Add(); err != nil {
+		return nil, err
+	}
+	defer w.tg.Done()
+	if maxFee.Cmp(sendFee) <= 0 {
+		return nil, errMaxFeeTooLow
+	}
+
+	txnSet, err := w.SendSiacoins(amount, dest)
+	if err != nil {
+		return nil, err
+	}
+	if len(txnSet) == 0 {
+		return nil, errNoTransactions
+	}
+	fee := setFee(txnSet)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	ab := &autoBump{
+		WalletAutoBump: modules.WalletAutoBump{
+			TransactionID: txnSet[len(txnSet)-1].ID(),
+			StartHeight:   w.consensusSetHeight,
+			Fee:           fee,
+			MaxFee:        maxFee,
+			Status:        "pending",
+		},
+		lastHeight: w.consensusSetHeight,
+	}
+	ab.changeID, ab.change, ab.hasChange = w.findChange(txnSet)
+	w.autoBumps = append(w.autoBumps, ab)
+	return txnSet, nil
+}
+
+// AutoBumps returns the sends whose fees are raised automatically by the
+// wallet, oldest first.
+func (w *Wallet) AutoBumps() []modules.WalletAutoBump {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	abs := make([]modules.WalletAutoBump, 0, len(w.autoBumps))
+	for _, ab := range w.autoBumps {
+		wab := ab.WalletAutoBump
+		wab.Bumps = append([]modules.WalletFeeBump(nil), ab.Bumps...)
+		abs = append(abs, wab)
+	}
+	return abs
+}
+
+// updateAutoBumps marks the sends that have been confirmed, and returns true
+// if any send is due for a bump.
+func (w *Wallet) updateAutoBumps() (due bool) {
+	for _, ab := range w.autoBumps {
+		if ab.Status == "confirmed" {
+			continue
+		}
+		if _, exists := w.processedTransactionMap[ab.TransactionID]; exists {
+			ab.Status = "confirmed"
+			continue
+		}
+		if ab.Status == "pending" && w.consensusSetHeight >= ab.lastHeight+autoBumpInterval {
+			due = true
+		}
+	}
+	return due
+}
+
+// buildBump creates a transaction raising the fee paid by the send tracked by
+// ab, spending the send's change output. The new fee is double the current
+// fee, capped at the maximum fee and at the value of the change output.
+func (w *Wallet) buildBump(ab *autoBump) (types.Transaction, types.Currency, error) {
+	if !ab.hasChange {
+		return types.Transaction{}, types.Currency{}, errNoBumpOutput
+	}
+	if _, spent := w.spentOutputs[types.OutputID(ab.changeID)]; spent {
+		return types.Transaction{}, types.Currency{}, errNoBumpOutput
+	}
+	bump := ab.Fee
+	if remaining := ab.MaxFee.Sub(ab.Fee); bump.Cmp(remaining) > 0 {
+		bump = remaining
+	}
+	if bump.Cmp(ab.change.Value) > 0 {
+		bump = ab.change.Value
+	}
+
+	uc := w.keys[ab.change.UnlockHash].UnlockConditions
+	txn := types.Transaction{
+		SiacoinInputs: []types.SiacoinInput{{
+			ParentID:         ab.changeID,
+			UnlockConditions: uc,
+		}},
+		MinerFees: []types.Currency{bump},
+	}
+	if ab.change.Value.Cmp(bump) > 0 {
+		refundUC, err := w.nextPrimarySeedAddress()
+		if err != nil {
+			return types.Transaction{}, types.Currency{}, err
+		}
+		txn.SiacoinOutputs = append(txn.SiacoinOutputs, types.SiacoinOutput{
+			Value:      ab.change.Value.Sub(bump),
+			UnlockHash: refundUC.UnlockHash(),
+		})
+	}
+	_, err := addSignatures(&txn, types.FullCoveredFields, uc, crypto.Hash(ab.changeID), w.keys[uc.UnlockHash()])
+	if err != nil {
+		return types.Transaction{}, types.Currency{}, err
+	}
+	return txn, bump, nil
+}
+
+// threadedBumpFees raises the fees of the sends that have been unconfirmed for
+// at least autoBumpInterval blocks since they were last bumped. Bumps are
+// submitted outside of the consensus change that triggered them, because the
+// transaction pool calls back into the consensus set.
+func (w *Wallet) threadedBumpFees() {
+	if err := w.tg.Add(); err != nil {
+		return
+	}
+	defer w.tg.Done()
+
+	type pendingBump struct {
+		ab   *autoBump
+		txn  types.Transaction
+		bump types.Currency
+	}
+	var pending []pendingBump
+	w.mu.Lock()
+	if !w.unlocked {
+		w.mu.Unlock()
+		return
+	}
+	for _, ab := range w.autoBumps {
+		if ab.Status != "pending" || w.consensusSetHeight < ab.lastHeight+autoBumpInterval {
+			continue
+		}
+		ab.lastHeight = w.consensusSetHeight
+		txn, bump, err := w.buildBump(ab)
+		if err != nil {
+			ab.Status = "failed"
+			ab.Error = err.Error()
+			continue
+		}
+		w.spentOutputs[types.OutputID(ab.changeID)] = w.consensusSetHeight
+		pending = append(pending, pendingBump{ab, txn, bump})
+	}
+	w.mu.Unlock()
+
+	for _, pb := range pending {
+		err := w.tpool.AcceptTransactionSet([]types.Transaction{pb.txn})
+
+		w.mu.Lock()
+		ab := pb.ab
+		if err != nil {
+			delete(w.spentOutputs, types.OutputID(ab.changeID))
+			ab.Status = "failed"
+			ab.Error = err.Error()
+			w.mu.Unlock()
+			continue
+		}
+		ab.Fee = ab.Fee.Add(pb.bump)
+		ab.Bumps = append(ab.Bumps, modules.WalletFeeBump{
+			Height:        w.consensusSetHeight,
+			TransactionID: pb.txn.ID(),
+			Fee:           pb.bump,
+		})
+		ab.hasChange = len(pb.txn.SiacoinOutputs) > 0
+		if ab.hasChange {
+			ab.changeID = pb.txn.SiacoinOutputID(0)
+			ab.change = pb.txn.SiacoinOutputs[0]
+		}
+		if ab.Fee.Cmp(ab.MaxFee) >= 0 {
+			ab.Status = "capped"
+		}
+		w.mu.Unlock()
+	}
+}
@@ -0,0 +1,81 @@
+package wallet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// addEmptyBlock mines a block that does not contain any of the transactions in
+// the transaction pool.
+func (wt *walletTester) addEmptyBlock() error {
+	b, target, err := wt.miner.BlockForWork()
+	if err != nil {
+		return err
+	}
+	b.Transactions = nil
+	b.MinerPayouts = []types.SiacoinOutput{{
+		Value:      b.CalculateSubsidy(wt.cs.Height() + 1),
+		UnlockHash: types.UnlockHash{},
+	}}
+	solved, _ := wt.miner.SolveBlock(b, target)
+	return wt.cs.AcceptBlock(solved)
+}
+
+// TestSendSiacoinsAutoBump checks that the fee of an unconfirmed send is
+// raised until it reaches the maximum fee, and that the send is reported as
+// confirmed once it makes it into a block.
+func TestSendSiacoinsAutoBump(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester("TestSendSiacoinsAutoBump")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	_, err = wt.wallet.SendSiacoinsAutoBump(types.NewCurrency64(5000), types.UnlockHash{}, sendFee)
+	if err != errMaxFeeTooLow {
+		t.Fatal("expected errMaxFeeTooLow, got", err)
+	}
+	maxFee := sendFee.Mul64(3)
+	txns, err := wt.wallet.SendSiacoinsAutoBump(types.NewCurrency64(5000), types.UnlockHash{}, maxFee)
+	if err != nil {
+		t.Fatal(err)
+	}
+	abs := wt.wallet.AutoBumps()
+	if len(abs) != 1 || abs[0].Status != "pending" || abs[0].TransactionID != txns[len(txns)-1].ID() || abs[0].Fee.Cmp(sendFee) != 0 {
+		t.Fatal("send was not tracked correctly:", abs)
+	}
+
+	// Each empty block should trigger a bump, doubling the fee and then
+	// hitting the cap. Bumps are made in a separate goroutine.
+	for _, expected := range []types.Currency{sendFee.Mul64(2), maxFee} {
+		if err := wt.addEmptyBlock(); err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 50; i++ {
+			abs = wt.wallet.AutoBumps()
+			if abs[0].Fee.Cmp(expected) == 0 {
+				break
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		if abs[0].Fee.Cmp(expected) != 0 {
+			t.Fatalf("expected fee %v, got %v (%v)", expected, abs[0].Fee, abs[0].Error)
+		}
+	}
+	if abs[0].Status != "capped" || len(abs[0].Bumps) != 2 {
+		t.Fatal("expected the send to be capped after two bumps:", abs[0])
+	}
+
+	// Mining a block with the transaction pool confirms the send.
+	if _, err := wt.miner.AddBlock(); err != nil {
+		t.Fatal(err)
+	}
+	if abs = wt.wallet.AutoBumps(); abs[0].Status != "confirmed" {
+		t.Fatal("expected the send to be confirmed, got", abs[0].Status)
+	}
+}
@@ -2,9 +2,14 @@ package wallet
 
 import (
 	"github.com/NebulousLabs/Sia/build"
+	"github.com/NebulousLabs/Sia/modules"
 	"github.com/NebulousLabs/Sia/types"
 )
 
+// sendFee is the miner fee paid by the transactions created by SendSiacoins
+// and SendSiafunds.
+var sendFee = types.SiacoinPrecision.Mul64(10) // TODO: better fee algo.
+
 // sortedOutputs is a struct containing a slice of siacoin outputs and their
 // corresponding ids. sortedOutputs can be sorted using the sort package.
 type sortedOutputs struct {
@@ -50,6 +55,45 @@ func (w *Wallet) UnconfirmedBalance() (outgoingSiacoins types.Currency, incoming
 	return
 }
 
+// BalanceBreakdown returns the siacoin balance of the wallet split by how soon
+// the outputs can be spent. Miner payouts and siafund claims do not appear in
+// the wallet's outputs until they mature, so the maturing bucket is built from
+// the confirmed transaction history.
+func (w *Wallet) BalanceBreakdown() (bd modules.WalletBalanceBreakdown) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, sco := range w.siacoinOutputs {
+		if w.keys[sco.UnlockHash].UnlockConditions.Timelock > w.consensusSetHeight {
+			bd.Timelocked.Value = bd.Timelocked.Value.Add(sco.Value)
+			bd.Timelocked.Count++
+			continue
+		}
+		bd.Spendable.Value = bd.Spendable.Value.Add(sco.Value)
+		bd.Spendable.Count++
+	}
+	for _, pt := range w.processedTransactions {
+		for _, po := range pt.Outputs {
+			if po.FundType != types.SpecifierMinerPayout && po.FundType != types.SpecifierClaimOutput {
+				continue
+			}
+			if po.WalletAddress && po.MaturityHeight > w.consensusSetHeight {
+				bd.Maturing.Value = bd.Maturing.Value.Add(po.Value)
+				bd.Maturing.Count++
+			}
+		}
+	}
+	for _, upt := range w.unconfirmedProcessedTransactions {
+		for _, output := range upt.Outputs {
+			if output.FundType == types.SpecifierSiacoinOutput && output.WalletAddress {
+				bd.Unconfirmed.Value = bd.Unconfirmed.Value.Add(output.Value)
+				bd.Unconfirmed.Count++
+			}
+		}
+	}
+	return bd
+}
+
 // SendSiacoins creates a transaction sending 'amount' to 'dest'. The transaction
 // is submitted to the transaction pool and is also returned.
 func (w *Wallet) SendSiacoins(amount types.Currency, dest types.UnlockHash) ([]types.Transaction, error) {
@@ -57,19 +101,21 @@ func (w *Wallet) SendSiacoins(amount types.Currency, dest types.UnlockHash) ([]t
 		return nil, err
 	}
 	defer w.tg.Done()
+	if !w.Unlocked() {
+		return nil, modules.ErrLockedWallet
+	}
 
-	tpoolFee := types.SiacoinPrecision.Mul64(10) // TODO: better fee algo.
 	output := types.SiacoinOutput{
 		Value:      amount,
 		UnlockHash: dest,
 	}
 
 	txnBuilder := w.StartTransaction()
-	err := txnBuilder.FundSiacoins(amount.Add(tpoolFee))
+	err := txnBuilder.FundSiacoins(amount.Add(sendFee))
 	if err != nil {
 		return nil, err
 	}
-	txnBuilder.AddMinerFee(tpoolFee)
+	txnBuilder.AddMinerFee(sendFee)
 	txnBuilder.AddSiacoinOutput(output)
 	txnSet, err := txnBuilder.Sign(true)
 	if err != nil {
@@ -89,14 +135,16 @@ func (w *Wallet) SendSiafunds(amount types.Currency, dest types.UnlockHash) ([]t
 		return nil, err
 	}
 	defer w.tg.Done()
-	tpoolFee := types.SiacoinPrecision.Mul64(10) // TODO: better fee algo.
+	if !w.Unlocked() {
+		return nil, modules.ErrLockedWallet
+	}
 	output := types.SiafundOutput{
 		Value:      amount,
 		UnlockHash: dest,
 	}
 
 	txnBuilder := w.StartTransaction()
-	err := txnBuilder.FundSiacoins(tpoolFee)
+	err := txnBuilder.FundSiacoins(sendFee)
 	if err != nil {
 		return nil, err
 	}
@@ -104,7 +152,7 @@ func (w *Wallet) SendSiafunds(amount types.Currency, dest types.UnlockHash) ([]t
 	if err != nil {
 		return nil, err
 	}
-	txnBuilder.AddMinerFee(tpoolFee)
+	txnBuilder.AddMinerFee(sendFee)
 	txnBuilder.AddSiafundOutput(output)
 	txnSet, err := txnBuilder.Sign(true)
 	if err != nil {
@@ -4,10 +4,12 @@ import (
 	"crypto/rand"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/NebulousLabs/Sia/crypto"
 	"github.com/NebulousLabs/Sia/modules"
 	"github.com/NebulousLabs/Sia/persist"
+	"github.com/NebulousLabs/Sia/types"
 )
 
 const (
@@ -36,6 +38,25 @@ type SpendableKeyFile struct {
 	SpendableKey           crypto.Ciphertext
 }
 
+// TimelockedKey identifies a timelocked address generated from the primary
+// seed. The timelock is not derived from the seed, so it must be saved for the
+// address to be recognized after the wallet is unlocked again.
+type TimelockedKey struct {
+	Index    uint64
+	Timelock types.BlockHeight
+}
+
+// ScheduledSendFile is a scheduled send that has not been broadcast yet,
+// saved so that it is still broadcast after the wallet restarts. Confirmed
+// holds the inputs that were confirmed outputs of the wallet when the send was
+// scheduled.
+type ScheduledSendFile struct {
+	Transaction types.Transaction
+	Outputs     []types.SiacoinOutput
+	BroadcastAt time.Time
+	Confirmed   []types.SiacoinOutputID
+}
+
 // WalletPersist contains all data that persists on disk during wallet
 // operation.
 type WalletPersist struct {
@@ -61,6 +82,24 @@ type WalletPersist struct {
 	// UnseededKeys are list of spendable keys that were not generated by a
 	// random seed.
 	UnseededKeys []SpendableKeyFile
+
+	// TimelockedKeys are the addresses of the primary seed that were
+	// generated with a timelock.
+	TimelockedKeys []TimelockedKey
+
+	// ViewKeys are the unlock conditions of all of the wallet's keys,
+	// encrypted separately from the seeds so that the wallet can be unlocked
+	// for viewing without decrypting any secret key material.
+	ViewKeys crypto.Ciphertext
+
+	// ClaimSettings control the automatic collection of siafund claims, and
+	// LastClaimCollection records the last collection that was made.
+	ClaimSettings       modules.WalletClaimSettings
+	LastClaimCollection *modules.WalletClaimCollection
+
+	// ScheduledSends are the scheduled sends that are waiting for their
+	// broadcast time.
+	ScheduledSends []ScheduledSendFile
 }
 
 // loadSettings reads the wallet's settings from the wallet's settings file,
@@ -71,14 +110,20 @@ func (w *Wallet) loadSettings() error {
 }
 
 // saveSettings writes the wallet's settings to the wallet's settings file,
-// replacing the existing file.
+// replacing the existing file. The view keys are brought up to date first.
 func (w *Wallet) saveSettings() error {
+	if err := w.updateViewKeys(); err != nil {
+		return err
+	}
 	return persist.SaveFile(settingsMetadata, w.persist, filepath.Join(w.persistDir, settingsFile))
 }
 
 // saveSettingsSync writes the wallet's settings to the wallet's settings file,
 // replacing the existing file, and then syncs to disk.
 func (w *Wallet) saveSettingsSync() error {
+	if err := w.updateViewKeys(); err != nil {
+		return err
+	}
 	return persist.SaveFileSync(settingsMetadata, w.persist, filepath.Join(w.persistDir, settingsFile))
 }
 
@@ -0,0 +1,129 @@
+package wallet
+
+import (
+	"github.com/NebulousLabs/Sia/crypto"
+	"github.com/NebulousLabs/Sia/encoding"
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// outputConfirmationHeights returns the height at which each siacoin output
+// created by the confirmed history of the wallet was confirmed. Miner payouts
+// are included; the wallet records them under the id of their block. The
+// wallet must be locked by the caller.
+func (w *Wallet) outputConfirmationHeights() map[types.SiacoinOutputID]types.BlockHeight {
+	heights := make(map[types.SiacoinOutputID]types.BlockHeight)
+	for _, pt := range w.processedTransactions {
+		for i := range pt.Transaction.SiacoinOutputs {
+			heights[pt.Transaction.SiacoinOutputID(uint64(i))] = pt.ConfirmationHeight
+		}
+		for i, po := range pt.Outputs {
+			if po.FundType == types.SpecifierMinerPayout {
+				id := types.SiacoinOutputID(crypto.HashAll(types.BlockID(pt.TransactionID), uint64(i)))
+				heights[id] = pt.ConfirmationHeight
+			}
+		}
+	}
+	return heights
+}
+
+// placeholderSignatures returns signatures for a siacoin input that have the
+// encoded length of the signatures added by addSignatures, so that the size of
+// a transaction can be measured before it is signed.
+func placeholderSignatures(sci types.SiacoinInput) []types.TransactionSignature {
+	var sigs []types.TransactionSignature
+	for i := uint64(0); i < sci.UnlockConditions.SignaturesRequired; i++ {
+		sigs = append(sigs, types.TransactionSignature{
+			ParentID:       crypto.Hash(sci.ParentID),
+			CoveredFields:  types.FullCoveredFields,
+			PublicKeyIndex: i,
+			Signature:      make([]byte, crypto.SignatureSize),
+		})
+	}
+	return sigs
+}
+
+// sendSetSize returns the encoded size of the transaction set created by
+// SendSiacoins when it spends the given inputs. Like FundSiacoins, the set
+// consists of a parent transaction that spends the inputs into an output of
+// the exact amount and an optional change output, and the send itself, which
+// spends the exact output. Signatures are filled with placeholders of the
+// correct length.
+func sendSetSize(inputs []types.SiacoinInput, change bool) uint64 {
+	sign := func(txn *types.Transaction, sci types.SiacoinInput) {
+		txn.TransactionSignatures = append(txn.TransactionSignatures, placeholderSignatures(sci)...)
+	}
+
+	parent := types.Transaction{
+		SiacoinInputs:  inputs,
+		SiacoinOutputs: []types.SiacoinOutput{{}},
+	}
+	if change {
+		parent.SiacoinOutputs = append(parent.SiacoinOutputs, types.SiacoinOutput{})
+	}
+	for _, sci := range inputs {
+		sign(&parent, sci)
+	}
+
+	// The exact output is sent to a new address of the primary seed, which
+	// has the same unlock conditions as the other addresses of the wallet.
+	exact := types.SiacoinInput{
+		ParentID:         parent.SiacoinOutputID(0),
+		UnlockConditions: inputs[0].UnlockConditions,
+	}
+	exact.UnlockConditions.Timelock = 0
+	send := types.Transaction{
+		SiacoinInputs:  []types.SiacoinInput{exact},
+		SiacoinOutputs: []types.SiacoinOutput{{}},
+		MinerFees:      []types.Currency{sendFee},
+	}
+	sign(&send, exact)
+	return uint64(len(encoding.Marshal(parent)) + len(encoding.Marshal(send)))
+}
+
+// PreviewSiacoinSend returns the outputs that SendSiacoins would spend to send
+// 'amount', along with the fee, the change returned to the wallet, and the
+// size of the resulting transaction set. Nothing is signed, broadcast, or
+// marked as spent, so a send made afterwards selects the same outputs unless
+// the wallet changes in between.
+func (w *Wallet) PreviewSiacoinSend(amount types.Currency) (modules.WalletSendPreview, error) {
+	if err := w.tg.Add(); err != nil {
+		return modules.WalletSendPreview{}, err
+	}
+	defer w.tg.Done()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.unlocked {
+		return modules.WalletSendPreview{}, modules.ErrLockedWallet
+	}
+
+	selected, fund, err := w.selectSiacoinOutputs(amount.Add(sendFee))
+	if err != nil {
+		return modules.WalletSendPreview{}, err
+	}
+	heights := w.outputConfirmationHeights()
+	preview := modules.WalletSendPreview{
+		Amount: amount,
+		Fee:    sendFee,
+		Change: fund.Sub(amount).Sub(sendFee),
+	}
+	var inputs []types.SiacoinInput
+	for i, id := range selected.ids {
+		input := modules.WalletSendInput{
+			ID:    id,
+			Value: selected.outputs[i].Value,
+		}
+		if _, confirmed := w.siacoinOutputs[id]; !confirmed {
+			input.Unconfirmed = true
+		} else if height, ok := heights[id]; ok {
+			input.Confirmations = w.consensusSetHeight - height + 1
+		}
+		preview.Inputs = append(preview.Inputs, input)
+		inputs = append(inputs, types.SiacoinInput{
+			ParentID:         id,
+			UnlockConditions: w.keys[selected.outputs[i].UnlockHash].UnlockConditions,
+		})
+	}
+	preview.Size = sendSetSize(inputs, !preview.Change.IsZero())
+	return preview, nil
+}
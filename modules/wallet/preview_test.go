@@ -0,0 +1,55 @@
+package wallet
+
+import (
+	"testing"
+
+	"github.com/NebulousLabs/Sia/encoding"
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// TestPreviewSiacoinSend checks that a preview reports the outputs, change,
+// and size of the send that follows it.
+func TestPreviewSiacoinSend(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester("TestPreviewSiacoinSend")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	amount := types.NewCurrency64(5000)
+	preview, err := wt.wallet.PreviewSiacoinSend(amount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(preview.Inputs) != 1 || preview.Inputs[0].Unconfirmed || preview.Inputs[0].Confirmations == 0 {
+		t.Fatalf("unexpected inputs: %+v", preview.Inputs)
+	}
+	if preview.Change.Cmp(preview.Inputs[0].Value.Sub(amount).Sub(sendFee)) != 0 {
+		t.Fatal("wrong change:", preview.Change)
+	}
+
+	// The preview does not mark the outputs as spent, so the send uses them.
+	txns, err := wt.wallet.SendSiacoins(amount, types.UnlockHash{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txns) != 2 || txns[0].SiacoinInputs[0].ParentID != preview.Inputs[0].ID {
+		t.Fatal("send did not spend the previewed output")
+	}
+	var size int
+	for _, txn := range txns {
+		size += len(encoding.Marshal(txn))
+	}
+	if uint64(size) != preview.Size {
+		t.Fatalf("previewed size %v does not match send size %v", preview.Size, size)
+	}
+
+	// A send larger than the balance cannot be previewed.
+	if _, err := wt.wallet.PreviewSiacoinSend(types.SiacoinPrecision.Mul64(1e12)); err != modules.ErrLowBalance {
+		t.Fatal("expected ErrLowBalance, got", err)
+	}
+}
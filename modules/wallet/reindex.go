@@ -0,0 +1,171 @@
+package wallet
+
+import (
+	"bytes"
+
+	"github.com/NebulousLabs/Sia/crypto"
+	"github.com/NebulousLabs/Sia/encoding"
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// historicSets walks the confirmed transaction history of the wallet and
+// returns the siacoin and siafund outputs that the history says should be
+// unspent, along with the set of output ids that the history says have been
+// spent.
+//
+// Outputs created by file contracts and siafund claims are not fully recorded
+// in the transaction history, and therefore will not appear in the returned
+// sets. The spent set is complete for every output the wallet has spent.
+func (w *Wallet) historicSets() (scos map[types.SiacoinOutputID]types.SiacoinOutput, sfos map[types.SiafundOutputID]types.SiafundOutput, spent map[types.OutputID]struct{}) {
+	scos = make(map[types.SiacoinOutputID]types.SiacoinOutput)
+	sfos = make(map[types.SiafundOutputID]types.SiafundOutput)
+	spent = make(map[types.OutputID]struct{})
+	for _, pt := range w.processedTransactions {
+		// Miner payouts are stored as a processed transaction whose id is the
+		// id of the block that created them. The payouts only enter the
+		// consensus set once they have matured.
+		for i, po := range pt.Outputs {
+			if po.FundType != types.SpecifierMinerPayout || !po.WalletAddress || po.MaturityHeight > w.consensusSetHeight {
+				continue
+			}
+			id := types.SiacoinOutputID(crypto.HashAll(types.BlockID(pt.TransactionID), uint64(i)))
+			scos[id] = types.SiacoinOutput{Value: po.Value, UnlockHash: po.RelatedAddress}
+		}
+
+		txn := pt.Transaction
+		for _, sci := range txn.SiacoinInputs {
+			spent[types.OutputID(sci.ParentID)] = struct{}{}
+		}
+		for i, sco := range txn.SiacoinOutputs {
+			if _, exists := w.keys[sco.UnlockHash]; exists {
+				scos[txn.SiacoinOutputID(uint64(i))] = sco
+			}
+		}
+		for _, sfi := range txn.SiafundInputs {
+			spent[types.OutputID(sfi.ParentID)] = struct{}{}
+		}
+		for i, sfo := range txn.SiafundOutputs {
+			if _, exists := w.keys[sfo.UnlockHash]; exists {
+				sfos[txn.SiafundOutputID(uint64(i))] = sfo
+			}
+		}
+	}
+	for id := range spent {
+		delete(scos, types.SiacoinOutputID(id))
+		delete(sfos, types.SiafundOutputID(id))
+	}
+	return scos, sfos, spent
+}
+
+// Reindex rebuilds the wallet's set of unspent outputs and its transaction
+// index from the confirmed transaction history, without rescanning the
+// consensus set. Outputs that the history shows as spent are removed, unspent
+// outputs that are missing from the set are restored, and outputs whose value
+// disagrees with the history are corrected. Outputs whose origin is not
+// recorded in the history, such as file contract payouts, are left alone
+// unless the history shows them as spent.
+func (w *Wallet) Reindex() (modules.WalletReindexReport, error) {
+	if err := w.tg.Add(); err != nil {
+		return modules.WalletReindexReport{}, err
+	}
+	defer w.tg.Done()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	// Ownership of outputs is determined using the wallet's keys, which are
+	// only loaded while the wallet is unlocked.
+	if !w.unlocked {
+		return modules.WalletReindexReport{}, modules.ErrLockedWallet
+	}
+
+	var report modules.WalletReindexReport
+	for _, sco := range w.siacoinOutputs {
+		report.SiacoinBalanceBefore = report.SiacoinBalanceBefore.Add(sco.Value)
+	}
+	for _, sfo := range w.siafundOutputs {
+		report.SiafundBalanceBefore = report.SiafundBalanceBefore.Add(sfo.Value)
+	}
+
+	scos, sfos, spent := w.historicSets()
+
+	// Repair the siacoin output set.
+	for id, sco := range w.siacoinOutputs {
+		_, isSpent := spent[types.OutputID(id)]
+		_, owned := w.keys[sco.UnlockHash]
+		if isSpent || !owned {
+			delete(w.siacoinOutputs, id)
+			report.RemovedSiacoinOutputs = append(report.RemovedSiacoinOutputs, id)
+		}
+	}
+	for id, sco := range scos {
+		cached, exists := w.siacoinOutputs[id]
+		if !exists {
+			report.RestoredSiacoinOutputs = append(report.RestoredSiacoinOutputs, id)
+		} else if cached.Value.Cmp(sco.Value) != 0 || cached.UnlockHash != sco.UnlockHash {
+			report.CorrectedSiacoinOutputs = append(report.CorrectedSiacoinOutputs, id)
+		} else {
+			continue
+		}
+		w.siacoinOutputs[id] = sco
+		w.historicOutputs[types.OutputID(id)] = sco.Value
+	}
+
+	// Repair the siafund output set.
+	for id, sfo := range w.siafundOutputs {
+		_, isSpent := spent[types.OutputID(id)]
+		_, owned := w.keys[sfo.UnlockHash]
+		if isSpent || !owned {
+			delete(w.siafundOutputs, id)
+			report.RemovedSiafundOutputs = append(report.RemovedSiafundOutputs, id)
+		}
+	}
+	for id, sfo := range sfos {
+		cached, exists := w.siafundOutputs[id]
+		if !exists {
+			report.RestoredSiafundOutputs = append(report.RestoredSiafundOutputs, id)
+		} else if cached.Value.Cmp(sfo.Value) != 0 || cached.UnlockHash != sfo.UnlockHash || cached.ClaimStart.Cmp(sfo.ClaimStart) != 0 {
+			report.CorrectedSiafundOutputs = append(report.CorrectedSiafundOutputs, id)
+		} else {
+			continue
+		}
+		w.siafundOutputs[id] = sfo
+		w.historicOutputs[types.OutputID(id)] = sfo.Value
+		w.historicClaimStarts[id] = sfo.ClaimStart
+	}
+
+	// Rebuild the transaction index so that every entry points into the
+	// processed transactions slice. An entry only counts as repaired if it
+	// was missing or held a different transaction; entries may point at an
+	// equal copy of the transaction, for example once appending to the slice
+	// has moved it.
+	index := make(map[types.TransactionID]*modules.ProcessedTransaction, len(w.processedTransactions))
+	for i := range w.processedTransactions {
+		pt := &w.processedTransactions[i]
+		old, exists := w.processedTransactionMap[pt.TransactionID]
+		if !exists || !bytes.Equal(encoding.Marshal(*old), encoding.Marshal(*pt)) {
+			report.RepairedTransactionIndexEntries++
+		}
+		index[pt.TransactionID] = pt
+	}
+	for txid := range w.processedTransactionMap {
+		if _, exists := index[txid]; !exists {
+			report.RepairedTransactionIndexEntries++
+		}
+	}
+	w.processedTransactionMap = index
+
+	for _, sco := range w.siacoinOutputs {
+		report.SiacoinBalanceAfter = report.SiacoinBalanceAfter.Add(sco.Value)
+	}
+	for _, sfo := range w.siafundOutputs {
+		report.SiafundBalanceAfter = report.SiafundBalanceAfter.Add(sfo.Value)
+	}
+
+	discrepancies := len(report.RemovedSiacoinOutputs) + len(report.RestoredSiacoinOutputs) + len(report.CorrectedSiacoinOutputs) +
+		len(report.RemovedSiafundOutputs) + len(report.RestoredSiafundOutputs) + len(report.CorrectedSiafundOutputs) +
+		report.RepairedTransactionIndexEntries
+	w.log.Printf("INFO: Wallet reindex repaired %v discrepancies; siacoin balance %v -> %v, siafund balance %v -> %v",
+		discrepancies, report.SiacoinBalanceBefore, report.SiacoinBalanceAfter, report.SiafundBalanceBefore, report.SiafundBalanceAfter)
+	return report, nil
+}
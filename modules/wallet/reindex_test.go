@@ -0,0 +1,115 @@
+package wallet
+
+import (
+	"testing"
+
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// TestReindex checks that Reindex leaves a healthy wallet untouched and
+// repairs a wallet whose output set has drifted from its history.
+func TestReindex(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester("TestReindex")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	// Send coins to the void and confirm the transaction, so that the
+	// history contains spent outputs as well as a refund output.
+	_, err = wt.wallet.SendSiacoins(types.NewCurrency64(5000), types.UnlockHash{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := wt.miner.FindBlock()
+	err = wt.cs.AcceptBlock(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	balance, _, _ := wt.wallet.ConfirmedBalance()
+
+	// A healthy wallet should not report any discrepancies.
+	report, err := wt.wallet.Reindex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.RemovedSiacoinOutputs) != 0 || len(report.RestoredSiacoinOutputs) != 0 || len(report.CorrectedSiacoinOutputs) != 0 || report.RepairedTransactionIndexEntries != 0 {
+		t.Fatalf("healthy wallet reported discrepancies: %+v", report)
+	}
+	if report.SiacoinBalanceBefore.Cmp(balance) != 0 || report.SiacoinBalanceAfter.Cmp(balance) != 0 {
+		t.Fatal("reindex changed the balance of a healthy wallet")
+	}
+
+	// Corrupt the output set: drop one output, inflate another, and
+	// resurrect an output that has been spent.
+	wt.wallet.mu.Lock()
+	var dropped, inflated types.SiacoinOutputID
+	i := 0
+	for id := range wt.wallet.siacoinOutputs {
+		if i == 0 {
+			dropped = id
+		} else if i == 1 {
+			inflated = id
+		}
+		i++
+	}
+	if i < 2 {
+		wt.wallet.mu.Unlock()
+		t.Fatal("expected the wallet to have at least two outputs")
+	}
+	delete(wt.wallet.siacoinOutputs, dropped)
+	sco := wt.wallet.siacoinOutputs[inflated]
+	sco.Value = sco.Value.Add(types.SiacoinPrecision)
+	wt.wallet.siacoinOutputs[inflated] = sco
+	var resurrected types.SiacoinOutputID
+	for _, pt := range wt.wallet.processedTransactions {
+		if len(pt.Transaction.SiacoinInputs) > 0 {
+			resurrected = pt.Transaction.SiacoinInputs[0].ParentID
+			break
+		}
+	}
+	wt.wallet.siacoinOutputs[resurrected] = types.SiacoinOutput{
+		Value:      types.SiacoinPrecision,
+		UnlockHash: sco.UnlockHash,
+	}
+	delete(wt.wallet.processedTransactionMap, wt.wallet.processedTransactions[0].TransactionID)
+	// An entry pointing at an equal copy of its transaction is not repaired.
+	copied := wt.wallet.processedTransactions[1]
+	wt.wallet.processedTransactionMap[copied.TransactionID] = &copied
+	wt.wallet.mu.Unlock()
+
+	report, err = wt.wallet.Reindex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.RestoredSiacoinOutputs) != 1 || report.RestoredSiacoinOutputs[0] != dropped {
+		t.Error("dropped output was not restored:", report.RestoredSiacoinOutputs)
+	}
+	if len(report.CorrectedSiacoinOutputs) != 1 || report.CorrectedSiacoinOutputs[0] != inflated {
+		t.Error("inflated output was not corrected:", report.CorrectedSiacoinOutputs)
+	}
+	if len(report.RemovedSiacoinOutputs) != 1 || report.RemovedSiacoinOutputs[0] != resurrected {
+		t.Error("spent output was not removed:", report.RemovedSiacoinOutputs)
+	}
+	if report.RepairedTransactionIndexEntries != 1 {
+		t.Error("expected one repaired index entry, got", report.RepairedTransactionIndexEntries)
+	}
+	if report.SiacoinBalanceAfter.Cmp(balance) != 0 {
+		t.Error("reindex did not restore the original balance")
+	}
+	newBalance, _, _ := wt.wallet.ConfirmedBalance()
+	if newBalance.Cmp(balance) != 0 {
+		t.Error("confirmed balance does not match the original balance")
+	}
+
+	// Reindexing requires the keys of an unlocked wallet.
+	if err := wt.wallet.Lock(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wt.wallet.Reindex(); err == nil {
+		t.Error("expected an error when reindexing a locked wallet")
+	}
+}
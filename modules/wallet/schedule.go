@@ -0,0 +1,359 @@
+package wallet
+
+import (
+	"errors"
+	"time"
+
+	"github.com/NebulousLabs/Sia/crypto"
+	"github.com/NebulousLabs/Sia/encoding"
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+const (
+	// scheduledSendRetention is how long a send that has been broadcast,
+	// cancelled or has failed is still reported by ScheduledSends.
+	scheduledSendRetention = 24 * time.Hour
+)
+
+var (
+	errNoScheduledOutputs      = errors.New("a scheduled send needs at least one output with a nonzero value")
+	errScheduleInPast          = errors.New("broadcast time must be in the future")
+	errScheduledSendTooLarge   = errors.New("send is too large to fit in a single transaction")
+	errScheduledSendNotFound   = errors.New("no scheduled send has that transaction id")
+	errScheduledSendNotPending = errors.New("send has already been broadcast, cancelled or failed")
+	errScheduledInputsSpent    = errors.New("inputs of the send were spent by another transaction")
+)
+
+// A scheduledSend is a signed transaction that is held by the wallet until its
+// broadcast time. The outputs that it spends stay in spentOutputs, refreshed
+// every block, so that other sends do not use them in the meantime.
+type scheduledSend struct {
+	modules.WalletScheduledSend
+
+	txn types.Transaction
+
+	// confirmed holds the inputs that were confirmed outputs of the wallet
+	// when the send was scheduled. If one of them leaves the wallet's set of
+	// outputs before the broadcast, it was spent by another transaction.
+	confirmed []types.SiacoinOutputID
+
+	// submitting is set while the transaction is being given to the
+	// transaction pool, after which the send can no longer be cancelled.
+	// cancel is closed when the send stops waiting for its broadcast time.
+	// finished is when the send stopped being pending.
+	submitting bool
+	cancel     chan struct{}
+	finished   time.Time
+}
+
+// buildScheduledSend creates and signs a transaction paying 'outputs' and the
+// send fee from the wallet's outputs, and marks the outputs that it spends as
+// spent. The wallet must be locked by the caller.
+func (w *Wallet) buildScheduledSend(outputs []types.SiacoinOutput) (types.Transaction, error) {
+	amount := sendFee
+	for _, sco := range outputs {
+		amount = amount.Add(sco.Value)
+	}
+	selected, fund, err := w.selectSiacoinOutputs(amount)
+	if err != nil {
+		return types.Transaction{}, err
+	}
+
+	txn := types.Transaction{
+		SiacoinOutputs: append([]types.SiacoinOutput(nil), outputs...),
+		MinerFees:      []types.Currency{sendFee},
+	}
+	if fund.Cmp(amount) > 0 {
+		uc, err := w.nextPrimarySeedAddress()
+		if err != nil {
+			return types.Transaction{}, err
+		}
+		txn.SiacoinOutputs = append(txn.SiacoinOutputs, types.SiacoinOutput{
+			Value:      fund.Sub(amount),
+			UnlockHash: uc.UnlockHash(),
+		})
+	}
+	for i, id := range selected.ids {
+		txn.SiacoinInputs = append(txn.SiacoinInputs, types.SiacoinInput{
+			ParentID:         id,
+			UnlockConditions: w.keys[selected.outputs[i].UnlockHash].UnlockConditions,
+		})
+	}
+	for _, sci := range txn.SiacoinInputs {
+		_, err := addSignatures(&txn, types.FullCoveredFields, sci.UnlockConditions, crypto.Hash(sci.ParentID), w.keys[sci.UnlockConditions.UnlockHash()])
+		if err != nil {
+			return types.Transaction{}, err
+		}
+	}
+	if len(encoding.Marshal(txn)) > modules.TransactionSizeLimit {
+		return types.Transaction{}, errScheduledSendTooLarge
+	}
+	for _, sci := range txn.SiacoinInputs {
+		w.spentOutputs[types.OutputID(sci.ParentID)] = w.consensusSetHeight
+	}
+	return txn, nil
+}
+
+// ScheduleSend creates and signs a transaction paying 'outputs', which is
+// broadcast once 'at' has passed. The send fee is added to the transaction.
+// The send is saved, and resumes once the wallet is unlocked after a restart.
+func (w *Wallet) ScheduleSend(outputs []types.SiacoinOutput, at time.Time) (modules.WalletScheduledSend, error) {
+	if err := w.tg.Add(); err != nil {
+		return modules.WalletScheduledSend{}, err
+	}
+	defer w.tg.Done()
+	if !w.Unlocked() {
+		return modules.WalletScheduledSend{}, modules.ErrLockedWallet
+	}
+	if len(outputs) == 0 {
+		return modules.WalletScheduledSend{}, errNoScheduledOutputs
+	}
+	for _, sco := range outputs {
+		if sco.Value.IsZero() {
+			return modules.WalletScheduledSend{}, errNoScheduledOutputs
+		}
+	}
+	if !at.After(time.Now()) {
+		return modules.WalletScheduledSend{}, errScheduleInPast
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	txn, err := w.buildScheduledSend(outputs)
+	if err != nil {
+		return modules.WalletScheduledSend{}, err
+	}
+	ss := &scheduledSend{
+		WalletScheduledSend: modules.WalletScheduledSend{
+			TransactionID: txn.ID(),
+			Outputs:       append([]types.SiacoinOutput(nil), outputs...),
+			Fee:           sendFee,
+			BroadcastAt:   at,
+			Status:        "scheduled",
+		},
+		txn:    txn,
+		cancel: make(chan struct{}),
+	}
+	for _, sci := range txn.SiacoinInputs {
+		if _, exists := w.siacoinOutputs[sci.ParentID]; exists {
+			ss.confirmed = append(ss.confirmed, sci.ParentID)
+		}
+	}
+	w.pruneScheduledSends()
+	w.scheduledSends = append(w.scheduledSends, ss)
+	if err := w.saveScheduledSends(); err != nil {
+		w.scheduledSends = w.scheduledSends[:len(w.scheduledSends)-1]
+		for _, sci := range txn.SiacoinInputs {
+			delete(w.spentOutputs, types.OutputID(sci.ParentID))
+		}
+		return modules.WalletScheduledSend{}, err
+	}
+	go w.threadedBroadcastScheduledSend(ss)
+	return ss.WalletScheduledSend, nil
+}
+
+// ScheduledSends returns the sends that are pending or finished within
+// scheduledSendRetention, oldest first.
+func (w *Wallet) ScheduledSends() []modules.WalletScheduledSend {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	sends := make([]modules.WalletScheduledSend, 0, len(w.scheduledSends))
+	for _, ss := range w.scheduledSends {
+		if ss.Status != "scheduled" && time.Since(ss.finished) >= scheduledSendRetention {
+			continue
+		}
+		wss := ss.WalletScheduledSend
+		wss.Outputs = append([]types.SiacoinOutput(nil), ss.Outputs...)
+		sends = append(sends, wss)
+	}
+	return sends
+}
+
+// CancelScheduledSend cancels the scheduled send with the given transaction
+// id, releasing the outputs that it would have spent.
+func (w *Wallet) CancelScheduledSend(id types.TransactionID) error {
+	if err := w.tg.Add(); err != nil {
+		return err
+	}
+	defer w.tg.Done()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, ss := range w.scheduledSends {
+		if ss.TransactionID != id {
+			continue
+		}
+		if ss.Status != "scheduled" || ss.submitting {
+			return errScheduledSendNotPending
+		}
+		w.stopScheduledSend(ss, "cancelled", "")
+		return nil
+	}
+	return errScheduledSendNotFound
+}
+
+// stopScheduledSend ends a send that is waiting for its broadcast time and
+// releases its inputs. The wallet must be locked by the caller.
+func (w *Wallet) stopScheduledSend(ss *scheduledSend, status, errMsg string) {
+	for _, sci := range ss.txn.SiacoinInputs {
+		delete(w.spentOutputs, types.OutputID(sci.ParentID))
+	}
+	close(ss.cancel)
+	w.finishScheduledSend(ss, status, errMsg)
+}
+
+// finishScheduledSend records that ss is no longer pending, and removes it
+// from the saved sends. The wallet must be locked by the caller.
+func (w *Wallet) finishScheduledSend(ss *scheduledSend, status, errMsg string) {
+	ss.Status = status
+	ss.Error = errMsg
+	ss.finished = time.Now()
+	if err := w.saveScheduledSends(); err != nil {
+		w.log.Println("WARN: could not save the scheduled sends:", err)
+	}
+}
+
+// saveScheduledSends saves the sends that are waiting for their broadcast
+// time. The wallet must be locked by the caller.
+func (w *Wallet) saveScheduledSends() error {
+	w.persist.ScheduledSends = nil
+	for _, ss := range w.scheduledSends {
+		if ss.Status != "scheduled" {
+			continue
+		}
+		w.persist.ScheduledSends = append(w.persist.ScheduledSends, ScheduledSendFile{
+			Transaction: ss.txn,
+			Outputs:     ss.Outputs,
+			BroadcastAt: ss.BroadcastAt,
+			Confirmed:   ss.confirmed,
+		})
+	}
+	return w.saveSettingsSync()
+}
+
+// pruneScheduledSends forgets the sends that finished more than
+// scheduledSendRetention ago. The wallet must be locked by the caller.
+func (w *Wallet) pruneScheduledSends() {
+	var kept []*scheduledSend
+	for _, ss := range w.scheduledSends {
+		if ss.Status == "scheduled" || time.Since(ss.finished) < scheduledSendRetention {
+			kept = append(kept, ss)
+		}
+	}
+	w.scheduledSends = kept
+}
+
+// restoreScheduledSends resumes the saved sends that are not already pending.
+// It is called once the wallet is unlocked and has caught up with the
+// consensus set, so that a send whose inputs were spent while the wallet was
+// not running fails. A send whose broadcast time passed in the meantime is
+// broadcast straight away. The wallet must be locked by the caller.
+func (w *Wallet) restoreScheduledSends() {
+	pending := make(map[types.TransactionID]struct{})
+	for _, ss := range w.scheduledSends {
+		pending[ss.TransactionID] = struct{}{}
+	}
+	var restored []*scheduledSend
+	for _, ssf := range w.persist.ScheduledSends {
+		if _, exists := pending[ssf.Transaction.ID()]; exists {
+			continue
+		}
+		ss := &scheduledSend{
+			WalletScheduledSend: modules.WalletScheduledSend{
+				TransactionID: ssf.Transaction.ID(),
+				Outputs:       ssf.Outputs,
+				BroadcastAt:   ssf.BroadcastAt,
+				Status:        "scheduled",
+			},
+			txn:       ssf.Transaction,
+			confirmed: ssf.Confirmed,
+			cancel:    make(chan struct{}),
+		}
+		if len(ssf.Transaction.MinerFees) > 0 {
+			ss.Fee = ssf.Transaction.MinerFees[0]
+		}
+		w.scheduledSends = append(w.scheduledSends, ss)
+		restored = append(restored, ss)
+	}
+	// Reserve the inputs of the restored sends, and fail those whose inputs
+	// are gone.
+	w.updateScheduledSends()
+	for _, ss := range restored {
+		if ss.Status == "scheduled" {
+			go w.threadedBroadcastScheduledSend(ss)
+		}
+	}
+}
+
+// updateScheduledSends keeps the inputs of the scheduled sends reserved, as
+// entries in spentOutputs otherwise expire after RespendTimeout blocks. A
+// send fails if one of its confirmed inputs is no longer an output of the
+// wallet, which happens when the input is spent by a transaction made
+// elsewhere with the same seed.
+func (w *Wallet) updateScheduledSends() {
+	w.pruneScheduledSends()
+	for _, ss := range w.scheduledSends {
+		if ss.Status != "scheduled" || ss.submitting {
+			continue
+		}
+		spent := false
+		for _, id := range ss.confirmed {
+			if _, exists := w.siacoinOutputs[id]; !exists {
+				spent = true
+				break
+			}
+		}
+		if spent {
+			w.stopScheduledSend(ss, "failed", errScheduledInputsSpent.Error())
+			continue
+		}
+		for _, sci := range ss.txn.SiacoinInputs {
+			w.spentOutputs[types.OutputID(sci.ParentID)] = w.consensusSetHeight
+		}
+	}
+}
+
+// threadedBroadcastScheduledSend waits until the broadcast time of ss and then
+// submits its transaction to the transaction pool. The wait ends early if the
+// send is cancelled or the wallet shuts down.
+func (w *Wallet) threadedBroadcastScheduledSend(ss *scheduledSend) {
+	if err := w.tg.Add(); err != nil {
+		return
+	}
+	defer w.tg.Done()
+
+	timer := time.NewTimer(ss.BroadcastAt.Sub(time.Now()))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ss.cancel:
+		return
+	case <-w.tg.StopChan():
+		return
+	}
+
+	w.mu.Lock()
+	if ss.Status != "scheduled" {
+		w.mu.Unlock()
+		return
+	}
+	ss.submitting = true
+	w.mu.Unlock()
+
+	// The transaction pool calls back into the wallet, so the transaction is
+	// submitted without holding the lock.
+	err := w.tpool.AcceptTransactionSet([]types.Transaction{ss.txn})
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	ss.submitting = false
+	if err != nil && err != modules.ErrDuplicateTransactionSet {
+		// A conflicting transaction in the pool or the blockchain means that
+		// the inputs were spent elsewhere.
+		w.stopScheduledSend(ss, "failed", err.Error())
+		return
+	}
+	w.finishScheduledSend(ss, "broadcast", "")
+}
@@ -0,0 +1,194 @@
+package wallet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// TestScheduleSend checks that a scheduled send keeps its inputs reserved
+// until it is broadcast, and that it is broadcast once its time has passed.
+func TestScheduleSend(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester("TestScheduleSend")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	outputs := []types.SiacoinOutput{
+		{Value: types.NewCurrency64(5000), UnlockHash: types.UnlockHash{1}},
+		{Value: types.NewCurrency64(7000), UnlockHash: types.UnlockHash{2}},
+	}
+	if _, err := wt.wallet.ScheduleSend(outputs, time.Now().Add(-time.Second)); err != errScheduleInPast {
+		t.Fatal("expected errScheduleInPast, got", err)
+	}
+	if _, err := wt.wallet.ScheduleSend(nil, time.Now().Add(time.Hour)); err != errNoScheduledOutputs {
+		t.Fatal("expected errNoScheduledOutputs, got", err)
+	}
+
+	ss, err := wt.wallet.ScheduleSend(outputs, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ss.Status != "scheduled" || len(ss.Outputs) != 2 || ss.Fee.Cmp(sendFee) != 0 {
+		t.Fatal("send was not scheduled correctly:", ss)
+	}
+	if len(wt.tpool.TransactionList()) != 0 {
+		t.Fatal("scheduled send was broadcast early")
+	}
+
+	// The inputs stay reserved after the usual respend timeout.
+	for i := types.BlockHeight(0); i <= RespendTimeout; i++ {
+		if err := wt.addEmptyBlock(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wt.wallet.mu.Lock()
+	txn := wt.wallet.scheduledSends[0].txn
+	for _, sci := range txn.SiacoinInputs {
+		if wt.wallet.spentOutputs[types.OutputID(sci.ParentID)] != wt.wallet.consensusSetHeight {
+			t.Error("input of the scheduled send is no longer reserved")
+		}
+	}
+	wt.wallet.mu.Unlock()
+
+	// Cancelling the send releases its inputs.
+	if err := wt.wallet.CancelScheduledSend(ss.TransactionID); err != nil {
+		t.Fatal(err)
+	}
+	if err := wt.wallet.CancelScheduledSend(ss.TransactionID); err != errScheduledSendNotPending {
+		t.Fatal("expected errScheduledSendNotPending, got", err)
+	}
+	if err := wt.wallet.CancelScheduledSend(types.TransactionID{}); err != errScheduledSendNotFound {
+		t.Fatal("expected errScheduledSendNotFound, got", err)
+	}
+	wt.wallet.mu.Lock()
+	for _, sci := range txn.SiacoinInputs {
+		if _, exists := wt.wallet.spentOutputs[types.OutputID(sci.ParentID)]; exists {
+			t.Error("input of the cancelled send is still reserved")
+		}
+	}
+	wt.wallet.mu.Unlock()
+
+	// A send is broadcast once its time has passed.
+	ss, err = wt.wallet.ScheduleSend(outputs, time.Now().Add(100*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sends []types.TransactionID
+	for i := 0; i < 50; i++ {
+		sends = nil
+		for _, txn := range wt.tpool.TransactionList() {
+			sends = append(sends, txn.ID())
+		}
+		if len(sends) > 0 {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if len(sends) != 1 || sends[0] != ss.TransactionID {
+		t.Fatal("scheduled send was not broadcast:", sends)
+	}
+	if ss := wt.wallet.ScheduledSends(); len(ss) != 2 || ss[0].Status != "cancelled" || ss[1].Status != "broadcast" {
+		t.Fatal("wrong statuses of the scheduled sends:", ss)
+	}
+}
+
+// TestScheduleSendInputsSpent checks that a scheduled send fails if one of its
+// inputs is spent by a transaction that the wallet did not make.
+func TestScheduleSendInputsSpent(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester("TestScheduleSendInputsSpent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	outputs := []types.SiacoinOutput{{Value: types.NewCurrency64(5000), UnlockHash: types.UnlockHash{}}}
+	ss, err := wt.wallet.ScheduleSend(outputs, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Remove an input from the wallet's outputs as a confirmed spend made
+	// elsewhere would.
+	wt.wallet.mu.Lock()
+	input := wt.wallet.scheduledSends[0].confirmed[0]
+	delete(wt.wallet.siacoinOutputs, input)
+	wt.wallet.updateScheduledSends()
+	wt.wallet.mu.Unlock()
+
+	sends := wt.wallet.ScheduledSends()
+	if len(sends) != 1 || sends[0].TransactionID != ss.TransactionID || sends[0].Status != "failed" || sends[0].Error != errScheduledInputsSpent.Error() {
+		t.Fatal("send did not fail:", sends)
+	}
+	if err := wt.wallet.CancelScheduledSend(ss.TransactionID); err != errScheduledSendNotPending {
+		t.Fatal("expected errScheduledSendNotPending, got", err)
+	}
+}
+
+// TestScheduleSendPersist checks that a pending send is saved, resumes when the
+// wallet is unlocked after a restart, and that finished sends are forgotten
+// after scheduledSendRetention.
+func TestScheduleSendPersist(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester("TestScheduleSendPersist")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	outputs := []types.SiacoinOutput{{Value: types.NewCurrency64(5000), UnlockHash: types.UnlockHash{1}}}
+	ss, err := wt.wallet.ScheduleSend(outputs, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Load the wallet again from its persist directory.
+	w, err := New(wt.cs, wt.tpool, wt.wallet.persistDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if len(w.ScheduledSends()) != 0 {
+		t.Fatal("scheduled send was resumed before the wallet was unlocked")
+	}
+	if err := w.Unlock(wt.walletMasterKey); err != nil {
+		t.Fatal(err)
+	}
+	sends := w.ScheduledSends()
+	if len(sends) != 1 || sends[0].TransactionID != ss.TransactionID || sends[0].Status != "scheduled" || sends[0].Fee.Cmp(sendFee) != 0 {
+		t.Fatal("scheduled send was not restored:", sends)
+	}
+	w.mu.Lock()
+	for _, sci := range w.scheduledSends[0].txn.SiacoinInputs {
+		if _, exists := w.spentOutputs[types.OutputID(sci.ParentID)]; !exists {
+			t.Error("input of the restored send is not reserved")
+		}
+	}
+	w.mu.Unlock()
+
+	// A cancelled send is no longer saved, and is forgotten once it has
+	// been finished for scheduledSendRetention.
+	if err := w.CancelScheduledSend(ss.TransactionID); err != nil {
+		t.Fatal(err)
+	}
+	w.mu.Lock()
+	if len(w.persist.ScheduledSends) != 0 {
+		t.Error("cancelled send is still saved")
+	}
+	w.scheduledSends[0].finished = time.Now().Add(-scheduledSendRetention)
+	w.pruneScheduledSends()
+	if len(w.scheduledSends) != 0 {
+		t.Error("finished send was not pruned")
+	}
+	w.mu.Unlock()
+}
@@ -166,6 +166,7 @@ func (w *Wallet) createSeed(masterKey crypto.TwofishKey, seed modules.Seed) erro
 	w.primarySeed = seed
 	w.persist.PrimarySeedFile = seedFile
 	w.persist.PrimarySeedProgress = 0
+	w.persist.TimelockedKeys = nil
 	// The wallet preloads keys to prevent confusion for people using the same
 	// seed/wallet file in multiple places.
 	for i := uint64(0); i < modules.WalletSeedPreloadDepth; i++ {
@@ -260,6 +261,34 @@ func (w *Wallet) NextAddress() (types.UnlockConditions, error) {
 	return w.nextPrimarySeedAddress()
 }
 
+// NextAddresses returns n unlock hashes that are ready to receive siacoins or
+// siafunds, generated using the primary address seed. The seed progress is
+// advanced and saved once for the whole batch.
+func (w *Wallet) NextAddresses(n uint64) ([]types.UnlockConditions, error) {
+	if err := w.tg.Add(); err != nil {
+		return nil, err
+	}
+	defer w.tg.Done()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.unlocked {
+		return nil, modules.ErrLockedWallet
+	}
+	ucs := make([]types.UnlockConditions, 0, n)
+	for i := uint64(0); i < n; i++ {
+		spendableKey := generateSpendableKey(w.primarySeed, w.persist.PrimarySeedProgress+modules.WalletSeedPreloadDepth+i)
+		w.keys[spendableKey.UnlockConditions.UnlockHash()] = spendableKey
+		ucs = append(ucs, spendableKey.UnlockConditions)
+	}
+	w.persist.PrimarySeedProgress += n
+	err := w.saveSettingsSync()
+	if err != nil {
+		return nil, err
+	}
+	return ucs, nil
+}
+
 // LoadSeed will track all of the addresses generated by the input seed,
 // reclaiming any funds that were lost due to a deleted file or lost encryption
 // key. An error will be returned if the seed has already been integrated with
@@ -277,3 +306,54 @@ func (w *Wallet) LoadSeed(masterKey crypto.TwofishKey, seed modules.Seed) error
 	}
 	return w.recoverSeed(masterKey, seed)
 }
+
+// VerifySeed derives the first n addresses of seed and reports how many of
+// them the wallet tracks. The wallet is not modified; a seed that the wallet
+// does not know about is reported as a mismatch rather than loaded.
+//
+// The wallet only tracks the primary seed's addresses up to its progress plus
+// WalletSeedPreloadDepth, so n is capped at that number, and that number is
+// checked if n is zero.
+func (w *Wallet) VerifySeed(seed modules.Seed, n uint64) (modules.WalletSeedVerification, error) {
+	if err := w.tg.Add(); err != nil {
+		return modules.WalletSeedVerification{}, err
+	}
+	defer w.tg.Done()
+
+	w.mu.RLock()
+	if !w.unlocked {
+		w.mu.RUnlock()
+		return modules.WalletSeedVerification{}, modules.ErrLockedWallet
+	}
+	loaded := w.persist.PrimarySeedProgress + modules.WalletSeedPreloadDepth
+	w.mu.RUnlock()
+	if n == 0 || n > loaded {
+		n = loaded
+	}
+
+	// Derive the addresses without holding the lock, as key generation is
+	// slow.
+	addrs := make([]types.UnlockHash, n)
+	for i := range addrs {
+		addrs[i] = generateSpendableKey(seed, uint64(i)).UnlockConditions.UnlockHash()
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if !w.unlocked {
+		return modules.WalletSeedVerification{}, modules.ErrLockedWallet
+	}
+	v := modules.WalletSeedVerification{
+		Checked: n,
+		Primary: seed == w.primarySeed,
+	}
+	for _, addr := range addrs {
+		if _, exists := w.keys[addr]; exists {
+			v.Matched++
+		} else {
+			v.MissingAddresses = append(v.MissingAddresses, addr)
+		}
+	}
+	v.Match = v.Matched == v.Checked
+	return v, nil
+}
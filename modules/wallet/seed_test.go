@@ -173,3 +173,145 @@ func TestLoadSeed(t *testing.T) {
 		t.Error("AllSeeds returned the wrong seed")
 	}
 }
+
+// TestVerifySeed checks that VerifySeed recognizes the primary seed and
+// rejects an unrelated seed.
+func TestVerifySeed(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester("TestVerifySeed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	primarySeed, _, err := wt.wallet.PrimarySeed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := wt.wallet.VerifySeed(primarySeed, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !v.Match || !v.Primary || v.Checked != 10 || v.Matched != 10 || len(v.MissingAddresses) != 0 {
+		t.Fatalf("primary seed did not verify: %+v", v)
+	}
+
+	// Only the addresses that the wallet has generated are checked.
+	_, progress, err := wt.wallet.PrimarySeed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded := progress + modules.WalletSeedPreloadDepth
+	for _, n := range []uint64{0, loaded * 10} {
+		v, err = wt.wallet.VerifySeed(primarySeed, n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !v.Match || v.Checked != loaded || v.Matched != loaded {
+			t.Fatalf("unexpected verification of %v addresses: %+v", n, v)
+		}
+	}
+
+	var other modules.Seed
+	other[0] = 1
+	v, err = wt.wallet.VerifySeed(other, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Match || v.Primary || v.Matched != 0 {
+		t.Fatalf("unrelated seed verified: %+v", v)
+	}
+
+	// The wallet must be unlocked.
+	wt.wallet.Lock()
+	if _, err := wt.wallet.VerifySeed(primarySeed, 10); err != modules.ErrLockedWallet {
+		t.Fatal("expected ErrLockedWallet, got", err)
+	}
+}
+
+// TestVerifySeedNewWallet checks that the primary seed of a wallet that has
+// not generated any addresses yet verifies with the default number of
+// addresses.
+func TestVerifySeedNewWallet(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createBlankWalletTester("TestVerifySeedNewWallet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+	seed, err := wt.wallet.Encrypt(crypto.TwofishKey{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := wt.wallet.Unlock(crypto.TwofishKey(crypto.HashObject(seed))); err != nil {
+		t.Fatal(err)
+	}
+
+	primarySeed, progress, err := wt.wallet.PrimarySeed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if progress != 0 {
+		t.Fatal("new wallet has seed progress", progress)
+	}
+	v, err := wt.wallet.VerifySeed(primarySeed, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !v.Match || v.Checked != modules.WalletSeedPreloadDepth {
+		t.Fatalf("primary seed of a new wallet did not verify: %+v", v)
+	}
+}
+
+// TestNextAddresses checks that a batch of addresses advances the primary seed
+// progress by the size of the batch, and that the addresses are the ones
+// NextAddress would have returned.
+func TestNextAddresses(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester("TestNextAddresses")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	_, progress, err := wt.wallet.PrimarySeed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ucs, err := wt.wallet.NextAddresses(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ucs) != 5 {
+		t.Fatal("expected 5 addresses, got", len(ucs))
+	}
+	_, newProgress, err := wt.wallet.PrimarySeed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newProgress != progress+5 {
+		t.Fatal("seed progress was not advanced by the batch:", progress, newProgress)
+	}
+	seen := make(map[types.UnlockHash]struct{})
+	for _, uc := range ucs {
+		seen[uc.UnlockHash()] = struct{}{}
+	}
+	if len(seen) != 5 {
+		t.Fatal("batch contains duplicate addresses")
+	}
+
+	// The next single address follows the batch.
+	uc, err := wt.wallet.NextAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := seen[uc.UnlockHash()]; ok {
+		t.Fatal("NextAddress returned an address from the batch")
+	}
+}
@@ -0,0 +1,194 @@
+package wallet
+
+import (
+	"errors"
+	"math"
+
+	"github.com/NebulousLabs/Sia/crypto"
+	"github.com/NebulousLabs/Sia/encoding"
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// maxSplitSendParts is the largest number of transactions that a single send
+// is split into.
+const maxSplitSendParts = 100
+
+var errSplitSendTooLarge = errors.New("send would need to be split into too many transactions")
+
+// splitOutputs divides a set of outputs into groups, keeping their order, such
+// that a transaction spending a group into a payment, a change output, and
+// the send fee stays within modules.TransactionSizeLimit once signed. The
+// wallet must be locked by the caller.
+func (w *Wallet) splitOutputs(so sortedOutputs) []sortedOutputs {
+	// The outputs are given the largest value that the transaction could
+	// need, since currencies are encoded with a variable length.
+	maxValue := types.NewCurrency64(math.MaxUint64).Mul64(math.MaxUint64)
+	base := types.Transaction{
+		SiacoinOutputs: []types.SiacoinOutput{{Value: maxValue}, {Value: maxValue}},
+		MinerFees:      []types.Currency{sendFee},
+	}
+	baseSize := len(encoding.Marshal(base))
+
+	var groups []sortedOutputs
+	var group sortedOutputs
+	size := baseSize
+	for i, id := range so.ids {
+		sci := types.SiacoinInput{
+			ParentID:         id,
+			UnlockConditions: w.keys[so.outputs[i].UnlockHash].UnlockConditions,
+		}
+		inputSize := len(encoding.Marshal(sci))
+		for _, sig := range placeholderSignatures(sci) {
+			inputSize += len(encoding.Marshal(sig))
+		}
+		if len(group.ids) > 0 && size+inputSize > modules.TransactionSizeLimit {
+			groups = append(groups, group)
+			group = sortedOutputs{}
+			size = baseSize
+		}
+		group.ids = append(group.ids, id)
+		group.outputs = append(group.outputs, so.outputs[i])
+		size += inputSize
+	}
+	if len(group.ids) > 0 {
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// buildSplitSend creates transactions that together send 'amount' to 'dest',
+// each spending a group of outputs from splitOutputs and paying the send fee.
+// Enough outputs are selected to pay the fee of every transaction. The spent
+// outputs are marked as spent. The wallet must be locked by the caller.
+func (w *Wallet) buildSplitSend(amount types.Currency, dest types.UnlockHash) ([]types.Transaction, error) {
+	var groups []sortedOutputs
+	for parts := 2; ; {
+		selected, _, err := w.selectSiacoinOutputs(amount.Add(sendFee.Mul64(uint64(parts))))
+		if err != nil {
+			return nil, err
+		}
+		groups = w.splitOutputs(selected)
+		if len(groups) <= parts {
+			break
+		} else if len(groups) > maxSplitSendParts {
+			return nil, errSplitSendTooLarge
+		}
+		parts = len(groups)
+	}
+
+	// Groups are filled with the largest outputs first, so every group but
+	// the last is spent entirely on the payment.
+	remaining := amount
+	var txns []types.Transaction
+	for _, group := range groups {
+		if remaining.IsZero() {
+			break
+		}
+		var value types.Currency
+		for _, sco := range group.outputs {
+			value = value.Add(sco.Value)
+		}
+		if value.Cmp(sendFee) <= 0 {
+			continue
+		}
+		pay := value.Sub(sendFee)
+		var change types.Currency
+		if pay.Cmp(remaining) > 0 {
+			change = pay.Sub(remaining)
+			pay = remaining
+		}
+		txn := types.Transaction{
+			SiacoinOutputs: []types.SiacoinOutput{{Value: pay, UnlockHash: dest}},
+			MinerFees:      []types.Currency{sendFee},
+		}
+		if !change.IsZero() {
+			uc, err := w.nextPrimarySeedAddress()
+			if err != nil {
+				return nil, err
+			}
+			txn.SiacoinOutputs = append(txn.SiacoinOutputs, types.SiacoinOutput{
+				Value:      change,
+				UnlockHash: uc.UnlockHash(),
+			})
+		}
+		for i, id := range group.ids {
+			txn.SiacoinInputs = append(txn.SiacoinInputs, types.SiacoinInput{
+				ParentID:         id,
+				UnlockConditions: w.keys[group.outputs[i].UnlockHash].UnlockConditions,
+			})
+		}
+		for _, sci := range txn.SiacoinInputs {
+			_, err := addSignatures(&txn, types.FullCoveredFields, sci.UnlockConditions, crypto.Hash(sci.ParentID), w.keys[sci.UnlockConditions.UnlockHash()])
+			if err != nil {
+				return nil, err
+			}
+		}
+		remaining = remaining.Sub(pay)
+		txns = append(txns, txn)
+	}
+	if !remaining.IsZero() {
+		return nil, modules.ErrLowBalance
+	}
+	for _, txn := range txns {
+		for _, sci := range txn.SiacoinInputs {
+			w.spentOutputs[types.OutputID(sci.ParentID)] = w.consensusSetHeight
+		}
+	}
+	return txns, nil
+}
+
+// SendSiacoinsSplit sends 'amount' to 'dest' like SendSiacoins, unless the
+// outputs needed to fund the send would make its transaction larger than
+// modules.TransactionSizeLimit. Such a send is split into several
+// transactions, each spending some of the outputs and paying part of the
+// amount to 'dest', along with its own fee. Each transaction is submitted to
+// the transaction pool as a separate set. If submitting a transaction fails,
+// the sets that were already submitted are returned along with the error.
+func (w *Wallet) SendSiacoinsSplit(amount types.Currency, dest types.UnlockHash) ([][]types.Transaction, error) {
+	if err := w.tg.Add(); err != nil {
+		return nil, err
+	}
+	defer w.tg.Done()
+	if !w.Unlocked() {
+		return nil, modules.ErrLockedWallet
+	}
+
+	w.mu.Lock()
+	selected, _, err := w.selectSiacoinOutputs(amount.Add(sendFee))
+	if err != nil {
+		w.mu.Unlock()
+		return nil, err
+	}
+	if len(w.splitOutputs(selected)) <= 1 {
+		w.mu.Unlock()
+		txnSet, err := w.SendSiacoins(amount, dest)
+		if err != nil {
+			return nil, err
+		}
+		return [][]types.Transaction{txnSet}, nil
+	}
+	txns, err := w.buildSplitSend(amount, dest)
+	w.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	// The transaction pool calls back into the wallet, so the transactions
+	// are submitted without holding the lock.
+	var sets [][]types.Transaction
+	for i, txn := range txns {
+		if err := w.tpool.AcceptTransactionSet([]types.Transaction{txn}); err != nil {
+			w.mu.Lock()
+			for _, unsent := range txns[i:] {
+				for _, sci := range unsent.SiacoinInputs {
+					delete(w.spentOutputs, types.OutputID(sci.ParentID))
+				}
+			}
+			w.mu.Unlock()
+			return sets, err
+		}
+		sets = append(sets, []types.Transaction{txn})
+	}
+	return sets, nil
+}
@@ -0,0 +1,87 @@
+package wallet
+
+import (
+	"testing"
+
+	"github.com/NebulousLabs/Sia/encoding"
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// TestBuildSplitSend checks that a send funded by many small outputs is split
+// into transactions that each fit within the size limit and that together
+// pay the full amount.
+func TestBuildSplitSend(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester("TestBuildSplitSend")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	// Replace the wallet's outputs with 150 outputs of 100 SC each, too many
+	// to spend in a single transaction.
+	wt.wallet.mu.Lock()
+	defer wt.wallet.mu.Unlock()
+	var uh types.UnlockHash
+	for uh = range wt.wallet.keys {
+		break
+	}
+	small := types.SiacoinPrecision.Mul64(100)
+	wt.wallet.siacoinOutputs = make(map[types.SiacoinOutputID]types.SiacoinOutput)
+	for i := 0; i < 150; i++ {
+		wt.wallet.siacoinOutputs[types.SiacoinOutputID{byte(i), 1}] = types.SiacoinOutput{Value: small, UnlockHash: uh}
+	}
+
+	// 12e3 SC needs 121 outputs once the fees are included, which is more
+	// than two transactions can hold.
+	amount := types.SiacoinPrecision.Mul64(12e3)
+	dest := types.UnlockHash{1}
+	txns, err := wt.wallet.buildSplitSend(amount, dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txns) < 3 {
+		t.Fatal("expected the send to be split into at least 3 transactions, got", len(txns))
+	}
+	var paid types.Currency
+	spent := make(map[types.SiacoinOutputID]struct{})
+	for _, txn := range txns {
+		if size := len(encoding.Marshal(txn)); size > modules.TransactionSizeLimit {
+			t.Fatal("transaction exceeds the size limit:", size)
+		}
+		if err := txn.StandaloneValid(wt.wallet.consensusSetHeight); err != nil {
+			t.Fatal(err)
+		}
+		if txn.SiacoinOutputs[0].UnlockHash != dest {
+			t.Fatal("first output does not pay the destination")
+		}
+		paid = paid.Add(txn.SiacoinOutputs[0].Value)
+		for _, sci := range txn.SiacoinInputs {
+			if _, exists := spent[sci.ParentID]; exists {
+				t.Fatal("output spent by more than one transaction")
+			}
+			spent[sci.ParentID] = struct{}{}
+			if _, exists := wt.wallet.spentOutputs[types.OutputID(sci.ParentID)]; !exists {
+				t.Fatal("spent output was not marked")
+			}
+		}
+	}
+	if paid.Cmp(amount) != 0 {
+		t.Fatal("transactions pay", paid, "instead of", amount)
+	}
+	if len(spent) != 121 {
+		t.Fatal("expected 121 outputs to be spent, got", len(spent))
+	}
+
+	// A send that would need more than maxSplitSendParts transactions is
+	// refused.
+	for i := 0; i < 60*maxSplitSendParts; i++ {
+		wt.wallet.siacoinOutputs[types.SiacoinOutputID{byte(i), byte(i >> 8), 2}] = types.SiacoinOutput{Value: small, UnlockHash: uh}
+	}
+	if _, err := wt.wallet.buildSplitSend(small.Mul64(60*maxSplitSendParts), dest); err != errSplitSendTooLarge {
+		t.Fatal("expected errSplitSendTooLarge, got", err)
+	}
+}
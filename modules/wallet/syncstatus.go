@@ -0,0 +1,44 @@
+package wallet
+
+import (
+	"time"
+
+	"github.com/NebulousLabs/Sia/modules"
+)
+
+// SyncStatus reports how far the wallet is behind the consensus set. The
+// wallet starts processing blocks when it is unlocked for the first time, and
+// its balances only reflect the blocks it has processed.
+func (w *Wallet) SyncStatus() modules.WalletSyncStatus {
+	// Query the consensus set before grabbing the wallet lock, because the
+	// consensus set calls into the wallet while holding its own lock.
+	consensusHeight := w.cs.Height()
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	// consensusSetHeight counts the blocks processed by the wallet, including
+	// the genesis block, so it is one more than the height of the last block
+	// that was processed.
+	ss := modules.WalletSyncStatus{
+		ConsensusHeight: consensusHeight,
+	}
+	if w.consensusSetHeight > 0 {
+		ss.Height = w.consensusSetHeight - 1
+	}
+	if w.consensusSetHeight <= consensusHeight {
+		ss.BlocksBehind = consensusHeight + 1 - w.consensusSetHeight
+	}
+	ss.Synced = w.subscribed && ss.BlocksBehind == 0
+
+	if !w.scanStart.IsZero() && w.consensusSetHeight > w.scanStartHeight {
+		elapsed := time.Since(w.scanStart).Seconds()
+		if elapsed > 0 {
+			ss.BlocksPerSecond = float64(w.consensusSetHeight-w.scanStartHeight) / elapsed
+		}
+	}
+	if ss.BlocksBehind > 0 && ss.BlocksPerSecond > 0 {
+		ss.EstimatedSecondsRemaining = uint64(float64(ss.BlocksBehind)/ss.BlocksPerSecond) + 1
+	}
+	return ss
+}
@@ -0,0 +1,45 @@
+package wallet
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestSyncStatus checks that a wallet that has processed every block reports
+// itself as synced, and that a fresh wallet that has not been unlocked
+// reports how far behind it is.
+func TestSyncStatus(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester("TestSyncStatus")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	ss := wt.wallet.SyncStatus()
+	if !ss.Synced || ss.BlocksBehind != 0 || ss.EstimatedSecondsRemaining != 0 {
+		t.Fatal("unlocked wallet should be synced:", ss)
+	}
+	if ss.Height != wt.cs.Height() || ss.ConsensusHeight != wt.cs.Height() {
+		t.Fatal("wallet height does not match consensus height:", ss)
+	}
+	if ss.BlocksPerSecond <= 0 {
+		t.Fatal("expected a processing rate after the initial scan:", ss)
+	}
+
+	// A wallet that has never been unlocked has not processed any blocks.
+	w, err := New(wt.cs, wt.tpool, filepath.Join(wt.persistDir, "fresh wallet"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	ss = w.SyncStatus()
+	if ss.Synced || ss.Height != 0 || ss.BlocksBehind != wt.cs.Height()+1 {
+		t.Fatal("fresh wallet should be behind the consensus set:", ss)
+	}
+	if ss.BlocksPerSecond != 0 || ss.EstimatedSecondsRemaining != 0 {
+		t.Fatal("fresh wallet should not report a processing rate:", ss)
+	}
+}
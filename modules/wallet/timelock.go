@@ -0,0 +1,58 @@
+package wallet
+
+import (
+	"errors"
+
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+var errTimelockPassed = errors.New("timelock must be greater than the current block height")
+
+// initTimelockedKeys regenerates the timelocked addresses of the primary seed
+// after the wallet gets unlocked.
+func (w *Wallet) initTimelockedKeys() {
+	for _, tk := range w.persist.TimelockedKeys {
+		sk := generateSpendableKey(w.primarySeed, tk.Index)
+		sk.UnlockConditions.Timelock = tk.Timelock
+		w.keys[sk.UnlockConditions.UnlockHash()] = sk
+	}
+}
+
+// NextTimelockedAddress returns unlock conditions generated from the primary
+// seed that cannot be spent until the given height. Outputs sent to the
+// address are tracked by the wallet, but are not used to fund transactions
+// until the timelock has passed. Because the timelock is not part of the seed,
+// the address cannot be recovered from the seed alone.
+func (w *Wallet) NextTimelockedAddress(timelock types.BlockHeight) (types.UnlockConditions, error) {
+	if err := w.tg.Add(); err != nil {
+		return types.UnlockConditions{}, err
+	}
+	defer w.tg.Done()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.unlocked {
+		return types.UnlockConditions{}, modules.ErrLockedWallet
+	}
+	if timelock <= w.consensusSetHeight {
+		return types.UnlockConditions{}, errTimelockPassed
+	}
+
+	// Use the same index that nextPrimarySeedAddress would have used, so that
+	// the key is never handed out as a regular address.
+	index := w.persist.PrimarySeedProgress + modules.WalletSeedPreloadDepth
+	sk := generateSpendableKey(w.primarySeed, index)
+	sk.UnlockConditions.Timelock = timelock
+	w.keys[sk.UnlockConditions.UnlockHash()] = sk
+	w.persist.PrimarySeedProgress++
+	w.persist.TimelockedKeys = append(w.persist.TimelockedKeys, TimelockedKey{
+		Index:    index,
+		Timelock: timelock,
+	})
+	err := w.saveSettingsSync()
+	if err != nil {
+		return types.UnlockConditions{}, err
+	}
+	return sk.UnlockConditions, nil
+}
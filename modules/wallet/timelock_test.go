@@ -0,0 +1,79 @@
+package wallet
+
+import (
+	"testing"
+
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// TestTimelockedAddress checks that coins sent to a timelocked wallet address
+// are reported separately and cannot be spent until the timelock passes.
+func TestTimelockedAddress(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester("TestTimelockedAddress")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+
+	if _, err := wt.wallet.NextTimelockedAddress(wt.cs.Height()); err != errTimelockPassed {
+		t.Fatal("expected errTimelockPassed, got", err)
+	}
+	timelock := wt.cs.Height() + 3
+	uc, err := wt.wallet.NextTimelockedAddress(timelock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uc.Timelock != timelock {
+		t.Fatal("unlock conditions have the wrong timelock:", uc.Timelock)
+	}
+
+	// Send everything except the fee to the timelocked address.
+	balance, _, _ := wt.wallet.ConfirmedBalance()
+	amount := balance.Sub(sendFee)
+	if _, err := wt.wallet.SendSiacoins(amount, uc.UnlockHash()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wt.miner.AddBlock(); err != nil {
+		t.Fatal(err)
+	}
+	bd := wt.wallet.BalanceBreakdown()
+	if bd.Timelocked.Value.Cmp(amount) != 0 || bd.Timelocked.Count != 1 {
+		t.Fatal("timelocked output was not reported:", bd.Timelocked)
+	}
+	// Sending the whole spendable balance leaves nothing for the fee, unless
+	// the timelocked output is used.
+	if _, err := wt.wallet.SendSiacoins(bd.Spendable.Value, types.UnlockHash{}); err == nil {
+		t.Fatal("timelocked output should not be spendable")
+	}
+
+	// The timelocked address should be recognized after the wallet is locked
+	// and unlocked again.
+	if err := wt.wallet.Lock(); err != nil {
+		t.Fatal(err)
+	}
+	delete(wt.wallet.keys, uc.UnlockHash())
+	if err := wt.wallet.Unlock(wt.walletMasterKey); err != nil {
+		t.Fatal(err)
+	}
+	if _, exists := wt.wallet.keys[uc.UnlockHash()]; !exists {
+		t.Fatal("timelocked address was not restored on unlock")
+	}
+
+	// Once the timelock passes, the output becomes spendable, and spending
+	// the whole spendable balance requires using it.
+	for wt.cs.Height() < timelock {
+		if err := wt.addEmptyBlock(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bd = wt.wallet.BalanceBreakdown()
+	if !bd.Timelocked.Value.IsZero() || bd.Spendable.Value.Cmp(amount) < 0 {
+		t.Fatal("output should no longer be timelocked:", bd)
+	}
+	if _, err := wt.wallet.SendSiacoins(bd.Spendable.Value.Sub(sendFee), types.UnlockHash{}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -82,25 +82,23 @@ func addSignatures(txn *types.Transaction, cf types.CoveredFields, uc types.Unlo
 	return newSigIndices, nil
 }
 
-// FundSiacoins will add a siacoin input of exactly 'amount' to the
-// transaction. A parent transaction may be needed to achieve an input with the
-// correct value. The siacoin input will not be signed until 'Sign' is called
-// on the transaction builder.
-func (tb *transactionBuilder) FundSiacoins(amount types.Currency) error {
-	tb.wallet.mu.Lock()
-	defer tb.wallet.mu.Unlock()
-
+// selectSiacoinOutputs chooses the outputs that fund a transaction of
+// 'amount' siacoins, largest first, skipping outputs that are timelocked or
+// were spent recently. Unconfirmed outputs belonging to the wallet are
+// included. The total value of the chosen outputs is returned alongside them.
+// The wallet must be locked by the caller.
+func (w *Wallet) selectSiacoinOutputs(amount types.Currency) (sortedOutputs, types.Currency, error) {
 	// Collect a value-sorted set of siacoin outputs.
 	var so sortedOutputs
-	for scoid, sco := range tb.wallet.siacoinOutputs {
+	for scoid, sco := range w.siacoinOutputs {
 		so.ids = append(so.ids, scoid)
 		so.outputs = append(so.outputs, sco)
 	}
 	// Add all of the unconfirmed outputs as well.
-	for _, upt := range tb.wallet.unconfirmedProcessedTransactions {
+	for _, upt := range w.unconfirmedProcessedTransactions {
 		for i, sco := range upt.Transaction.SiacoinOutputs {
 			// Determine if the output belongs to the wallet.
-			_, exists := tb.wallet.keys[sco.UnlockHash]
+			_, exists := w.keys[sco.UnlockHash]
 			if !exists {
 				continue
 			}
@@ -110,42 +108,33 @@ func (tb *transactionBuilder) FundSiacoins(amount types.Currency) error {
 	}
 	sort.Sort(sort.Reverse(so))
 
-	// Create and fund a parent transaction that will add the correct amount of
-	// siacoins to the transaction.
+	// Select outputs, largest first, until their value reaches the amount.
 	var fund types.Currency
 	// potentialFund tracks the balance of the wallet including outputs that
 	// have been spent in other unconfirmed transactions recently. This is to
 	// provide the user with a more useful error message in the event that they
 	// are overspending.
 	var potentialFund types.Currency
-	parentTxn := types.Transaction{}
-	var spentScoids []types.SiacoinOutputID
+	var selected sortedOutputs
 	for i := range so.ids {
 		scoid := so.ids[i]
 		sco := so.outputs[i]
 		// Check that this output has not recently been spent by the wallet.
-		spendHeight := tb.wallet.spentOutputs[types.OutputID(scoid)]
+		spendHeight := w.spentOutputs[types.OutputID(scoid)]
 		// Prevent an underflow error.
-		allowedHeight := tb.wallet.consensusSetHeight - RespendTimeout
-		if tb.wallet.consensusSetHeight < RespendTimeout {
+		allowedHeight := w.consensusSetHeight - RespendTimeout
+		if w.consensusSetHeight < RespendTimeout {
 			allowedHeight = 0
 		}
 		if spendHeight > allowedHeight {
 			potentialFund = potentialFund.Add(sco.Value)
 			continue
 		}
-		outputUnlockConditions := tb.wallet.keys[sco.UnlockHash].UnlockConditions
-		if tb.wallet.consensusSetHeight < outputUnlockConditions.Timelock {
+		if w.consensusSetHeight < w.keys[sco.UnlockHash].UnlockConditions.Timelock {
 			continue
 		}
-
-		// Add a siacoin input for this output.
-		sci := types.SiacoinInput{
-			ParentID:         scoid,
-			UnlockConditions: outputUnlockConditions,
-		}
-		parentTxn.SiacoinInputs = append(parentTxn.SiacoinInputs, sci)
-		spentScoids = append(spentScoids, scoid)
+		selected.ids = append(selected.ids, scoid)
+		selected.outputs = append(selected.outputs, sco)
 
 		// Add the output to the total fund
 		fund = fund.Add(sco.Value)
@@ -155,11 +144,37 @@ func (tb *transactionBuilder) FundSiacoins(amount types.Currency) error {
 		}
 	}
 	if potentialFund.Cmp(amount) >= 0 && fund.Cmp(amount) < 0 {
-		return modules.ErrIncompleteTransactions
+		return sortedOutputs{}, types.Currency{}, modules.ErrIncompleteTransactions
 	}
 	if fund.Cmp(amount) < 0 {
-		return modules.ErrLowBalance
+		return sortedOutputs{}, types.Currency{}, modules.ErrLowBalance
+	}
+	return selected, fund, nil
+}
+
+// FundSiacoins will add a siacoin input of exactly 'amount' to the
+// transaction. A parent transaction may be needed to achieve an input with the
+// correct value. The siacoin input will not be signed until 'Sign' is called
+// on the transaction builder.
+func (tb *transactionBuilder) FundSiacoins(amount types.Currency) error {
+	tb.wallet.mu.Lock()
+	defer tb.wallet.mu.Unlock()
+
+	// Select the outputs and add a siacoin input for each of them to a parent
+	// transaction that will add the correct amount of siacoins to the
+	// transaction.
+	selected, fund, err := tb.wallet.selectSiacoinOutputs(amount)
+	if err != nil {
+		return err
+	}
+	parentTxn := types.Transaction{}
+	for i, scoid := range selected.ids {
+		parentTxn.SiacoinInputs = append(parentTxn.SiacoinInputs, types.SiacoinInput{
+			ParentID:         scoid,
+			UnlockConditions: tb.wallet.keys[selected.outputs[i].UnlockHash].UnlockConditions,
+		})
 	}
+	spentScoids := selected.ids
 
 	// Create and add the output that will be used to fund the standard
 	// transaction.
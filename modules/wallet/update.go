@@ -221,6 +221,13 @@ func (w *Wallet) ProcessConsensusChange(cc modules.ConsensusChange) {
 	w.updateConfirmedSet(cc)
 	w.revertHistory(cc)
 	w.applyHistory(cc)
+	if w.updateAutoBumps() {
+		go w.threadedBumpFees()
+	}
+	if w.updateClaimCollection() {
+		go w.threadedCollectClaims()
+	}
+	w.updateScheduledSends()
 }
 
 // ReceiveUpdatedUnconfirmedTransactions updates the wallet's unconfirmed
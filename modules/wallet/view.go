@@ -0,0 +1,119 @@
+package wallet
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/NebulousLabs/Sia/crypto"
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+var (
+	errNoViewKeys = errors.New("wallet must be fully unlocked once before it can be unlocked for viewing")
+
+	viewModifier = types.Specifier{'v', 'i', 'e', 'w'}
+)
+
+// viewEncryptionKey creates the key that encrypts the wallet's view keys.
+// Unlike the master key, it cannot be used to decrypt the wallet's seeds, so
+// it is safe to keep in memory while the wallet is unlocked.
+func viewEncryptionKey(masterKey crypto.TwofishKey, uid UniqueID) crypto.TwofishKey {
+	return crypto.TwofishKey(crypto.HashAll(masterKey, uid, viewModifier))
+}
+
+// updateViewKeys encrypts the unlock conditions of every key in the wallet
+// into the wallet's persist object, so that the wallet can track its
+// addresses after a view unlock. The view keys are only updated while the
+// view encryption key is known, which is from the time of a full unlock until
+// the wallet is locked.
+func (w *Wallet) updateViewKeys() error {
+	if w.viewKey == (crypto.TwofishKey{}) {
+		return nil
+	}
+	ucs := make([]types.UnlockConditions, 0, len(w.keys))
+	for _, sk := range w.keys {
+		ucs = append(ucs, sk.UnlockConditions)
+	}
+	ucsBytes, err := json.Marshal(ucs)
+	if err != nil {
+		return err
+	}
+	w.persist.ViewKeys, err = w.viewKey.EncryptBytes(ucsBytes)
+	return err
+}
+
+// managedViewUnlock loads the unlock conditions of the wallet's keys into
+// memory, without loading any seeds or secret keys.
+func (w *Wallet) managedViewUnlock(masterKey crypto.TwofishKey) error {
+	err := func() error {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+
+		if w.unlocked || w.viewUnlocked {
+			return errAlreadyUnlocked
+		}
+		if len(w.persist.EncryptionVerification) == 0 {
+			return errUnencryptedWallet
+		}
+		err := w.checkMasterKey(masterKey)
+		if err != nil {
+			return err
+		}
+		if len(w.persist.ViewKeys) == 0 {
+			return errNoViewKeys
+		}
+
+		ucsBytes, err := viewEncryptionKey(masterKey, w.persist.UID).DecryptBytes(w.persist.ViewKeys)
+		if err != nil {
+			return err
+		}
+		var ucs []types.UnlockConditions
+		err = json.Unmarshal(ucsBytes, &ucs)
+		if err != nil {
+			return err
+		}
+		for _, uc := range ucs {
+			w.keys[uc.UnlockHash()] = spendableKey{UnlockConditions: uc}
+		}
+		return nil
+	}()
+	if err != nil {
+		return err
+	}
+
+	err = w.managedSubscribe()
+	if err != nil {
+		return err
+	}
+	w.mu.Lock()
+	w.viewUnlocked = true
+	w.mu.Unlock()
+	return nil
+}
+
+// ViewUnlock unlocks the wallet for viewing. The wallet tracks its addresses
+// and balances, but its seeds and secret keys stay encrypted, so nothing can
+// be spent until the wallet is fully unlocked with Unlock.
+func (w *Wallet) ViewUnlock(masterKey crypto.TwofishKey) error {
+	if err := w.tg.Add(); err != nil {
+		return err
+	}
+	defer w.tg.Done()
+	w.log.Println("INFO: Unlocking wallet for viewing.")
+	return w.managedViewUnlock(masterKey)
+}
+
+// UnlockLevel returns how far the wallet is unlocked.
+func (w *Wallet) UnlockLevel() modules.WalletUnlockLevel {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	switch {
+	case w.unlocked:
+		return modules.WalletFullyUnlocked
+	case w.viewUnlocked:
+		return modules.WalletViewUnlocked
+	default:
+		return modules.WalletLocked
+	}
+}
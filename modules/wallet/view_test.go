@@ -0,0 +1,104 @@
+package wallet
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/NebulousLabs/Sia/crypto"
+	"github.com/NebulousLabs/Sia/modules"
+	"github.com/NebulousLabs/Sia/types"
+)
+
+// TestViewUnlock checks that a wallet unlocked for viewing tracks its balance
+// without loading any secret keys, and that it can then be fully unlocked.
+func TestViewUnlock(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+	wt, err := createWalletTester("TestViewUnlock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wt.closeWt()
+	if level := wt.wallet.UnlockLevel(); level != modules.WalletFullyUnlocked {
+		t.Fatal("wrong unlock level:", level)
+	}
+	balance, _, _ := wt.wallet.ConfirmedBalance()
+
+	// A wallet that has never been fully unlocked has no view keys.
+	blank, err := createBlankWalletTester("TestViewUnlock - blank")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer blank.closeWt()
+	seed, err := blank.wallet.Encrypt(crypto.TwofishKey{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := blank.wallet.ViewUnlock(crypto.TwofishKey(crypto.HashObject(seed))); err != errNoViewKeys {
+		t.Fatal("expected errNoViewKeys, got", err)
+	}
+
+	// Load a second wallet from the same settings file, as a restart would.
+	w, err := New(wt.cs, wt.tpool, filepath.Join(wt.persistDir, modules.WalletDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.ViewUnlock(crypto.TwofishKey{}); err != modules.ErrBadEncryptionKey {
+		t.Fatal("expected ErrBadEncryptionKey, got", err)
+	}
+	if err := w.ViewUnlock(wt.walletMasterKey); err != nil {
+		t.Fatal(err)
+	}
+	if level := w.UnlockLevel(); level != modules.WalletViewUnlocked || w.Unlocked() {
+		t.Fatal("wrong unlock level:", level)
+	}
+	if err := w.ViewUnlock(wt.walletMasterKey); err != errAlreadyUnlocked {
+		t.Fatal("expected errAlreadyUnlocked, got", err)
+	}
+	if balance2, _, _ := w.ConfirmedBalance(); balance2.Cmp(balance) != 0 {
+		t.Fatalf("balance of view unlocked wallet is %v, expected %v", balance2, balance)
+	}
+	if len(w.keys) != len(wt.wallet.keys) {
+		t.Fatalf("view unlocked wallet has %v keys, expected %v", len(w.keys), len(wt.wallet.keys))
+	}
+	for _, sk := range w.keys {
+		if len(sk.SecretKeys) != 0 {
+			t.Fatal("view unlocked wallet holds secret keys")
+		}
+	}
+	if len(w.seeds) != 0 || w.primarySeed != (modules.Seed{}) {
+		t.Fatal("view unlocked wallet holds seeds")
+	}
+	if _, err := w.SendSiacoins(types.NewCurrency64(1), types.UnlockHash{}); err != modules.ErrLockedWallet {
+		t.Fatal("expected ErrLockedWallet, got", err)
+	}
+	if _, err := w.NextAddress(); err != modules.ErrLockedWallet {
+		t.Fatal("expected ErrLockedWallet, got", err)
+	}
+
+	// A full unlock makes the wallet spendable.
+	if err := w.Unlock(wt.walletMasterKey); err != nil {
+		t.Fatal(err)
+	}
+	if level := w.UnlockLevel(); level != modules.WalletFullyUnlocked {
+		t.Fatal("wrong unlock level:", level)
+	}
+	if _, err := w.SendSiacoins(types.SiacoinPrecision, types.UnlockHash{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Locking ends a view unlock as well.
+	if err := w.Lock(); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.ViewUnlock(wt.walletMasterKey); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Lock(); err != nil {
+		t.Fatal(err)
+	}
+	if level := w.UnlockLevel(); level != modules.WalletLocked {
+		t.Fatal("wrong unlock level:", level)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/NebulousLabs/Sia/build"
 	"github.com/NebulousLabs/Sia/crypto"
@@ -53,6 +54,13 @@ type Wallet struct {
 	persist     WalletPersist
 	primarySeed modules.Seed
 
+	// viewUnlocked indicates whether the wallet has been unlocked for
+	// viewing only, in which case it tracks its addresses but holds no seeds
+	// or secret keys. viewKey encrypts the wallet's view keys, and is known
+	// from the time of a full unlock until the wallet is locked.
+	viewUnlocked bool
+	viewKey      crypto.TwofishKey
+
 	// The wallet's dependencies. The items 'consensusSetHeight' and
 	// 'siafundPool' are tracked separately from the consensus set to minimize
 	// the number of queries that the wallet needs to make to the consensus
@@ -98,6 +106,26 @@ type Wallet struct {
 	historicOutputs     map[types.OutputID]types.Currency
 	historicClaimStarts map[types.SiafundOutputID]types.Currency
 
+	// autoBumps are the sends whose fees are raised automatically while they
+	// remain unconfirmed. They are not persisted, so sends are no longer
+	// bumped after the wallet restarts.
+	autoBumps []*autoBump
+
+	// scheduledSends are the sends that are held until their broadcast time,
+	// and the sends that finished within scheduledSendRetention. The pending
+	// sends are saved in persist.ScheduledSends.
+	scheduledSends []*scheduledSend
+
+	// collectingClaims is set while a siafund claim collection is being
+	// submitted, so that consecutive blocks do not start a second one.
+	collectingClaims bool
+
+	// scanStart and scanStartHeight record when the wallet subscribed to the
+	// consensus set and how many blocks it had processed at the time, so that
+	// the rate of catching up can be measured.
+	scanStart       time.Time
+	scanStartHeight types.BlockHeight
+
 	persistDir string
 	log        *persist.Logger
 	mu         sync.RWMutex
@@ -154,7 +182,7 @@ func (w *Wallet) Close() error {
 	// Once the wallet is locked it cannot be unlocked except using the
 	// unexported unlock method (w.Unlock returns an error if the wallet's
 	// ThreadGroup is stopped).
-	if w.Unlocked() {
+	if w.UnlockLevel() != modules.WalletLocked {
 		if err := w.Lock(); err != nil {
 			errs = append(errs, err)
 		}
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
 
 	"github.com/NebulousLabs/Sia/api"
 
@@ -53,11 +55,18 @@ func updatecmd() {
 		return
 	}
 
-	err = post("/daemon/update", "")
+	// siad answers with a body only if it was already up to date
+	var utd api.DaemonUpdateUpToDatePOST
+	resp, err := apiPost("/daemon/update", "")
 	if err != nil {
 		fmt.Println("Could not apply update:", err)
 		return
 	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent && json.NewDecoder(resp.Body).Decode(&utd) == nil && utd.UpToDate {
+		fmt.Println("Already up to date.")
+		return
+	}
 	fmt.Printf("Updated to version %s! Restart siad now.\n", update.Version)
 
 }
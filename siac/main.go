@@ -19,7 +19,9 @@ import (
 // flags
 var (
 	addr              string // override default API address
+	apiBasePath       string // path that siad serves the API under
 	initPassword      bool   // supply a custom password when creating a wallet
+	unlockView        bool   // unlock the wallet for viewing only
 	hostVerbose       bool   // display additional host info
 	renterShowHistory bool   // Show download history in addition to download queue.
 	renterListVerbose bool   // Show additional info about uploaded files.
@@ -50,14 +52,24 @@ func decodeError(resp *http.Response) error {
 	return apiErr
 }
 
+// apiURL returns the URL of an API call, defaulting to localhost if addr does
+// not specify a host.
+func apiURL(call string) string {
+	if host, port, _ := net.SplitHostPort(addr); host == "" {
+		addr = net.JoinHostPort("localhost", port)
+	}
+	basePath, err := api.CleanBasePath(apiBasePath)
+	if err != nil {
+		die("Invalid API base path:", err)
+	}
+	return "http://" + addr + basePath + call
+}
+
 // apiGet wraps a GET request with a status code check, such that if the GET does
 // not return 2xx, the error will be read and returned. The response body is
 // not closed.
 func apiGet(call string) (*http.Response, error) {
-	if host, port, _ := net.SplitHostPort(addr); host == "" {
-		addr = net.JoinHostPort("localhost", port)
-	}
-	resp, err := api.HttpGET("http://" + addr + call)
+	resp, err := api.HttpGET(apiURL(call))
 	if err != nil {
 		return nil, errors.New("no response from daemon")
 	}
@@ -69,7 +81,7 @@ func apiGet(call string) (*http.Response, error) {
 		if err != nil {
 			return nil, err
 		}
-		resp, err = api.HttpGETAuthenticated("http://"+addr+call, password)
+		resp, err = api.HttpGETAuthenticated(apiURL(call), password)
 		if err != nil {
 			return nil, errors.New("no response from daemon - authentication failed")
 		}
@@ -121,11 +133,7 @@ func get(call string) error {
 // does not return 2xx, the error will be read and returned. The response body
 // is not closed.
 func apiPost(call, vals string) (*http.Response, error) {
-	if host, port, _ := net.SplitHostPort(addr); host == "" {
-		addr = net.JoinHostPort("localhost", port)
-	}
-
-	resp, err := api.HttpPOST("http://"+addr+call, vals)
+	resp, err := api.HttpPOST(apiURL(call), vals)
 	if err != nil {
 		return nil, errors.New("no response from daemon")
 	}
@@ -137,7 +145,7 @@ func apiPost(call, vals string) (*http.Response, error) {
 		if err != nil {
 			return nil, err
 		}
-		resp, err = api.HttpPOSTAuthenticated("http://"+addr+call, vals, password)
+		resp, err = api.HttpPOSTAuthenticated(apiURL(call), vals, password)
 		if err != nil {
 			return nil, errors.New("no response from daemon - authentication failed")
 		}
@@ -260,6 +268,7 @@ func main() {
 		walletLoadCmd, walletLockCmd, walletSeedsCmd, walletSendCmd,
 		walletBalanceCmd, walletTransactionsCmd, walletUnlockCmd)
 	walletInitCmd.Flags().BoolVarP(&initPassword, "password", "p", false, "Prompt for a custom password")
+	walletUnlockCmd.Flags().BoolVarP(&unlockView, "view", "", false, "Unlock for viewing balances only, without loading spending keys")
 	walletLoadCmd.AddCommand(walletLoad033xCmd, walletLoadSeedCmd, walletLoadSiagCmd)
 	walletSendCmd.AddCommand(walletSendSiacoinsCmd, walletSendSiafundsCmd)
 
@@ -279,6 +288,7 @@ func main() {
 
 	// parse flags
 	root.PersistentFlags().StringVarP(&addr, "addr", "a", "localhost:9980", "which host/port to communicate with (i.e. the host/port siad is listening on)")
+	root.PersistentFlags().StringVarP(&apiBasePath, "api-base-path", "", "", "path that siad serves the API under (i.e. the value of siad's --api-base-path)")
 
 	// run
 	if err := root.Execute(); err != nil {
@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/NebulousLabs/Sia/api"
+	"github.com/NebulousLabs/Sia/modules"
 	"github.com/NebulousLabs/Sia/types"
 )
 
@@ -293,13 +294,17 @@ func walletbalancecmd() {
 	if status.Encrypted {
 		encStatus = "Encrypted"
 	}
-	if !status.Unlocked {
+	if !status.Unlocked && status.UnlockLevel != modules.WalletViewUnlocked {
 		fmt.Printf(`Wallet status:
 %v, Locked
 Unlock the wallet to view balance
 `, encStatus)
 		return
 	}
+	lockStatus := "Unlocked"
+	if status.UnlockLevel == modules.WalletViewUnlocked {
+		lockStatus = "Unlocked for viewing"
+	}
 
 	unconfirmedBalance := status.ConfirmedSiacoinBalance.Add(status.UnconfirmedIncomingSiacoins).Sub(status.UnconfirmedOutgoingSiacoins)
 	var delta string
@@ -310,13 +315,13 @@ Unlock the wallet to view balance
 	}
 
 	fmt.Printf(`Wallet status:
-%s, Unlocked
+%s, %s
 Confirmed Balance:   %v
 Unconfirmed Delta:  %v
 Exact:               %v H
 Siafunds:            %v SF
 Siafund Claims:      %v H
-`, encStatus, currencyUnits(status.ConfirmedSiacoinBalance), delta,
+`, encStatus, lockStatus, currencyUnits(status.ConfirmedSiacoinBalance), delta,
 		status.ConfirmedSiacoinBalance, status.SiafundBalance, status.SiacoinClaimBalance)
 }
 
@@ -387,6 +392,14 @@ func walletunlockcmd() {
 	}
 	fmt.Println("Unlocking the wallet. This may take several minutes...")
 	qs := fmt.Sprintf("encryptionpassword=%s&dictonary=%s", password, "english")
+	if unlockView {
+		err = post("/wallet/unlock/view", qs)
+		if err != nil {
+			die("Could not unlock wallet for viewing:", err)
+		}
+		fmt.Println("Wallet unlocked for viewing")
+		return
+	}
 	err = post("/wallet/unlock", qs)
 	if err != nil {
 		die("Could not unlock wallet:", err)
@@ -21,11 +21,20 @@ import (
 	"github.com/NebulousLabs/Sia/modules/transactionpool"
 	"github.com/NebulousLabs/Sia/modules/wallet"
 	"github.com/NebulousLabs/Sia/profile"
+	"github.com/NebulousLabs/Sia/types"
 
 	"github.com/bgentry/speakeasy"
 	"github.com/spf13/cobra"
 )
 
+const (
+	// apiTLSCertFile and apiTLSKeyFile are the names of the self-signed
+	// certificate and key generated in the Sia directory when TLS is enabled
+	// without a certificate.
+	apiTLSCertFile = "apitls.crt"
+	apiTLSKeyFile  = "apitls.key"
+)
+
 // verifyAPISecurity checks that the security values are consistent with a
 // sane, secure system.
 func verifyAPISecurity(config Config) error {
@@ -76,6 +85,23 @@ func processModules(modules string) (string, error) {
 	return modules, nil
 }
 
+// processTLS fills in the default TLS certificate and key paths. TLS is
+// enabled implicitly when a certificate is provided, and the certificate and
+// key must be provided together.
+func processTLS(config Config) (Config, error) {
+	if config.Siad.APITLSCert != "" || config.Siad.APITLSKey != "" {
+		if config.Siad.APITLSCert == "" || config.Siad.APITLSKey == "" {
+			return Config{}, errors.New("--api-tls-cert and --api-tls-key must be used together")
+		}
+		config.Siad.APITLS = true
+	}
+	if config.Siad.APITLS && config.Siad.APITLSCert == "" {
+		config.Siad.APITLSCert = filepath.Join(config.Siad.SiaDir, apiTLSCertFile)
+		config.Siad.APITLSKey = filepath.Join(config.Siad.SiaDir, apiTLSKeyFile)
+	}
+	return config, nil
+}
+
 // processConfig checks the configuration values and performs cleanup on
 // incorrect-but-allowed values.
 func processConfig(config Config) (Config, error) {
@@ -85,7 +111,8 @@ func processConfig(config Config) (Config, error) {
 	config.Siad.HostAddr = processNetAddr(config.Siad.HostAddr)
 	config.Siad.Modules, err1 = processModules(config.Siad.Modules)
 	err2 := verifyAPISecurity(config)
-	err := build.JoinErrors([]error{err1, err2}, ", and ")
+	config, err3 := processTLS(config)
+	err := build.JoinErrors([]error{err1, err2, err3}, ", and ")
 	if err != nil {
 		return Config{}, err
 	}
@@ -121,7 +148,7 @@ func startDaemon(config Config) (err error) {
 	if strings.Contains(config.Siad.Modules, "g") {
 		i++
 		fmt.Printf("(%d/%d) Loading gateway...\n", i, len(config.Siad.Modules))
-		g, err = gateway.New(config.Siad.RPCaddr, filepath.Join(config.Siad.SiaDir, modules.GatewayDir))
+		g, err = gateway.NewWithFamily(config.Siad.RPCaddr, config.Siad.RPCFamily, filepath.Join(config.Siad.SiaDir, modules.GatewayDir))
 		if err != nil {
 			return err
 		}
@@ -134,6 +161,10 @@ func startDaemon(config Config) (err error) {
 		if err != nil {
 			return err
 		}
+		err = cs.SetMaxReorgDepth(types.BlockHeight(config.Siad.MaxReorgDepth))
+		if err != nil {
+			return err
+		}
 	}
 	var e modules.Explorer
 	if strings.Contains(config.Siad.Modules, "e") {
@@ -152,6 +183,16 @@ func startDaemon(config Config) (err error) {
 		if err != nil {
 			return err
 		}
+		err = tpool.SetLimits(config.Siad.TpoolMaxTransactions, config.Siad.TpoolMaxSize)
+		if err != nil {
+			return err
+		}
+		var minRelayFee types.Currency
+		_, err = fmt.Sscan(config.Siad.TpoolMinRelayFee, &minRelayFee)
+		if err != nil {
+			return errors.New("unable to parse --tpool-min-relay-fee: " + err.Error())
+		}
+		tpool.SetMinRelayFee(minRelayFee)
 	}
 	var w modules.Wallet
 	if strings.Contains(config.Siad.Modules, "w") {
@@ -189,6 +230,11 @@ func startDaemon(config Config) (err error) {
 			return err
 		}
 	}
+	if h != nil && r != nil {
+		// The renter's host database provides the market prices used by the
+		// host's automatic pricing.
+		h.SetMarket(r)
+	}
 	srv, err := api.NewServer(
 		config.Siad.APIaddr,
 		config.Siad.RequiredUserAgent,
@@ -205,6 +251,50 @@ func startDaemon(config Config) (err error) {
 	if err != nil {
 		return err
 	}
+	if config.Siad.APITLS {
+		err = srv.EnableTLS(config.Siad.APITLSCert, config.Siad.APITLSKey)
+		if err != nil {
+			return err
+		}
+		fmt.Println("Serving the API over https using the certificate at", config.Siad.APITLSCert)
+	}
+	if config.Siad.APIBasePath != "" {
+		err = srv.SetBasePath(config.Siad.APIBasePath)
+		if err != nil {
+			return err
+		}
+	}
+	if config.Siad.APIDebug {
+		srv.EnableDebug()
+	}
+	err = srv.SetPagination(config.Siad.APIPageSize, config.Siad.APIMaxPageSize)
+	if err != nil {
+		return err
+	}
+	err = srv.SetUpdateChannel(config.Siad.UpdateChannel)
+	if err != nil {
+		return err
+	}
+	err = srv.SetUpdateCheckTimeout(time.Duration(config.Siad.UpdateCheckTimeout) * time.Second)
+	if err != nil {
+		return err
+	}
+	err = srv.SetUpdateCacheTTL(time.Duration(config.Siad.UpdateCacheTTL) * time.Second)
+	if err != nil {
+		return err
+	}
+	err = srv.SetPersistDir(config.Siad.SiaDir)
+	if err != nil {
+		return err
+	}
+	err = srv.SetUpdateKeyFile(config.Siad.UpdateKeyFile)
+	if err != nil {
+		return err
+	}
+	err = srv.SetUpdateSource(config.Siad.UpdateRepo, config.Siad.UpdateArchiveName, config.Siad.UpdateChecksumsName)
+	if err != nil {
+		return err
+	}
 
 	// Bootstrap to the network.
 	if !config.Siad.NoBootstrap && g != nil {
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"path/filepath"
 	"testing"
 )
 
@@ -163,3 +164,49 @@ func TestVerifyAPISecurity(t *testing.T) {
 		t.Error("public + securityOff with authentication was rejected:", err)
 	}
 }
+
+// TestProcessTLS checks that processTLS enables TLS when a certificate is
+// provided and fills in the default certificate paths.
+func TestProcessTLS(t *testing.T) {
+	// TLS should stay disabled by default.
+	var plain Config
+	plain, err := processTLS(plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plain.Siad.APITLS || plain.Siad.APITLSCert != "" {
+		t.Error("TLS was enabled without being requested")
+	}
+
+	// Enabling TLS without a certificate should use the default paths.
+	var selfSigned Config
+	selfSigned.Siad.APITLS = true
+	selfSigned.Siad.SiaDir = "foo"
+	selfSigned, err = processTLS(selfSigned)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if selfSigned.Siad.APITLSCert != filepath.Join("foo", apiTLSCertFile) || selfSigned.Siad.APITLSKey != filepath.Join("foo", apiTLSKeyFile) {
+		t.Error("default certificate paths were not used:", selfSigned.Siad.APITLSCert, selfSigned.Siad.APITLSKey)
+	}
+
+	// Providing a certificate should enable TLS.
+	var provided Config
+	provided.Siad.APITLSCert = "cert.pem"
+	provided.Siad.APITLSKey = "key.pem"
+	provided, err = processTLS(provided)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !provided.Siad.APITLS || provided.Siad.APITLSCert != "cert.pem" {
+		t.Error("provided certificate was not used")
+	}
+
+	// A certificate without a key should be rejected.
+	var missingKey Config
+	missingKey.Siad.APITLSCert = "cert.pem"
+	_, err = processTLS(missingKey)
+	if err == nil {
+		t.Error("certificate without a key was accepted")
+	}
+}
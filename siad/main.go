@@ -6,7 +6,10 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/NebulousLabs/Sia/api"
 	"github.com/NebulousLabs/Sia/build"
+	"github.com/NebulousLabs/Sia/modules/gateway"
+	"github.com/NebulousLabs/Sia/modules/transactionpool"
 )
 
 var (
@@ -37,11 +40,70 @@ type Config struct {
 		HostAddr     string
 		AllowAPIBind bool
 
+		// RPCFamily restricts the gateway to IPv4 or IPv6 addresses, or
+		// allows both if it is "dual".
+		RPCFamily string
+
 		Modules           string
 		NoBootstrap       bool
 		RequiredUserAgent string
 		AuthenticateAPI   bool
 
+		// The API is served over TLS if APITLS is set or a certificate is
+		// provided. Without a certificate, a self-signed certificate is
+		// generated in the Sia directory.
+		APITLS     bool
+		APITLSCert string
+		APITLSKey  string
+
+		// APIBasePath is prepended to the path of every API route.
+		APIBasePath string
+
+		// APIDebug enables the profiling routes under /daemon/debug.
+		APIDebug bool
+
+		// APIPageSize and APIMaxPageSize are the default and maximum number
+		// of items returned by list endpoints. Zero is no limit.
+		APIPageSize    int
+		APIMaxPageSize int
+
+		// UpdateChannel is the release track checked by /daemon/update,
+		// either "stable" or "beta".
+		UpdateChannel string
+
+		// UpdateCheckTimeout is the number of seconds a request to GitHub
+		// may take while checking for an update.
+		UpdateCheckTimeout int
+
+		// UpdateCacheTTL is the number of seconds that the result of an
+		// update check is reused. Zero disables the cache.
+		UpdateCacheTTL int
+
+		// UpdateKeyFile is a file of PEM encoded public keys that replace
+		// the developer key when verifying updates.
+		UpdateKeyFile string
+
+		// UpdateRepo is the GitHub 'owner/repo', or the URL of a mirror,
+		// that releases are fetched from. UpdateArchiveName and
+		// UpdateChecksumsName are templates for the names of the release
+		// assets. Empty values keep the official Sia releases.
+		UpdateRepo          string
+		UpdateArchiveName   string
+		UpdateChecksumsName string
+
+		// MaxReorgDepth is the largest number of blocks that the consensus set
+		// will revert to switch to a heavier chain. Zero is no limit.
+		MaxReorgDepth uint64
+
+		// Limits on the number of transactions and the encoded size of the
+		// transaction pool. A limit of zero is disabled.
+		TpoolMaxTransactions int
+		TpoolMaxSize         int
+
+		// TpoolMinRelayFee is the minimum fee, in hastings per byte, that
+		// transaction sets from peers must pay to be accepted and relayed.
+		TpoolMinRelayFee string
+
 		Profile    bool
 		ProfileDir string
 		SiaDir     string
@@ -67,6 +129,12 @@ func versionCmd(*cobra.Command, []string) {
 	default:
 		fmt.Println("Sia Daemon v" + build.Version + "-???")
 	}
+	if build.GitRevision != "" {
+		fmt.Println("Git Revision " + build.GitRevision)
+	}
+	if build.BuildTime != "" {
+		fmt.Println("Build Time " + build.BuildTime)
+	}
 }
 
 // modulesCmd is a cobra command that prints help info about modules.
@@ -162,9 +230,28 @@ func main() {
 	root.Flags().BoolVarP(&globalConfig.Siad.NoBootstrap, "no-bootstrap", "", false, "disable bootstrapping on this run")
 	root.Flags().BoolVarP(&globalConfig.Siad.Profile, "profile", "", false, "enable profiling")
 	root.Flags().StringVarP(&globalConfig.Siad.RPCaddr, "rpc-addr", "", ":9981", "which port the gateway listens on")
+	root.Flags().StringVarP(&globalConfig.Siad.RPCFamily, "rpc-family", "", gateway.FamilyDual, "which IP family the gateway listens on and dials: dual, ipv4, or ipv6")
 	root.Flags().StringVarP(&globalConfig.Siad.Modules, "modules", "M", "cghmrtw", "enabled modules, see 'siad modules' for more info")
 	root.Flags().BoolVarP(&globalConfig.Siad.AuthenticateAPI, "authenticate-api", "", false, "enable API password protection")
 	root.Flags().BoolVarP(&globalConfig.Siad.AllowAPIBind, "disable-api-security", "", false, "allow siad to listen on a non-localhost address (DANGEROUS)")
+	root.Flags().BoolVarP(&globalConfig.Siad.APITLS, "api-tls", "", false, "serve the API over https, generating a self-signed certificate if none is provided")
+	root.Flags().StringVarP(&globalConfig.Siad.APITLSCert, "api-tls-cert", "", "", "location of the TLS certificate used by the API; implies --api-tls")
+	root.Flags().StringVarP(&globalConfig.Siad.APITLSKey, "api-tls-key", "", "", "location of the private key of the TLS certificate used by the API")
+	root.Flags().StringVarP(&globalConfig.Siad.APIBasePath, "api-base-path", "", "", "serve the API under this path, e.g. /sia, for use behind a reverse proxy")
+	root.Flags().BoolVarP(&globalConfig.Siad.APIDebug, "api-debug", "", false, "serve goroutine, heap, and CPU profiles under /daemon/debug")
+	root.Flags().IntVarP(&globalConfig.Siad.APIPageSize, "api-page-size", "", 0, "number of items returned by API list calls that do not set a limit, 0 for no limit")
+	root.Flags().IntVarP(&globalConfig.Siad.APIMaxPageSize, "api-max-page-size", "", 0, "most items returned by any API list call, 0 for no limit")
+	root.Flags().StringVarP(&globalConfig.Siad.UpdateChannel, "update-channel", "", api.UpdateChannelStable, "which releases /daemon/update checks: stable, or beta to include prereleases")
+	root.Flags().IntVarP(&globalConfig.Siad.UpdateCheckTimeout, "update-check-timeout", "", 30, "seconds to wait for GitHub when checking for an update")
+	root.Flags().IntVarP(&globalConfig.Siad.UpdateCacheTTL, "update-cache-ttl", "", 600, "seconds that the result of an update check is reused, 0 to always ask GitHub")
+	root.Flags().StringVarP(&globalConfig.Siad.UpdateKeyFile, "update-key-file", "", "", "file of PEM encoded public keys trusted to sign updates, replacing the built-in developer key")
+	root.Flags().StringVarP(&globalConfig.Siad.UpdateRepo, "update-repo", "", "", "GitHub owner/repo, or URL of a mirror's releases API, that updates are fetched from (default NebulousLabs/Sia)")
+	root.Flags().StringVarP(&globalConfig.Siad.UpdateArchiveName, "update-archive-name", "", "", "name of the release archive for this platform, where {tag}, {version}, {os}, and {arch} are filled in (default Sia-{tag}-{os}-{arch}.zip)")
+	root.Flags().StringVarP(&globalConfig.Siad.UpdateChecksumsName, "update-checksums-name", "", "", "name of the SHA256SUMS file of a release, with the same placeholders as --update-archive-name (default Sia-{tag}-SHA256SUMS.txt)")
+	root.Flags().Uint64VarP(&globalConfig.Siad.MaxReorgDepth, "max-reorg-depth", "", 0, "refuse to switch to a heavier chain that reverts more than this many blocks, 0 for no limit")
+	root.Flags().IntVarP(&globalConfig.Siad.TpoolMaxTransactions, "tpool-max-transactions", "", 0, "maximum number of transactions held by the transaction pool, 0 for no limit")
+	root.Flags().IntVarP(&globalConfig.Siad.TpoolMaxSize, "tpool-max-size", "", transactionpool.TransactionPoolSizeLimit, "maximum size in bytes of the transaction pool, 0 for no limit")
+	root.Flags().StringVarP(&globalConfig.Siad.TpoolMinRelayFee, "tpool-min-relay-fee", "", "0", "minimum fee in hastings per byte for transactions from peers to be accepted and relayed, 0 to accept any fee")
 
 	// Parse cmdline flags, overwriting both the default values and the config
 	// file values.